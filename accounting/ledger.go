@@ -0,0 +1,607 @@
+// Package accounting builds accounting ledgers from the orders, cases and
+// wallet transactions saved by the node.
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/multiwallet"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/op/go-logging"
+	"gorm.io/gorm"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	log = logging.MustGetLogger("ACCT")
+
+	// ErrUnknownRole is returned when a ledger query has a role other
+	// than buyer, vendor or moderator.
+	ErrUnknownRole = errors.New("unknown ledger role")
+)
+
+// ledgerBatchSize is the number of orders, cases or wallet transactions
+// read in each database transaction when building the ledger.
+const ledgerBatchSize = 100
+
+// BuildLedger returns the ledger entries matching the query sorted by
+// timestamp. The whole ledger is held in memory so StreamLedger should be
+// used to write large ledgers.
+func BuildLedger(db database.Database, mw multiwallet.Multiwallet, query *models.LedgerQuery) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := StreamLedger(db, mw, query, func(entry models.LedgerEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+// StreamLedger passes each ledger entry matching the query to fn as it is
+// built. Orders and cases are walked for payments, refunds, escrow releases
+// and moderator fees. Wallet transactions from each wallet in the
+// multiwallet which are not already part of an order are added as
+// TRANSACTION entries. The fiat value of each entry is calculated in our
+// local currency using the saved rate history.
+//
+// The entries are not sorted. The entries for each order are passed
+// together, followed by the moderator fees and then the wallet transactions.
+// The database is read in batches and fn is only called between them so
+// that a slow writer doesn't hold the database lock.
+func StreamLedger(db database.Database, mw multiwallet.Multiwallet, query *models.LedgerQuery, fn func(entry models.LedgerEntry) error) error {
+	if query == nil {
+		query = &models.LedgerQuery{}
+	}
+	switch query.Role {
+	case "", models.RoleBuyer, models.RoleVendor, models.RoleModerator:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownRole, query.Role)
+	}
+
+	var prefs models.UserPreferences
+	err := db.View(func(tx database.Tx) error {
+		return tx.Read().First(&prefs).Error
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	b := &ledgerBuilder{
+		localCurrency: models.CurrencyCode(strings.ToUpper(prefs.LocalCurrency)),
+		orderTxids:    make(map[string]bool),
+	}
+	// inBatches calls build with each offset in a new database transaction
+	// and passes the entries it added to fn once the transaction is done.
+	// It stops when build reads fewer than ledgerBatchSize rows.
+	inBatches := func(build func(offset int) (int, error)) error {
+		for offset := 0; ; offset += ledgerBatchSize {
+			var n int
+			err := db.View(func(tx database.Tx) error {
+				b.tx = tx
+				var err error
+				n, err = build(offset)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			for _, entry := range b.entries {
+				if query.Matches(&entry) {
+					if err := fn(entry); err != nil {
+						return err
+					}
+				}
+			}
+			b.entries = b.entries[:0]
+			if n < ledgerBatchSize {
+				return nil
+			}
+		}
+	}
+
+	roles := []string{string(models.RoleBuyer), string(models.RoleVendor)}
+	err = inBatches(func(offset int) (int, error) {
+		var orders []models.Order
+		if err := b.tx.Read().Where("my_role IN ?", roles).Order("id").Offset(offset).Limit(ledgerBatchSize).Find(&orders).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		for i := range orders {
+			if err := b.addOrder(&orders[i]); err != nil {
+				log.Errorf("Error adding order %s to ledger: %s", orders[i].ID, err)
+			}
+		}
+		return len(orders), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = inBatches(func(offset int) (int, error) {
+		var cases []models.Case
+		if err := b.tx.Read().Order("id").Offset(offset).Limit(ledgerBatchSize).Find(&cases).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		for i := range cases {
+			if err := b.addCase(&cases[i]); err != nil {
+				log.Errorf("Error adding case %s to ledger: %s", cases[i].ID, err)
+			}
+		}
+		return len(cases), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for ct, wal := range mw {
+		txs, err := wal.Transactions(-1, "")
+		if err != nil {
+			return err
+		}
+		err = inBatches(func(offset int) (int, error) {
+			batch := txs[offset:]
+			if len(batch) > ledgerBatchSize {
+				batch = batch[:ledgerBatchSize]
+			}
+			for _, wtx := range batch {
+				if err := b.addWalletTransaction(ct, wtx); err != nil {
+					return 0, err
+				}
+			}
+			return len(batch), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClosestRate returns the saved exchange rate from the base currency to the
+// given currency closest to the timestamp. If no rate is saved it returns nil.
+func ClosestRate(tx database.Tx, base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error) {
+	base = models.CurrencyCode(strings.TrimPrefix(base.String(), "T"))
+	timestamp = timestamp.UTC()
+
+	var before, after models.ExchangeRateRecord
+	err := tx.Read().Where("base = ? AND currency = ? AND timestamp <= ?", base.String(), currency.String(), timestamp).
+		Order("timestamp desc").First(&before).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	err = tx.Read().Where("base = ? AND currency = ? AND timestamp > ?", base.String(), currency.String(), timestamp).
+		Order("timestamp").First(&after).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	switch {
+	case before.ID == 0 && after.ID == 0:
+		return nil, nil
+	case before.ID == 0:
+		return &after, nil
+	case after.ID == 0:
+		return &before, nil
+	case timestamp.Sub(before.Timestamp) <= after.Timestamp.Sub(timestamp):
+		return &before, nil
+	default:
+		return &after, nil
+	}
+}
+
+// ledgerBuilder accumulates the ledger entries for a batch. The txids of the
+// order entries are kept across batches so that wallet transactions which
+// are part of an order are not added again.
+type ledgerBuilder struct {
+	tx            database.Tx
+	localCurrency models.CurrencyCode
+	orderTxids    map[string]bool
+	entries       []models.LedgerEntry
+}
+
+// add completes the entry with the fiat value and the wallet metadata and
+// appends it to the ledger.
+func (b *ledgerBuilder) add(entry models.LedgerEntry, amount iwallet.Amount) error {
+	coin, err := models.CurrencyDefinitions.Lookup(entry.Coin)
+	if err != nil {
+		return err
+	}
+	entry.Amount = formatAmount(amount, coin.Divisibility)
+
+	if entry.TransactionID != "" {
+		b.orderTxids[entry.TransactionID] = true
+		m, err := b.metadataFor(entry.TransactionID)
+		if err != nil {
+			return err
+		}
+		if m != nil {
+			entry.Memo = m.Memo
+			if entry.OrderID == "" {
+				entry.OrderID = m.OrderID
+			}
+		}
+	}
+
+	if b.localCurrency != "" {
+		rate, err := b.rateFor(&entry)
+		if err != nil {
+			return err
+		}
+		if rate != nil {
+			fiat, err := models.CurrencyDefinitions.Lookup(b.localCurrency.String())
+			if err != nil {
+				return err
+			}
+			value, err := rate.Convert(amount)
+			if err != nil {
+				return err
+			}
+			entry.FiatCurrency = fiat.Code.String()
+			entry.FiatValue = formatAmount(value, fiat.Divisibility)
+		}
+	}
+
+	b.entries = append(b.entries, entry)
+	return nil
+}
+
+// metadataFor returns the saved metadata for the transaction or nil if there
+// is none.
+func (b *ledgerBuilder) metadataFor(txid string) (*models.TransactionMetadata, error) {
+	var m models.TransactionMetadata
+	err := b.tx.Read().Where("txid = ?", txid).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// rateFor returns the rate snapshot saved for the entry's transaction if
+// there is one, otherwise the saved rate closest to the entry's timestamp.
+func (b *ledgerBuilder) rateFor(entry *models.LedgerEntry) (*models.ExchangeRateRecord, error) {
+	if entry.OrderID != "" && entry.TransactionID != "" {
+		var record models.ExchangeRateRecord
+		err := b.tx.Read().Where("order_id = ? AND transaction_id = ? AND currency = ?",
+			entry.OrderID.String(), entry.TransactionID, b.localCurrency.String()).First(&record).Error
+		if err == nil {
+			return &record, nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return ClosestRate(b.tx, models.CurrencyCode(entry.Coin), b.localCurrency, entry.Timestamp)
+}
+
+// addOrder adds the entries for an order in which we are the buyer or vendor.
+func (b *ledgerBuilder) addOrder(order *models.Order) error {
+	orderOpen, err := order.OrderOpenMessage()
+	if errors.Is(err, models.ErrMessageDoesNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if orderOpen.Payment == nil || orderOpen.BuyerID == nil || len(orderOpen.Listings) == 0 ||
+		orderOpen.Listings[0].Listing == nil || orderOpen.Listings[0].Listing.VendorID == nil {
+		return errors.New("order open message is missing fields")
+	}
+	opened, err := order.Timestamp()
+	if err != nil {
+		return err
+	}
+
+	var (
+		role           = order.Role()
+		buyer          = orderOpen.BuyerID.PeerID
+		vendor         = orderOpen.Listings[0].Listing.VendorID.PeerID
+		moderator      = orderOpen.Payment.Moderator
+		paymentAddress = orderOpen.Payment.Address
+		counterparty   = buyer
+		ourAddresses   = map[string]bool{}
+	)
+	if role == models.RoleBuyer {
+		counterparty = vendor
+	}
+	newEntry := func(entryType models.LedgerEntryType, direction models.LedgerDirection, timestamp time.Time, txid string) models.LedgerEntry {
+		return models.LedgerEntry{
+			Timestamp:     timestamp,
+			Type:          entryType,
+			Direction:     direction,
+			Role:          role,
+			OrderID:       order.ID,
+			Coin:          orderOpen.Payment.Coin,
+			Counterparty:  counterparty,
+			TransactionID: txid,
+		}
+	}
+	// inbound returns IN if the funds were paid to us.
+	inbound := func(toUs bool) models.LedgerDirection {
+		if toUs {
+			return models.LedgerIn
+		}
+		return models.LedgerOut
+	}
+
+	// Payments into the order's payment address.
+	txs, err := order.GetTransactions()
+	if err != nil && !errors.Is(err, models.ErrMessageDoesNotExist) {
+		return err
+	}
+	var spends []iwallet.Transaction
+	for _, tx := range txs {
+		paid := iwallet.NewAmount(0)
+		for _, to := range tx.To {
+			if to.Address.String() == paymentAddress {
+				paid = paid.Add(to.Amount)
+			}
+		}
+		for _, from := range tx.From {
+			if from.Address.String() == paymentAddress {
+				spends = append(spends, tx)
+				break
+			}
+		}
+		if paid.Cmp(iwallet.NewAmount(0)) == 0 {
+			continue
+		}
+		timestamp := tx.Timestamp
+		if timestamp.IsZero() {
+			timestamp = opened
+		}
+		entry := newEntry(models.LedgerPayment, inbound(role == models.RoleVendor), timestamp, tx.ID.String())
+		if err := b.add(entry, paid); err != nil {
+			return err
+		}
+	}
+
+	refunds, err := order.Refunds()
+	if err != nil && !errors.Is(err, models.ErrMessageDoesNotExist) {
+		return err
+	}
+	for _, refund := range refunds {
+		timestamp, err := ptypes.Timestamp(refund.Timestamp)
+		if err != nil {
+			return err
+		}
+		entry := newEntry(models.LedgerRefund, inbound(role == models.RoleBuyer), timestamp, refund.GetTransactionID())
+		if err := b.add(entry, iwallet.NewAmount(refund.Amount)); err != nil {
+			return err
+		}
+	}
+
+	orderCancel, err := order.OrderCancelMessage()
+	if err != nil && !errors.Is(err, models.ErrMessageDoesNotExist) {
+		return err
+	}
+	if orderCancel != nil {
+		timestamp, err := ptypes.Timestamp(orderCancel.Timestamp)
+		if err != nil {
+			return err
+		}
+		total, err := order.FundingTotal()
+		if err != nil {
+			return err
+		}
+		entry := newEntry(models.LedgerRefund, inbound(role == models.RoleBuyer), timestamp, orderCancel.TransactionID)
+		if err := b.add(entry, total); err != nil {
+			return err
+		}
+	}
+
+	orderComplete, err := order.OrderCompleteMessage()
+	if err != nil && !errors.Is(err, models.ErrMessageDoesNotExist) {
+		return err
+	}
+	if orderComplete != nil && orderComplete.ReleaseInfo != nil {
+		timestamp, err := ptypes.Timestamp(orderComplete.Timestamp)
+		if err != nil {
+			return err
+		}
+		entry := newEntry(models.LedgerEscrowRelease, inbound(role == models.RoleVendor), timestamp, releaseTxid(spends, orderComplete.ReleaseInfo.ToAddress))
+		if err := b.add(entry, iwallet.NewAmount(orderComplete.ReleaseInfo.ToAmount)); err != nil {
+			return err
+		}
+	}
+
+	// A PAYMENT_FINALIZED message carries no amount so we use the
+	// transaction spending from the escrow.
+	if _, err := order.PaymentFinalizedMessage(); err == nil {
+		for _, tx := range spends {
+			released := iwallet.NewAmount(0)
+			for _, to := range tx.To {
+				if to.Address.String() != paymentAddress {
+					released = released.Add(to.Amount)
+				}
+			}
+			entry := newEntry(models.LedgerEscrowRelease, inbound(role == models.RoleVendor), tx.Timestamp, tx.ID.String())
+			if err := b.add(entry, released); err != nil {
+				return err
+			}
+		}
+	} else if !errors.Is(err, models.ErrMessageDoesNotExist) {
+		return err
+	}
+
+	disputeClose, err := order.DisputeClosedMessage()
+	if err != nil && !errors.Is(err, models.ErrMessageDoesNotExist) {
+		return err
+	}
+	if disputeClose != nil && disputeClose.ReleaseInfo != nil {
+		if role == models.RoleBuyer {
+			ourAddresses[disputeClose.ReleaseInfo.BuyerAddress] = true
+		} else {
+			ourAddresses[disputeClose.ReleaseInfo.VendorAddress] = true
+		}
+		if err := b.addDisputePayout(disputeClose, role, order.ID, orderOpen.Payment.Coin, counterparty, moderator, spends, ourAddresses); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addCase adds the moderator fee for a dispute which we closed as the
+// moderator. The counterparty is the party who opened the dispute.
+func (b *ledgerBuilder) addCase(c *models.Case) error {
+	disputeClose, err := c.DisputeCloseMessage()
+	if errors.Is(err, models.ErrMessageDoesNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if disputeClose.ReleaseInfo == nil {
+		return nil
+	}
+	orderOpen, err := c.OrderOpenMessage()
+	if err != nil {
+		return err
+	}
+	if orderOpen.Payment == nil {
+		return errors.New("order open message is missing payment")
+	}
+	counterparty := ""
+	if openedBy, err := c.OpenedBy(); err == nil {
+		if openedBy == pb.DisputeOpen_VENDOR && len(orderOpen.Listings) > 0 &&
+			orderOpen.Listings[0].Listing != nil && orderOpen.Listings[0].Listing.VendorID != nil {
+			counterparty = orderOpen.Listings[0].Listing.VendorID.PeerID
+		} else if openedBy == pb.DisputeOpen_BUYER && orderOpen.BuyerID != nil {
+			counterparty = orderOpen.BuyerID.PeerID
+		}
+	}
+	ourAddresses := map[string]bool{disputeClose.ReleaseInfo.ModeratorAddress: true}
+	return b.addDisputePayout(disputeClose, models.RoleModerator, c.ID, orderOpen.Payment.Coin, counterparty, "", nil, ourAddresses)
+}
+
+// addDisputePayout adds the escrow releases and moderator fee paid when a
+// dispute is closed. As the moderator we only record our fee.
+func (b *ledgerBuilder) addDisputePayout(disputeClose *pb.DisputeClose, role models.OrderRole, orderID models.OrderID, coin, counterparty, moderator string, spends []iwallet.Transaction, ourAddresses map[string]bool) error {
+	timestamp, err := ptypes.Timestamp(disputeClose.Timestamp)
+	if err != nil {
+		return err
+	}
+	info := disputeClose.ReleaseInfo
+	feeCounterparty := moderator
+	if role == models.RoleModerator {
+		feeCounterparty = counterparty
+	}
+	payouts := []struct {
+		entryType    models.LedgerEntryType
+		address      string
+		amount       string
+		counterparty string
+	}{
+		{models.LedgerEscrowRelease, info.BuyerAddress, info.BuyerAmount, counterparty},
+		{models.LedgerEscrowRelease, info.VendorAddress, info.VendorAmount, counterparty},
+		{models.LedgerModeratorFee, info.ModeratorAddress, info.ModeratorAmount, feeCounterparty},
+	}
+	for _, payout := range payouts {
+		if payout.address == "" || payout.amount == "" {
+			continue
+		}
+		amount := iwallet.NewAmount(payout.amount)
+		if amount.Cmp(iwallet.NewAmount(0)) <= 0 {
+			continue
+		}
+		if role == models.RoleModerator && payout.entryType != models.LedgerModeratorFee {
+			continue
+		}
+		if role != models.RoleModerator && payout.entryType == models.LedgerEscrowRelease && !ourAddresses[payout.address] {
+			continue
+		}
+		direction := models.LedgerOut
+		if ourAddresses[payout.address] {
+			direction = models.LedgerIn
+		}
+		entry := models.LedgerEntry{
+			Timestamp:     timestamp,
+			Type:          payout.entryType,
+			Direction:     direction,
+			Role:          role,
+			OrderID:       orderID,
+			Coin:          coin,
+			Counterparty:  payout.counterparty,
+			TransactionID: releaseTxid(spends, payout.address),
+		}
+		if err := b.add(entry, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addWalletTransaction adds a wallet transaction which is not already part
+// of an order entry.
+func (b *ledgerBuilder) addWalletTransaction(ct iwallet.CoinType, tx iwallet.Transaction) error {
+	if b.orderTxids[tx.ID.String()] {
+		return nil
+	}
+	direction := models.LedgerIn
+	amount := tx.Value
+	if amount.Cmp(iwallet.NewAmount(0)) < 0 {
+		direction = models.LedgerOut
+		x := big.Int(amount)
+		amount = iwallet.NewAmount(new(big.Int).Neg(&x))
+	}
+	entry := models.LedgerEntry{
+		Timestamp:     tx.Timestamp,
+		Type:          models.LedgerTransaction,
+		Direction:     direction,
+		Coin:          ct.CurrencyCode(),
+		TransactionID: tx.ID.String(),
+	}
+	m, err := b.metadataFor(tx.ID.String())
+	if err != nil {
+		return err
+	}
+	if m != nil && m.OrderID != "" {
+		// The transaction is part of an order but was not found in the
+		// order itself. Its role is unknown so it stays a TRANSACTION.
+		entry.OrderID = m.OrderID
+	}
+	if err := b.add(entry, amount); err != nil {
+		// Coins without a currency definition can't be formatted.
+		log.Warningf("Skipping wallet transaction %s: %s", tx.ID, err)
+	}
+	return nil
+}
+
+// releaseTxid returns the ID of the transaction spending from the escrow
+// to the address if there is one.
+func releaseTxid(spends []iwallet.Transaction, address string) string {
+	for _, tx := range spends {
+		for _, to := range tx.To {
+			if to.Address.String() == address {
+				return tx.ID.String()
+			}
+		}
+	}
+	return ""
+}
+
+// formatAmount formats an amount in the smallest unit of a currency as a
+// decimal string in whole units.
+func formatAmount(amount iwallet.Amount, divisibility uint) string {
+	x := big.Int(amount)
+	s := new(big.Int).Abs(&x).String()
+	if divisibility > 0 {
+		if len(s) <= int(divisibility) {
+			s = strings.Repeat("0", int(divisibility)-len(s)+1) + s
+		}
+		s = s[:len(s)-int(divisibility)] + "." + s[len(s)-int(divisibility):]
+	}
+	if x.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
@@ -0,0 +1,313 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/multiwallet"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	"github.com/cpacia/openbazaar3.0/repo"
+	"github.com/cpacia/openbazaar3.0/wallet"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"testing"
+	"time"
+)
+
+// mockTxWallet returns a fixed list of wallet transactions.
+type mockTxWallet struct {
+	*wallet.MockWallet
+	txs []iwallet.Transaction
+}
+
+func (w *mockTxWallet) Transactions(limit int, offsetID iwallet.TransactionID) ([]iwallet.Transaction, error) {
+	return w.txs, nil
+}
+
+func TestBuildLedger(t *testing.T) {
+	db, err := repo.MockDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		start       = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		hour        = func(n int) time.Time { return start.Add(time.Hour * time.Duration(n)) }
+		protoTime   = func(n int) *timestamp.Timestamp { ts, _ := ptypes.TimestampProto(hour(n)); return ts }
+		vendorAddr  = iwallet.NewAddress("vendor", iwallet.CtMock)
+		buyerID     = "QmBuyer"
+		moderatorID = "QmModerator"
+	)
+
+	// An order in which we are the vendor that was paid and completed.
+	sale, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sale.BuyerID.PeerID = buyerID
+	sale.Payment.Moderator = moderatorID
+	sale.Timestamp = protoTime(0)
+	saleAddr := iwallet.NewAddress(sale.Payment.Address, iwallet.CtMock)
+	saleOrder := models.Order{ID: "sale"}
+	saleOrder.SetRole(models.RoleVendor)
+	if err := saleOrder.PutMessage(utils.MustWrapOrderMessage(sale)); err != nil {
+		t.Fatal(err)
+	}
+	txs := []iwallet.Transaction{
+		{
+			ID:        "tx1",
+			Timestamp: hour(1),
+			To:        []iwallet.SpendInfo{{Address: saleAddr, Amount: iwallet.NewAmount(sale.Payment.Amount)}},
+		},
+		{
+			ID:        "tx2",
+			Timestamp: hour(3),
+			From:      []iwallet.SpendInfo{{Address: saleAddr, Amount: iwallet.NewAmount(sale.Payment.Amount)}},
+			To:        []iwallet.SpendInfo{{Address: vendorAddr, Amount: iwallet.NewAmount(4992211)}},
+		},
+	}
+	for _, tx := range txs {
+		if err := saleOrder.PutTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = saleOrder.PutMessage(utils.MustWrapOrderMessage(&pb.OrderComplete{
+		ReleaseInfo: &pb.EscrowRelease{
+			ToAddress: vendorAddr.String(),
+			ToAmount:  "4992211",
+		},
+		Timestamp: protoTime(3),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An order in which we are the buyer that was paid and canceled.
+	purchase, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	purchase.Timestamp = protoTime(4)
+	purchaseAddr := iwallet.NewAddress(purchase.Payment.Address, iwallet.CtMock)
+	purchaseOrder := models.Order{ID: "purchase"}
+	purchaseOrder.SetRole(models.RoleBuyer)
+	if err := purchaseOrder.PutMessage(utils.MustWrapOrderMessage(purchase)); err != nil {
+		t.Fatal(err)
+	}
+	err = purchaseOrder.PutTransaction(iwallet.Transaction{
+		ID:        "tx3",
+		Timestamp: hour(5),
+		To:        []iwallet.SpendInfo{{Address: purchaseAddr, Amount: iwallet.NewAmount("100000000")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = purchaseOrder.PutMessage(utils.MustWrapOrderMessage(&pb.OrderCancel{
+		TransactionID: "tx4",
+		Timestamp:     protoTime(6),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A dispute which we closed as the moderator.
+	disputed, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	disputed.BuyerID.PeerID = buyerID
+	contract, err := proto.Marshal(&pb.Contract{OrderOpen: disputed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	disputeCase := models.Case{ID: "case"}
+	err = disputeCase.PutDisputeOpen(&pb.DisputeOpen{
+		OpenedBy:  pb.DisputeOpen_BUYER,
+		Timestamp: protoTime(7),
+		Contract:  contract,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = disputeCase.PutDisputeClose(&pb.DisputeClose{
+		ReleaseInfo: &pb.DisputeClose_ModeratedEscrowRelease{
+			BuyerAddress:     "buyer",
+			BuyerAmount:      "3000000",
+			VendorAddress:    "vendor",
+			VendorAmount:     "1000000",
+			ModeratorAddress: "moderator",
+			ModeratorAmount:  "500000",
+		},
+		Timestamp: protoTime(8),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Update(func(tx database.Tx) error {
+		if err := tx.Save(&models.UserPreferences{LocalCurrency: "usd"}); err != nil {
+			return err
+		}
+		for _, m := range []interface{}{&saleOrder, &purchaseOrder, &disputeCase} {
+			if err := tx.Save(m); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&models.TransactionMetadata{Txid: "tx5", Memo: "coffee"}); err != nil {
+			return err
+		}
+		// The snapshot for the payment takes precedence over the
+		// closest rate.
+		records := []models.ExchangeRateRecord{
+			{Base: "MCK", Currency: "USD", Rate: "300000", Timestamp: hour(1), OrderID: "sale", Event: models.RateEventPayment, TransactionID: "tx1"},
+			{Base: "MCK", Currency: "USD", Rate: "200000", Timestamp: hour(2)},
+			{Base: "MCK", Currency: "USD", Rate: "100000", Timestamp: hour(6)},
+		}
+		for _, record := range records {
+			if err := tx.Save(&record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mw := multiwallet.Multiwallet{
+		iwallet.CtMock: &mockTxWallet{
+			MockWallet: wallet.NewMockWallet(),
+			txs: []iwallet.Transaction{
+				txs[0],
+				{ID: "tx5", Timestamp: hour(9), Value: iwallet.NewAmount(100000000)},
+				{ID: "tx6", Timestamp: hour(10), Value: iwallet.NewAmount(-50000000)},
+			},
+		},
+	}
+
+	expected := []models.LedgerEntry{
+		{Timestamp: hour(1), Type: models.LedgerPayment, Direction: models.LedgerIn, Role: models.RoleVendor, OrderID: "sale", Coin: "MCK", Amount: "0.04992221", FiatCurrency: "USD", FiatValue: "149.76", Counterparty: buyerID, TransactionID: "tx1"},
+		{Timestamp: hour(3), Type: models.LedgerEscrowRelease, Direction: models.LedgerIn, Role: models.RoleVendor, OrderID: "sale", Coin: "MCK", Amount: "0.04992211", FiatCurrency: "USD", FiatValue: "99.84", Counterparty: buyerID, TransactionID: "tx2"},
+		{Timestamp: hour(5), Type: models.LedgerPayment, Direction: models.LedgerOut, Role: models.RoleBuyer, OrderID: "purchase", Coin: "MCK", Amount: "1.00000000", FiatCurrency: "USD", FiatValue: "1000.00", Counterparty: purchase.Listings[0].Listing.VendorID.PeerID, TransactionID: "tx3"},
+		{Timestamp: hour(6), Type: models.LedgerRefund, Direction: models.LedgerIn, Role: models.RoleBuyer, OrderID: "purchase", Coin: "MCK", Amount: "1.00000000", FiatCurrency: "USD", FiatValue: "1000.00", Counterparty: purchase.Listings[0].Listing.VendorID.PeerID, TransactionID: "tx4"},
+		{Timestamp: hour(8), Type: models.LedgerModeratorFee, Direction: models.LedgerIn, Role: models.RoleModerator, OrderID: "case", Coin: "MCK", Amount: "0.00500000", FiatCurrency: "USD", FiatValue: "5.00", Counterparty: buyerID},
+		{Timestamp: hour(9), Type: models.LedgerTransaction, Direction: models.LedgerIn, Coin: "MCK", Amount: "1.00000000", FiatCurrency: "USD", FiatValue: "1000.00", TransactionID: "tx5", Memo: "coffee"},
+		{Timestamp: hour(10), Type: models.LedgerTransaction, Direction: models.LedgerOut, Coin: "MCK", Amount: "0.50000000", FiatCurrency: "USD", FiatValue: "500.00", TransactionID: "tx6"},
+	}
+
+	entries, err := BuildLedger(db, mw, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d: %v", len(expected), len(entries), entries)
+	}
+	for i := range entries {
+		if !entries[i].Timestamp.Equal(expected[i].Timestamp) {
+			t.Errorf("Entry %d: expected timestamp %s, got %s", i, expected[i].Timestamp, entries[i].Timestamp)
+		}
+		entries[i].Timestamp = expected[i].Timestamp
+		if entries[i] != expected[i] {
+			t.Errorf("Entry %d: expected %v, got %v", i, expected[i], entries[i])
+		}
+	}
+
+	entries, err = BuildLedger(db, mw, &models.LedgerQuery{Role: models.RoleBuyer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].OrderID != "purchase" || entries[1].OrderID != "purchase" {
+		t.Errorf("Expected the purchase entries, got %v", entries)
+	}
+
+	entries, err = BuildLedger(db, mw, &models.LedgerQuery{From: hour(4), To: hour(8)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("Expected 3 entries, got %v", entries)
+	}
+
+	if _, err := BuildLedger(db, mw, &models.LedgerQuery{Role: "shopper"}); !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("Expected unknown role error, got %v", err)
+	}
+}
+
+func TestStreamLedger(t *testing.T) {
+	db, err := repo.MockDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Enough transactions for several batches.
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	txs := make([]iwallet.Transaction, ledgerBatchSize*2+5)
+	for i := range txs {
+		txs[i] = iwallet.Transaction{
+			ID:        iwallet.TransactionID(fmt.Sprintf("tx%d", i)),
+			Timestamp: start.Add(time.Minute * time.Duration(i)),
+			Value:     iwallet.NewAmount(1000),
+		}
+	}
+	mw := multiwallet.Multiwallet{
+		iwallet.CtMock: &mockTxWallet{MockWallet: wallet.NewMockWallet(), txs: txs},
+	}
+
+	var streamed []models.LedgerEntry
+	err = StreamLedger(db, mw, nil, func(entry models.LedgerEntry) error {
+		streamed = append(streamed, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streamed) != len(txs) {
+		t.Fatalf("Expected %d entries, got %d", len(txs), len(streamed))
+	}
+	for i, entry := range streamed {
+		if entry.TransactionID != txs[i].ID.String() {
+			t.Errorf("Entry %d: expected txid %s, got %s", i, txs[i].ID, entry.TransactionID)
+		}
+	}
+
+	// An error from fn stops the stream.
+	errWrite := errors.New("write failed")
+	count := 0
+	err = StreamLedger(db, mw, nil, func(entry models.LedgerEntry) error {
+		count++
+		return errWrite
+	})
+	if !errors.Is(err, errWrite) {
+		t.Errorf("Expected write error, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected stream to stop after the first entry, got %d", count)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount       iwallet.Amount
+		divisibility uint
+		expected     string
+	}{
+		{iwallet.NewAmount(100000000), 8, "1.00000000"},
+		{iwallet.NewAmount(1), 8, "0.00000001"},
+		{iwallet.NewAmount(12345), 2, "123.45"},
+		{iwallet.NewAmount(5), 2, "0.05"},
+		{iwallet.NewAmount(-5), 2, "-0.05"},
+		{iwallet.NewAmount(500), 0, "500"},
+	}
+	for i, test := range tests {
+		if s := formatAmount(test.amount, test.divisibility); s != test.expected {
+			t.Errorf("Test %d: expected %s, got %s", i, test.expected, s)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package accounting
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/models"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// FormatCSV writes the ledger as comma separated values with a header row.
+	FormatCSV = "csv"
+	// FormatJSON writes the ledger as a JSON array of entries.
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat is returned when the export format is not csv or json.
+var ErrUnknownFormat = errors.New("unknown ledger format")
+
+var csvHeader = []string{
+	"timestamp",
+	"type",
+	"direction",
+	"role",
+	"order_id",
+	"coin",
+	"amount",
+	"fiat_currency",
+	"fiat_value",
+	"counterparty",
+	"transaction_id",
+	"memo",
+}
+
+var transactionCSVHeader = []string{
+	"timestamp",
+	"coin",
+	"transaction_id",
+	"direction",
+	"status",
+	"value",
+	"confirmations",
+	"height",
+	"address",
+	"order_id",
+	"memo",
+}
+
+// ContentType returns the MIME type for the ledger format.
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatCSV:
+		return "text/csv", nil
+	case FormatJSON:
+		return "application/json", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// WriteLedger writes the entries to the writer in the given format.
+func WriteLedger(w io.Writer, format string, entries []models.LedgerEntry) error {
+	lw, err := NewLedgerWriter(w, format)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := lw.Write(entry); err != nil {
+			return err
+		}
+	}
+	return lw.Close()
+}
+
+// LedgerWriter writes ledger entries one at a time so that large ledgers
+// can be streamed. Nothing is written to the underlying writer until the
+// first entry is written or the writer is closed.
+type LedgerWriter struct {
+	w       io.Writer
+	format  string
+	csv     *csv.Writer
+	started bool
+}
+
+// NewLedgerWriter returns a LedgerWriter for the given format.
+func NewLedgerWriter(w io.Writer, format string) (*LedgerWriter, error) {
+	lw := &LedgerWriter{w: w, format: format}
+	switch format {
+	case FormatCSV:
+		lw.csv = csv.NewWriter(w)
+	case FormatJSON:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	return lw, nil
+}
+
+// Write writes the entry.
+func (lw *LedgerWriter) Write(entry models.LedgerEntry) error {
+	started := lw.started
+	if err := lw.start(); err != nil {
+		return err
+	}
+	if lw.format == FormatCSV {
+		return lw.csv.Write([]string{
+			entry.Timestamp.UTC().Format(time.RFC3339),
+			string(entry.Type),
+			string(entry.Direction),
+			string(entry.Role),
+			entry.OrderID.String(),
+			entry.Coin,
+			entry.Amount,
+			entry.FiatCurrency,
+			entry.FiatValue,
+			entry.Counterparty,
+			entry.TransactionID,
+			entry.Memo,
+		})
+	}
+	if started {
+		if _, err := io.WriteString(lw.w, ","); err != nil {
+			return err
+		}
+	}
+	out, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = lw.w.Write(out)
+	return err
+}
+
+// Close finishes the ledger. It does not close the underlying writer.
+func (lw *LedgerWriter) Close() error {
+	if err := lw.start(); err != nil {
+		return err
+	}
+	if lw.format == FormatCSV {
+		lw.csv.Flush()
+		return lw.csv.Error()
+	}
+	_, err := io.WriteString(lw.w, "]")
+	return err
+}
+
+// start writes the CSV header or the opening bracket of the JSON array if
+// they have not been written yet.
+func (lw *LedgerWriter) start() error {
+	if lw.started {
+		return nil
+	}
+	lw.started = true
+	if lw.format == FormatCSV {
+		return lw.csv.Write(csvHeader)
+	}
+	_, err := io.WriteString(lw.w, "[")
+	return err
+}
+
+// WriteTransactions writes the wallet transaction history to the writer in
+// the given format.
+func WriteTransactions(w io.Writer, format string, txs []models.WalletTransaction) error {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(transactionCSVHeader); err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			record := []string{
+				tx.Timestamp.UTC().Format(time.RFC3339),
+				tx.Coin,
+				tx.Txid,
+				string(tx.Direction),
+				tx.Status,
+				tx.Value,
+				strconv.FormatUint(tx.Confirmations, 10),
+				strconv.FormatUint(tx.Height, 10),
+				tx.Address,
+				tx.OrderID.String(),
+				tx.Memo,
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(txs)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
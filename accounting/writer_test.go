@@ -0,0 +1,137 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/models"
+	"testing"
+	"time"
+)
+
+func TestWriteLedger(t *testing.T) {
+	entries := []models.LedgerEntry{
+		{
+			Timestamp:     time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC),
+			Type:          models.LedgerPayment,
+			Direction:     models.LedgerIn,
+			Role:          models.RoleVendor,
+			OrderID:       "1234",
+			Coin:          "BTC",
+			Amount:        "0.01000000",
+			FiatCurrency:  "USD",
+			FiatValue:     "95.00",
+			Counterparty:  "QmBuyer",
+			TransactionID: "abc",
+		},
+		{
+			Timestamp:     time.Date(2020, 6, 2, 12, 0, 0, 0, time.UTC),
+			Type:          models.LedgerTransaction,
+			Direction:     models.LedgerOut,
+			Coin:          "BTC",
+			Amount:        "0.50000000",
+			TransactionID: "def",
+			Memo:          "rent, june",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLedger(&buf, FormatCSV, entries); err != nil {
+		t.Fatal(err)
+	}
+	expected := "timestamp,type,direction,role,order_id,coin,amount,fiat_currency,fiat_value,counterparty,transaction_id,memo\n" +
+		"2020-06-01T12:00:00Z,PAYMENT,IN,vendor,1234,BTC,0.01000000,USD,95.00,QmBuyer,abc,\n" +
+		"2020-06-02T12:00:00Z,TRANSACTION,OUT,,,BTC,0.50000000,,,,def,\"rent, june\"\n"
+	if buf.String() != expected {
+		t.Errorf("Expected csv:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteLedger(&buf, FormatJSON, entries); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []models.LedgerEntry
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(entries) {
+		t.Fatalf("Expected %d entries, got %d", len(entries), len(decoded))
+	}
+	for i := range decoded {
+		if decoded[i] != entries[i] {
+			t.Errorf("Entry %d: expected %v, got %v", i, entries[i], decoded[i])
+		}
+	}
+
+	buf.Reset()
+	if err := WriteLedger(&buf, FormatJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]" {
+		t.Errorf("Expected empty array, got %s", buf.String())
+	}
+
+	if err := WriteLedger(&buf, "xml", entries); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected unknown format error, got %v", err)
+	}
+
+	// Nothing is written until the first entry so that errors can still
+	// be returned to the caller before the download starts.
+	buf.Reset()
+	lw, err := NewLedgerWriter(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written, got %s", buf.String())
+	}
+	if err := lw.Write(entries[0]); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Expected entry to be written")
+	}
+}
+
+func TestWriteTransactions(t *testing.T) {
+	txs := []models.WalletTransaction{
+		{
+			Coin:          "BTC",
+			Txid:          "abc",
+			Value:         "-5000",
+			Direction:     models.TransactionOutgoing,
+			Status:        "CONFIRMED",
+			Timestamp:     time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC),
+			Confirmations: 7,
+			Height:        100,
+			OrderID:       "1234",
+			Memo:          "refund, partial",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTransactions(&buf, FormatCSV, txs); err != nil {
+		t.Fatal(err)
+	}
+	expected := "timestamp,coin,transaction_id,direction,status,value,confirmations,height,address,order_id,memo\n" +
+		"2020-06-01T12:00:00Z,BTC,abc,OUTGOING,CONFIRMED,-5000,7,100,,1234,\"refund, partial\"\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteTransactions(&buf, FormatJSON, txs); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []models.WalletTransaction
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0].Txid != "abc" || decoded[0].Memo != "refund, partial" {
+		t.Errorf("Incorrect transactions %v", decoded)
+	}
+
+	if err := WriteTransactions(&buf, "xml", txs); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected unknown format error, got %v", err)
+	}
+}
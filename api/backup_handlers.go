@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"mime"
+	"net/http"
+	"time"
+)
+
+const (
+	// backupPassphraseHeader is the request header holding the passphrase
+	// of a backup being restored. The backup itself is the request body.
+	backupPassphraseHeader = "X-Backup-Passphrase"
+
+	// maxRestoreRequestSize is the maximum size of a backup which may be
+	// restored through the API.
+	maxRestoreRequestSize = 4 << 30
+)
+
+// backupWriter sets the download headers on the first write so that errors
+// returned before the backup is written can still be served as JSON.
+type backupWriter struct {
+	w           http.ResponseWriter
+	filename    string
+	wroteHeader bool
+}
+
+func (bw *backupWriter) Write(p []byte) (int, error) {
+	if !bw.wroteHeader {
+		bw.w.Header().Set("Content-Type", "application/octet-stream")
+		bw.w.Header().Set("X-Content-Type-Options", "nosniff")
+		bw.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": bw.filename}))
+		bw.wroteHeader = true
+	}
+	return bw.w.Write(p)
+}
+
+func (g *Gateway) handlePOSTBackup(w http.ResponseWriter, r *http.Request) {
+	type backup struct {
+		Passphrase string `json:"passphrase"`
+	}
+	var b backup
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	bw := &backupWriter{
+		w:        w,
+		filename: "openbazaar-backup-" + time.Now().UTC().Format("20060102T150405Z") + ".obbak",
+	}
+	err := g.node.BackupRepo(b.Passphrase, bw)
+	if err != nil && bw.wroteHeader {
+		// The download has already started so all we can do is log.
+		log.Errorf("Error streaming backup: %s", err)
+		return
+	}
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTRestore(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRestoreRequestSize)
+
+	err := g.node.RestoreRepo(r.URL.Query().Get("dataDir"), r.Header.Get(backupPassphraseHeader), r.Body)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestBackupHandlers(t *testing.T) {
+	runAPITests(t, apiTests{
+		{
+			name:   "Post backup",
+			path:   "/v1/ob/backup",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.backupRepoFunc = func(passphrase string, w io.Writer) error {
+					if passphrase != "letmein" {
+						return errors.New("incorrect passphrase")
+					}
+					_, err := io.WriteString(w, "OBBACKUP")
+					return err
+				}
+			},
+			body:       []byte(`{"passphrase": "letmein"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return []byte("OBBACKUP"), nil
+			},
+		},
+		{
+			name:   "Post backup empty passphrase",
+			path:   "/v1/ob/backup",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.backupRepoFunc = func(passphrase string, w io.Writer) error {
+					return fmt.Errorf("%w: backup passphrase must not be empty", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "bad request: backup passphrase must not be empty"}`)), nil
+			},
+		},
+		{
+			name:   "Post restore",
+			path:   "/v1/ob/restore?dataDir=/tmp/restored",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.restoreRepoFunc = func(dataDir, passphrase string, backup io.Reader) error {
+					b, err := ioutil.ReadAll(backup)
+					if err != nil {
+						return err
+					}
+					if dataDir != "/tmp/restored" || passphrase != "letmein" || string(b) != "OBBACKUP" {
+						return errors.New("incorrect restore")
+					}
+					return nil
+				}
+			},
+			body:       []byte("OBBACKUP"),
+			headers:    map[string]string{backupPassphraseHeader: "letmein"},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post restore invalid backup",
+			path:   "/v1/ob/restore?dataDir=/tmp/restored",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.restoreRepoFunc = func(dataDir, passphrase string, backup io.Reader) error {
+					return fmt.Errorf("%w: invalid backup: incorrect passphrase or corrupt backup", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte("OBBACKUP"),
+			headers:    map[string]string{backupPassphraseHeader: "wrong"},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "bad request: invalid backup: incorrect passphrase or corrupt backup"}`)), nil
+			},
+		},
+	})
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/gorilla/mux"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"net/http"
+)
+
+func (g *Gateway) handlePOSTBlockNode(w http.ResponseWriter, r *http.Request) {
+	peerIDStr := mux.Vars(r)["peerID"]
+	pid, err := peer.Decode(peerIDStr)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	err = g.node.BlockNode(pid)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handleDELETEBlockNode(w http.ResponseWriter, r *http.Request) {
+	peerIDStr := mux.Vars(r)["peerID"]
+	pid, err := peer.Decode(peerIDStr)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	err = g.node.UnblockNode(pid)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handleGETBlockedNodes(w http.ResponseWriter, r *http.Request) {
+	blocked, err := g.node.GetBlockedNodes()
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	ret := make([]string, 0, len(blocked))
+	for _, pid := range blocked {
+		ret = append(ret, pid.Pretty())
+	}
+	sanitizedJSONResponse(w, ret)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"net/http"
+	"testing"
+)
+
+func TestBlockHandlers(t *testing.T) {
+	runAPITests(t, apiTests{
+		{
+			name:   "Post block",
+			path:   "/v1/ob/block/12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.blockNodeFunc = func(peerID peer.ID) error {
+					if peerID.Pretty() != "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv" {
+						return errors.New("invalid peerID")
+					}
+					return nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post block already blocked",
+			path:   "/v1/ob/block/12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.blockNodeFunc = func(peerID peer.ID) error {
+					return fmt.Errorf("%w: peer already blocked", coreiface.ErrBadRequest)
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "bad request: peer already blocked"}`)), nil
+			},
+		},
+		{
+			name:   "Post block invalid peerID",
+			path:   "/v1/ob/block/xxx",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.blockNodeFunc = func(peerID peer.ID) error {
+					return nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "failed to parse peer ID: selected encoding not supported"}`)), nil
+			},
+		},
+		{
+			name:   "Delete block",
+			path:   "/v1/ob/block/12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+			method: http.MethodDelete,
+			setNodeMethods: func(n *mockNode) {
+				n.unblockNodeFunc = func(peerID peer.ID) error {
+					if peerID.Pretty() != "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv" {
+						return errors.New("invalid peerID")
+					}
+					return nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Delete block fail",
+			path:   "/v1/ob/block/12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+			method: http.MethodDelete,
+			setNodeMethods: func(n *mockNode) {
+				n.unblockNodeFunc = func(peerID peer.ID) error {
+					return errors.New("error")
+				}
+			},
+			statusCode: http.StatusInternalServerError,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "error"}`)), nil
+			},
+		},
+		{
+			name:   "Get blocked",
+			path:   "/v1/ob/blocked",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getBlockedNodesFunc = func() ([]peer.ID, error) {
+					pid, err := peer.Decode("12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv")
+					if err != nil {
+						return nil, err
+					}
+					return []peer.ID{pid}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]string{"12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv"})
+			},
+		},
+		{
+			name:   "Get blocked empty",
+			path:   "/v1/ob/blocked",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getBlockedNodesFunc = func() ([]peer.ID, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(`[]`), nil
+			},
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+func (g *Gateway) handleGETCases(w http.ResponseWriter, r *http.Request) {
+	query, err := parseCaseQuery(r)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	summaries, err := g.node.GetCases(query)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	if summaries == nil {
+		summaries = []models.CaseSummary{}
+	}
+	sanitizedJSONResponse(w, summaries)
+}
+
+func (g *Gateway) handleGETCase(w http.ResponseWriter, r *http.Request) {
+	caseID := mux.Vars(r)["caseID"]
+
+	view, err := g.node.GetCase(models.OrderID(caseID))
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	sanitizedJSONResponse(w, view)
+}
+
+func (g *Gateway) handlePOSTCloseDispute(w http.ResponseWriter, r *http.Request) {
+	type closeDispute struct {
+		OrderID          string  `json:"orderID"`
+		Verdict          string  `json:"verdict"`
+		BuyerPercentage  float32 `json:"buyerPercentage"`
+		VendorPercentage float32 `json:"vendorPercentage"`
+	}
+	var c closeDispute
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	view, err := g.node.GetCase(models.OrderID(c.OrderID))
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	err = g.node.CloseDispute(view.Case, c.Verdict, c.BuyerPercentage, c.VendorPercentage, nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseCaseQuery builds a CaseQuery from the URL query parameters.
+func parseCaseQuery(r *http.Request) (*models.CaseQuery, error) {
+	var (
+		params = r.URL.Query()
+		query  = &models.CaseQuery{
+			OffsetID: models.OrderID(params.Get("offsetID")),
+		}
+		err error
+	)
+	if openStr := params.Get("open"); openStr != "" {
+		open, err := strconv.ParseBool(openStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid open parameter: %s", err)
+		}
+		query.Open = &open
+	}
+	if limitStr := params.Get("limit"); limitStr != "" {
+		query.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
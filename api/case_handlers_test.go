@@ -0,0 +1,156 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"net/http"
+	"testing"
+)
+
+func TestCaseHandlers(t *testing.T) {
+	runAPITests(t, apiTests{
+		{
+			name:   "Get cases",
+			path:   "/v1/ob/cases?open=true&limit=5&offsetID=abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getCasesFunc = func(query *models.CaseQuery) ([]models.CaseSummary, error) {
+					if query.Open == nil || !*query.Open || query.Limit != 5 || query.OffsetID != "abc" {
+						return nil, errors.New("incorrect query")
+					}
+					return []models.CaseSummary{{CaseID: "xyz", Open: true}}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]models.CaseSummary{{CaseID: "xyz", Open: true}})
+			},
+		},
+		{
+			name:   "Get cases empty",
+			path:   "/v1/ob/cases",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getCasesFunc = func(query *models.CaseQuery) ([]models.CaseSummary, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]models.CaseSummary{})
+			},
+		},
+		{
+			name:   "Get cases invalid open",
+			path:   "/v1/ob/cases?open=maybe",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getCasesFunc = func(query *models.CaseQuery) ([]models.CaseSummary, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Get cases offset not found",
+			path:   "/v1/ob/cases?offsetID=abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getCasesFunc = func(query *models.CaseQuery) ([]models.CaseSummary, error) {
+					return nil, fmt.Errorf("%w: offset case not found", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: offset case not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get case",
+			path:   "/v1/ob/case/abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getCaseFunc = func(caseID models.OrderID) (*models.CaseView, error) {
+					return &models.CaseView{CaseSummary: models.CaseSummary{CaseID: caseID}, EscrowBalance: "100"}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(&models.CaseView{CaseSummary: models.CaseSummary{CaseID: "abc"}, EscrowBalance: "100"})
+			},
+		},
+		{
+			name:   "Get case not found",
+			path:   "/v1/ob/case/abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getCaseFunc = func(caseID models.OrderID) (*models.CaseView, error) {
+					return nil, fmt.Errorf("%w: case not found", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: case not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post close dispute",
+			path:   "/v1/ob/closedispute",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.getCaseFunc = func(caseID models.OrderID) (*models.CaseView, error) {
+					return &models.CaseView{Case: &models.Case{ID: caseID}}, nil
+				}
+				n.closeDisputeFunc = func(disputeCase *models.Case, verdict string, buyerPercentage, vendorPercentage float32, done chan struct{}) error {
+					if disputeCase.ID != "abc" || verdict != "split" || buyerPercentage != 60 || vendorPercentage != 40 {
+						return errors.New("incorrect arguments")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc", "verdict": "split", "buyerPercentage": 60, "vendorPercentage": 40}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post close dispute case not found",
+			path:   "/v1/ob/closedispute",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.getCaseFunc = func(caseID models.OrderID) (*models.CaseView, error) {
+					return nil, fmt.Errorf("%w: case not found", coreiface.ErrNotFound)
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: case not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post close dispute bad request",
+			path:   "/v1/ob/closedispute",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.getCaseFunc = func(caseID models.OrderID) (*models.CaseView, error) {
+					return &models.CaseView{Case: &models.Case{ID: caseID}}, nil
+				}
+				n.closeDisputeFunc = func(disputeCase *models.Case, verdict string, buyerPercentage, vendorPercentage float32, done chan struct{}) error {
+					return fmt.Errorf("%w: percentages must add up to 100", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: percentages must add up to 100"}%s`, "\n")), nil
+			},
+		},
+	})
+}
@@ -2,16 +2,19 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/gorilla/mux"
 	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"net/http"
 	"strconv"
 )
 
 func (g *Gateway) handlePOSTPublishChannelMessage(w http.ResponseWriter, r *http.Request) {
 	type message struct {
-		Message string `json:"message"`
-		Topic   string `json:"topic"`
+		Topic string `json:"topic"`
+		models.ChannelMessageContent
 	}
 	var m message
 	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
@@ -19,8 +22,8 @@ func (g *Gateway) handlePOSTPublishChannelMessage(w http.ResponseWriter, r *http
 		return
 	}
 
-	if err := g.node.PublishChannelMessage(r.Context(), m.Topic, m.Message); err != nil {
-		http.Error(w, wrapError(err), http.StatusInternalServerError)
+	if err := g.node.PublishChannelMessage(r.Context(), m.Topic, m.ChannelMessageContent); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
 		return
 	}
 }
@@ -79,3 +82,205 @@ func (g *Gateway) handleGETChannelMessages(w http.ResponseWriter, r *http.Reques
 	}
 	sanitizedJSONResponse(w, messages)
 }
+
+func (g *Gateway) handleGETChannelPolicy(w http.ResponseWriter, r *http.Request) {
+	topic := mux.Vars(r)["topic"]
+
+	policy, err := g.node.GetChannelPolicy(topic)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	sanitizedJSONResponse(w, policy)
+}
+
+func (g *Gateway) handlePOSTPublishChannelPolicy(w http.ResponseWriter, r *http.Request) {
+	topic := mux.Vars(r)["topic"]
+
+	var policy models.ChannelOwnerPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.node.PublishChannelPolicy(r.Context(), topic, policy); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTSetChannelOwner(w http.ResponseWriter, r *http.Request) {
+	topic := mux.Vars(r)["topic"]
+
+	type owner struct {
+		PeerID string `json:"peerID"`
+	}
+	var o owner
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	pid, err := peer.Decode(o.PeerID)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.node.SetChannelOwner(topic, pid); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTSetChannelRateLimit(w http.ResponseWriter, r *http.Request) {
+	topic := mux.Vars(r)["topic"]
+
+	type rateLimit struct {
+		MessagesPerMinute int `json:"messagesPerMinute"`
+	}
+	var rl rateLimit
+	if err := json.NewDecoder(r.Body).Decode(&rl); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.node.SetChannelRateLimit(topic, rl.MessagesPerMinute); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTMuteChannelPeer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	pid, err := peer.Decode(vars["peerID"])
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.node.MuteChannelPeer(vars["topic"], pid); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTUnmuteChannelPeer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	pid, err := peer.Decode(vars["peerID"])
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.node.UnmuteChannelPeer(vars["topic"], pid); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTDeleteChannelMessages(w http.ResponseWriter, r *http.Request) {
+	topic := mux.Vars(r)["topic"]
+
+	type deletion struct {
+		Cids []string `json:"cids"`
+	}
+	var d deletion
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	ids := make([]cid.Cid, 0, len(d.Cids))
+	for _, s := range d.Cids {
+		id, err := cid.Decode(s)
+		if err != nil {
+			http.Error(w, wrapError(err), http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	if err := g.node.DeleteChannelMessages(r.Context(), topic, ids); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTCreatePrivateChannel(w http.ResponseWriter, r *http.Request) {
+	type privateChannel struct {
+		Topic   string   `json:"topic"`
+		Members []string `json:"members"`
+	}
+	var pc privateChannel
+	if err := json.NewDecoder(r.Body).Decode(&pc); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	if pc.Topic == "" {
+		http.Error(w, wrapError(errors.New("topic is required")), http.StatusBadRequest)
+		return
+	}
+	members, err := decodePeerIDs(pc.Members)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.node.CreatePrivateChannel(pc.Topic, members); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func (g *Gateway) handleGETPrivateChannel(w http.ResponseWriter, r *http.Request) {
+	topic := mux.Vars(r)["topic"]
+
+	pc, err := g.node.GetPrivateChannel(topic)
+	if err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+	sanitizedJSONResponse(w, pc)
+}
+
+func (g *Gateway) handlePOSTUpdatePrivateChannelMembers(w http.ResponseWriter, r *http.Request) {
+	topic := mux.Vars(r)["topic"]
+
+	type update struct {
+		Add    []string `json:"add"`
+		Remove []string `json:"remove"`
+	}
+	var u update
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	add, err := decodePeerIDs(u.Add)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	remove, err := decodePeerIDs(u.Remove)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.node.UpdatePrivateChannelMembers(topic, add, remove); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+}
+
+func decodePeerIDs(ids []string) ([]peer.ID, error) {
+	pids := make([]peer.ID, 0, len(ids))
+	for _, id := range ids {
+		pid, err := peer.Decode(id)
+		if err != nil {
+			return nil, err
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"net/http"
 	"testing"
 )
@@ -17,7 +19,7 @@ func TestChannelHandlers(t *testing.T) {
 			path:   "/v1/ob/channelmessage",
 			method: http.MethodPost,
 			setNodeMethods: func(n *mockNode) {
-				n.publishChannelMessage = func(ctx context.Context, topic, message string) error {
+				n.publishChannelMessage = func(ctx context.Context, topic string, content models.ChannelMessageContent) error {
 					return nil
 				}
 			},
@@ -27,12 +29,63 @@ func TestChannelHandlers(t *testing.T) {
 				return nil, nil
 			},
 		},
+		{
+			name:   "Post channel reply with listing",
+			path:   "/v1/ob/channelmessage",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.publishChannelMessage = func(ctx context.Context, topic string, content models.ChannelMessageContent) error {
+					if topic != "general" || content.ReplyTo != "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n" || content.Listing == nil || content.Listing.Slug != "shirt" {
+						return errors.New("invalid content")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"message": "check this out", "topic": "general", "replyTo": "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n", "listing": {"vendorID": "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", "slug": "shirt"}}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post channel reaction",
+			path:   "/v1/ob/channelmessage",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.publishChannelMessage = func(ctx context.Context, topic string, content models.ChannelMessageContent) error {
+					if content.Reaction == nil || content.Reaction.Emoji != "👍" || content.Reaction.Target != "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n" {
+						return errors.New("invalid content")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"topic": "general", "reaction": {"target": "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n", "emoji": "👍"}}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post channel message listing not found",
+			path:   "/v1/ob/channelmessage",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.publishChannelMessage = func(ctx context.Context, topic string, content models.ChannelMessageContent) error {
+					return fmt.Errorf("%w: listing not found", coreiface.ErrNotFound)
+				}
+			},
+			body:       []byte(`{"topic": "general", "listing": {"vendorID": "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", "slug": "shirt"}}`),
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: listing not found"}%s`, "\n")), nil
+			},
+		},
 		{
 			name:   "Post invalid channel message",
 			path:   "/v1/ob/channelmessage",
 			method: http.MethodPost,
 			setNodeMethods: func(n *mockNode) {
-				n.publishChannelMessage = func(ctx context.Context, topic, message string) error {
+				n.publishChannelMessage = func(ctx context.Context, topic string, content models.ChannelMessageContent) error {
 					return nil
 				}
 			},
@@ -47,7 +100,7 @@ func TestChannelHandlers(t *testing.T) {
 			path:   "/v1/ob/channelmessage",
 			method: http.MethodPost,
 			setNodeMethods: func(n *mockNode) {
-				n.publishChannelMessage = func(ctx context.Context, topic, message string) error {
+				n.publishChannelMessage = func(ctx context.Context, topic string, content models.ChannelMessageContent) error {
 					return errors.New("error")
 				}
 			},
@@ -271,5 +324,281 @@ func TestChannelHandlers(t *testing.T) {
 				return []byte(fmt.Sprintf(`{"error": "error"}%s`, "\n")), nil
 			},
 		},
+		{
+			name:   "Get channel policy",
+			path:   "/v1/ob/channelpolicy/general",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getChannelPolicy = func(topic string) (*models.ChannelPolicy, error) {
+					return &models.ChannelPolicy{Topic: topic, Owner: "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", Muted: []string{}}, nil
+				}
+			},
+			body:       nil,
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(&models.ChannelPolicy{Topic: "general", Owner: "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", Muted: []string{}})
+			},
+		},
+		{
+			name:   "Get channel policy error",
+			path:   "/v1/ob/channelpolicy/general",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getChannelPolicy = func(topic string) (*models.ChannelPolicy, error) {
+					return nil, errors.New("error")
+				}
+			},
+			body:       nil,
+			statusCode: http.StatusInternalServerError,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "error"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Publish channel policy",
+			path:   "/v1/ob/channelpolicy/general",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.publishChannelPolicy = func(ctx context.Context, topic string, policy models.ChannelOwnerPolicy) error {
+					if topic != "general" || len(policy.Deny) != 1 || policy.PoWBits != 8 || policy.RateLimit != 10 {
+						return errors.New("invalid policy")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"deny": ["12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv"], "powBits": 8, "rateLimit": 10}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Publish channel policy not owner",
+			path:   "/v1/ob/channelpolicy/general",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.publishChannelPolicy = func(ctx context.Context, topic string, policy models.ChannelOwnerPolicy) error {
+					return fmt.Errorf("%w: not the channel owner", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: not the channel owner"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Set channel owner",
+			path:   "/v1/ob/channelowner/general",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.setChannelOwner = func(topic string, owner peer.ID) error {
+					if owner.Pretty() != "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv" {
+						return errors.New("invalid owner")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"peerID": "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Set channel owner invalid peer ID",
+			path:   "/v1/ob/channelowner/general",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.setChannelOwner = func(topic string, owner peer.ID) error {
+					return nil
+				}
+			},
+			body:       []byte(`{"peerID": "xyz"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "failed to parse peer ID: selected encoding not supported"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Set channel rate limit",
+			path:   "/v1/ob/channelratelimit/general",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.setChannelRateLimit = func(topic string, messagesPerMinute int) error {
+					if messagesPerMinute != 5 {
+						return errors.New("invalid rate limit")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"messagesPerMinute": 5}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Mute channel peer",
+			path:   "/v1/ob/mutechannelpeer/general/12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.muteChannelPeer = func(topic string, peerID peer.ID) error {
+					if topic != "general" || peerID.Pretty() != "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv" {
+						return errors.New("invalid peer")
+					}
+					return nil
+				}
+			},
+			body:       nil,
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Unmute channel peer not muted",
+			path:   "/v1/ob/unmutechannelpeer/general/12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.unmuteChannelPeer = func(topic string, peerID peer.ID) error {
+					return fmt.Errorf("%w: peer is not muted", coreiface.ErrNotFound)
+				}
+			},
+			body:       nil,
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: peer is not muted"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Delete channel messages",
+			path:   "/v1/ob/deletechannelmessages/general",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.deleteChannelMessages = func(ctx context.Context, topic string, ids []cid.Cid) error {
+					if len(ids) != 1 || ids[0].String() != "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n" {
+						return errors.New("invalid cids")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"cids": ["QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n"]}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Delete channel messages invalid cid",
+			path:   "/v1/ob/deletechannelmessages/general",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.deleteChannelMessages = func(ctx context.Context, topic string, ids []cid.Cid) error {
+					return nil
+				}
+			},
+			body:       []byte(`{"cids": ["a"]}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "cid too short"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Create private channel",
+			path:   "/v1/ob/privatechannel",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.createPrivateChannel = func(topic string, members []peer.ID) error {
+					if topic != "wholesale" || len(members) != 1 || members[0].Pretty() != "12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi" {
+						return errors.New("invalid private channel")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"topic": "wholesale", "members": ["12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi"]}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Create private channel invalid member",
+			path:   "/v1/ob/privatechannel",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.createPrivateChannel = func(topic string, members []peer.ID) error {
+					return nil
+				}
+			},
+			body:       []byte(`{"topic": "wholesale", "members": ["abc"]}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "failed to parse peer ID: selected encoding not supported"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Create private channel already exists",
+			path:   "/v1/ob/privatechannel",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.createPrivateChannel = func(topic string, members []peer.ID) error {
+					return fmt.Errorf("%w: channel already exists", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{"topic": "general", "members": []}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: channel already exists"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get private channel",
+			path:   "/v1/ob/privatechannel/wholesale",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getPrivateChannel = func(topic string) (*models.PrivateChannel, error) {
+					return &models.PrivateChannel{Topic: topic, Owner: "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", Epoch: 1, Members: []string{"12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv"}}, nil
+				}
+			},
+			body:       nil,
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(&models.PrivateChannel{Topic: "wholesale", Owner: "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", Epoch: 1, Members: []string{"12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv"}})
+			},
+		},
+		{
+			name:   "Get private channel not found",
+			path:   "/v1/ob/privatechannel/general",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getPrivateChannel = func(topic string) (*models.PrivateChannel, error) {
+					return nil, fmt.Errorf("%w: private channel not found", coreiface.ErrNotFound)
+				}
+			},
+			body:       nil,
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: private channel not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Update private channel members",
+			path:   "/v1/ob/privatechannelmembers/wholesale",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.updatePrivateChannelMembers = func(topic string, add, remove []peer.ID) error {
+					if topic != "wholesale" || len(add) != 0 || len(remove) != 1 {
+						return errors.New("invalid update")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"remove": ["12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi"]}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
 	})
 }
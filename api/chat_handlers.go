@@ -1,19 +1,34 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/gorilla/mux"
+	"github.com/ipfs/go-cid"
 	peer "github.com/libp2p/go-libp2p-core/peer"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// maxChatAttachmentRequestSize is the maximum size of a chat attachment
+// upload request. This leaves room for the base64 encoding of a 10 MiB
+// attachment plus the filename.
+const maxChatAttachmentRequestSize = 14 << 20
+
 func (g *Gateway) handlePOSTSendChatMessage(w http.ResponseWriter, r *http.Request) {
 	type message struct {
-		PeerID  string `json:"peerID"`
-		Message string `json:"message"`
-		OrderID string `json:"orderID"`
+		PeerID      string   `json:"peerID"`
+		Message     string   `json:"message"`
+		OrderID     string   `json:"orderID"`
+		Attachments []string `json:"attachments"`
 	}
 	var m message
 	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
@@ -27,17 +42,24 @@ func (g *Gateway) handlePOSTSendChatMessage(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if err := g.node.SendChatMessage(pid, m.Message, models.OrderID(m.OrderID), nil); err != nil {
-		http.Error(w, wrapError(err), http.StatusInternalServerError)
+	attachments, err := decodeAttachmentIDs(m.Attachments)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.sendChatMessage(pid, m.Message, models.OrderID(m.OrderID), attachments); err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
 		return
 	}
 }
 
 func (g *Gateway) handlePOSTSendGroupChatMessage(w http.ResponseWriter, r *http.Request) {
 	type message struct {
-		PeerIDs []string `json:"peerIDs"`
-		Message string   `json:"message"`
-		OrderID string   `json:"orderID"`
+		PeerIDs     []string `json:"peerIDs"`
+		Message     string   `json:"message"`
+		OrderID     string   `json:"orderID"`
+		Attachments []string `json:"attachments"`
 	}
 	var m message
 	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
@@ -45,6 +67,12 @@ func (g *Gateway) handlePOSTSendGroupChatMessage(w http.ResponseWriter, r *http.
 		return
 	}
 
+	attachments, err := decodeAttachmentIDs(m.Attachments)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
 	for _, peerID := range m.PeerIDs {
 		pid, err := peer.Decode(peerID)
 		if err != nil {
@@ -52,13 +80,113 @@ func (g *Gateway) handlePOSTSendGroupChatMessage(w http.ResponseWriter, r *http.
 			return
 		}
 
-		if err := g.node.SendChatMessage(pid, m.Message, models.OrderID(m.OrderID), nil); err != nil {
-			http.Error(w, wrapError(err), http.StatusInternalServerError)
+		if err := g.sendChatMessage(pid, m.Message, models.OrderID(m.OrderID), attachments); err != nil {
+			http.Error(w, wrapError(err), statusCodeForChatError(err))
 			return
 		}
 	}
 }
 
+func (g *Gateway) handlePOSTChatAttachment(w http.ResponseWriter, r *http.Request) {
+	type attachment struct {
+		Filename string `json:"filename"`
+		Data     string `json:"data"`
+	}
+	var a attachment
+	r.Body = http.MaxBytesReader(w, r.Body, maxChatAttachmentRequestSize)
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	data, err := base64.StdEncoding.DecodeString(a.Data)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	chatAttachment, err := g.node.AddChatAttachment(a.Filename, data)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	sanitizedJSONResponse(w, chatAttachment)
+}
+
+func (g *Gateway) handleGETChatAttachment(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["cid"]
+
+	id, err := cid.Decode(idStr)
+	if err != nil {
+		http.Error(w, wrapError(fmt.Errorf("invalid attachment id: %s", err.Error())), http.StatusBadRequest)
+		return
+	}
+
+	attachment, reader, err := g.node.GetChatAttachment(r.Context(), id)
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	// The MIME type in the attachment was set by the sender so we
+	// sniff the decrypted file ourselves before serving it.
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(reader, head)
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	contentType := http.DetectContentType(head[:n])
+	disposition := "attachment"
+	if strings.HasPrefix(contentType, "image/") {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("Cache-Control", "private, max-age=29030400, immutable")
+	http.ServeContent(w, r, attachment.Filename, time.Now(), reader)
+}
+
+// sendChatMessage sends the chat message including the attachments, if any.
+func (g *Gateway) sendChatMessage(pid peer.ID, message string, orderID models.OrderID, attachments []cid.Cid) error {
+	if len(attachments) == 0 {
+		return g.node.SendChatMessage(pid, message, orderID, nil)
+	}
+	return g.node.SendChatMessageWithAttachments(pid, message, orderID, attachments, nil)
+}
+
+func decodeAttachmentIDs(ids []string) ([]cid.Cid, error) {
+	attachments := make([]cid.Cid, 0, len(ids))
+	for _, idStr := range ids {
+		id, err := cid.Decode(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attachment id: %s", err.Error())
+		}
+		attachments = append(attachments, id)
+	}
+	return attachments, nil
+}
+
+func statusCodeForChatError(err error) int {
+	switch {
+	case errors.Is(err, coreiface.ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, coreiface.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (g *Gateway) handlePOSTSendTypingMessage(w http.ResponseWriter, r *http.Request) {
 	type message struct {
 		PeerID  string `json:"peerID"`
@@ -200,6 +328,42 @@ func (g *Gateway) handleGETGroupChatMessages(w http.ResponseWriter, r *http.Requ
 	sanitizedJSONResponse(w, messages)
 }
 
+func (g *Gateway) handleGETChatSearch(w http.ResponseWriter, r *http.Request) {
+	var (
+		params = r.URL.Query()
+		query  = &models.ChatSearchQuery{
+			Query:   params.Get("q"),
+			PeerID:  params.Get("peerID"),
+			OrderID: models.OrderID(params.Get("orderID")),
+		}
+		err error
+	)
+	if limitStr := params.Get("limit"); limitStr != "" {
+		query.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			http.Error(w, wrapError(err), http.StatusBadRequest)
+			return
+		}
+	}
+	if offsetStr := params.Get("offset"); offsetStr != "" {
+		query.Offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			http.Error(w, wrapError(err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	results, err := g.node.SearchChatMessages(query)
+	if err != nil {
+		http.Error(w, wrapError(err), statusCodeForChatError(err))
+		return
+	}
+	if results == nil {
+		results = []models.ChatSearchResult{}
+	}
+	sanitizedJSONResponse(w, results)
+}
+
 func (g *Gateway) handleDELETEChatMessages(w http.ResponseWriter, r *http.Request) {
 	messageID := mux.Vars(r)["messageID"]
 	err := g.node.DeleteChatMessage(messageID)
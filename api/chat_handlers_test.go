@@ -1,11 +1,17 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/ipfs/go-cid"
 	peer "github.com/libp2p/go-libp2p-core/peer"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -666,5 +672,239 @@ func TestChatHandlers(t *testing.T) {
 				return []byte(fmt.Sprintf(`{"error": "error"}%s`, "\n")), nil
 			},
 		},
+		{
+			name:   "Post chat message with attachments",
+			path:   "/v1/ob/chatmessage",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.sendChatMessageWithAttachmentsFunc = func(to peer.ID, message string, orderID models.OrderID, attachments []cid.Cid, done chan<- struct{}) error {
+					if len(attachments) != 1 || attachments[0].String() != "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n" {
+						return errors.New("error")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"peerID": "12D3KooWLbTBv97L6jvaLkdSRpqhCX3w7PyPDWU7kwJsKJyztAUN", "message": "", "orderID": "", "attachments": ["QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n"]}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post chat message invalid attachment",
+			path:   "/v1/ob/chatmessage",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.sendChatMessageWithAttachmentsFunc = func(to peer.ID, message string, orderID models.OrderID, attachments []cid.Cid, done chan<- struct{}) error {
+					return nil
+				}
+			},
+			body:       []byte(`{"peerID": "12D3KooWLbTBv97L6jvaLkdSRpqhCX3w7PyPDWU7kwJsKJyztAUN", "message": "", "orderID": "", "attachments": ["xxx"]}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "invalid attachment id: selected encoding not supported"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post chat message attachment not found",
+			path:   "/v1/ob/chatmessage",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.sendChatMessageWithAttachmentsFunc = func(to peer.ID, message string, orderID models.OrderID, attachments []cid.Cid, done chan<- struct{}) error {
+					return coreiface.ErrNotFound
+				}
+			},
+			body:       []byte(`{"peerID": "12D3KooWLbTBv97L6jvaLkdSRpqhCX3w7PyPDWU7kwJsKJyztAUN", "message": "", "orderID": "", "attachments": ["QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n"]}`),
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post chat attachment",
+			path:   "/v1/ob/chatattachment",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.addChatAttachmentFunc = func(filename string, data []byte) (*models.ChatAttachment, error) {
+					if string(data) != "hello" {
+						return nil, errors.New("error")
+					}
+					return &models.ChatAttachment{
+						CID:      "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n",
+						Key:      []byte{0x01},
+						Filename: filename,
+						MimeType: "text/plain; charset=utf-8",
+						Size:     int64(len(data)),
+					}, nil
+				}
+			},
+			body:       []byte(`{"filename": "hello.txt", "data": "aGVsbG8="}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(&models.ChatAttachment{
+					CID:      "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n",
+					Filename: "hello.txt",
+					MimeType: "text/plain; charset=utf-8",
+					Size:     5,
+				})
+			},
+		},
+		{
+			name:   "Post chat attachment invalid data",
+			path:   "/v1/ob/chatattachment",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.addChatAttachmentFunc = func(filename string, data []byte) (*models.ChatAttachment, error) {
+					return &models.ChatAttachment{}, nil
+				}
+			},
+			body:       []byte(`{"filename": "hello.txt", "data": "!!!"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "illegal base64 data at input byte 0"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get chat search",
+			path:   "/v1/ob/chatsearch?q=blue&peerID=abc&orderID=xyz&limit=5&offset=10",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.searchChatMessagesFunc = func(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error) {
+					if query.Query != "blue" || query.PeerID != "abc" || query.OrderID != "xyz" || query.Limit != 5 || query.Offset != 10 {
+						return nil, errors.New("invalid query")
+					}
+					return []models.ChatSearchResult{
+						{
+							ChatMessage: models.ChatMessage{MessageID: "1", Message: "the blue one"},
+							Snippet:     "the <mark>blue</mark> one",
+						},
+					}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				response := []models.ChatSearchResult{
+					{
+						ChatMessage: models.ChatMessage{MessageID: "1", Message: "the blue one"},
+						Snippet:     "the <mark>blue</mark> one",
+					},
+				}
+				return marshalAndSanitizeJSON(&response)
+			},
+		},
+		{
+			name:   "Get chat search nil",
+			path:   "/v1/ob/chatsearch?q=blue",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.searchChatMessagesFunc = func(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(`[]`), nil
+			},
+		},
+		{
+			name:   "Get chat search invalid offset",
+			path:   "/v1/ob/chatsearch?q=blue&offset=a",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.searchChatMessagesFunc = func(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "strconv.Atoi: parsing "a": invalid syntax"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get chat search empty query",
+			path:   "/v1/ob/chatsearch",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.searchChatMessagesFunc = func(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error) {
+					return nil, coreiface.ErrBadRequest
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post chat attachment bad request",
+			path:   "/v1/ob/chatattachment",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.addChatAttachmentFunc = func(filename string, data []byte) (*models.ChatAttachment, error) {
+					return nil, coreiface.ErrBadRequest
+				}
+			},
+			body:       []byte(`{"filename": "hello.txt", "data": "aGVsbG8="}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post chat attachment too large",
+			path:   "/v1/ob/chatattachment",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.addChatAttachmentFunc = func(filename string, data []byte) (*models.ChatAttachment, error) {
+					return &models.ChatAttachment{}, nil
+				}
+			},
+			body:       []byte(`{"filename": "hello.txt", "data": "` + strings.Repeat("A", maxChatAttachmentRequestSize) + `"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Get chat attachment",
+			path:   "/v1/ob/chatattachment/QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getChatAttachmentFunc = func(ctx context.Context, id cid.Cid) (*models.ChatAttachment, io.ReadSeeker, error) {
+					return &models.ChatAttachment{CID: id.String(), Filename: "hello.txt"}, bytes.NewReader([]byte("hello")), nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return []byte("hello"), nil
+			},
+		},
+		{
+			name:   "Get chat attachment invalid cid",
+			path:   "/v1/ob/chatattachment/xxx",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getChatAttachmentFunc = func(ctx context.Context, id cid.Cid) (*models.ChatAttachment, io.ReadSeeker, error) {
+					return &models.ChatAttachment{}, bytes.NewReader(nil), nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "invalid attachment id: selected encoding not supported"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get chat attachment not found",
+			path:   "/v1/ob/chatattachment/QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getChatAttachmentFunc = func(ctx context.Context, id cid.Cid) (*models.ChatAttachment, io.ReadSeeker, error) {
+					return nil, nil, coreiface.ErrNotFound
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found"}%s`, "\n")), nil
+			},
+		},
 	})
 }
@@ -117,11 +117,21 @@ func (g *Gateway) newV1Router() *mux.Router {
 		r.HandleFunc("/v1/wallet/balance", g.handleGETBalance).Methods("GET")
 		r.HandleFunc("/v1/wallet/balance/{coinType}", g.handleGETBalance).Methods("GET")
 		r.HandleFunc("/v1/wallet/transactions/{coinType}", g.handleGETTransactions).Methods("GET")
+		r.HandleFunc("/v1/wallet/history", g.handleGETWalletHistory).Methods("GET")
 		r.HandleFunc("/v1/wallet/spend", g.handlePOSTSpend).Methods("POST")
+		r.HandleFunc("/v1/wallet/utxos/{coinType}", g.handleGETUtxos).Methods("GET")
+		r.HandleFunc("/v1/wallet/bumpfee", g.handlePOSTBumpFee).Methods("POST")
 		r.HandleFunc("/v1/ob/profile", g.handlePOSTProfile).Methods("POST")
 		r.HandleFunc("/v1/ob/profile", g.handlePUTProfile).Methods("PUT")
 		r.HandleFunc("/v1/ob/follow/{peerID}", g.handlePOSTFollow).Methods("POST")
 		r.HandleFunc("/v1/ob/unfollow/{peerID}", g.handlePOSTUnFollow).Methods("POST")
+		r.HandleFunc("/v1/ob/block/{peerID}", g.handlePOSTBlockNode).Methods("POST")
+		r.HandleFunc("/v1/ob/block/{peerID}", g.handleDELETEBlockNode).Methods("DELETE")
+		r.HandleFunc("/v1/ob/blocked", g.handleGETBlockedNodes).Methods("GET")
+		r.HandleFunc("/v1/ob/ratelimits", g.handleGETRateLimits).Methods("GET")
+		r.HandleFunc("/v1/ob/outbox", g.handleGETOutbox).Methods("GET")
+		r.HandleFunc("/v1/ob/outbox/{messageID}/retry", g.handlePOSTRetryOutgoingMessage).Methods("POST")
+		r.HandleFunc("/v1/ob/outbox/{messageID}", g.handleDELETEOutgoingMessage).Methods("DELETE")
 		r.HandleFunc("/v1/ob/chatmessage", g.handlePOSTSendChatMessage).Methods("POST")
 		r.HandleFunc("/v1/ob/groupchatmessage", g.handlePOSTSendGroupChatMessage).Methods("POST")
 		r.HandleFunc("/v1/ob/typingmessage", g.handlePOSTSendTypingMessage).Methods("POST")
@@ -133,6 +143,9 @@ func (g *Gateway) newV1Router() *mux.Router {
 		r.HandleFunc("/v1/ob/chatmessage/{messageID}", g.handleDELETEChatMessages).Methods("DELETE")
 		r.HandleFunc("/v1/ob/groupchatmessages/{orderID}", g.handleDELETEGroupChatMessages).Methods("DELETE")
 		r.HandleFunc("/v1/ob/chatconversation/{peerID}", g.handleDELETEChatConversation).Methods("DELETE")
+		r.HandleFunc("/v1/ob/chatattachment", g.handlePOSTChatAttachment).Methods("POST")
+		r.HandleFunc("/v1/ob/chatattachment/{cid}", g.handleGETChatAttachment).Methods("GET")
+		r.HandleFunc("/v1/ob/chatsearch", g.handleGETChatSearch).Methods("GET")
 		r.HandleFunc("/v1/ob/mylisting/{slugOrCID}", g.handleGETMyListing).Methods("GET")
 		r.HandleFunc("/v1/ob/listing", g.handlePOSTListing).Methods("POST")
 		r.HandleFunc("/v1/ob/listing", g.handlePUTListing).Methods("PUT")
@@ -149,6 +162,41 @@ func (g *Gateway) newV1Router() *mux.Router {
 		r.HandleFunc("/v1/ob/closechannel/{topic}", g.handlePOSTCloseChannel).Methods("POST")
 		r.HandleFunc("/v1/ob/channels", g.handleGETListChannels).Methods("GET")
 		r.HandleFunc("/v1/ob/channelmessages/{topic}", g.handleGETChannelMessages).Methods("GET")
+		r.HandleFunc("/v1/ob/channelpolicy/{topic}", g.handleGETChannelPolicy).Methods("GET")
+		r.HandleFunc("/v1/ob/channelpolicy/{topic}", g.handlePOSTPublishChannelPolicy).Methods("POST")
+		r.HandleFunc("/v1/ob/channelowner/{topic}", g.handlePOSTSetChannelOwner).Methods("POST")
+		r.HandleFunc("/v1/ob/channelratelimit/{topic}", g.handlePOSTSetChannelRateLimit).Methods("POST")
+		r.HandleFunc("/v1/ob/mutechannelpeer/{topic}/{peerID}", g.handlePOSTMuteChannelPeer).Methods("POST")
+		r.HandleFunc("/v1/ob/unmutechannelpeer/{topic}/{peerID}", g.handlePOSTUnmuteChannelPeer).Methods("POST")
+		r.HandleFunc("/v1/ob/deletechannelmessages/{topic}", g.handlePOSTDeleteChannelMessages).Methods("POST")
+		r.HandleFunc("/v1/ob/privatechannel", g.handlePOSTCreatePrivateChannel).Methods("POST")
+		r.HandleFunc("/v1/ob/privatechannel/{topic}", g.handleGETPrivateChannel).Methods("GET")
+		r.HandleFunc("/v1/ob/privatechannelmembers/{topic}", g.handlePOSTUpdatePrivateChannelMembers).Methods("POST")
+		r.HandleFunc("/v1/ob/purchase", g.handlePOSTPurchase).Methods("POST")
+		r.HandleFunc("/v1/ob/estimatetotal", g.handlePOSTEstimateTotal).Methods("POST")
+		r.HandleFunc("/v1/ob/orderconfirmation", g.handlePOSTConfirmOrder).Methods("POST")
+		r.HandleFunc("/v1/ob/orderrejection", g.handlePOSTRejectOrder).Methods("POST")
+		r.HandleFunc("/v1/ob/orderfulfillment", g.handlePOSTFulfillOrder).Methods("POST")
+		r.HandleFunc("/v1/ob/ordercancel", g.handlePOSTCancelOrder).Methods("POST")
+		r.HandleFunc("/v1/ob/refund", g.handlePOSTRefundOrder).Methods("POST")
+		r.HandleFunc("/v1/ob/ordercompletion", g.handlePOSTCompleteOrder).Methods("POST")
+		r.HandleFunc("/v1/ob/opendispute", g.handlePOSTOpenDispute).Methods("POST")
+		r.HandleFunc("/v1/ob/order/{orderID}", g.handleGETOrder).Methods("GET")
+		r.HandleFunc("/v1/ob/orderfiatvalues/{orderID}", g.handleGETOrderFiatValues).Methods("GET")
+		r.HandleFunc("/v1/ob/orders", g.handleGETOrders).Methods("GET")
+		r.HandleFunc("/v1/ob/sales", g.handleGETSales).Methods("GET")
+		r.HandleFunc("/v1/ob/purchases", g.handleGETPurchases).Methods("GET")
+		r.HandleFunc("/v1/ob/exchangeratehistory/{base}/{currency}", g.handleGETExchangeRateHistory).Methods("GET")
+		r.HandleFunc("/v1/ob/ledger", g.handleGETLedger).Methods("GET")
+		r.HandleFunc("/v1/ob/backup", g.handlePOSTBackup).Methods("POST")
+		r.HandleFunc("/v1/ob/restore", g.handlePOSTRestore).Methods("POST")
+		r.HandleFunc("/v1/ob/case/{caseID}", g.handleGETCase).Methods("GET")
+		r.HandleFunc("/v1/ob/cases", g.handleGETCases).Methods("GET")
+		r.HandleFunc("/v1/ob/closedispute", g.handlePOSTCloseDispute).Methods("POST")
+		r.HandleFunc("/v1/ob/moderator", g.handlePOSTModerator).Methods("POST")
+		r.HandleFunc("/v1/ob/moderator", g.handleDELETEModerator).Methods("DELETE")
+		r.HandleFunc("/v1/ob/storemoderators", g.handlePUTStoreModerators).Methods("PUT")
+		r.HandleFunc("/v1/ob/moderators", g.handleGETModerators).Methods("GET")
 	}
 	r.HandleFunc("/v1/ob/image/{imageID}", g.handleGETImage).Methods("GET")
 	r.HandleFunc("/v1/ob/avatar/{peerID}/{size}", g.handleGETAvatar).Methods("GET")
@@ -157,6 +205,8 @@ func (g *Gateway) newV1Router() *mux.Router {
 	r.HandleFunc("/v1/ob/listing/{peerID}/{slug}", g.handleGETListing).Methods("GET")
 	r.HandleFunc("/v1/ob/listingindex/{peerID}", g.handleGETListingIndex).Methods("GET")
 	r.HandleFunc("/v1/ob/listingindex", g.handleGETListingIndex).Methods("GET")
+	r.HandleFunc("/v1/ob/inventory/{peerID}", g.handleGETInventory).Methods("GET")
+	r.HandleFunc("/v1/ob/inventory", g.handleGETInventory).Methods("GET")
 	r.HandleFunc("/v1/ob/profile/{peerID}", g.handleGETProfile).Methods("GET")
 	r.HandleFunc("/v1/ob/profile", g.handleGETProfile).Methods("GET")
 	r.HandleFunc("/v1/ob/fetchprofiles", g.handlePOSTFetchProfiles).Methods("POST")
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/accounting"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ledgerWriter sets the download headers on the first write so that errors
+// returned before the ledger is written can still be served as JSON.
+type ledgerWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	wroteHeader bool
+}
+
+func (lw *ledgerWriter) Write(p []byte) (int, error) {
+	if !lw.wroteHeader {
+		lw.w.Header().Set("Content-Type", lw.contentType)
+		lw.w.Header().Set("X-Content-Type-Options", "nosniff")
+		lw.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": lw.filename}))
+		lw.wroteHeader = true
+	}
+	return lw.w.Write(p)
+}
+
+func (g *Gateway) handleGETLedger(w http.ResponseWriter, r *http.Request) {
+	query, format, err := parseLedgerQuery(r)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	contentType, err := accounting.ContentType(format)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	lw := &ledgerWriter{
+		w:           w,
+		contentType: contentType,
+		filename:    "ledger." + format,
+	}
+	err = g.node.ExportLedger(query, format, lw)
+	if err != nil && lw.wroteHeader {
+		// The download has already started so all we can do is log.
+		log.Errorf("Error streaming ledger: %s", err)
+		return
+	}
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseLedgerQuery builds a LedgerQuery and the export format from the URL
+// query parameters. Dates must be in RFC3339 format and the format defaults
+// to csv.
+func parseLedgerQuery(r *http.Request) (*models.LedgerQuery, string, error) {
+	var (
+		params = r.URL.Query()
+		query  = &models.LedgerQuery{
+			Role: models.OrderRole(strings.ToLower(params.Get("role"))),
+		}
+		format = strings.ToLower(params.Get("format"))
+		err    error
+	)
+	if format == "" {
+		format = accounting.FormatCSV
+	}
+	if fromStr := params.Get("from"); fromStr != "" {
+		query.From, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid from parameter: %s", err)
+		}
+	}
+	if toStr := params.Get("to"); toStr != "" {
+		query.To, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid to parameter: %s", err)
+		}
+	}
+	return query, format, nil
+}
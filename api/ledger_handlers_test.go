@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLedgerHandlers(t *testing.T) {
+	runAPITests(t, apiTests{
+		{
+			name:   "Get ledger",
+			path:   "/v1/ob/ledger?format=json&role=vendor&from=2020-06-01T00:00:00Z&to=2020-07-01T00:00:00Z",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.exportLedgerFunc = func(query *models.LedgerQuery, format string, w io.Writer) error {
+					if format != "json" || query.Role != models.RoleVendor ||
+						!query.From.Equal(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)) ||
+						!query.To.Equal(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)) {
+						return errors.New("incorrect query")
+					}
+					_, err := io.WriteString(w, `[]`)
+					return err
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(`[]`), nil
+			},
+		},
+		{
+			name:   "Get ledger default csv",
+			path:   "/v1/ob/ledger",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.exportLedgerFunc = func(query *models.LedgerQuery, format string, w io.Writer) error {
+					if format != "csv" {
+						return errors.New("incorrect format")
+					}
+					_, err := io.WriteString(w, "timestamp\n")
+					return err
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return []byte("timestamp\n"), nil
+			},
+		},
+		{
+			name:   "Get ledger invalid format",
+			path:   "/v1/ob/ledger?format=xml",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.exportLedgerFunc = func(query *models.LedgerQuery, format string, w io.Writer) error {
+					return nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Get ledger invalid date",
+			path:   "/v1/ob/ledger?from=yesterday",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.exportLedgerFunc = func(query *models.LedgerQuery, format string, w io.Writer) error {
+					return nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Get ledger invalid role",
+			path:   "/v1/ob/ledger?role=shopper",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.exportLedgerFunc = func(query *models.LedgerQuery, format string, w io.Writer) error {
+					return fmt.Errorf("%w: unknown ledger role: shopper", coreiface.ErrBadRequest)
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: unknown ledger role: shopper"}%s`, "\n")), nil
+			},
+		},
+	})
+}
@@ -112,6 +112,48 @@ func (g *Gateway) handleGETListingIndex(w http.ResponseWriter, r *http.Request)
 	sanitizedJSONResponse(w, index)
 }
 
+func (g *Gateway) handleGETInventory(w http.ResponseWriter, r *http.Request) {
+	peerIDStr := mux.Vars(r)["peerID"]
+	var (
+		inventory models.Inventory
+		err       error
+	)
+	if peerIDStr == "" || peerIDStr == g.node.Identity().Pretty() {
+		inventory, err = g.node.GetMyInventory()
+	} else {
+		pid, perr := peer.Decode(peerIDStr)
+		if perr != nil {
+			http.Error(w, wrapError(fmt.Errorf("invalid peer id: %s", perr.Error())), http.StatusBadRequest)
+			return
+		}
+		useCache, _ := strconv.ParseBool(r.URL.Query().Get("usecache"))
+		inventory, err = g.node.GetInventory(r.Context(), pid, useCache)
+	}
+
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	if slug := r.URL.Query().Get("slug"); slug != "" {
+		filtered := models.Inventory{}
+		for _, item := range inventory {
+			if item.Slug == slug {
+				filtered = append(filtered, item)
+			}
+		}
+		inventory = filtered
+	}
+	if inventory == nil {
+		inventory = models.Inventory{}
+	}
+
+	sanitizedJSONResponse(w, inventory)
+}
+
 func (g *Gateway) handlePOSTListing(w http.ResponseWriter, r *http.Request) {
 	listing := new(pb.Listing)
 
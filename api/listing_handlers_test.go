@@ -15,6 +15,53 @@ import (
 
 func TestListingHandlers(t *testing.T) {
 	runAPITests(t, apiTests{
+		{
+			name:   "Get my inventory",
+			path:   "/v1/ob/inventory?slug=t-shirt",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getMyInventoryFunc = func() (models.Inventory, error) {
+					return models.Inventory{
+						{Slug: "t-shirt", Variants: "Size:Large", Quantity: "5"},
+						{Slug: "hat", Quantity: models.UnlimitedInventory},
+					}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(models.Inventory{
+					{Slug: "t-shirt", Variants: "Size:Large", Quantity: "5"},
+				})
+			},
+		},
+		{
+			name:   "Get inventory",
+			path:   "/v1/ob/inventory/12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi?usecache=true",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getInventoryFunc = func(ctx context.Context, pid peer.ID, useCache bool) (models.Inventory, error) {
+					if pid.Pretty() != "12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi" || !useCache {
+						return nil, errors.New("not found")
+					}
+					return models.Inventory{{Slug: "t-shirt", Quantity: "0"}}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(models.Inventory{{Slug: "t-shirt", Quantity: "0"}})
+			},
+		},
+		{
+			name:   "Get inventory invalid peer ID",
+			path:   "/v1/ob/inventory/abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
 		{
 			name:   "Get my listing index",
 			path:   "/v1/ob/listingindex",
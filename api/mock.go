@@ -12,76 +12,117 @@ import (
 	"github.com/ipfs/go-ipfs/core"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	"io"
+	"time"
 )
 
 type mockNode struct {
-	requestAddressFunc           func(ctx context.Context, to peer.ID, coinType iwallet.CoinType) (iwallet.Address, error)
-	sendChatMessageFunc          func(to peer.ID, message string, orderID models.OrderID, done chan<- struct{}) error
-	sendTypingMessageFunc        func(to peer.ID, orderID models.OrderID) error
-	markChatMessagesAsReadFunc   func(peer peer.ID, orderID models.OrderID) error
-	getChatConversationsFunc     func() ([]models.ChatConversation, error)
-	getChatMessagesByPeerFunc    func(peer peer.ID, limit int, offsetID string) ([]models.ChatMessage, error)
-	getChatMessagesByOrderIDFunc func(orderID models.OrderID, limit int, offsetID string) ([]models.ChatMessage, error)
-	deleteChatMessageFunc        func(messageID string) error
-	deleteChatConversationFunc   func(peerID peer.ID) error
-	deleteGroupChatMessagesFunc  func(orderID models.OrderID) error
-	confirmOrderFunc             func(orderID models.OrderID, done chan struct{}) error
-	fulfillOrderFunc             func(orderID models.OrderID, fulfillments []models.Fulfillment, done chan struct{}) error
-	cancelOrderFunc              func(orderID models.OrderID, done chan struct{}) error
-	followNodeFunc               func(peerID peer.ID, done chan<- struct{}) error
-	unfollowNodeFunc             func(peerID peer.ID, done chan<- struct{}) error
-	getMyFollowersFunc           func() (models.Followers, error)
-	getMyFollowingFunc           func() (models.Following, error)
-	getFollowersFunc             func(ctx context.Context, peerID peer.ID, useCache bool) (models.Followers, error)
-	getFollowingFunc             func(ctx context.Context, peerID peer.ID, useCache bool) (models.Following, error)
-	saveListingFunc              func(listing *pb.Listing, done chan<- struct{}) error
-	updateAllListingsFunc        func(updateFunc func(l *pb.Listing) (bool, error), done chan<- struct{}) error
-	deleteListingFunc            func(slug string, done chan<- struct{}) error
-	getMyListingsFunc            func() (models.ListingIndex, error)
-	getListingsFunc              func(ctx context.Context, peerID peer.ID, useCache bool) (models.ListingIndex, error)
-	getMyListingBySlugFunc       func(slug string) (*pb.SignedListing, error)
-	getMyListingByCIDFunc        func(cid cid.Cid) (*pb.SignedListing, error)
-	getListingBySlugFunc         func(ctx context.Context, peerID peer.ID, slug string, useCache bool) (*pb.SignedListing, error)
-	getListingByCIDFunc          func(ctx context.Context, cid cid.Cid) (*pb.SignedListing, error)
-	getImageFunc                 func(ctx context.Context, cid cid.Cid) (io.ReadSeeker, error)
-	getAvatarFunc                func(ctx context.Context, peerID peer.ID, size models.ImageSize, useCache bool) (io.ReadSeeker, error)
-	getHeaderFunc                func(ctx context.Context, peerID peer.ID, size models.ImageSize, useCache bool) (io.ReadSeeker, error)
-	setAvatarImageFunc           func(base64ImageData string, done chan struct{}) (models.ImageHashes, error)
-	setHeaderImageFunc           func(base64ImageData string, done chan struct{}) (models.ImageHashes, error)
-	setProductImageFunc          func(base64ImageData string, filename string) (models.ImageHashes, error)
-	setSelfAsModeratorFunc       func(ctx context.Context, modInfo *models.ModeratorInfo, done chan struct{}) error
-	setModeratorsOnListingsFunc  func(mods []peer.ID, done chan struct{}) error
-	removeSelfAsModeratorFunc    func(ctx context.Context, done chan<- struct{}) error
-	getModeratorsFunc            func(ctx context.Context) []peer.ID
-	getModeratorsAsyncFunc       func(ctx context.Context) <-chan peer.ID
-	openChannel                  func(topic string) error
-	closeChannel                 func(topic string) error
-	listChannels                 func() []string
-	publishChannelMessage        func(ctx context.Context, topic, message string) error
-	getChannelMessages           func(ctx context.Context, topic string, from *cid.Cid, limit int) ([]models.ChannelMessage, error)
-	publishFunc                  func(done chan<- struct{})
-	usingTestnetFunc             func() bool
-	usingTorFunc                 func() bool
-	ipfsNodeFunc                 func() *core.IpfsNode
-	multiwalletFunc              func() multiwallet.Multiwallet
-	identityFunc                 func() peer.ID
-	subscribeEventFunc           func(event interface{}) (events.Subscription, error)
-	setProfileFunc               func(profile *models.Profile, done chan<- struct{}) error
-	getMyProfileFunc             func() (*models.Profile, error)
-	getProfileFunc               func(ctx context.Context, peerID peer.ID, useCache bool) (*models.Profile, error)
-	getMyRatingsFunc             func() (models.RatingIndex, error)
-	getRatingsFunc               func(ctx context.Context, peerID peer.ID, useCache bool) (models.RatingIndex, error)
-	getRatingFunc                func(ctx context.Context, cid cid.Cid) (*pb.Rating, error)
-	purchaseFunc                 func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error)
-	estimateOrderTotalFunc       func(ctx context.Context, purchase *models.Purchase) (models.OrderTotals, error)
-	rejectOrderFunc              func(orderID models.OrderID, reason string, done chan struct{}) error
-	refundOrderFunc              func(orderID models.OrderID, done chan struct{}) error
-	pingNodeFunc                 func(ctx context.Context, peer peer.ID) error
-	getUserPreferencesFunc       func() (*models.UserPreferences, error)
-	saveUserPreferencesFunc      func(prefs *models.UserPreferences, done chan struct{}) error
-	saveTransactionMetadataFunc  func(metadata *models.TransactionMetadata) error
-	getTransactionMetadataFunc   func(txid iwallet.TransactionID) (models.TransactionMetadata, error)
-	getExchangeRatesFunc         func() *wallet.ExchangeRateProvider
+	requestAddressFunc                 func(ctx context.Context, to peer.ID, coinType iwallet.CoinType) (iwallet.Address, error)
+	sendChatMessageFunc                func(to peer.ID, message string, orderID models.OrderID, done chan<- struct{}) error
+	sendChatMessageWithAttachmentsFunc func(to peer.ID, message string, orderID models.OrderID, attachments []cid.Cid, done chan<- struct{}) error
+	addChatAttachmentFunc              func(filename string, data []byte) (*models.ChatAttachment, error)
+	getChatAttachmentFunc              func(ctx context.Context, id cid.Cid) (*models.ChatAttachment, io.ReadSeeker, error)
+	sendTypingMessageFunc              func(to peer.ID, orderID models.OrderID) error
+	markChatMessagesAsReadFunc         func(peer peer.ID, orderID models.OrderID) error
+	getChatConversationsFunc           func() ([]models.ChatConversation, error)
+	getChatMessagesByPeerFunc          func(peer peer.ID, limit int, offsetID string) ([]models.ChatMessage, error)
+	getChatMessagesByOrderIDFunc       func(orderID models.OrderID, limit int, offsetID string) ([]models.ChatMessage, error)
+	deleteChatMessageFunc              func(messageID string) error
+	deleteChatConversationFunc         func(peerID peer.ID) error
+	deleteGroupChatMessagesFunc        func(orderID models.OrderID) error
+	searchChatMessagesFunc             func(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error)
+	confirmOrderFunc                   func(orderID models.OrderID, done chan struct{}) error
+	fulfillOrderFunc                   func(orderID models.OrderID, fulfillments []models.Fulfillment, done chan struct{}) error
+	cancelOrderFunc                    func(orderID models.OrderID, done chan struct{}) error
+	completeOrderFunc                  func(orderID models.OrderID, ratings []models.Rating, includeIDInRating bool, done chan struct{}) error
+	openDisputeFunc                    func(orderID models.OrderID, reason string, done chan struct{}) error
+	closeDisputeFunc                   func(disputeCase *models.Case, verdict string, buyerPercentage, vendorPercentage float32, done chan struct{}) error
+	acceptDisputePayoutFunc            func(orderID models.OrderID, done chan struct{}) error
+	releaseEscrowAfterTimeoutFunc      func(orderID models.OrderID, done chan struct{}) error
+	getOrderFunc                       func(orderID models.OrderID) (*models.Order, error)
+	getOrderFiatValuesFunc             func(orderID models.OrderID) ([]models.FiatValue, error)
+	getOrdersFunc                      func(query *models.OrderQuery) ([]models.OrderSummary, error)
+	getSalesFunc                       func(query *models.OrderQuery) ([]models.OrderSummary, error)
+	getPurchasesFunc                   func(query *models.OrderQuery) ([]models.OrderSummary, error)
+	getCasesFunc                       func(query *models.CaseQuery) ([]models.CaseSummary, error)
+	getCaseFunc                        func(caseID models.OrderID) (*models.CaseView, error)
+	exportLedgerFunc                   func(query *models.LedgerQuery, format string, w io.Writer) error
+	getMyInventoryFunc                 func() (models.Inventory, error)
+	getInventoryFunc                   func(ctx context.Context, peerID peer.ID, useCache bool) (models.Inventory, error)
+	blockNodeFunc                      func(peerID peer.ID) error
+	unblockNodeFunc                    func(peerID peer.ID) error
+	backupRepoFunc                     func(passphrase string, w io.Writer) error
+	restoreRepoFunc                    func(dataDir, passphrase string, backup io.Reader) error
+	getOutgoingMessagesFunc            func() ([]models.OutboxMessage, error)
+	retryOutgoingMessageFunc           func(messageID string) error
+	cancelOutgoingMessageFunc          func(messageID string) error
+	getBlockedNodesFunc                func() ([]peer.ID, error)
+	followNodeFunc                     func(peerID peer.ID, done chan<- struct{}) error
+	unfollowNodeFunc                   func(peerID peer.ID, done chan<- struct{}) error
+	getMyFollowersFunc                 func() (models.Followers, error)
+	getMyFollowingFunc                 func() (models.Following, error)
+	getFollowersFunc                   func(ctx context.Context, peerID peer.ID, useCache bool) (models.Followers, error)
+	getFollowingFunc                   func(ctx context.Context, peerID peer.ID, useCache bool) (models.Following, error)
+	saveListingFunc                    func(listing *pb.Listing, done chan<- struct{}) error
+	updateAllListingsFunc              func(updateFunc func(l *pb.Listing) (bool, error), done chan<- struct{}) error
+	deleteListingFunc                  func(slug string, done chan<- struct{}) error
+	getMyListingsFunc                  func() (models.ListingIndex, error)
+	getListingsFunc                    func(ctx context.Context, peerID peer.ID, useCache bool) (models.ListingIndex, error)
+	getMyListingBySlugFunc             func(slug string) (*pb.SignedListing, error)
+	getMyListingByCIDFunc              func(cid cid.Cid) (*pb.SignedListing, error)
+	getListingBySlugFunc               func(ctx context.Context, peerID peer.ID, slug string, useCache bool) (*pb.SignedListing, error)
+	getListingByCIDFunc                func(ctx context.Context, cid cid.Cid) (*pb.SignedListing, error)
+	getImageFunc                       func(ctx context.Context, cid cid.Cid) (io.ReadSeeker, error)
+	getAvatarFunc                      func(ctx context.Context, peerID peer.ID, size models.ImageSize, useCache bool) (io.ReadSeeker, error)
+	getHeaderFunc                      func(ctx context.Context, peerID peer.ID, size models.ImageSize, useCache bool) (io.ReadSeeker, error)
+	setAvatarImageFunc                 func(base64ImageData string, done chan struct{}) (models.ImageHashes, error)
+	setHeaderImageFunc                 func(base64ImageData string, done chan struct{}) (models.ImageHashes, error)
+	setProductImageFunc                func(base64ImageData string, filename string) (models.ImageHashes, error)
+	setSelfAsModeratorFunc             func(ctx context.Context, modInfo *models.ModeratorInfo, done chan struct{}) error
+	setModeratorsOnListingsFunc        func(mods []peer.ID, done chan struct{}) error
+	removeSelfAsModeratorFunc          func(ctx context.Context, done chan<- struct{}) error
+	getModeratorsFunc                  func(ctx context.Context) []peer.ID
+	getModeratorsAsyncFunc             func(ctx context.Context) <-chan peer.ID
+	openChannel                        func(topic string) error
+	closeChannel                       func(topic string) error
+	listChannels                       func() []string
+	publishChannelMessage              func(ctx context.Context, topic string, content models.ChannelMessageContent) error
+	getChannelMessages                 func(ctx context.Context, topic string, from *cid.Cid, limit int) ([]models.ChannelMessage, error)
+	getChannelPolicy                   func(topic string) (*models.ChannelPolicy, error)
+	setChannelOwner                    func(topic string, owner peer.ID) error
+	setChannelRateLimit                func(topic string, messagesPerMinute int) error
+	muteChannelPeer                    func(topic string, peerID peer.ID) error
+	unmuteChannelPeer                  func(topic string, peerID peer.ID) error
+	publishChannelPolicy               func(ctx context.Context, topic string, policy models.ChannelOwnerPolicy) error
+	deleteChannelMessages              func(ctx context.Context, topic string, ids []cid.Cid) error
+	createPrivateChannel               func(topic string, members []peer.ID) error
+	updatePrivateChannelMembers        func(topic string, add, remove []peer.ID) error
+	getPrivateChannel                  func(topic string) (*models.PrivateChannel, error)
+	publishFunc                        func(done chan<- struct{})
+	usingTestnetFunc                   func() bool
+	usingTorFunc                       func() bool
+	ipfsNodeFunc                       func() *core.IpfsNode
+	multiwalletFunc                    func() multiwallet.Multiwallet
+	identityFunc                       func() peer.ID
+	subscribeEventFunc                 func(event interface{}) (events.Subscription, error)
+	setProfileFunc                     func(profile *models.Profile, done chan<- struct{}) error
+	getMyProfileFunc                   func() (*models.Profile, error)
+	getProfileFunc                     func(ctx context.Context, peerID peer.ID, useCache bool) (*models.Profile, error)
+	getMyRatingsFunc                   func() (models.RatingIndex, error)
+	getRatingsFunc                     func(ctx context.Context, peerID peer.ID, useCache bool) (models.RatingIndex, error)
+	getRatingFunc                      func(ctx context.Context, cid cid.Cid) (*pb.Rating, error)
+	purchaseFunc                       func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error)
+	estimateOrderTotalFunc             func(ctx context.Context, purchase *models.Purchase) (models.OrderTotals, error)
+	rejectOrderFunc                    func(orderID models.OrderID, reason string, done chan struct{}) error
+	refundOrderFunc                    func(orderID models.OrderID, done chan struct{}) error
+	pingNodeFunc                       func(ctx context.Context, peer peer.ID) error
+	getRateLimitMetricsFunc            func() models.RateLimitMetrics
+	getUserPreferencesFunc             func() (*models.UserPreferences, error)
+	saveUserPreferencesFunc            func(prefs *models.UserPreferences, done chan struct{}) error
+	saveTransactionMetadataFunc        func(metadata *models.TransactionMetadata) error
+	getTransactionMetadataFunc         func(txid iwallet.TransactionID) (models.TransactionMetadata, error)
+	getWalletTransactionsFunc          func(query *models.TransactionQuery) ([]models.WalletTransaction, error)
+	getExchangeRatesFunc               func() *wallet.ExchangeRateProvider
+	getHistoricalRateFunc              func(base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error)
 }
 
 func (m *mockNode) RequestAddress(ctx context.Context, to peer.ID, coinType iwallet.CoinType) (iwallet.Address, error) {
@@ -90,6 +131,15 @@ func (m *mockNode) RequestAddress(ctx context.Context, to peer.ID, coinType iwal
 func (m *mockNode) SendChatMessage(to peer.ID, message string, orderID models.OrderID, done chan<- struct{}) error {
 	return m.sendChatMessageFunc(to, message, orderID, done)
 }
+func (m *mockNode) SendChatMessageWithAttachments(to peer.ID, message string, orderID models.OrderID, attachments []cid.Cid, done chan<- struct{}) error {
+	return m.sendChatMessageWithAttachmentsFunc(to, message, orderID, attachments, done)
+}
+func (m *mockNode) AddChatAttachment(filename string, data []byte) (*models.ChatAttachment, error) {
+	return m.addChatAttachmentFunc(filename, data)
+}
+func (m *mockNode) GetChatAttachment(ctx context.Context, id cid.Cid) (*models.ChatAttachment, io.ReadSeeker, error) {
+	return m.getChatAttachmentFunc(ctx, id)
+}
 func (m *mockNode) SendTypingMessage(to peer.ID, orderID models.OrderID) error {
 	return m.sendTypingMessageFunc(to, orderID)
 }
@@ -114,6 +164,9 @@ func (m *mockNode) DeleteChatConversation(peerID peer.ID) error {
 func (m *mockNode) DeleteGroupChatMessages(orderID models.OrderID) error {
 	return m.deleteGroupChatMessagesFunc(orderID)
 }
+func (m *mockNode) SearchChatMessages(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error) {
+	return m.searchChatMessagesFunc(query)
+}
 func (m *mockNode) ConfirmOrder(orderID models.OrderID, done chan struct{}) error {
 	return m.confirmOrderFunc(orderID, done)
 }
@@ -123,6 +176,75 @@ func (m *mockNode) FulfillOrder(orderID models.OrderID, fulfillments []models.Fu
 func (m *mockNode) CancelOrder(orderID models.OrderID, done chan struct{}) error {
 	return m.cancelOrderFunc(orderID, done)
 }
+func (m *mockNode) CompleteOrder(orderID models.OrderID, ratings []models.Rating, includeIDInRating bool, done chan struct{}) error {
+	return m.completeOrderFunc(orderID, ratings, includeIDInRating, done)
+}
+func (m *mockNode) OpenDispute(orderID models.OrderID, reason string, done chan struct{}) error {
+	return m.openDisputeFunc(orderID, reason, done)
+}
+func (m *mockNode) CloseDispute(disputeCase *models.Case, verdict string, buyerPercentage, vendorPercentage float32, done chan struct{}) error {
+	return m.closeDisputeFunc(disputeCase, verdict, buyerPercentage, vendorPercentage, done)
+}
+func (m *mockNode) AcceptDisputePayout(orderID models.OrderID, done chan struct{}) error {
+	return m.acceptDisputePayoutFunc(orderID, done)
+}
+func (m *mockNode) ReleaseEscrowAfterTimeout(orderID models.OrderID, done chan struct{}) error {
+	return m.releaseEscrowAfterTimeoutFunc(orderID, done)
+}
+func (m *mockNode) GetOrder(orderID models.OrderID) (*models.Order, error) {
+	return m.getOrderFunc(orderID)
+}
+func (m *mockNode) GetOrderFiatValues(orderID models.OrderID) ([]models.FiatValue, error) {
+	return m.getOrderFiatValuesFunc(orderID)
+}
+func (m *mockNode) GetOrders(query *models.OrderQuery) ([]models.OrderSummary, error) {
+	return m.getOrdersFunc(query)
+}
+func (m *mockNode) GetSales(query *models.OrderQuery) ([]models.OrderSummary, error) {
+	return m.getSalesFunc(query)
+}
+func (m *mockNode) GetPurchases(query *models.OrderQuery) ([]models.OrderSummary, error) {
+	return m.getPurchasesFunc(query)
+}
+func (m *mockNode) GetCases(query *models.CaseQuery) ([]models.CaseSummary, error) {
+	return m.getCasesFunc(query)
+}
+func (m *mockNode) GetCase(caseID models.OrderID) (*models.CaseView, error) {
+	return m.getCaseFunc(caseID)
+}
+func (m *mockNode) ExportLedger(query *models.LedgerQuery, format string, w io.Writer) error {
+	return m.exportLedgerFunc(query, format, w)
+}
+func (m *mockNode) GetMyInventory() (models.Inventory, error) {
+	return m.getMyInventoryFunc()
+}
+func (m *mockNode) GetInventory(ctx context.Context, peerID peer.ID, useCache bool) (models.Inventory, error) {
+	return m.getInventoryFunc(ctx, peerID, useCache)
+}
+func (m *mockNode) BlockNode(peerID peer.ID) error {
+	return m.blockNodeFunc(peerID)
+}
+func (m *mockNode) UnblockNode(peerID peer.ID) error {
+	return m.unblockNodeFunc(peerID)
+}
+func (m *mockNode) BackupRepo(passphrase string, w io.Writer) error {
+	return m.backupRepoFunc(passphrase, w)
+}
+func (m *mockNode) RestoreRepo(dataDir, passphrase string, backup io.Reader) error {
+	return m.restoreRepoFunc(dataDir, passphrase, backup)
+}
+func (m *mockNode) GetOutgoingMessages() ([]models.OutboxMessage, error) {
+	return m.getOutgoingMessagesFunc()
+}
+func (m *mockNode) RetryOutgoingMessage(messageID string) error {
+	return m.retryOutgoingMessageFunc(messageID)
+}
+func (m *mockNode) CancelOutgoingMessage(messageID string) error {
+	return m.cancelOutgoingMessageFunc(messageID)
+}
+func (m *mockNode) GetBlockedNodes() ([]peer.ID, error) {
+	return m.getBlockedNodesFunc()
+}
 func (m *mockNode) FollowNode(peerID peer.ID, done chan<- struct{}) error {
 	return m.followNodeFunc(peerID, done)
 }
@@ -261,21 +383,57 @@ func (m *mockNode) CloseChannel(topic string) error {
 func (m *mockNode) ListChannels() []string {
 	return m.listChannels()
 }
-func (m *mockNode) PublishChannelMessage(ctx context.Context, topic, message string) error {
-	return m.publishChannelMessage(ctx, topic, message)
+func (m *mockNode) PublishChannelMessage(ctx context.Context, topic string, content models.ChannelMessageContent) error {
+	return m.publishChannelMessage(ctx, topic, content)
 }
 func (m *mockNode) GetChannelMessages(ctx context.Context, topic string, from *cid.Cid, limit int) ([]models.ChannelMessage, error) {
 	return m.getChannelMessages(ctx, topic, from, limit)
 }
+func (m *mockNode) GetChannelPolicy(topic string) (*models.ChannelPolicy, error) {
+	return m.getChannelPolicy(topic)
+}
+func (m *mockNode) SetChannelOwner(topic string, owner peer.ID) error {
+	return m.setChannelOwner(topic, owner)
+}
+func (m *mockNode) SetChannelRateLimit(topic string, messagesPerMinute int) error {
+	return m.setChannelRateLimit(topic, messagesPerMinute)
+}
+func (m *mockNode) MuteChannelPeer(topic string, peerID peer.ID) error {
+	return m.muteChannelPeer(topic, peerID)
+}
+func (m *mockNode) UnmuteChannelPeer(topic string, peerID peer.ID) error {
+	return m.unmuteChannelPeer(topic, peerID)
+}
+func (m *mockNode) PublishChannelPolicy(ctx context.Context, topic string, policy models.ChannelOwnerPolicy) error {
+	return m.publishChannelPolicy(ctx, topic, policy)
+}
+func (m *mockNode) DeleteChannelMessages(ctx context.Context, topic string, ids []cid.Cid) error {
+	return m.deleteChannelMessages(ctx, topic, ids)
+}
+func (m *mockNode) CreatePrivateChannel(topic string, members []peer.ID) error {
+	return m.createPrivateChannel(topic, members)
+}
+func (m *mockNode) UpdatePrivateChannelMembers(topic string, add, remove []peer.ID) error {
+	return m.updatePrivateChannelMembers(topic, add, remove)
+}
+func (m *mockNode) GetPrivateChannel(topic string) (*models.PrivateChannel, error) {
+	return m.getPrivateChannel(topic)
+}
 func (m *mockNode) PingNode(ctx context.Context, peer peer.ID) error {
 	return m.pingNodeFunc(ctx, peer)
 }
+func (m *mockNode) GetRateLimitMetrics() models.RateLimitMetrics {
+	return m.getRateLimitMetricsFunc()
+}
 func (m *mockNode) SaveTransactionMetadata(metadata *models.TransactionMetadata) error {
 	return m.saveTransactionMetadataFunc(metadata)
 }
 func (m *mockNode) GetTransactionMetadata(txid iwallet.TransactionID) (models.TransactionMetadata, error) {
 	return m.getTransactionMetadataFunc(txid)
 }
+func (m *mockNode) GetWalletTransactions(query *models.TransactionQuery) ([]models.WalletTransaction, error) {
+	return m.getWalletTransactionsFunc(query)
+}
 func (m *mockNode) SavePreferences(prefs *models.UserPreferences, done chan struct{}) error {
 	return m.saveUserPreferencesFunc(prefs, done)
 }
@@ -285,3 +443,6 @@ func (m *mockNode) GetPreferences() (*models.UserPreferences, error) {
 func (m *mockNode) ExchangeRates() *wallet.ExchangeRateProvider {
 	return m.getExchangeRatesFunc()
 }
+func (m *mockNode) GetHistoricalRate(base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error) {
+	return m.getHistoricalRateFunc(base, currency, timestamp)
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// moderatorDiscoveryTimeout is the maximum amount of time an async moderator
+// query will keep searching the network after the request returns.
+const moderatorDiscoveryTimeout = time.Minute * 2
+
+type moderatorResponse struct {
+	ID      string          `json:"id,omitempty"`
+	PeerID  string          `json:"peerID"`
+	Profile *models.Profile `json:"profile"`
+}
+
+type moderatorError struct {
+	ID     string `json:"id"`
+	PeerID string `json:"peerID"`
+	Error  string `json:"error"`
+}
+
+func (g *Gateway) handlePOSTModerator(w http.ResponseWriter, r *http.Request) {
+	var modInfo models.ModeratorInfo
+	if err := json.NewDecoder(r.Body).Decode(&modInfo); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.SetSelfAsModerator(r.Context(), &modInfo, nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	sanitizedJSONResponse(w, struct{}{})
+}
+
+func (g *Gateway) handleDELETEModerator(w http.ResponseWriter, r *http.Request) {
+	if err := g.node.RemoveSelfAsModerator(r.Context(), nil); err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	sanitizedJSONResponse(w, struct{}{})
+}
+
+func (g *Gateway) handlePUTStoreModerators(w http.ResponseWriter, r *http.Request) {
+	var modStrs []string
+	if err := json.NewDecoder(r.Body).Decode(&modStrs); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	mods := make([]peer.ID, 0, len(modStrs))
+	for _, modStr := range modStrs {
+		mod, err := peer.Decode(modStr)
+		if err != nil {
+			http.Error(w, wrapError(err), http.StatusBadRequest)
+			return
+		}
+		mods = append(mods, mod)
+	}
+
+	err := g.node.SetModeratorsOnListings(mods, nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	sanitizedJSONResponse(w, struct{}{})
+}
+
+// handleGETModerators searches the network for moderators and resolves each
+// one to its profile. Peers whose profile does not contain moderator info are
+// skipped. The optional currency parameter restricts the results to moderators
+// that accept the given currency code.
+//
+// If async is set the request returns immediately with an ID and each
+// moderator is pushed over the websocket as it is found.
+func (g *Gateway) handleGETModerators(w http.ResponseWriter, r *http.Request) {
+	useCache, _ := strconv.ParseBool(r.URL.Query().Get("usecache"))
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	currency := strings.ToUpper(r.URL.Query().Get("currency"))
+
+	var (
+		ctx    = r.Context()
+		cancel context.CancelFunc
+	)
+	if async {
+		// The request context is canceled as soon as we respond so the
+		// search needs to run in a context of its own.
+		ctx, cancel = context.WithTimeout(context.Background(), moderatorDiscoveryTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	var (
+		responseChan = make(chan interface{}, 8)
+		wg           sync.WaitGroup
+	)
+
+	go func() {
+		defer cancel()
+		for pid := range g.node.GetModeratorsAsync(ctx) {
+			wg.Add(1)
+			go func(p peer.ID) {
+				defer wg.Done()
+				profile, err := g.node.GetProfile(ctx, p, useCache)
+				if err != nil {
+					responseChan <- moderatorError{
+						PeerID: p.Pretty(),
+						Error:  err.Error(),
+					}
+					return
+				}
+				if !profile.Moderator || profile.ModeratorInfo == nil || !acceptsCurrency(profile.ModeratorInfo, currency) {
+					return
+				}
+				responseChan <- moderatorResponse{
+					PeerID:  p.Pretty(),
+					Profile: profile,
+				}
+			}(pid)
+		}
+		wg.Wait()
+		close(responseChan)
+	}()
+
+	if !async {
+		moderators := make([]moderatorResponse, 0)
+		for i := range responseChan {
+			switch m := i.(type) {
+			case moderatorResponse:
+				moderators = append(moderators, m)
+			}
+		}
+		sanitizedJSONResponse(w, moderators)
+	} else {
+		asyncID := r.URL.Query().Get("asyncID")
+		if asyncID == "" {
+			r := make([]byte, 20)
+			rand.Read(r)
+			asyncID = hex.EncodeToString(r)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		sanitizedJSONResponse(w, struct {
+			ID string `json:"id"`
+		}{ID: asyncID})
+
+		go func() {
+			for i := range responseChan {
+				switch m := i.(type) {
+				case moderatorResponse:
+					m.ID = asyncID
+					g.NotifyWebsockets(m)
+				case moderatorError:
+					m.ID = asyncID
+					g.NotifyWebsockets(m)
+				}
+			}
+		}()
+	}
+}
+
+// acceptsCurrency returns whether the moderator accepts the currency code.
+// An empty currency code matches all moderators.
+func acceptsCurrency(modInfo *models.ModeratorInfo, currency string) bool {
+	if currency == "" {
+		return true
+	}
+	for _, cc := range modInfo.AcceptedCurrencies {
+		if strings.ToUpper(cc) == currency {
+			return true
+		}
+	}
+	return false
+}
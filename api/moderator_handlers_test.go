@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-testutil"
+	"net/http"
+	"testing"
+)
+
+func TestModeratorHandlers(t *testing.T) {
+	mod1, err := testutil.RandPeerID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod2, err := testutil.RandPeerID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notMod, err := testutil.RandPeerID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiles := map[peer.ID]*models.Profile{
+		mod1: {
+			Name:      "Ron Paul",
+			Moderator: true,
+			ModeratorInfo: &models.ModeratorInfo{
+				AcceptedCurrencies: []string{"BTC"},
+				Languages:          []string{"English"},
+				Fee: models.ModeratorFee{
+					FeeType:    models.PercentageFee,
+					Percentage: 10,
+				},
+			},
+		},
+		mod2: {
+			Name:      "Rand Paul",
+			Moderator: true,
+			ModeratorInfo: &models.ModeratorInfo{
+				AcceptedCurrencies: []string{"MCK"},
+			},
+		},
+		notMod: {
+			Name: "Not a moderator",
+		},
+	}
+
+	setModerators := func(n *mockNode) {
+		n.getModeratorsAsyncFunc = func(ctx context.Context) <-chan peer.ID {
+			ch := make(chan peer.ID, 3)
+			ch <- mod1
+			ch <- mod2
+			ch <- notMod
+			close(ch)
+			return ch
+		}
+		n.getProfileFunc = func(ctx context.Context, peerID peer.ID, useCache bool) (*models.Profile, error) {
+			return profiles[peerID], nil
+		}
+	}
+
+	runAPITests(t, apiTests{
+		{
+			name:   "Post moderator",
+			path:   "/v1/ob/moderator",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.setSelfAsModeratorFunc = func(ctx context.Context, modInfo *models.ModeratorInfo, done chan struct{}) error {
+					if modInfo.Fee.Percentage != 10 {
+						return errors.New("incorrect fee")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"fee": {"feeType": 1, "percentage": 10}}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(struct{}{})
+			},
+		},
+		{
+			name:   "Post moderator invalid JSON",
+			path:   "/v1/ob/moderator",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.setSelfAsModeratorFunc = func(ctx context.Context, modInfo *models.ModeratorInfo, done chan struct{}) error {
+					return nil
+				}
+			},
+			body:       []byte(`{`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post moderator bad request",
+			path:   "/v1/ob/moderator",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.setSelfAsModeratorFunc = func(ctx context.Context, modInfo *models.ModeratorInfo, done chan struct{}) error {
+					return fmt.Errorf("%w: fixed fee must be set when using a fixed fee type", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: fixed fee must be set when using a fixed fee type"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Delete moderator",
+			path:   "/v1/ob/moderator",
+			method: http.MethodDelete,
+			setNodeMethods: func(n *mockNode) {
+				n.removeSelfAsModeratorFunc = func(ctx context.Context, done chan<- struct{}) error {
+					return nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(struct{}{})
+			},
+		},
+		{
+			name:   "Delete moderator internal error",
+			path:   "/v1/ob/moderator",
+			method: http.MethodDelete,
+			setNodeMethods: func(n *mockNode) {
+				n.removeSelfAsModeratorFunc = func(ctx context.Context, done chan<- struct{}) error {
+					return errors.New("error")
+				}
+			},
+			statusCode: http.StatusInternalServerError,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "error"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Put store moderators",
+			path:   "/v1/ob/storemoderators",
+			method: http.MethodPut,
+			setNodeMethods: func(n *mockNode) {
+				n.setModeratorsOnListingsFunc = func(mods []peer.ID, done chan struct{}) error {
+					if len(mods) != 2 || mods[0] != mod1 || mods[1] != mod2 {
+						return errors.New("incorrect moderators")
+					}
+					return nil
+				}
+			},
+			body:       []byte(fmt.Sprintf(`["%s", "%s"]`, mod1.Pretty(), mod2.Pretty())),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(struct{}{})
+			},
+		},
+		{
+			name:   "Put store moderators invalid peer ID",
+			path:   "/v1/ob/storemoderators",
+			method: http.MethodPut,
+			setNodeMethods: func(n *mockNode) {
+				n.setModeratorsOnListingsFunc = func(mods []peer.ID, done chan struct{}) error {
+					return nil
+				}
+			},
+			body:       []byte(`["abc"]`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:           "Get moderators",
+			path:           "/v1/ob/moderators?currency=btc",
+			method:         http.MethodGet,
+			setNodeMethods: setModerators,
+			statusCode:     http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]moderatorResponse{
+					{
+						PeerID:  mod1.Pretty(),
+						Profile: profiles[mod1],
+					},
+				})
+			},
+		},
+		{
+			name:   "Get moderators none found",
+			path:   "/v1/ob/moderators",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getModeratorsAsyncFunc = func(ctx context.Context) <-chan peer.ID {
+					ch := make(chan peer.ID)
+					close(ch)
+					return ch
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]moderatorResponse{})
+			},
+		},
+		{
+			name:   "Get moderators async",
+			path:   "/v1/ob/moderators?async=true&asyncID=abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getModeratorsAsyncFunc = func(ctx context.Context) <-chan peer.ID {
+					ch := make(chan peer.ID)
+					close(ch)
+					return ch
+				}
+			},
+			statusCode: http.StatusAccepted,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(struct {
+					ID string `json:"id"`
+				}{ID: "abc"})
+			},
+		},
+	})
+}
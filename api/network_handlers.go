@@ -0,0 +1,14 @@
+package api
+
+import (
+	"github.com/cpacia/openbazaar3.0/models"
+	"net/http"
+)
+
+func (g *Gateway) handleGETRateLimits(w http.ResponseWriter, r *http.Request) {
+	metrics := g.node.GetRateLimitMetrics()
+	if metrics.Peers == nil {
+		metrics.Peers = []models.PeerRateLimitInfo{}
+	}
+	sanitizedJSONResponse(w, metrics)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"github.com/cpacia/openbazaar3.0/models"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNetworkHandlers(t *testing.T) {
+	bannedUntil := time.Unix(1600000000, 0).UTC()
+	runAPITests(t, apiTests{
+		{
+			name:   "Get rate limits",
+			path:   "/v1/ob/ratelimits",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getRateLimitMetricsFunc = func() models.RateLimitMetrics {
+					return models.RateLimitMetrics{
+						MessagesAllowed: 10,
+						MessagesDropped: 2,
+						TemporaryBans:   1,
+						Peers: []models.PeerRateLimitInfo{
+							{
+								PeerID:          "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+								Score:           12,
+								MessagesAllowed: 10,
+								MessagesDropped: 2,
+								BannedUntil:     &bannedUntil,
+							},
+						},
+					}
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(models.RateLimitMetrics{
+					MessagesAllowed: 10,
+					MessagesDropped: 2,
+					TemporaryBans:   1,
+					Peers: []models.PeerRateLimitInfo{
+						{
+							PeerID:          "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+							Score:           12,
+							MessagesAllowed: 10,
+							MessagesDropped: 2,
+							BannedUntil:     &bannedUntil,
+						},
+					},
+				})
+			},
+		},
+		{
+			name:   "Get rate limits no peers",
+			path:   "/v1/ob/ratelimits",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getRateLimitMetricsFunc = func() models.RateLimitMetrics {
+					return models.RateLimitMetrics{}
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(models.RateLimitMetrics{
+					Peers: []models.PeerRateLimitInfo{},
+				})
+			},
+		},
+	})
+}
@@ -0,0 +1,347 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type purchaseResponse struct {
+	OrderID        string                `json:"orderID"`
+	PaymentAddress string                `json:"paymentAddress"`
+	Amount         *models.CurrencyValue `json:"amount"`
+}
+
+func (g *Gateway) handlePOSTPurchase(w http.ResponseWriter, r *http.Request) {
+	var purchase models.Purchase
+	if err := json.NewDecoder(r.Body).Decode(&purchase); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	orderID, paymentAddress, paymentAmount, err := g.node.PurchaseListing(r.Context(), &purchase)
+	if errors.Is(err, coreiface.ErrBadRequest) || errors.Is(err, coreiface.ErrDustAmount) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	sanitizedJSONResponse(w, purchaseResponse{
+		OrderID:        orderID.String(),
+		PaymentAddress: paymentAddress.String(),
+		Amount:         &paymentAmount,
+	})
+}
+
+func (g *Gateway) handlePOSTEstimateTotal(w http.ResponseWriter, r *http.Request) {
+	var purchase models.Purchase
+	if err := json.NewDecoder(r.Body).Decode(&purchase); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	totals, err := g.node.EstimateOrderTotal(r.Context(), &purchase)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	sanitizedJSONResponse(w, totals)
+}
+
+func (g *Gateway) handlePOSTConfirmOrder(w http.ResponseWriter, r *http.Request) {
+	type confirm struct {
+		OrderID string `json:"orderID"`
+	}
+	var c confirm
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.ConfirmOrder(models.OrderID(c.OrderID), nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTRejectOrder(w http.ResponseWriter, r *http.Request) {
+	type reject struct {
+		OrderID string `json:"orderID"`
+		Reason  string `json:"reason"`
+	}
+	var rej reject
+	if err := json.NewDecoder(r.Body).Decode(&rej); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.RejectOrder(models.OrderID(rej.OrderID), rej.Reason, nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTFulfillOrder(w http.ResponseWriter, r *http.Request) {
+	type fulfill struct {
+		OrderID      string               `json:"orderID"`
+		Fulfillments []models.Fulfillment `json:"fulfillments"`
+	}
+	var f fulfill
+	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.FulfillOrder(models.OrderID(f.OrderID), f.Fulfillments, nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTCancelOrder(w http.ResponseWriter, r *http.Request) {
+	type cancel struct {
+		OrderID string `json:"orderID"`
+	}
+	var c cancel
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.CancelOrder(models.OrderID(c.OrderID), nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTRefundOrder(w http.ResponseWriter, r *http.Request) {
+	type refund struct {
+		OrderID string `json:"orderID"`
+	}
+	var ref refund
+	if err := json.NewDecoder(r.Body).Decode(&ref); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.RefundOrder(models.OrderID(ref.OrderID), nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTCompleteOrder(w http.ResponseWriter, r *http.Request) {
+	type complete struct {
+		OrderID           string          `json:"orderID"`
+		Ratings           []models.Rating `json:"ratings"`
+		IncludeIDInRating bool            `json:"includeIDInRating"`
+	}
+	var c complete
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.CompleteOrder(models.OrderID(c.OrderID), c.Ratings, c.IncludeIDInRating, nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handlePOSTOpenDispute(w http.ResponseWriter, r *http.Request) {
+	type dispute struct {
+		OrderID string `json:"orderID"`
+		Reason  string `json:"reason"`
+	}
+	var d dispute
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	err := g.node.OpenDispute(models.OrderID(d.OrderID), d.Reason, nil)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handleGETOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := mux.Vars(r)["orderID"]
+
+	order, err := g.node.GetOrder(models.OrderID(orderID))
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	sanitizedJSONResponse(w, order)
+}
+
+func (g *Gateway) handleGETOrderFiatValues(w http.ResponseWriter, r *http.Request) {
+	orderID := mux.Vars(r)["orderID"]
+
+	values, err := g.node.GetOrderFiatValues(models.OrderID(orderID))
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	sanitizedJSONResponse(w, values)
+}
+
+func (g *Gateway) handleGETOrders(w http.ResponseWriter, r *http.Request) {
+	g.serveOrderSummaries(w, r, g.node.GetOrders)
+}
+
+func (g *Gateway) handleGETSales(w http.ResponseWriter, r *http.Request) {
+	g.serveOrderSummaries(w, r, g.node.GetSales)
+}
+
+func (g *Gateway) handleGETPurchases(w http.ResponseWriter, r *http.Request) {
+	g.serveOrderSummaries(w, r, g.node.GetPurchases)
+}
+
+func (g *Gateway) serveOrderSummaries(w http.ResponseWriter, r *http.Request, getFunc func(query *models.OrderQuery) ([]models.OrderSummary, error)) {
+	query, err := parseOrderQuery(r)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	summaries, err := getFunc(query)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	if summaries == nil {
+		summaries = []models.OrderSummary{}
+	}
+	sanitizedJSONResponse(w, summaries)
+}
+
+// parseOrderQuery builds an OrderQuery from the URL query parameters. Dates
+// must be in RFC3339 format and statuses may be comma separated.
+func parseOrderQuery(r *http.Request) (*models.OrderQuery, error) {
+	var (
+		params = r.URL.Query()
+		query  = &models.OrderQuery{
+			Role:     models.OrderRole(strings.ToLower(params.Get("role"))),
+			BuyerID:  params.Get("buyerID"),
+			VendorID: params.Get("vendorID"),
+			Search:   params.Get("search"),
+			OffsetID: models.OrderID(params.Get("offsetID")),
+		}
+		err error
+	)
+	if openStr := params.Get("open"); openStr != "" {
+		open, err := strconv.ParseBool(openStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid open parameter: %s", err)
+		}
+		query.Open = &open
+	}
+	if statusStr := params.Get("status"); statusStr != "" {
+		for _, s := range strings.Split(statusStr, ",") {
+			query.Statuses = append(query.Statuses, models.OrderStatus(strings.ToUpper(strings.TrimSpace(s))))
+		}
+	}
+	if fromStr := params.Get("from"); fromStr != "" {
+		query.From, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from parameter: %s", err)
+		}
+	}
+	if toStr := params.Get("to"); toStr != "" {
+		query.To, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to parameter: %s", err)
+		}
+	}
+	if limitStr := params.Get("limit"); limitStr != "" {
+		query.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
@@ -0,0 +1,511 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	iwallet "github.com/cpacia/wallet-interface"
+	"net/http"
+	"testing"
+)
+
+func TestOrderHandlers(t *testing.T) {
+	runAPITests(t, apiTests{
+		{
+			name:   "Post purchase",
+			path:   "/v1/ob/purchase",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.purchaseFunc = func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error) {
+					return models.OrderID("abc"), iwallet.NewAddress("xyz", iwallet.CtMock), *models.NewCurrencyValueFromUint(100, models.CurrencyDefinitions["MCK"]), nil
+				}
+			},
+			body:       []byte(`{"items": [{"listingHash": "abc", "quantity": "1"}], "paymentCoin": "MCK"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(purchaseResponse{
+					OrderID:        "abc",
+					PaymentAddress: "xyz",
+					Amount:         models.NewCurrencyValueFromUint(100, models.CurrencyDefinitions["MCK"]),
+				})
+			},
+		},
+		{
+			name:   "Post purchase invalid JSON",
+			path:   "/v1/ob/purchase",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.purchaseFunc = func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error) {
+					return
+				}
+			},
+			body:       []byte(`"items": []}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "json: cannot unmarshal string into Go value of type models.Purchase"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post purchase bad request",
+			path:   "/v1/ob/purchase",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.purchaseFunc = func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error) {
+					err = fmt.Errorf("%w: invalid quantity", coreiface.ErrBadRequest)
+					return
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: invalid quantity"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post purchase dust amount",
+			path:   "/v1/ob/purchase",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.purchaseFunc = func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error) {
+					err = coreiface.ErrDustAmount
+					return
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "dust amount"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post purchase listing not found",
+			path:   "/v1/ob/purchase",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.purchaseFunc = func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error) {
+					err = fmt.Errorf("%w: listing not found", coreiface.ErrNotFound)
+					return
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: listing not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post purchase internal error",
+			path:   "/v1/ob/purchase",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.purchaseFunc = func(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error) {
+					err = errors.New("error")
+					return
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusInternalServerError,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "error"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post estimate total",
+			path:   "/v1/ob/estimatetotal",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.estimateOrderTotalFunc = func(ctx context.Context, purchase *models.Purchase) (models.OrderTotals, error) {
+					return models.OrderTotals{
+						Subtotal: iwallet.NewAmount(100),
+						Total:    iwallet.NewAmount(100),
+					}, nil
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(models.OrderTotals{
+					Subtotal: iwallet.NewAmount(100),
+					Total:    iwallet.NewAmount(100),
+				})
+			},
+		},
+		{
+			name:   "Post estimate total bad request",
+			path:   "/v1/ob/estimatetotal",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.estimateOrderTotalFunc = func(ctx context.Context, purchase *models.Purchase) (models.OrderTotals, error) {
+					return models.OrderTotals{}, fmt.Errorf("%w: no items", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: no items"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post confirm order",
+			path:   "/v1/ob/orderconfirmation",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.confirmOrderFunc = func(orderID models.OrderID, done chan struct{}) error {
+					if orderID != "abc" {
+						return errors.New("incorrect order ID")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post confirm order not found",
+			path:   "/v1/ob/orderconfirmation",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.confirmOrderFunc = func(orderID models.OrderID, done chan struct{}) error {
+					return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: order not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post confirm order bad state",
+			path:   "/v1/ob/orderconfirmation",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.confirmOrderFunc = func(orderID models.OrderID, done chan struct{}) error {
+					return fmt.Errorf("%w: order is not in a state where it can be confirmed", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: order is not in a state where it can be confirmed"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post reject order",
+			path:   "/v1/ob/orderrejection",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.rejectOrderFunc = func(orderID models.OrderID, reason string, done chan struct{}) error {
+					if orderID != "abc" || reason != "out of stock" {
+						return errors.New("incorrect parameters")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc", "reason": "out of stock"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post reject order internal error",
+			path:   "/v1/ob/orderrejection",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.rejectOrderFunc = func(orderID models.OrderID, reason string, done chan struct{}) error {
+					return fmt.Errorf("%w: db closed", coreiface.ErrInternalServer)
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusInternalServerError,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "internal server error: db closed"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post fulfill order",
+			path:   "/v1/ob/orderfulfillment",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.fulfillOrderFunc = func(orderID models.OrderID, fulfillments []models.Fulfillment, done chan struct{}) error {
+					if len(fulfillments) != 1 || fulfillments[0].PhysicalDelivery == nil || fulfillments[0].PhysicalDelivery.TrackingNumber != "1234" {
+						return errors.New("incorrect fulfillments")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc", "fulfillments": [{"itemIndex": 0, "physicalDelivery": {"shipper": "UPS", "trackingNumber": "1234"}}]}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post fulfill order bad request",
+			path:   "/v1/ob/orderfulfillment",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.fulfillOrderFunc = func(orderID models.OrderID, fulfillments []models.Fulfillment, done chan struct{}) error {
+					return fmt.Errorf("%w: invalid item index", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{"orderID": "abc", "fulfillments": [{"itemIndex": 5}]}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: invalid item index"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post cancel order",
+			path:   "/v1/ob/ordercancel",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.cancelOrderFunc = func(orderID models.OrderID, done chan struct{}) error {
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post cancel order invalid JSON",
+			path:   "/v1/ob/ordercancel",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.cancelOrderFunc = func(orderID models.OrderID, done chan struct{}) error {
+					return nil
+				}
+			},
+			body:       []byte(`"orderID": "abc"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "json: cannot unmarshal string into Go value of type api.cancel"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post refund order",
+			path:   "/v1/ob/refund",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.refundOrderFunc = func(orderID models.OrderID, done chan struct{}) error {
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post refund order not found",
+			path:   "/v1/ob/refund",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.refundOrderFunc = func(orderID models.OrderID, done chan struct{}) error {
+					return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: order not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post complete order",
+			path:   "/v1/ob/ordercompletion",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.completeOrderFunc = func(orderID models.OrderID, ratings []models.Rating, includeIDInRating bool, done chan struct{}) error {
+					if len(ratings) != 1 || ratings[0].Overall != 5 || !includeIDInRating {
+						return errors.New("incorrect ratings")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc", "ratings": [{"Overall": 5, "Quality": 4, "Description": 3, "DeliverySpeed": 2, "CustomerService": 1, "Review": "good"}], "includeIDInRating": true}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post complete order bad state",
+			path:   "/v1/ob/ordercompletion",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.completeOrderFunc = func(orderID models.OrderID, ratings []models.Rating, includeIDInRating bool, done chan struct{}) error {
+					return fmt.Errorf("%w: order is not in a state where it can be completed", coreiface.ErrBadRequest)
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "bad request: order is not in a state where it can be completed"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Post open dispute",
+			path:   "/v1/ob/opendispute",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.openDisputeFunc = func(orderID models.OrderID, reason string, done chan struct{}) error {
+					if reason != "never arrived" {
+						return errors.New("incorrect reason")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"orderID": "abc", "reason": "never arrived"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post open dispute internal error",
+			path:   "/v1/ob/opendispute",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.openDisputeFunc = func(orderID models.OrderID, reason string, done chan struct{}) error {
+					return errors.New("error")
+				}
+			},
+			body:       []byte(`{"orderID": "abc"}`),
+			statusCode: http.StatusInternalServerError,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "error"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get order",
+			path:   "/v1/ob/order/abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getOrderFunc = func(orderID models.OrderID) (*models.Order, error) {
+					return &models.Order{ID: orderID}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(&models.Order{ID: "abc"})
+			},
+		},
+		{
+			name:   "Get order not found",
+			path:   "/v1/ob/order/abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getOrderFunc = func(orderID models.OrderID) (*models.Order, error) {
+					return nil, fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: order not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get order fiat values",
+			path:   "/v1/ob/orderfiatvalues/abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getOrderFiatValuesFunc = func(orderID models.OrderID) ([]models.FiatValue, error) {
+					if orderID != "abc" {
+						return nil, errors.New("incorrect order ID")
+					}
+					return []models.FiatValue{{Currency: "USD", Amount: "12480", Rate: "250000", Event: models.RateEventOrderOpen}}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]models.FiatValue{{Currency: "USD", Amount: "12480", Rate: "250000", Event: models.RateEventOrderOpen}})
+			},
+		},
+		{
+			name:   "Get order fiat values not found",
+			path:   "/v1/ob/orderfiatvalues/abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getOrderFiatValuesFunc = func(orderID models.OrderID) ([]models.FiatValue, error) {
+					return nil, fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: order not found"}%s`, "\n")), nil
+			},
+		},
+		{
+			name:   "Get orders",
+			path:   "/v1/ob/orders?role=vendor&open=true&status=funded,fulfilled&from=2020-01-01T00:00:00Z&search=shirt&limit=5&offsetID=abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getOrdersFunc = func(query *models.OrderQuery) ([]models.OrderSummary, error) {
+					if query.Role != models.RoleVendor || query.Open == nil || !*query.Open ||
+						len(query.Statuses) != 2 || query.Statuses[0] != models.StatusFunded || query.Statuses[1] != models.StatusFulfilled ||
+						query.From.Year() != 2020 || query.Search != "shirt" || query.Limit != 5 || query.OffsetID != "abc" {
+						return nil, errors.New("incorrect query")
+					}
+					return []models.OrderSummary{{OrderID: "xyz", Status: models.StatusFunded}}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]models.OrderSummary{{OrderID: "xyz", Status: models.StatusFunded}})
+			},
+		},
+		{
+			name:   "Get orders invalid date",
+			path:   "/v1/ob/orders?from=yesterday",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getOrdersFunc = func(query *models.OrderQuery) ([]models.OrderSummary, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Get sales empty",
+			path:   "/v1/ob/sales",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getSalesFunc = func(query *models.OrderQuery) ([]models.OrderSummary, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]models.OrderSummary{})
+			},
+		},
+		{
+			name:   "Get purchases offset not found",
+			path:   "/v1/ob/purchases?offsetID=abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getPurchasesFunc = func(query *models.OrderQuery) ([]models.OrderSummary, error) {
+					return nil, fmt.Errorf("%w: offset order not found", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: offset order not found"}%s`, "\n")), nil
+			},
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (g *Gateway) handleGETOutbox(w http.ResponseWriter, r *http.Request) {
+	messages, err := g.node.GetOutgoingMessages()
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	sanitizedJSONResponse(w, messages)
+}
+
+func (g *Gateway) handlePOSTRetryOutgoingMessage(w http.ResponseWriter, r *http.Request) {
+	err := g.node.RetryOutgoingMessage(mux.Vars(r)["messageID"])
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (g *Gateway) handleDELETEOutgoingMessage(w http.ResponseWriter, r *http.Request) {
+	err := g.node.CancelOutgoingMessage(mux.Vars(r)["messageID"])
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+}
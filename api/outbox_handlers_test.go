@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOutboxHandlers(t *testing.T) {
+	ts := time.Unix(1600000000, 0).UTC()
+	runAPITests(t, apiTests{
+		{
+			name:   "Get outbox",
+			path:   "/v1/ob/outbox",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getOutgoingMessagesFunc = func() ([]models.OutboxMessage, error) {
+					return []models.OutboxMessage{
+						{
+							MessageID:   "abc",
+							Recipient:   "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+							MessageType: "ORDER",
+							Timestamp:   ts,
+							Age:         "1h0m0s",
+							Attempts:    3,
+							LastAttempt: ts,
+							LastError:   "peer unreachable",
+							Failed:      true,
+						},
+					}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]models.OutboxMessage{
+					{
+						MessageID:   "abc",
+						Recipient:   "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+						MessageType: "ORDER",
+						Timestamp:   ts,
+						Age:         "1h0m0s",
+						Attempts:    3,
+						LastAttempt: ts,
+						LastError:   "peer unreachable",
+						Failed:      true,
+					},
+				})
+			},
+		},
+		{
+			name:   "Post retry outgoing message",
+			path:   "/v1/ob/outbox/abc/retry",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.retryOutgoingMessageFunc = func(messageID string) error {
+					if messageID != "abc" {
+						return errors.New("invalid messageID")
+					}
+					return nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post retry outgoing message not found",
+			path:   "/v1/ob/outbox/abc/retry",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.retryOutgoingMessageFunc = func(messageID string) error {
+					return fmt.Errorf("%w: outgoing message", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "not found: outgoing message"}`)), nil
+			},
+		},
+		{
+			name:   "Delete outgoing message",
+			path:   "/v1/ob/outbox/abc",
+			method: http.MethodDelete,
+			setNodeMethods: func(n *mockNode) {
+				n.cancelOutgoingMessageFunc = func(messageID string) error {
+					if messageID != "abc" {
+						return errors.New("invalid messageID")
+					}
+					return nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Delete outgoing message not found",
+			path:   "/v1/ob/outbox/abc",
+			method: http.MethodDelete,
+			setNodeMethods: func(n *mockNode) {
+				n.cancelOutgoingMessageFunc = func(messageID string) error {
+					return fmt.Errorf("%w: outgoing message", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "not found: outgoing message"}`)), nil
+			},
+		},
+	})
+}
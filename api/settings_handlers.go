@@ -7,7 +7,10 @@ import (
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/version"
 	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/gorilla/mux"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type nodeConfig struct {
@@ -63,10 +66,49 @@ func (g *Gateway) handleGetUserPreferences(w http.ResponseWriter, r *http.Reques
 }
 
 func (g *Gateway) handleGETExchangeRates(w http.ResponseWriter, r *http.Request) {
-	rates, err := g.node.ExchangeRates().GetAllRates(iwallet.CtBitcoin, false)
+	erp := g.node.ExchangeRates()
+	rates, err := erp.GetAllRates(iwallet.CtBitcoin, false)
 	if err != nil {
 		http.Error(w, wrapError(err), http.StatusInternalServerError)
 		return
 	}
+	if includeSources, _ := strconv.ParseBool(r.URL.Query().Get("sources")); includeSources {
+		sanitizedJSONResponse(w, struct {
+			Rates   map[models.CurrencyCode]iwallet.Amount `json:"rates"`
+			Sources []models.ExchangeRateSource            `json:"sources"`
+		}{
+			Rates:   rates,
+			Sources: erp.Sources(),
+		})
+		return
+	}
 	sanitizedJSONResponse(w, rates)
 }
+
+// handleGETExchangeRateHistory returns the saved exchange rate closest to the
+// timestamp query parameter. The timestamp must be in RFC3339 format and
+// defaults to now.
+func (g *Gateway) handleGETExchangeRateHistory(w http.ResponseWriter, r *http.Request) {
+	var (
+		vars      = mux.Vars(r)
+		timestamp = time.Now()
+	)
+	if ts := r.URL.Query().Get("timestamp"); ts != "" {
+		var err error
+		timestamp, err = time.Parse(time.RFC3339, ts)
+		if err != nil {
+			http.Error(w, wrapError(err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	record, err := g.node.GetHistoricalRate(models.CurrencyCode(vars["base"]), models.CurrencyCode(vars["currency"]), timestamp)
+	if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	sanitizedJSONResponse(w, record)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"github.com/cpacia/multiwallet"
 	"github.com/cpacia/openbazaar3.0/core/coreiface"
@@ -11,6 +12,7 @@ import (
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestSettingsHandlers(t *testing.T) {
@@ -116,5 +118,79 @@ func TestSettingsHandlers(t *testing.T) {
 				return marshalAndSanitizeJSON(rates)
 			},
 		},
+		{
+			name:   "Get exchange rates with sources",
+			path:   "/v1/ob/exchangerates?sources=true",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getExchangeRatesFunc = func() *wallet.ExchangeRateProvider {
+					erp, _ := wallet.NewMockExchangeRates()
+					return erp
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				erp, err := wallet.NewMockExchangeRates()
+				if err != nil {
+					return nil, err
+				}
+				rates, err := erp.GetAllRates(iwallet.CtBitcoin, true)
+				if err != nil {
+					return nil, err
+				}
+				return marshalAndSanitizeJSON(struct {
+					Rates   map[models.CurrencyCode]iwallet.Amount `json:"rates"`
+					Sources []models.ExchangeRateSource            `json:"sources"`
+				}{
+					Rates:   rates,
+					Sources: erp.Sources(),
+				})
+			},
+		},
+		{
+			name:   "Get exchange rate history",
+			path:   "/v1/ob/exchangeratehistory/BTC/USD?timestamp=2020-06-01T12:00:00Z",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getHistoricalRateFunc = func(base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error) {
+					if base != "BTC" || currency != "USD" || !timestamp.Equal(time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)) {
+						return nil, errors.New("incorrect query")
+					}
+					return &models.ExchangeRateRecord{Base: base, Currency: currency, Rate: "950000", Timestamp: timestamp}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON(&models.ExchangeRateRecord{Base: "BTC", Currency: "USD", Rate: "950000", Timestamp: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)})
+			},
+		},
+		{
+			name:   "Get exchange rate history invalid timestamp",
+			path:   "/v1/ob/exchangeratehistory/BTC/USD?timestamp=yesterday",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getHistoricalRateFunc = func(base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Get exchange rate history not found",
+			path:   "/v1/ob/exchangeratehistory/BTC/JPY",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getHistoricalRateFunc = func(base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error) {
+					return nil, fmt.Errorf("%w: no BTC/JPY exchange rate saved", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf(`{"error": "not found: no BTC/JPY exchange rate saved"}%s`, "\n")), nil
+			},
+		},
 	})
 }
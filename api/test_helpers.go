@@ -18,6 +18,7 @@ type apiTest struct {
 	path             string
 	method           string
 	body             []byte
+	headers          map[string]string
 	setNodeMethods   func(n *mockNode)
 	statusCode       int
 	expectedResponse func() ([]byte, error)
@@ -47,6 +48,9 @@ func runAPITests(t *testing.T, tests apiTests) {
 		if err != nil {
 			t.Fatal(err)
 		}
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
 		res, err := http.DefaultClient.Do(req)
 		if err != nil {
 			t.Fatal(err)
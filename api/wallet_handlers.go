@@ -1,12 +1,18 @@
 package api
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/accounting"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/wallet"
 	iwallet "github.com/cpacia/wallet-interface"
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
+	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
@@ -214,17 +220,125 @@ func (g *Gateway) handleGETTransactions(w http.ResponseWriter, r *http.Request)
 	sanitizedJSONResponse(w, ret)
 }
 
+func (g *Gateway) handleGETWalletHistory(w http.ResponseWriter, r *http.Request) {
+	query, err := parseTransactionQuery(r)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	contentType := ""
+	if format != "" {
+		contentType, err = accounting.ContentType(format)
+		if err != nil {
+			http.Error(w, wrapError(err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	txs, err := g.node.GetWalletTransactions(query)
+	if errors.Is(err, coreiface.ErrBadRequest) {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if errors.Is(err, coreiface.ErrNotFound) {
+		http.Error(w, wrapError(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	if format == "" {
+		sanitizedJSONResponse(w, txs)
+		return
+	}
+	lw := &ledgerWriter{
+		w:           w,
+		contentType: contentType,
+		filename:    "transactions." + format,
+	}
+	if err := accounting.WriteTransactions(lw, format, txs); err != nil {
+		log.Errorf("Error writing transaction history: %s", err)
+	}
+}
+
+// parseTransactionQuery builds a TransactionQuery from the URL query
+// parameters. Coins are comma separated, dates must be in RFC3339 format
+// and amounts are in the coin's base unit.
+func parseTransactionQuery(r *http.Request) (*models.TransactionQuery, error) {
+	var (
+		params = r.URL.Query()
+		query  = &models.TransactionQuery{
+			OrderID:  models.OrderID(params.Get("orderID")),
+			OffsetID: params.Get("offsetID"),
+		}
+		err error
+	)
+	if coins := params.Get("coins"); coins != "" {
+		query.Coins = strings.Split(coins, ",")
+	}
+	switch strings.ToLower(params.Get("direction")) {
+	case "":
+	case "in", "incoming":
+		query.Direction = models.TransactionIncoming
+	case "out", "outgoing":
+		query.Direction = models.TransactionOutgoing
+	default:
+		return nil, errors.New("invalid direction parameter")
+	}
+	if fromStr := params.Get("from"); fromStr != "" {
+		query.From, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from parameter: %s", err)
+		}
+	}
+	if toStr := params.Get("to"); toStr != "" {
+		query.To, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to parameter: %s", err)
+		}
+	}
+	for param, dest := range map[string]**iwallet.Amount{"minAmount": &query.MinAmount, "maxAmount": &query.MaxAmount} {
+		if amountStr := params.Get(param); amountStr != "" {
+			if _, ok := new(big.Int).SetString(amountStr, 10); !ok {
+				return nil, fmt.Errorf("invalid %s parameter", param)
+			}
+			amount := iwallet.NewAmount(amountStr)
+			*dest = &amount
+		}
+	}
+	if limitStr := params.Get("limit"); limitStr != "" {
+		query.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit parameter: %s", err)
+		}
+	}
+	return query, nil
+}
+
 func (g *Gateway) handleGETCurrencies(w http.ResponseWriter, r *http.Request) {
 	sanitizedJSONResponse(w, models.CurrencyDefinitions)
 }
 
+type walletSpendResponse struct {
+	Txid  string `json:"txid"`
+	Fee   string `json:"fee,omitempty"`
+	RawTx string `json:"rawTx,omitempty"`
+}
+
 func (g *Gateway) handlePOSTSpend(w http.ResponseWriter, r *http.Request) {
 	type Spend struct {
 		CoinType string `json:"coinType"`
 		Address  string `json:"address"`
 		Amount   string `json:"amount"`
-		FeeLevel string `json:"feeLevel"`
-		Memo     string `json:"memo"`
+		Outputs  []struct {
+			Address string `json:"address"`
+			Amount  string `json:"amount"`
+		} `json:"outputs"`
+		Utxos    []string `json:"utxos"`
+		FeeLevel string   `json:"feeLevel"`
+		DryRun   bool     `json:"dryRun"`
+		Memo     string   `json:"memo"`
 	}
 
 	var spendData Spend
@@ -234,50 +348,89 @@ func (g *Gateway) handlePOSTSpend(w http.ResponseWriter, r *http.Request) {
 	}
 
 	mw := g.node.Multiwallet()
-	wallet, err := mw.WalletForCurrencyCode(spendData.CoinType)
+	wal, err := mw.WalletForCurrencyCode(spendData.CoinType)
 	if err != nil {
 		http.Error(w, wrapError(err), http.StatusBadRequest)
 		return
 	}
 
-	addr := iwallet.NewAddress(spendData.Address, iwallet.CoinType(spendData.CoinType))
-	amt := iwallet.NewAmount(spendData.Amount)
-	if amt.Cmp(iwallet.NewAmount(0)) == 0 {
-		http.Error(w, wrapError(errors.New("cannot send zero amount")), http.StatusBadRequest)
-		return
+	var outputs []wallet.SpendOutput
+	if spendData.Address != "" || len(spendData.Outputs) == 0 {
+		outputs = append(outputs, wallet.SpendOutput{
+			Address: iwallet.NewAddress(spendData.Address, iwallet.CoinType(spendData.CoinType)),
+			Amount:  iwallet.NewAmount(spendData.Amount),
+		})
 	}
-
-	var feeLevel iwallet.FeeLevel
-	switch strings.ToUpper(spendData.FeeLevel) {
-	case "PRIORITY":
-		feeLevel = iwallet.FlPriority
-	case "NORMAL":
-		feeLevel = iwallet.FlNormal
-	case "ECONOMIC":
-		feeLevel = iwallet.FlEconomic
-	case "SUPER_ECONOMIC":
-		feeLevel = iwallet.FLSuperEconomic
-	default:
-		customFee, err := strconv.Atoi(spendData.FeeLevel)
-		if err != nil {
-			http.Error(w, wrapError(errors.New("invalid custom fee")), http.StatusBadRequest)
+	for _, out := range spendData.Outputs {
+		outputs = append(outputs, wallet.SpendOutput{
+			Address: iwallet.NewAddress(out.Address, iwallet.CoinType(spendData.CoinType)),
+			Amount:  iwallet.NewAmount(out.Amount),
+		})
+	}
+	for _, out := range outputs {
+		if out.Amount.Cmp(iwallet.NewAmount(0)) == 0 {
+			http.Error(w, wrapError(errors.New("cannot send zero amount")), http.StatusBadRequest)
 			return
 		}
-		feeLevel = iwallet.FeeLevel(customFee)
 	}
 
-	wtx, err := wallet.Begin()
+	feeLevel, err := parseFeeLevel(spendData.FeeLevel)
 	if err != nil {
-		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		http.Error(w, wrapError(err), http.StatusBadRequest)
 		return
 	}
 
-	txid, err := wallet.Spend(wtx, addr, amt, feeLevel)
+	wtx, err := wal.Begin()
 	if err != nil {
 		http.Error(w, wrapError(err), http.StatusInternalServerError)
 		return
 	}
 
+	var (
+		txid iwallet.TransactionID
+		resp walletSpendResponse
+	)
+	if len(outputs) == 1 && len(spendData.Utxos) == 0 && !spendData.DryRun {
+		txid, err = wal.Spend(wtx, outputs[0].Address, outputs[0].Amount, feeLevel)
+		if err != nil {
+			http.Error(w, wrapError(err), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		coinControl, ok := wal.(wallet.CoinControl)
+		if !ok {
+			wtx.Rollback()
+			http.Error(w, wrapError(errors.New("wallet does not support coin control")), http.StatusBadRequest)
+			return
+		}
+		result, err := coinControl.SpendOutputs(wtx, &wallet.SpendRequest{
+			Outputs:  outputs,
+			Utxos:    spendData.Utxos,
+			FeeLevel: feeLevel,
+			DryRun:   spendData.DryRun,
+		})
+		if errors.Is(err, wallet.ErrUtxoNotFound) {
+			wtx.Rollback()
+			http.Error(w, wrapError(err), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			wtx.Rollback()
+			http.Error(w, wrapError(err), http.StatusInternalServerError)
+			return
+		}
+		if spendData.DryRun {
+			wtx.Rollback()
+			sanitizedJSONResponse(w, walletSpendResponse{
+				Txid:  result.Txid.String(),
+				Fee:   result.Fee.String(),
+				RawTx: hex.EncodeToString(result.RawTx),
+			})
+			return
+		}
+		txid = result.Txid
+		resp.Fee = result.Fee.String()
+	}
+
 	if err := wtx.Commit(); err != nil {
 		http.Error(w, wrapError(err), http.StatusInternalServerError)
 		return
@@ -285,7 +438,7 @@ func (g *Gateway) handlePOSTSpend(w http.ResponseWriter, r *http.Request) {
 
 	md := models.TransactionMetadata{
 		Txid:           txid,
-		PaymentAddress: addr.String(),
+		PaymentAddress: outputs[0].Address.String(),
 		Memo:           spendData.Memo,
 	}
 
@@ -294,9 +447,170 @@ func (g *Gateway) handlePOSTSpend(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sanitizedJSONResponse(w, struct {
-		Txid string `json:"txid"`
-	}{
-		Txid: txid.String(),
+	resp.Txid = txid.String()
+	sanitizedJSONResponse(w, resp)
+}
+
+type walletUtxoResponse struct {
+	Outpoint      string `json:"outpoint"`
+	Address       string `json:"address"`
+	Amount        string `json:"amount"`
+	Height        uint64 `json:"height"`
+	Confirmations uint64 `json:"confirmations"`
+}
+
+func (g *Gateway) handleGETUtxos(w http.ResponseWriter, r *http.Request) {
+	coinType := mux.Vars(r)["coinType"]
+
+	mw := g.node.Multiwallet()
+	wal, err := mw.WalletForCurrencyCode(coinType)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	coinControl, ok := wal.(wallet.CoinControl)
+	if !ok {
+		http.Error(w, wrapError(errors.New("wallet does not support coin control")), http.StatusBadRequest)
+		return
+	}
+
+	chainInfo, err := wal.BlockchainInfo()
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	utxos, err := coinControl.ListUnspent()
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	ret := make([]walletUtxoResponse, 0, len(utxos))
+	for _, utxo := range utxos {
+		confirmations := uint64(0)
+		if utxo.Height > 0 {
+			confirmations = (chainInfo.Height - utxo.Height) + 1
+		}
+		ret = append(ret, walletUtxoResponse{
+			Outpoint:      utxo.Outpoint,
+			Address:       utxo.Address,
+			Amount:        utxo.Amount.String(),
+			Height:        utxo.Height,
+			Confirmations: confirmations,
+		})
+	}
+
+	sanitizedJSONResponse(w, ret)
+}
+
+func (g *Gateway) handlePOSTBumpFee(w http.ResponseWriter, r *http.Request) {
+	type BumpFee struct {
+		CoinType string `json:"coinType"`
+		Txid     string `json:"txid"`
+		Method   string `json:"method"`
+		FeeLevel string `json:"feeLevel"`
+		Memo     string `json:"memo"`
+	}
+
+	var bumpData BumpFee
+	if err := json.NewDecoder(r.Body).Decode(&bumpData); err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	mw := g.node.Multiwallet()
+	wal, err := mw.WalletForCurrencyCode(bumpData.CoinType)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	coinControl, ok := wal.(wallet.CoinControl)
+	if !ok {
+		http.Error(w, wrapError(errors.New("wallet does not support coin control")), http.StatusBadRequest)
+		return
+	}
+
+	method := wallet.BumpMethod(strings.ToUpper(bumpData.Method))
+	if method == "" {
+		method = wallet.BumpRBF
+	}
+	if method != wallet.BumpRBF && method != wallet.BumpCPFP {
+		http.Error(w, wrapError(errors.New("method must be RBF or CPFP")), http.StatusBadRequest)
+		return
+	}
+
+	feeLevel, err := parseFeeLevel(bumpData.FeeLevel)
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	}
+
+	wtx, err := wal.Begin()
+	if err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	txid := iwallet.TransactionID(bumpData.Txid)
+	result, err := coinControl.BumpFee(wtx, txid, method, feeLevel)
+	if errors.Is(err, wallet.ErrCannotBump) {
+		wtx.Rollback()
+		http.Error(w, wrapError(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		wtx.Rollback()
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := wtx.Commit(); err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	// A replacement keeps the metadata of the original transaction.
+	md, err := g.node.GetTransactionMetadata(txid)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+	if method == wallet.BumpCPFP {
+		md = models.TransactionMetadata{}
+	}
+	md.Txid = result.Txid
+	if bumpData.Memo != "" {
+		md.Memo = bumpData.Memo
+	}
+	if err := g.node.SaveTransactionMetadata(&md); err != nil {
+		http.Error(w, wrapError(err), http.StatusInternalServerError)
+		return
+	}
+
+	sanitizedJSONResponse(w, walletSpendResponse{
+		Txid: result.Txid.String(),
+		Fee:  result.Fee.String(),
 	})
 }
+
+// parseFeeLevel parses a named fee level or a custom fee rate.
+func parseFeeLevel(s string) (iwallet.FeeLevel, error) {
+	switch strings.ToUpper(s) {
+	case "PRIORITY":
+		return iwallet.FlPriority, nil
+	case "NORMAL":
+		return iwallet.FlNormal, nil
+	case "ECONOMIC":
+		return iwallet.FlEconomic, nil
+	case "SUPER_ECONOMIC":
+		return iwallet.FLSuperEconomic, nil
+	default:
+		customFee, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, errors.New("invalid custom fee")
+		}
+		return iwallet.FeeLevel(customFee), nil
+	}
+}
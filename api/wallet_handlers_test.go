@@ -1,12 +1,15 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"github.com/cpacia/multiwallet"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/wallet"
 	iwallet "github.com/cpacia/wallet-interface"
+	"gorm.io/gorm"
 	"net/http"
 	"testing"
 	"time"
@@ -474,5 +477,353 @@ func TestWalletHandlers(t *testing.T) {
 				return []byte(fmt.Sprintf("%s\n", `{"error": "invalid custom fee"}`)), nil
 			},
 		},
+		{
+			name:   "Post spend multiple outputs",
+			path:   "/v1/wallet/spend",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+				n.saveTransactionMetadataFunc = func(md *models.TransactionMetadata) error {
+					if md.Memo != "payouts" || md.PaymentAddress != "de92e54c8a52742be470bdf21f00420828888542" {
+						return errors.New("incorrect metadata")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"coinType":"MCK","outputs":[{"address":"de92e54c8a52742be470bdf21f00420828888542","amount":"10000"},{"address":"4ed3f8a0d3a1b9a6b4e1c8d2f35de5ab34f1a020","amount":"20000"}],"feeLevel":"normal","memo":"payouts"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post spend dry run",
+			path:   "/v1/wallet/spend",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+				n.saveTransactionMetadataFunc = func(md *models.TransactionMetadata) error {
+					return errors.New("dry run saved metadata")
+				}
+			},
+			body:       []byte(`{"coinType":"MCK","address":"de92e54c8a52742be470bdf21f00420828888542","amount":"10000","utxos":["1234567800000000"],"feeLevel":"10","dryRun":true}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post spend unknown utxo",
+			path:   "/v1/wallet/spend",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+				n.saveTransactionMetadataFunc = func(md *models.TransactionMetadata) error {
+					return nil
+				}
+			},
+			body:       []byte(`{"coinType":"MCK","address":"de92e54c8a52742be470bdf21f00420828888542","amount":"10000","utxos":["abcd"],"feeLevel":"10"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "utxo not found: abcd"}`)), nil
+			},
+		},
+		{
+			name:   "Get utxos",
+			path:   "/v1/wallet/utxos/mck",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Get utxos unknown coin",
+			path:   "/v1/wallet/utxos/btc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "multiwallet does not contain an implementation for the given coin"}`)), nil
+			},
+		},
+		{
+			name:   "Post bump fee",
+			path:   "/v1/wallet/bumpfee",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+				n.getTransactionMetadataFunc = func(txid iwallet.TransactionID) (models.TransactionMetadata, error) {
+					return models.TransactionMetadata{}, gorm.ErrRecordNotFound
+				}
+				n.saveTransactionMetadataFunc = func(md *models.TransactionMetadata) error {
+					if md.Memo != "speed up" || md.Txid == "12345678" {
+						return errors.New("incorrect metadata")
+					}
+					return nil
+				}
+			},
+			body:       []byte(`{"coinType":"MCK","txid":"12345678","method":"cpfp","feeLevel":"10","memo":"speed up"}`),
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return nil, nil
+			},
+		},
+		{
+			name:   "Post bump fee cannot bump",
+			path:   "/v1/wallet/bumpfee",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+			},
+			body:       []byte(`{"coinType":"MCK","txid":"12345678","method":"rbf","feeLevel":"10"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "transaction fee cannot be bumped: inputs are not ours"}`)), nil
+			},
+		},
+		{
+			name:   "Post bump fee invalid method",
+			path:   "/v1/wallet/bumpfee",
+			method: http.MethodPost,
+			setNodeMethods: func(n *mockNode) {
+				n.multiwalletFunc = func() multiwallet.Multiwallet {
+					w := wallet.NewMockWallet()
+					w.Start()
+					bus := events.NewBus()
+					w.SetEventBus(bus)
+					sub, _ := bus.Subscribe(&events.TransactionReceived{})
+					txn := w.GenerateTransaction(iwallet.NewAmount(100000))
+					txn.Timestamp = time.Unix(111111, 0)
+					txn.ID = "12345678"
+					w.IngestTransaction(txn)
+					<-sub.Out()
+
+					mw := multiwallet.Multiwallet{
+						"MCK": w,
+					}
+					return mw
+				}
+			},
+			body:       []byte(`{"coinType":"MCK","txid":"12345678","method":"double","feeLevel":"10"}`),
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "method must be RBF or CPFP"}`)), nil
+			},
+		},
+		{
+			name:   "Get wallet history",
+			path:   "/v1/wallet/history?coins=mck,tbtc&direction=in&from=2020-06-01T00:00:00Z&minAmount=1000&orderID=1234&limit=10&offsetID=abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getWalletTransactionsFunc = func(query *models.TransactionQuery) ([]models.WalletTransaction, error) {
+					if len(query.Coins) != 2 || query.Coins[1] != "tbtc" || query.Direction != models.TransactionIncoming ||
+						!query.From.Equal(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)) || query.MinAmount.String() != "1000" ||
+						query.MaxAmount != nil || query.OrderID != "1234" || query.Limit != 10 || query.OffsetID != "abc" {
+						return nil, errors.New("incorrect query")
+					}
+					return []models.WalletTransaction{
+						{
+							Coin:      "MCK",
+							Txid:      "def",
+							Value:     "100000",
+							Direction: models.TransactionIncoming,
+							Status:    "CONFIRMED",
+							Timestamp: time.Unix(111111, 0),
+							OrderID:   "1234",
+							Memo:      "payment",
+						},
+					}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return marshalAndSanitizeJSON([]models.WalletTransaction{
+					{
+						Coin:      "MCK",
+						Txid:      "def",
+						Value:     "100000",
+						Direction: models.TransactionIncoming,
+						Status:    "CONFIRMED",
+						Timestamp: time.Unix(111111, 0),
+						OrderID:   "1234",
+						Memo:      "payment",
+					},
+				})
+			},
+		},
+		{
+			name:   "Get wallet history csv",
+			path:   "/v1/wallet/history?format=csv",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getWalletTransactionsFunc = func(query *models.TransactionQuery) ([]models.WalletTransaction, error) {
+					return []models.WalletTransaction{}, nil
+				}
+			},
+			statusCode: http.StatusOK,
+			expectedResponse: func() ([]byte, error) {
+				return []byte("timestamp,coin,transaction_id,direction,status,value,confirmations,height,address,order_id,memo\n"), nil
+			},
+		},
+		{
+			name:   "Get wallet history invalid direction",
+			path:   "/v1/wallet/history?direction=sideways",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getWalletTransactionsFunc = func(query *models.TransactionQuery) ([]models.WalletTransaction, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "invalid direction parameter"}`)), nil
+			},
+		},
+		{
+			name:   "Get wallet history invalid amount",
+			path:   "/v1/wallet/history?maxAmount=1.5",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getWalletTransactionsFunc = func(query *models.TransactionQuery) ([]models.WalletTransaction, error) {
+					return nil, nil
+				}
+			},
+			statusCode: http.StatusBadRequest,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "invalid maxAmount parameter"}`)), nil
+			},
+		},
+		{
+			name:   "Get wallet history offset not found",
+			path:   "/v1/wallet/history?offsetID=abc",
+			method: http.MethodGet,
+			setNodeMethods: func(n *mockNode) {
+				n.getWalletTransactionsFunc = func(query *models.TransactionQuery) ([]models.WalletTransaction, error) {
+					return nil, fmt.Errorf("%w: offset transaction", coreiface.ErrNotFound)
+				}
+			},
+			statusCode: http.StatusNotFound,
+			expectedResponse: func() ([]byte, error) {
+				return []byte(fmt.Sprintf("%s\n", `{"error": "not found: offset transaction"}`)), nil
+			},
+		},
 	})
 }
@@ -47,18 +47,35 @@ type Channel struct {
 	object   iface.ObjectAPI
 	topic    string
 	ns       *net.NetworkService
+	bm       *net.BanManager
 	db       database.Database
 	privKey  crypto.PrivKey
 	identity peer.ID
 	cache    map[cid.Cid]bool
 	cacheMtx sync.RWMutex
+	limiter  *rateLimiter
+	listings *listingCache
 
+	// mtx guards boostrapped so that it is only changed along with the
+	// head of the channel.
+	mtx         sync.Mutex
 	boostrapped bool
-	shutdown    chan struct{}
+
+	// applied is closed once the last message received has been applied.
+	// Each message waits on the one before it so that messages are applied
+	// in the order they were received even though listing embeds are
+	// validated in parallel.
+	applied chan struct{}
+
+	shutdown chan struct{}
 }
 
 // NewChannel instantiates a new chat channel, subscribes to the pubsub topic, and bootstraps the initial messages.
-func NewChannel(topic string, ipfsNode *core.IpfsNode, ns *net.NetworkService, bus events.Bus, db database.Database) (*Channel, error) {
+// Messages from peers in the ban manager are kept in the channel history but are not returned or emitted.
+// If the topic is a private channel, messages are encrypted with the group key and only accepted from members.
+// Messages which violate the channel policy are dropped. If a ListingValidator is provided, messages
+// embedding a listing are only accepted once the listing has been found.
+func NewChannel(topic string, ipfsNode *core.IpfsNode, ns *net.NetworkService, bm *net.BanManager, bus events.Bus, db database.Database, validator ListingValidator) (*Channel, error) {
 	api, err := coreapi.NewCoreAPI(ipfsNode)
 	if err != nil {
 		return nil, err
@@ -71,12 +88,17 @@ func NewChannel(topic string, ipfsNode *core.IpfsNode, ns *net.NetworkService, b
 		topic:    strings.ToLower(topic),
 		db:       db,
 		ns:       ns,
+		bm:       bm,
 		privKey:  ipfsNode.PrivateKey,
 		identity: ipfsNode.Identity,
 		cache:    make(map[cid.Cid]bool),
 		cacheMtx: sync.RWMutex{},
+		limiter:  newRateLimiter(),
+		listings: newListingCache(validator),
+		applied:  make(chan struct{}),
 		shutdown: make(chan struct{}),
 	}
+	close(c.applied)
 	if err := c.run(); err != nil {
 		return nil, err
 	}
@@ -90,14 +112,56 @@ func (c *Channel) Topic() string {
 
 // Publish broadcasts a message to this chat channel. The message will contain
 // a pointer to the previous message(s) in the channel so that the channel
-// history can be loaded by traversing the DAG backwards.
-func (c *Channel) Publish(ctx context.Context, message string) error {
-	msg := &pb.ChannelMessage{
-		Message:   message,
+// history can be loaded by traversing the DAG backwards. If the channel
+// policy requires proof-of-work it is computed before publishing.
+func (c *Channel) Publish(ctx context.Context, content models.ChannelMessageContent) error {
+	if err := ValidateContent(&content); err != nil {
+		return err
+	}
+
+	var policy *models.ChannelPolicy
+	err := c.db.View(func(tx database.Tx) error {
+		var err error
+		policy, err = LoadPolicy(tx, c.topic)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := c.newMessage()
+	setContent(msg, content)
+	if policy.Owner != c.identity.Pretty() {
+		if !isAllowed(msg.PeerID, policy) {
+			return ErrNotAllowed
+		}
+		if err := mineNonce(ctx, msg, policy.Published.PoWBits); err != nil {
+			return err
+		}
+	}
+	return c.publishMessage(ctx, msg)
+}
+
+func (c *Channel) newMessage() *pb.ChannelMessage {
+	return &pb.ChannelMessage{
 		Topic:     c.topic,
 		PeerID:    c.identity.Pretty(),
 		Timestamp: ptypes.TimestampNow(),
 	}
+}
+
+// publishMessage signs the message, links it to the head of the channel
+// and broadcasts it. In private channels the signed message is encrypted
+// with the current group key.
+func (c *Channel) publishMessage(ctx context.Context, msg *pb.ChannelMessage) error {
+	group, keys, err := c.loadGroup()
+	if err != nil {
+		return err
+	}
+	if group != nil && !group.IsMember(c.identity.Pretty()) {
+		return ErrNotMember
+	}
+
 	ser, err := proto.Marshal(msg)
 	if err != nil {
 		return err
@@ -112,6 +176,12 @@ func (c *Channel) Publish(ctx context.Context, message string) error {
 	if err != nil {
 		return err
 	}
+	if group != nil {
+		serializedMsg, err = seal(group, keys, serializedMsg)
+		if err != nil {
+			return err
+		}
+	}
 
 	nd, err := c.object.New(ctx)
 	if err != nil {
@@ -168,6 +238,15 @@ func (c *Channel) Messages(ctx context.Context, from *cid.Cid, limit int) ([]mod
 		limit = 20
 	}
 
+	policy, deleted, err := c.loadModeration()
+	if err != nil {
+		return nil, err
+	}
+	group, keys, err := c.loadGroup()
+	if err != nil {
+		return nil, err
+	}
+
 	level := make(map[cid.Cid]bool)
 	if from != nil {
 		c.cacheMtx.RLock()
@@ -196,10 +275,20 @@ func (c *Channel) Messages(ctx context.Context, from *cid.Cid, limit int) ([]mod
 		}
 	}
 
-	ret := make([]models.ChannelMessage, 0, limit)
+	// Messages which were dropped, such as those with an invalid listing
+	// embed, leave the head holding messages which are also linked from
+	// other messages so we track the messages already visited.
+	var (
+		ret     = make([]models.ChannelMessage, 0, limit)
+		visited = make(map[cid.Cid]bool)
+	)
 	for {
 		nextLevel := make(map[cid.Cid]bool)
 		for id := range level {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
 			nd, err := c.object.Get(ctx, path.IpldPath(id))
 			if err != nil {
 				continue
@@ -209,22 +298,33 @@ func (c *Channel) Messages(ctx context.Context, from *cid.Cid, limit int) ([]mod
 				continue
 			}
 
-			var cm pb.ChannelMessage
-			if err := proto.Unmarshal(pnd.Data(), &cm); err != nil {
+			var outer pb.ChannelMessage
+			if err := proto.Unmarshal(pnd.Data(), &outer); err != nil {
+				continue
+			}
+			cm, err := open(&outer, group, keys)
+			if err != nil {
 				continue
 			}
 
-			valid, err := validateMessage(&cm)
+			valid, err := validateMessage(cm)
 			if err != nil || !valid {
 				continue
 			}
-			if from == nil || nd.Cid().String() != from.String() {
+			visible := c.checkMessage(cm, policy) == nil
+			if visible && isControlMessage(cm) {
+				if err := c.applyControl(cm); err != nil {
+					log.Errorf("Error applying channel policy, topic %s: %s", c.topic, err)
+				}
+			}
+			visible = visible && !isHidden(cm, nd.Cid(), policy, deleted) && c.validEmbed(cm)
+			if visible && (from == nil || nd.Cid().String() != from.String()) && !c.isBlocked(cm.PeerID) {
 				ret = append(ret, models.ChannelMessage{
-					PeerID:    cm.PeerID,
-					Topic:     c.topic,
-					Message:   cm.Message,
-					Timestamp: time.Unix(cm.Timestamp.Seconds, int64(cm.Timestamp.Nanos)),
-					Cid:       nd.Cid().String(),
+					PeerID:                cm.PeerID,
+					Topic:                 c.topic,
+					Timestamp:             time.Unix(cm.Timestamp.Seconds, int64(cm.Timestamp.Nanos)),
+					ChannelMessageContent: contentFromProto(cm),
+					Cid:                   nd.Cid().String(),
 				})
 			}
 
@@ -253,6 +353,18 @@ func (c *Channel) Close() {
 	close(c.shutdown)
 }
 
+// isBlocked returns whether the peer that sent a message has been blocked.
+func (c *Channel) isBlocked(peerID string) bool {
+	if c.bm == nil {
+		return false
+	}
+	pid, err := peer.Decode(peerID)
+	if err != nil {
+		return false
+	}
+	return c.bm.IsBanned(pid)
+}
+
 // run is subscribing to the pubsub topic and bootstrapping the last
 // known messages in the channel. If a new message is received on the channel
 // before the bootstrap finishes we will set that message as the head and
@@ -284,12 +396,23 @@ func (c *Channel) run() error {
 				continue
 			}
 
-			channelMsg := new(pb.ChannelMessage)
-			if err := proto.Unmarshal(pnd.Data(), channelMsg); err != nil {
+			outer := new(pb.ChannelMessage)
+			if err := proto.Unmarshal(pnd.Data(), outer); err != nil {
 				log.Errorf("Error decoding channel protobuf, topic %s: %s", c.topic, err)
 				continue
 			}
 
+			group, keys, err := c.loadGroup()
+			if err != nil {
+				log.Errorf("Error loading private channel, topic %s: %s", c.topic, err)
+				continue
+			}
+			channelMsg, err := open(outer, group, keys)
+			if err != nil {
+				log.Debugf("Dropping channel message, topic %s: %s", c.topic, err)
+				continue
+			}
+
 			valid, err := validateMessage(channelMsg)
 			if err != nil {
 				log.Error("Error validating message, topic %s: %s", c.topic, err)
@@ -300,57 +423,54 @@ func (c *Channel) run() error {
 				continue
 			}
 
-			pth, err := c.object.Put(context.Background(), bytes.NewReader(msg.Data()), caopts.Object.InputEnc("protobuf"), caopts.Object.Pin(true))
-			if err != nil {
-				log.Errorf("Error putting message to IPFS, topic %s: peer %s: %s", c.topic, channelMsg.PeerID, err)
+			// New messages in private channels must be sent by a current
+			// member using the latest group key. Removed members still hold
+			// the old keys so messages from earlier epochs are dropped.
+			if group != nil && (outer.KeyEpoch != group.Epoch || !group.IsMember(channelMsg.PeerID)) {
+				log.Debugf("Dropping channel message from %s, topic %s: %s", channelMsg.PeerID, c.topic, ErrNotMember)
 				continue
 			}
 
-			nd, err := c.object.Get(context.Background(), pth)
+			policy, deleted, err := c.loadModeration()
 			if err != nil {
-				log.Errorf("Error getting IPFS object, topic %s: peer %s: %s", c.topic, channelMsg.PeerID, err)
+				log.Errorf("Error loading channel policy, topic %s: %s", c.topic, err)
 				continue
 			}
-
-			wasBoostrapped := c.boostrapped
-			err = c.db.Update(func(tx database.Tx) error {
-				var channelRec models.Channel
-				err := tx.Read().Where("topic=?", c.topic).First(&channelRec).Error
-				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-					return err
-				}
-				channelRec.Topic = c.topic
-				channelRec.LastMessage = time.Now()
-				if !c.boostrapped {
-					if err := channelRec.SetHead([]cid.Cid{nd.Cid()}); err != nil {
-						return err
-					}
-				} else {
-					if err := channelRec.UpdateHead(nd); err != nil {
-						return err
-					}
-				}
-				return tx.Save(&channelRec)
-			})
-			if err != nil {
-				log.Errorf("Error updating database with new cids, topic %s: peer %s: %s", c.topic, channelMsg.PeerID, err)
+			if err := c.checkMessage(channelMsg, policy); err != nil {
+				log.Debugf("Dropping channel message from %s, topic %s: %s", channelMsg.PeerID, c.topic, err)
 				continue
 			}
-
-			if !wasBoostrapped {
-				log.Infof("Bootstrapped channel %s with %d cid(s)", c.topic, 1)
-				c.bus.Emit(&events.ChannelBootstrapped{Topic: c.topic})
+			if channelMsg.PeerID != c.identity.Pretty() && channelMsg.PeerID != policy.Owner && !c.limiter.allow(channelMsg.PeerID, effectiveRateLimit(policy)) {
+				log.Debugf("Dropping channel message from %s, topic %s: rate limit exceeded", channelMsg.PeerID, c.topic)
+				continue
 			}
 
-			c.boostrapped = true
+			validateEmbed := channelMsg.Listing != nil && channelMsg.PeerID != c.identity.Pretty() && c.listings.validator != nil
+			if validateEmbed {
+				select {
+				case c.listings.sem <- struct{}{}:
+				default:
+					log.Debugf("Dropping channel message from %s, topic %s: too many listings being validated", channelMsg.PeerID, c.topic)
+					continue
+				}
+			}
 
-			c.bus.Emit(&events.ChannelMessage{
-				PeerID:    channelMsg.PeerID,
-				Topic:     c.topic,
-				Message:   channelMsg.Message,
-				Timestamp: time.Unix(channelMsg.Timestamp.Seconds, int64(channelMsg.Timestamp.Nanos)),
-				Cid:       nd.Cid().String(),
-			})
+			prev, done := c.applied, make(chan struct{})
+			c.applied = done
+			go func(data []byte, channelMsg *pb.ChannelMessage) {
+				defer close(done)
+				var err error
+				if validateEmbed {
+					err = c.listings.validate(channelMsg.Listing)
+					<-c.listings.sem
+				}
+				<-prev
+				if err != nil {
+					log.Debugf("Dropping channel message from %s, topic %s: invalid listing embed: %s", channelMsg.PeerID, c.topic, err)
+					return
+				}
+				c.processMessage(data, channelMsg, policy, deleted)
+			}(msg.Data(), channelMsg)
 		}
 	}()
 
@@ -363,6 +483,75 @@ func (c *Channel) run() error {
 	return nil
 }
 
+// processMessage stores a message which has passed validation, updates the
+// head of the channel and emits the message.
+func (c *Channel) processMessage(data []byte, channelMsg *pb.ChannelMessage, policy *models.ChannelPolicy, deleted map[string]bool) {
+	pth, err := c.object.Put(context.Background(), bytes.NewReader(data), caopts.Object.InputEnc("protobuf"), caopts.Object.Pin(true))
+	if err != nil {
+		log.Errorf("Error putting message to IPFS, topic %s: peer %s: %s", c.topic, channelMsg.PeerID, err)
+		return
+	}
+
+	nd, err := c.object.Get(context.Background(), pth)
+	if err != nil {
+		log.Errorf("Error getting IPFS object, topic %s: peer %s: %s", c.topic, channelMsg.PeerID, err)
+		return
+	}
+
+	c.mtx.Lock()
+	wasBoostrapped := c.boostrapped
+	err = c.db.Update(func(tx database.Tx) error {
+		var channelRec models.Channel
+		err := tx.Read().Where("topic=?", c.topic).First(&channelRec).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		channelRec.Topic = c.topic
+		channelRec.LastMessage = time.Now()
+		if !wasBoostrapped {
+			if err := channelRec.SetHead([]cid.Cid{nd.Cid()}); err != nil {
+				return err
+			}
+		} else {
+			if err := channelRec.UpdateHead(nd); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&channelRec)
+	})
+	if err == nil {
+		c.boostrapped = true
+	}
+	c.mtx.Unlock()
+	if err != nil {
+		log.Errorf("Error updating database with new cids, topic %s: peer %s: %s", c.topic, channelMsg.PeerID, err)
+		return
+	}
+
+	if !wasBoostrapped {
+		log.Infof("Bootstrapped channel %s with %d cid(s)", c.topic, 1)
+		c.bus.Emit(&events.ChannelBootstrapped{Topic: c.topic})
+	}
+
+	if isControlMessage(channelMsg) {
+		if err := c.applyControl(channelMsg); err != nil {
+			log.Errorf("Error applying channel policy, topic %s: %s", c.topic, err)
+		}
+		return
+	}
+
+	if c.isBlocked(channelMsg.PeerID) {
+		log.Debugf("Received channel message from blocked peer %s, topic %s", channelMsg.PeerID, c.topic)
+		return
+	}
+	if isHidden(channelMsg, nd.Cid(), policy, deleted) {
+		log.Debugf("Received hidden channel message from %s, topic %s", channelMsg.PeerID, c.topic)
+		return
+	}
+
+	c.bus.Emit(newMessageEvent(channelMsg, nd.Cid()))
+}
+
 // bootstrapState loops until some channel peers connect. Once they connect
 // it queries each of them for the cid(s) they believe to be the head of the
 // channel. The responses are set as the head in our database.
@@ -373,7 +562,7 @@ func (c *Channel) bootstrapState() {
 	)
 	ticker := time.NewTicker(time.Second * 4)
 	for ; true; <-ticker.C {
-		if c.boostrapped {
+		if c.isBootstrapped() {
 			return
 		}
 		peers, err = c.pubsub.Peers(context.Background(), caopts.PubSub.Topic(topicPrefix+c.topic))
@@ -387,8 +576,13 @@ func (c *Channel) bootstrapState() {
 	}
 
 	if c.ns == nil {
+		c.mtx.Lock()
+		wasBoostrapped := c.boostrapped
 		c.boostrapped = true
-		c.bus.Emit(&events.ChannelBootstrapped{Topic: c.topic})
+		c.mtx.Unlock()
+		if !wasBoostrapped {
+			c.bus.Emit(&events.ChannelBootstrapped{Topic: c.topic})
+		}
 		return
 	}
 
@@ -460,10 +654,15 @@ func (c *Channel) bootstrapState() {
 		ids = append(ids, id)
 	}
 
-	if c.boostrapped || len(ids) == 0 {
+	if len(ids) == 0 {
 		return
 	}
 
+	c.mtx.Lock()
+	if c.boostrapped {
+		c.mtx.Unlock()
+		return
+	}
 	err = c.db.Update(func(tx database.Tx) error {
 		var channelRec models.Channel
 		err := tx.Read().Where("topic=?", c.topic).First(&channelRec).Error
@@ -477,16 +676,37 @@ func (c *Channel) bootstrapState() {
 		}
 		return tx.Save(&channelRec)
 	})
+	c.boostrapped = true
+	c.mtx.Unlock()
 	if err != nil {
 		log.Errorf("Error updating db with cids from peers: %s", err)
 	}
 
 	log.Infof("Bootstrapped channel %s with %d cid(s)", c.topic, len(ids))
-
-	c.boostrapped = true
 	c.bus.Emit(&events.ChannelBootstrapped{Topic: c.topic})
 }
 
+func (c *Channel) isBootstrapped() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.boostrapped
+}
+
+// validEmbed returns false if the listing embedded in the message was recently
+// rejected by the listing validator. Listings are not fetched here as loading
+// the history would block on every offline vendor. Our own messages are not
+// checked.
+func (c *Channel) validEmbed(cm *pb.ChannelMessage) bool {
+	if cm.Listing == nil || cm.PeerID == c.identity.Pretty() || c.listings.validator == nil {
+		return true
+	}
+	if c.listings.knownInvalid(cm.Listing) {
+		log.Debugf("Hiding channel message from %s, topic %s: invalid listing embed", cm.PeerID, c.topic)
+		return false
+	}
+	return true
+}
+
 func validateMessage(cm *pb.ChannelMessage) (bool, error) {
 	cloneMsg := proto.Clone(cm)
 	cloneMsg.(*pb.ChannelMessage).Signature = nil
@@ -2,14 +2,18 @@ package channels_test
 
 import (
 	"context"
+	"errors"
 	"github.com/cpacia/openbazaar3.0/channels"
 	"github.com/cpacia/openbazaar3.0/core"
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/database/ffsqlite"
 	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"os"
 	"path"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -27,7 +31,7 @@ func TestChannels(t *testing.T) {
 	}
 	defer db0.Close()
 	err = db0.Update(func(tx database.Tx) error {
-		return tx.Migrate(&models.Channel{})
+		return migrate(tx)
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -39,14 +43,14 @@ func TestChannels(t *testing.T) {
 	}
 	defer db1.Close()
 	err = db1.Update(func(tx database.Tx) error {
-		return tx.Migrate(&models.Channel{})
+		return migrate(tx)
 	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	bus0 := events.NewBus()
-	channel0, err := channels.NewChannel("general", mn.Nodes()[1].IPFSNode(), nil, bus0, db0)
+	channel0, err := channels.NewChannel("general", mn.Nodes()[1].IPFSNode(), nil, nil, bus0, db0, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -58,7 +62,7 @@ func TestChannels(t *testing.T) {
 	}
 
 	bus1 := events.NewBus()
-	channel1, err := channels.NewChannel("general", mn.Nodes()[2].IPFSNode(), nil, bus1, db0)
+	channel1, err := channels.NewChannel("general", mn.Nodes()[2].IPFSNode(), nil, nil, bus1, db0, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -91,7 +95,7 @@ func TestChannels(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := channel0.Publish(context.Background(), "test"); err != nil {
+	if err := channel0.Publish(context.Background(), models.ChannelMessageContent{Message: "test"}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -127,7 +131,7 @@ func TestChannels(t *testing.T) {
 		}
 	}
 
-	if err := channel1.Publish(context.Background(), "test2"); err != nil {
+	if err := channel1.Publish(context.Background(), models.ChannelMessageContent{Message: "test2"}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -197,3 +201,476 @@ func TestChannels(t *testing.T) {
 		t.Errorf("Expected message %s, got %s", "test", msgs[1].Message)
 	}
 }
+
+func TestChannelModeration(t *testing.T) {
+	mn, err := core.NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mn.TearDown()
+
+	var (
+		owner  = mn.Nodes()[1].Identity()
+		member = mn.Nodes()[2].Identity()
+	)
+
+	db0, err := ffsqlite.NewFFMemoryDB(path.Join(os.TempDir(), "channel_moderation_test", "0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db0.Close()
+
+	db1, err := ffsqlite.NewFFMemoryDB(path.Join(os.TempDir(), "channel_moderation_test", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db1.Close()
+
+	err = db1.Update(func(tx database.Tx) error {
+		if err := migrate(tx); err != nil {
+			return err
+		}
+		return channels.SavePolicy(tx, &models.ChannelPolicy{Topic: "moderated", Owner: owner.Pretty()})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db0.Update(migrate); err != nil {
+		t.Fatal(err)
+	}
+
+	bus0, bus1 := events.NewBus(), events.NewBus()
+	boot0, err := bus0.Subscribe(&events.ChannelBootstrapped{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	boot1, err := bus1.Subscribe(&events.ChannelBootstrapped{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel0, err := channels.NewChannel("moderated", mn.Nodes()[1].IPFSNode(), nil, nil, bus0, db0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer channel0.Close()
+
+	channel1, err := channels.NewChannel("moderated", mn.Nodes()[2].IPFSNode(), nil, nil, bus1, db1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer channel1.Close()
+
+	for _, sub := range []events.Subscription{boot0, boot1} {
+		select {
+		case <-time.After(time.Second * 10):
+			t.Fatal("Timed out waiting on bootstrap")
+		case <-sub.Out():
+		}
+	}
+
+	if err := channel1.PublishPolicy(context.Background(), models.ChannelOwnerPolicy{}); err != channels.ErrNotOwner {
+		t.Errorf("Expected ErrNotOwner got %v", err)
+	}
+
+	policySub, err := bus1.Subscribe(&events.ChannelPolicyUpdated{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := channel0.PublishPolicy(context.Background(), models.ChannelOwnerPolicy{PoWBits: 8}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting on policy")
+	case event := <-policySub.Out():
+		if event.(*events.ChannelPolicyUpdated).Sequence != 1 {
+			t.Errorf("Expected sequence 1 got %d", event.(*events.ChannelPolicyUpdated).Sequence)
+		}
+	}
+
+	// The member's message must include proof-of-work to be accepted by the owner.
+	msgSub, err := bus0.Subscribe(&events.ChannelMessage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := channel1.Publish(context.Background(), models.ChannelMessageContent{Message: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	var msgID cid.Cid
+	select {
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting on message")
+	case event := <-msgSub.Out():
+		msg := event.(*events.ChannelMessage)
+		if msg.PeerID != member.Pretty() {
+			t.Errorf("Expected peerID %s, got %s", member.Pretty(), msg.PeerID)
+		}
+		msgID, err = cid.Decode(msg.Cid)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleteSub, err := bus1.Subscribe(&events.ChannelMessagesDeleted{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := channel0.DeleteMessages(context.Background(), []cid.Cid{msgID}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting on deletion")
+	case <-deleteSub.Out():
+	}
+	for _, ch := range []*channels.Channel{channel0, channel1} {
+		msgs, err := ch.Messages(context.Background(), nil, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(msgs) != 0 {
+			t.Errorf("Expected 0 messages got %d", len(msgs))
+		}
+	}
+
+	// Muted peers are hidden locally.
+	if err := channel1.Publish(context.Background(), models.ChannelMessageContent{Message: "hello again"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting on message")
+	case <-msgSub.Out():
+	}
+	err = db0.Update(func(tx database.Tx) error {
+		policy, err := channels.LoadPolicy(tx, "moderated")
+		if err != nil {
+			return err
+		}
+		policy.Muted = append(policy.Muted, member.Pretty())
+		return channels.SavePolicy(tx, policy)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := channel0.Messages(context.Background(), nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 {
+		t.Errorf("Expected 0 messages got %d", len(msgs))
+	}
+
+	// Denied peers may not publish.
+	if err := channel0.PublishPolicy(context.Background(), models.ChannelOwnerPolicy{Deny: []string{member.Pretty()}}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting on policy")
+	case <-policySub.Out():
+	}
+	if err := channel1.Publish(context.Background(), models.ChannelMessageContent{Message: "spam"}); err != channels.ErrNotAllowed {
+		t.Errorf("Expected ErrNotAllowed got %v", err)
+	}
+}
+
+func TestChannelRichMessages(t *testing.T) {
+	mn, err := core.NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mn.TearDown()
+
+	var dbs []database.Database
+	for i := 0; i < 2; i++ {
+		db, err := ffsqlite.NewFFMemoryDB(path.Join(os.TempDir(), "channel_rich_test", strconv.Itoa(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if err := db.Update(migrate); err != nil {
+			t.Fatal(err)
+		}
+		dbs = append(dbs, db)
+	}
+
+	validator := func(ctx context.Context, vendor peer.ID, slug string) error {
+		if slug != "shirt" {
+			return errors.New("listing not found")
+		}
+		return nil
+	}
+
+	bus0, bus1 := events.NewBus(), events.NewBus()
+	boot0, err := bus0.Subscribe(&events.ChannelBootstrapped{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	boot1, err := bus1.Subscribe(&events.ChannelBootstrapped{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel0, err := channels.NewChannel("rich", mn.Nodes()[1].IPFSNode(), nil, nil, bus0, dbs[0], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer channel0.Close()
+
+	channel1, err := channels.NewChannel("rich", mn.Nodes()[2].IPFSNode(), nil, nil, bus1, dbs[1], validator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer channel1.Close()
+
+	for _, sub := range []events.Subscription{boot0, boot1} {
+		select {
+		case <-time.After(time.Second * 10):
+			t.Fatal("Timed out waiting on bootstrap")
+		case <-sub.Out():
+		}
+	}
+
+	msgSub, err := bus1.Subscribe(&events.ChannelMessage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Our own messages are applied asynchronously so we wait for them
+	// before reading the history.
+	ownSub, err := bus0.Subscribe(&events.ChannelMessage{}, events.MatchFields(map[string]string{"Message": "good"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func() *events.ChannelMessage {
+		select {
+		case <-time.After(time.Second * 10):
+			t.Fatal("Timed out waiting on message")
+		case event := <-msgSub.Out():
+			return event.(*events.ChannelMessage)
+		}
+		return nil
+	}
+
+	if err := channel0.Publish(context.Background(), models.ChannelMessageContent{Message: "parent"}); err != nil {
+		t.Fatal(err)
+	}
+	parent := next()
+
+	if err := channel0.Publish(context.Background(), models.ChannelMessageContent{Message: "reply", ReplyTo: parent.Cid}); err != nil {
+		t.Fatal(err)
+	}
+	if reply := next(); reply.ReplyTo != parent.Cid {
+		t.Errorf("Expected reply to %s got %s", parent.Cid, reply.ReplyTo)
+	}
+
+	if err := channel0.Publish(context.Background(), models.ChannelMessageContent{Reaction: &models.ChannelReaction{Target: parent.Cid, Emoji: "👍"}}); err != nil {
+		t.Fatal(err)
+	}
+	if reaction := next(); reaction.Reaction == nil || reaction.Reaction.Emoji != "👍" || reaction.Reaction.Target != parent.Cid {
+		t.Errorf("Incorrect reaction %v", reaction.Reaction)
+	}
+
+	// Messages embedding a listing which can't be found are dropped.
+	vendor := mn.Nodes()[1].Identity().Pretty()
+	if err := channel0.Publish(context.Background(), models.ChannelMessageContent{Message: "bad", Listing: &models.ChannelListingEmbed{VendorID: vendor, Slug: "hat"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := channel0.Publish(context.Background(), models.ChannelMessageContent{Message: "good", Listing: &models.ChannelListingEmbed{VendorID: vendor, Slug: "shirt"}}); err != nil {
+		t.Fatal(err)
+	}
+	if listing := next(); listing.Message != "good" || listing.Listing == nil || listing.Listing.Slug != "shirt" {
+		t.Errorf("Expected good listing got %v", listing)
+	}
+
+	if err := channel0.Publish(context.Background(), models.ChannelMessageContent{Message: "bad", ReplyTo: "abc"}); err == nil {
+		t.Error("Expected error publishing invalid reply")
+	}
+	select {
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting on own message")
+	case <-ownSub.Out():
+	}
+	msgs, err := channel0.Messages(context.Background(), nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 5 {
+		t.Fatalf("Expected 5 messages got %d", len(msgs))
+	}
+	var reply *models.ChannelMessage
+	for i := range msgs {
+		if msgs[i].Message == "reply" {
+			reply = &msgs[i]
+		}
+	}
+	if reply == nil || reply.ReplyTo != parent.Cid {
+		t.Errorf("Reply not returned in channel history")
+	}
+
+	// The dropped listing embed is linked from later messages but must not
+	// show up in the history either.
+	msgs, err = channel1.Messages(context.Background(), nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 4 {
+		t.Fatalf("Expected 4 messages got %d", len(msgs))
+	}
+	for _, msg := range msgs {
+		if msg.Message == "bad" {
+			t.Error("Message with invalid listing embed returned in channel history")
+		}
+	}
+}
+
+func TestPrivateChannel(t *testing.T) {
+	mn, err := core.NewMocknet(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mn.TearDown()
+
+	var (
+		dbs   []database.Database
+		buses []events.Bus
+		chans []*channels.Channel
+	)
+	for i := 0; i < 3; i++ {
+		db, err := ffsqlite.NewFFMemoryDB(path.Join(os.TempDir(), "private_channel_test", strconv.Itoa(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if err := db.Update(migrate); err != nil {
+			t.Fatal(err)
+		}
+		dbs = append(dbs, db)
+		buses = append(buses, events.NewBus())
+	}
+
+	// Node 1 is the owner, node 2 a member and node 3 is not a member.
+	owner, member := mn.Nodes()[1], mn.Nodes()[2]
+	gk, err := channels.NewGroupKey(owner.IPFSNode().PrivateKey, "wholesale", 1, []peer.ID{member.Identity()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, db := range dbs[:2] {
+		if err := db.Update(func(tx database.Tx) error { return channels.SaveGroupKey(tx, gk) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Keys for the topic from anyone else are rejected.
+	other, err := channels.NewGroupKey(member.IPFSNode().PrivateKey, "wholesale", 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbs[1].Update(func(tx database.Tx) error { return channels.SaveGroupKey(tx, other) }); err != channels.ErrNotOwner {
+		t.Errorf("Expected ErrNotOwner got %v", err)
+	}
+
+	var boots []events.Subscription
+	for i := 0; i < 3; i++ {
+		boot, err := buses[i].Subscribe(&events.ChannelBootstrapped{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		boots = append(boots, boot)
+		ch, err := channels.NewChannel("wholesale", mn.Nodes()[i+1].IPFSNode(), nil, nil, buses[i], dbs[i], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ch.Close()
+		chans = append(chans, ch)
+	}
+	for _, boot := range boots {
+		select {
+		case <-time.After(time.Second * 10):
+			t.Fatal("Timed out waiting on bootstrap")
+		case <-boot.Out():
+		}
+	}
+
+	var subs []events.Subscription
+	for _, bus := range buses {
+		sub, err := bus.Subscribe(&events.ChannelMessage{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		subs = append(subs, sub)
+	}
+	expectMessage := func(sub events.Subscription, message string) {
+		select {
+		case <-time.After(time.Second * 10):
+			t.Fatalf("Timed out waiting on message %s", message)
+		case event := <-sub.Out():
+			if m := event.(*events.ChannelMessage).Message; m != message {
+				t.Errorf("Expected message %s got %s", message, m)
+			}
+		}
+	}
+	expectNothing := func(sub events.Subscription) {
+		select {
+		case <-time.After(time.Second * 2):
+		case event := <-sub.Out():
+			t.Errorf("Unexpected message %v", event)
+		}
+	}
+
+	if err := chans[1].Publish(context.Background(), models.ChannelMessageContent{Message: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	expectMessage(subs[0], "secret")
+	expectMessage(subs[1], "secret")
+	expectNothing(subs[2])
+
+	// Unencrypted messages from non-members are dropped.
+	if err := chans[2].Publish(context.Background(), models.ChannelMessageContent{Message: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	expectMessage(subs[2], "hello")
+	expectNothing(subs[0])
+
+	// Rotate the key to remove the member.
+	gk2, err := channels.NewGroupKey(owner.IPFSNode().PrivateKey, "wholesale", 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbs[0].Update(func(tx database.Tx) error { return channels.SaveGroupKey(tx, gk2) }); err != nil {
+		t.Fatal(err)
+	}
+
+	// The removed member can still use the old key but the owner drops
+	// messages from the old epoch.
+	if err := chans[1].Publish(context.Background(), models.ChannelMessageContent{Message: "removed"}); err != nil {
+		t.Fatal(err)
+	}
+	expectMessage(subs[1], "removed")
+	expectNothing(subs[0])
+
+	if err := chans[0].Publish(context.Background(), models.ChannelMessageContent{Message: "rotated"}); err != nil {
+		t.Fatal(err)
+	}
+	expectMessage(subs[0], "rotated")
+	expectNothing(subs[1])
+
+	// The owner can traverse the history across both epochs.
+	msgs, err := chans[0].Messages(context.Background(), nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || msgs[0].Message != "rotated" || msgs[1].Message != "secret" {
+		t.Errorf("Incorrect owner history %v", msgs)
+	}
+}
+
+func migrate(tx database.Tx) error {
+	for _, m := range []interface{}{&models.Channel{}, &models.ChannelPolicy{}, &models.ChannelPeerRule{}, &models.ChannelDeletion{}, &models.PrivateChannel{}, &models.ChannelKey{}} {
+		if err := tx.Migrate(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/channels/pb"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxReactionLength is the maximum length in bytes of a reaction.
+	// It's long enough for emoji made up of several code points.
+	maxReactionLength = 32
+
+	// maxSlugLength is the maximum length of an embedded listing slug.
+	maxSlugLength = 70
+
+	// maxListingValidations is the number of embedded listings which may
+	// be validated at once. Messages received while at the limit are
+	// dropped.
+	maxListingValidations = 5
+
+	// listingValidationTimeout is how long we wait to fetch an embedded
+	// listing before dropping the message.
+	listingValidationTimeout = time.Second * 30
+
+	// validListingTTL is how long the result of validating a listing is
+	// remembered so that repeated embeds do not refetch it.
+	validListingTTL = time.Minute * 10
+)
+
+// ListingValidator returns an error if the vendor does not have a listing
+// with the given slug.
+type ListingValidator func(ctx context.Context, vendor peer.ID, slug string) error
+
+// listingCache bounds and caches the validation of embedded listings.
+type listingCache struct {
+	validator ListingValidator
+	sem       chan struct{}
+	valid     map[string]time.Time
+	invalid   map[string]time.Time
+	mtx       sync.Mutex
+}
+
+func newListingCache(validator ListingValidator) *listingCache {
+	return &listingCache{
+		validator: validator,
+		sem:       make(chan struct{}, maxListingValidations),
+		valid:     make(map[string]time.Time),
+		invalid:   make(map[string]time.Time),
+		mtx:       sync.Mutex{},
+	}
+}
+
+// validate checks the embedded listing exists using the validator. Listings
+// the validator rejected before the timeout are remembered as invalid. A
+// timeout only tells us the vendor is slow so it is not remembered.
+func (lc *listingCache) validate(embed *pb.ChannelMessage_ListingEmbed) error {
+	key := embed.VendorID + "/" + embed.Slug
+	lc.mtx.Lock()
+	validated, ok := lc.valid[key]
+	lc.mtx.Unlock()
+	if ok && time.Since(validated) < validListingTTL {
+		return nil
+	}
+
+	vendor, err := peer.Decode(embed.VendorID)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), listingValidationTimeout)
+	defer cancel()
+	err = lc.validator(ctx, vendor, embed.Slug)
+
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+	now := time.Now()
+	for _, m := range []map[string]time.Time{lc.valid, lc.invalid} {
+		for k, t := range m {
+			if now.Sub(t) >= validListingTTL {
+				delete(m, k)
+			}
+		}
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			lc.invalid[key] = now
+		}
+		return err
+	}
+	delete(lc.invalid, key)
+	lc.valid[key] = now
+	return nil
+}
+
+// knownInvalid returns whether the validator recently rejected the embedded
+// listing. It never fetches the listing.
+func (lc *listingCache) knownInvalid(embed *pb.ChannelMessage_ListingEmbed) bool {
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+	rejected, ok := lc.invalid[embed.VendorID+"/"+embed.Slug]
+	return ok && time.Since(rejected) < validListingTTL
+}
+
+// ValidateContent returns an error if the channel message content is
+// malformed.
+func ValidateContent(content *models.ChannelMessageContent) error {
+	if content.ReplyTo != "" {
+		if _, err := cid.Decode(content.ReplyTo); err != nil {
+			return fmt.Errorf("invalid reply cid: %s", err)
+		}
+	}
+	if content.Reaction != nil {
+		if content.Message != "" || content.Listing != nil || content.ReplyTo != "" {
+			return errors.New("reactions may not contain other content")
+		}
+		if _, err := cid.Decode(content.Reaction.Target); err != nil {
+			return fmt.Errorf("invalid reaction target: %s", err)
+		}
+		if err := validateReaction(content.Reaction.Emoji); err != nil {
+			return err
+		}
+	}
+	if content.Listing != nil {
+		if _, err := peer.Decode(content.Listing.VendorID); err != nil {
+			return fmt.Errorf("invalid listing vendor ID: %s", err)
+		}
+		slug := content.Listing.Slug
+		if slug == "" || len(slug) > maxSlugLength || strings.ContainsAny(slug, `/\`) || strings.Contains(slug, "..") {
+			return errors.New("invalid listing slug")
+		}
+	}
+	return nil
+}
+
+func validateReaction(emoji string) error {
+	if emoji == "" || len(emoji) > maxReactionLength || !utf8.ValidString(emoji) {
+		return errors.New("invalid reaction")
+	}
+	for _, r := range emoji {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return errors.New("invalid reaction")
+		}
+	}
+	return nil
+}
+
+// contentFromProto returns the content of the protobuf message.
+func contentFromProto(cm *pb.ChannelMessage) models.ChannelMessageContent {
+	content := models.ChannelMessageContent{
+		Message: cm.Message,
+		ReplyTo: cm.ReplyTo,
+	}
+	if cm.Reaction != nil {
+		content.Reaction = &models.ChannelReaction{
+			Target: cm.Reaction.Target,
+			Emoji:  cm.Reaction.Emoji,
+		}
+	}
+	if cm.Listing != nil {
+		content.Listing = &models.ChannelListingEmbed{
+			VendorID: cm.Listing.VendorID,
+			Slug:     cm.Listing.Slug,
+		}
+	}
+	return content
+}
+
+// setContent sets the content fields of the protobuf message.
+func setContent(cm *pb.ChannelMessage, content models.ChannelMessageContent) {
+	cm.Message = content.Message
+	cm.ReplyTo = content.ReplyTo
+	if content.Reaction != nil {
+		cm.Reaction = &pb.ChannelMessage_Reaction{
+			Target: content.Reaction.Target,
+			Emoji:  content.Reaction.Emoji,
+		}
+	}
+	if content.Listing != nil {
+		cm.Listing = &pb.ChannelMessage_ListingEmbed{
+			VendorID: content.Listing.VendorID,
+			Slug:     content.Listing.Slug,
+		}
+	}
+}
+
+// newMessageEvent returns the event emitted for a new channel message.
+func newMessageEvent(cm *pb.ChannelMessage, id cid.Cid) *events.ChannelMessage {
+	event := &events.ChannelMessage{
+		PeerID:    cm.PeerID,
+		Topic:     cm.Topic,
+		Message:   cm.Message,
+		Timestamp: time.Unix(cm.Timestamp.Seconds, int64(cm.Timestamp.Nanos)),
+		Cid:       id.String(),
+		ReplyTo:   cm.ReplyTo,
+	}
+	if cm.Reaction != nil {
+		event.Reaction = &events.ChannelReaction{
+			Target: cm.Reaction.Target,
+			Emoji:  cm.Reaction.Emoji,
+		}
+	}
+	if cm.Listing != nil {
+		event.Listing = &events.ChannelListingEmbed{
+			VendorID: cm.Listing.VendorID,
+			Slug:     cm.Listing.Slug,
+		}
+	}
+	return event
+}
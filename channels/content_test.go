@@ -0,0 +1,91 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/channels/pb"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateContent(t *testing.T) {
+	var (
+		id     = "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n"
+		vendor = "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv"
+	)
+	tests := []struct {
+		name    string
+		content models.ChannelMessageContent
+		valid   bool
+	}{
+		{"text", models.ChannelMessageContent{Message: "hello"}, true},
+		{"reply", models.ChannelMessageContent{Message: "hello", ReplyTo: id}, true},
+		{"invalid reply", models.ChannelMessageContent{Message: "hello", ReplyTo: "abc"}, false},
+		{"reaction", models.ChannelMessageContent{Reaction: &models.ChannelReaction{Target: id, Emoji: "👍🏽"}}, true},
+		{"reaction with text", models.ChannelMessageContent{Message: "hello", Reaction: &models.ChannelReaction{Target: id, Emoji: "👍"}}, false},
+		{"reaction invalid target", models.ChannelMessageContent{Reaction: &models.ChannelReaction{Target: "abc", Emoji: "👍"}}, false},
+		{"empty reaction", models.ChannelMessageContent{Reaction: &models.ChannelReaction{Target: id}}, false},
+		{"reaction with whitespace", models.ChannelMessageContent{Reaction: &models.ChannelReaction{Target: id, Emoji: "a b"}}, false},
+		{"reaction too long", models.ChannelMessageContent{Reaction: &models.ChannelReaction{Target: id, Emoji: strings.Repeat("👍", 10)}}, false},
+		{"listing", models.ChannelMessageContent{Listing: &models.ChannelListingEmbed{VendorID: vendor, Slug: "shirt"}}, true},
+		{"listing invalid vendor", models.ChannelMessageContent{Listing: &models.ChannelListingEmbed{VendorID: "abc", Slug: "shirt"}}, false},
+		{"listing empty slug", models.ChannelMessageContent{Listing: &models.ChannelListingEmbed{VendorID: vendor}}, false},
+		{"listing path slug", models.ChannelMessageContent{Listing: &models.ChannelListingEmbed{VendorID: vendor, Slug: "../profile"}}, false},
+	}
+	for _, test := range tests {
+		if err := ValidateContent(&test.content); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t got error %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestContentProtoRoundTrip(t *testing.T) {
+	content := models.ChannelMessageContent{
+		Message:  "hello",
+		ReplyTo:  "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n",
+		Listing:  &models.ChannelListingEmbed{VendorID: "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", Slug: "shirt"},
+		Reaction: &models.ChannelReaction{Target: "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n", Emoji: "👍"},
+	}
+	msg := &pb.ChannelMessage{Timestamp: ptypes.TimestampNow()}
+	setContent(msg, content)
+	if !reflect.DeepEqual(contentFromProto(msg), content) {
+		t.Errorf("Expected %v got %v", content, contentFromProto(msg))
+	}
+
+	id, err := cid.Decode("QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := newMessageEvent(msg, id)
+	if event.ReplyTo != content.ReplyTo || event.Listing.Slug != "shirt" || event.Reaction.Emoji != "👍" {
+		t.Errorf("Incorrect event %v", event)
+	}
+}
+
+func TestListingCache(t *testing.T) {
+	calls := 0
+	lc := newListingCache(func(ctx context.Context, vendor peer.ID, slug string) error {
+		calls++
+		if slug != "shirt" {
+			return errors.New("not found")
+		}
+		return nil
+	})
+	embed := &pb.ChannelMessage_ListingEmbed{VendorID: "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv", Slug: "shirt"}
+	for i := 0; i < 2; i++ {
+		if err := lc.validate(embed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected validator to be called once got %d", calls)
+	}
+	if err := lc.validate(&pb.ChannelMessage_ListingEmbed{VendorID: embed.VendorID, Slug: "hat"}); err == nil {
+		t.Error("Expected error for missing listing")
+	}
+}
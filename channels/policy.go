@@ -0,0 +1,445 @@
+package channels
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/channels/pb"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/golang/protobuf/proto"
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"gorm.io/gorm"
+	"math"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxPoWBits is the highest proof-of-work difficulty an owner may
+	// set. It keeps the cost of publishing to a few seconds at most.
+	MaxPoWBits = 24
+
+	// maxRateLimitPeers is the number of peers we track before pruning
+	// idle rate limit buckets.
+	maxRateLimitPeers = 1000
+)
+
+var (
+	// ErrNotOwner is returned when a policy or deletion is published
+	// by someone other than the channel owner.
+	ErrNotOwner = errors.New("not the channel owner")
+
+	// ErrNotAllowed is returned when a peer is not permitted to post
+	// in the channel by the owner's policy.
+	ErrNotAllowed = errors.New("not allowed to post in channel")
+)
+
+// LoadPolicy loads the policy for the topic. If no policy has been saved
+// an empty policy is returned.
+func LoadPolicy(tx database.Tx, topic string) (*models.ChannelPolicy, error) {
+	topic = strings.ToLower(topic)
+	policy := &models.ChannelPolicy{Topic: topic}
+	err := tx.Read().Where("topic = ?", topic).First(policy).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var rules []models.ChannelPeerRule
+	if err := tx.Read().Where("topic = ?", topic).Order("peer_id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	policy.Muted, policy.Published.Allow, policy.Published.Deny = []string{}, []string{}, []string{}
+	for _, rule := range rules {
+		switch rule.Rule {
+		case models.ChannelRuleMute:
+			policy.Muted = append(policy.Muted, rule.PeerID)
+		case models.ChannelRuleAllow:
+			policy.Published.Allow = append(policy.Published.Allow, rule.PeerID)
+		case models.ChannelRuleDeny:
+			policy.Published.Deny = append(policy.Published.Deny, rule.PeerID)
+		}
+	}
+	return policy, nil
+}
+
+// SavePolicy saves the policy, replacing the existing peer rules for
+// the topic.
+func SavePolicy(tx database.Tx, policy *models.ChannelPolicy) error {
+	policy.Topic = strings.ToLower(policy.Topic)
+	if err := tx.Save(policy); err != nil {
+		return err
+	}
+	if err := tx.Delete("topic", policy.Topic, nil, &models.ChannelPeerRule{}); err != nil {
+		return err
+	}
+	lists := map[models.ChannelRule][]string{
+		models.ChannelRuleMute:  policy.Muted,
+		models.ChannelRuleAllow: policy.Published.Allow,
+		models.ChannelRuleDeny:  policy.Published.Deny,
+	}
+	for rule, peers := range lists {
+		for _, p := range peers {
+			if err := tx.Save(&models.ChannelPeerRule{Topic: policy.Topic, PeerID: p, Rule: rule}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PublishPolicy signs and publishes a new owner policy to the channel. If
+// no owner has been set for the channel this node becomes the owner.
+func (c *Channel) PublishPolicy(ctx context.Context, published models.ChannelOwnerPolicy) error {
+	if published.PoWBits > MaxPoWBits {
+		return fmt.Errorf("proof-of-work bits may not exceed %d", MaxPoWBits)
+	}
+	if published.RateLimit < 0 {
+		return errors.New("rate limit may not be negative")
+	}
+	for _, p := range append(published.Allow, published.Deny...) {
+		if _, err := peer.Decode(p); err != nil {
+			return fmt.Errorf("invalid peer ID %s: %s", p, err)
+		}
+	}
+
+	var policy *models.ChannelPolicy
+	err := c.db.Update(func(tx database.Tx) error {
+		var err error
+		policy, err = LoadPolicy(tx, c.topic)
+		if err != nil {
+			return err
+		}
+		if policy.Owner == "" {
+			policy.Owner = c.identity.Pretty()
+			return SavePolicy(tx, policy)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if policy.Owner != c.identity.Pretty() {
+		return ErrNotOwner
+	}
+
+	msg := c.newMessage()
+	msg.Policy = &pb.ChannelMessage_Policy{
+		Sequence:  policy.Published.Sequence + 1,
+		Allow:     published.Allow,
+		Deny:      published.Deny,
+		PowBits:   published.PoWBits,
+		RateLimit: uint32(published.RateLimit),
+	}
+	if err := c.applyControl(msg); err != nil {
+		return err
+	}
+	return c.publishMessage(ctx, msg)
+}
+
+// DeleteMessages signs and publishes a deletion marker for the messages.
+// Only the channel owner may delete messages.
+func (c *Channel) DeleteMessages(ctx context.Context, ids []cid.Cid) error {
+	if len(ids) == 0 {
+		return errors.New("no messages to delete")
+	}
+	var policy *models.ChannelPolicy
+	err := c.db.View(func(tx database.Tx) error {
+		var err error
+		policy, err = LoadPolicy(tx, c.topic)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if policy.Owner != c.identity.Pretty() {
+		return ErrNotOwner
+	}
+
+	msg := c.newMessage()
+	msg.Deletion = &pb.ChannelMessage_Deletion{}
+	for _, id := range ids {
+		msg.Deletion.Cids = append(msg.Deletion.Cids, id.String())
+	}
+	if err := c.applyControl(msg); err != nil {
+		return err
+	}
+	return c.publishMessage(ctx, msg)
+}
+
+// loadModeration loads the policy and the set of deleted messages for
+// the channel.
+func (c *Channel) loadModeration() (*models.ChannelPolicy, map[string]bool, error) {
+	var (
+		policy    *models.ChannelPolicy
+		deletions []models.ChannelDeletion
+	)
+	err := c.db.View(func(tx database.Tx) error {
+		var err error
+		policy, err = LoadPolicy(tx, c.topic)
+		if err != nil {
+			return err
+		}
+		return tx.Read().Where("topic = ?", c.topic).Find(&deletions).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	deleted := make(map[string]bool, len(deletions))
+	for _, d := range deletions {
+		deleted[d.Cid] = true
+	}
+	return policy, deleted, nil
+}
+
+// checkMessage returns an error if the message violates the channel
+// policy. Messages which fail this check are not stored or shown.
+func (c *Channel) checkMessage(cm *pb.ChannelMessage, policy *models.ChannelPolicy) error {
+	if cm.Topic != c.topic {
+		return errors.New("message topic does not match channel")
+	}
+	if isControlMessage(cm) {
+		if policy.Owner == "" || cm.PeerID != policy.Owner {
+			return ErrNotOwner
+		}
+		return nil
+	}
+	content := contentFromProto(cm)
+	if err := ValidateContent(&content); err != nil {
+		return err
+	}
+	if cm.PeerID == policy.Owner {
+		return nil
+	}
+	if !isAllowed(cm.PeerID, policy) {
+		return ErrNotAllowed
+	}
+	if leadingZeroBits(powHash(cm)) < int(policy.Published.PoWBits) {
+		return errors.New("insufficient proof-of-work")
+	}
+	return nil
+}
+
+// applyControl applies a policy or deletion message from the owner. It
+// must only be called after the message has passed checkMessage.
+func (c *Channel) applyControl(cm *pb.ChannelMessage) error {
+	var (
+		updated bool
+		deleted []string
+	)
+	err := c.db.Update(func(tx database.Tx) error {
+		if cm.Policy != nil {
+			policy, err := LoadPolicy(tx, c.topic)
+			if err != nil {
+				return err
+			}
+			if cm.Policy.Sequence <= policy.Published.Sequence {
+				return nil
+			}
+			policy.Published = models.ChannelOwnerPolicy{
+				Sequence:  cm.Policy.Sequence,
+				Allow:     validPeerIDs(cm.Policy.Allow),
+				Deny:      validPeerIDs(cm.Policy.Deny),
+				PoWBits:   cm.Policy.PowBits,
+				RateLimit: int(cm.Policy.RateLimit),
+			}
+			if policy.Published.PoWBits > MaxPoWBits {
+				policy.Published.PoWBits = MaxPoWBits
+			}
+			updated = true
+			return SavePolicy(tx, policy)
+		}
+		if cm.Deletion != nil {
+			for _, s := range cm.Deletion.Cids {
+				id, err := cid.Decode(s)
+				if err != nil {
+					continue
+				}
+				var count int64
+				if err := tx.Read().Model(&models.ChannelDeletion{}).Where("topic = ? AND cid = ?", c.topic, id.String()).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					continue
+				}
+				if err := tx.Save(&models.ChannelDeletion{Topic: c.topic, Cid: id.String(), Timestamp: time.Now()}); err != nil {
+					return err
+				}
+				deleted = append(deleted, id.String())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if updated {
+		c.bus.Emit(&events.ChannelPolicyUpdated{Topic: c.topic, Sequence: cm.Policy.Sequence})
+	}
+	if len(deleted) > 0 {
+		c.bus.Emit(&events.ChannelMessagesDeleted{Topic: c.topic, Cids: deleted})
+	}
+	return nil
+}
+
+// isHidden returns whether a message which passed checkMessage should
+// be hidden from the user.
+func isHidden(cm *pb.ChannelMessage, id cid.Cid, policy *models.ChannelPolicy, deleted map[string]bool) bool {
+	if isControlMessage(cm) || deleted[id.String()] {
+		return true
+	}
+	for _, p := range policy.Muted {
+		if p == cm.PeerID {
+			return true
+		}
+	}
+	return false
+}
+
+func isControlMessage(cm *pb.ChannelMessage) bool {
+	return cm.Policy != nil || cm.Deletion != nil
+}
+
+func isAllowed(peerID string, policy *models.ChannelPolicy) bool {
+	for _, p := range policy.Published.Deny {
+		if p == peerID {
+			return false
+		}
+	}
+	if len(policy.Published.Allow) == 0 {
+		return true
+	}
+	for _, p := range policy.Published.Allow {
+		if p == peerID {
+			return true
+		}
+	}
+	return false
+}
+
+func validPeerIDs(ids []string) []string {
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := peer.Decode(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	return valid
+}
+
+// effectiveRateLimit returns the stricter of the local and owner rate
+// limits.
+func effectiveRateLimit(policy *models.ChannelPolicy) int {
+	local, owner := policy.RateLimit, policy.Published.RateLimit
+	switch {
+	case local <= 0:
+		return owner
+	case owner <= 0:
+		return local
+	case local < owner:
+		return local
+	default:
+		return owner
+	}
+}
+
+// powHash returns the hash used for the message proof-of-work. It covers
+// the message including the nonce but not the signature.
+func powHash(cm *pb.ChannelMessage) []byte {
+	clone := proto.Clone(cm).(*pb.ChannelMessage)
+	clone.Signature = nil
+	ser, err := proto.Marshal(clone)
+	if err != nil {
+		return nil
+	}
+	h := sha256.Sum256(ser)
+	return h[:]
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
+
+// mineNonce sets the message nonce so that its hash has at least the
+// required number of leading zero bits.
+func mineNonce(ctx context.Context, cm *pb.ChannelMessage, powBits uint32) error {
+	if powBits == 0 {
+		return nil
+	}
+	for cm.Nonce = 0; cm.Nonce < math.MaxUint64; cm.Nonce++ {
+		if cm.Nonce%10000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+		if leadingZeroBits(powHash(cm)) >= int(powBits) {
+			return nil
+		}
+	}
+	return errors.New("nonce space exhausted")
+}
+
+// rateLimiter is a token bucket per peer which refills at the channel's
+// rate limit per minute.
+type rateLimiter struct {
+	buckets map[string]*rateBucket
+	mtx     sync.Mutex
+}
+
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[string]*rateBucket),
+		mtx:     sync.Mutex{},
+	}
+}
+
+// allow returns whether the peer may post another message given the
+// limit in messages per minute.
+func (rl *rateLimiter) allow(peerID string, perMinute int) bool {
+	if perMinute <= 0 {
+		return true
+	}
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	now := time.Now()
+	if len(rl.buckets) > maxRateLimitPeers {
+		for p, b := range rl.buckets {
+			if now.Sub(b.updated) > time.Minute {
+				delete(rl.buckets, p)
+			}
+		}
+	}
+
+	b, ok := rl.buckets[peerID]
+	if !ok {
+		b = &rateBucket{tokens: float64(perMinute), updated: now}
+		rl.buckets[peerID] = b
+	}
+	b.tokens = math.Min(float64(perMinute), b.tokens+now.Sub(b.updated).Minutes()*float64(perMinute))
+	b.updated = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,170 @@
+package channels
+
+import (
+	"context"
+	"github.com/cpacia/openbazaar3.0/channels/pb"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/golang/protobuf/ptypes"
+	"testing"
+)
+
+func TestMineNonce(t *testing.T) {
+	msg := &pb.ChannelMessage{
+		Message:   "hello",
+		Topic:     "general",
+		PeerID:    "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv",
+		Timestamp: ptypes.TimestampNow(),
+	}
+	if err := mineNonce(context.Background(), msg, 12); err != nil {
+		t.Fatal(err)
+	}
+	if leadingZeroBits(powHash(msg)) < 12 {
+		t.Error("Mined nonce does not meet difficulty")
+	}
+
+	// The signature is not covered by the proof-of-work.
+	msg.Signature = []byte{0x01}
+	if leadingZeroBits(powHash(msg)) < 12 {
+		t.Error("Signature changed proof-of-work hash")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := mineNonce(ctx, msg, MaxPoWBits); err == nil {
+		t.Error("Expected error from cancelled context")
+	}
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	tests := []struct {
+		b        []byte
+		expected int
+	}{
+		{[]byte{0xff}, 0},
+		{[]byte{0x01}, 7},
+		{[]byte{0x00, 0x10}, 11},
+		{[]byte{0x00, 0x00}, 16},
+	}
+	for _, test := range tests {
+		if n := leadingZeroBits(test.b); n != test.expected {
+			t.Errorf("Expected %d got %d for %x", test.expected, n, test.b)
+		}
+	}
+}
+
+func TestChannel_checkMessage(t *testing.T) {
+	var (
+		owner  = "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv"
+		member = "12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi"
+		c      = &Channel{topic: "general"}
+	)
+
+	tests := []struct {
+		name    string
+		msg     *pb.ChannelMessage
+		policy  *models.ChannelPolicy
+		allowed bool
+	}{
+		{
+			name:    "no policy",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: member},
+			policy:  &models.ChannelPolicy{},
+			allowed: true,
+		},
+		{
+			name:    "wrong topic",
+			msg:     &pb.ChannelMessage{Topic: "other", PeerID: member},
+			policy:  &models.ChannelPolicy{},
+			allowed: false,
+		},
+		{
+			name:    "denied",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: member},
+			policy:  &models.ChannelPolicy{Owner: owner, Published: models.ChannelOwnerPolicy{Deny: []string{member}}},
+			allowed: false,
+		},
+		{
+			name:    "not on allowlist",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: member},
+			policy:  &models.ChannelPolicy{Owner: owner, Published: models.ChannelOwnerPolicy{Allow: []string{owner}}},
+			allowed: false,
+		},
+		{
+			name:    "on allowlist",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: member},
+			policy:  &models.ChannelPolicy{Owner: owner, Published: models.ChannelOwnerPolicy{Allow: []string{member}}},
+			allowed: true,
+		},
+		{
+			name:    "insufficient proof-of-work",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: member},
+			policy:  &models.ChannelPolicy{Owner: owner, Published: models.ChannelOwnerPolicy{PoWBits: 20}},
+			allowed: false,
+		},
+		{
+			name:    "owner is exempt",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: owner},
+			policy:  &models.ChannelPolicy{Owner: owner, Published: models.ChannelOwnerPolicy{PoWBits: 20, Deny: []string{owner}}},
+			allowed: true,
+		},
+		{
+			name:    "policy from owner",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: owner, Policy: &pb.ChannelMessage_Policy{}},
+			policy:  &models.ChannelPolicy{Owner: owner},
+			allowed: true,
+		},
+		{
+			name:    "policy from non-owner",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: member, Policy: &pb.ChannelMessage_Policy{}},
+			policy:  &models.ChannelPolicy{Owner: owner},
+			allowed: false,
+		},
+		{
+			name:    "deletion without owner",
+			msg:     &pb.ChannelMessage{Topic: "general", PeerID: member, Deletion: &pb.ChannelMessage_Deletion{}},
+			policy:  &models.ChannelPolicy{},
+			allowed: false,
+		},
+	}
+	for _, test := range tests {
+		if err := c.checkMessage(test.msg, test.policy); (err == nil) != test.allowed {
+			t.Errorf("%s: expected allowed %t got error %v", test.name, test.allowed, err)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	rl := newRateLimiter()
+	for i := 0; i < 3; i++ {
+		if !rl.allow("peer1", 3) {
+			t.Fatalf("Message %d was limited", i)
+		}
+	}
+	if rl.allow("peer1", 3) {
+		t.Error("Expected message to be limited")
+	}
+	if !rl.allow("peer2", 3) {
+		t.Error("Limit was shared between peers")
+	}
+	if !rl.allow("peer1", 0) {
+		t.Error("Zero limit should allow all messages")
+	}
+}
+
+func TestEffectiveRateLimit(t *testing.T) {
+	tests := []struct {
+		local, owner, expected int
+	}{
+		{0, 0, 0},
+		{5, 0, 5},
+		{0, 5, 5},
+		{5, 10, 5},
+		{10, 5, 5},
+	}
+	for _, test := range tests {
+		policy := &models.ChannelPolicy{RateLimit: test.local, Published: models.ChannelOwnerPolicy{RateLimit: test.owner}}
+		if limit := effectiveRateLimit(policy); limit != test.expected {
+			t.Errorf("Expected %d got %d", test.expected, limit)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package channels
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/channels/pb"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/net"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	// MaxPrivateChannelMembers is the maximum number of members in a
+	// private channel, including the owner. Each key rotation sends a
+	// message to every member.
+	MaxPrivateChannelMembers = 250
+
+	groupKeySize = 32
+)
+
+var (
+	// ErrNotMember is returned when publishing to a private channel
+	// which we are not a member of.
+	ErrNotMember = errors.New("not a member of the private channel")
+
+	errNoGroupKey = errors.New("group key not found")
+)
+
+// LoadPrivateChannel loads the membership of the private channel with the
+// topic. If the channel is not private it returns nil.
+func LoadPrivateChannel(tx database.Tx, topic string) (*models.PrivateChannel, error) {
+	var pc models.PrivateChannel
+	err := tx.Read().Where("topic = ?", strings.ToLower(topic)).First(&pc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	ml := new(pb.MemberList)
+	if err := proto.Unmarshal(pc.MemberList, ml); err != nil {
+		return nil, err
+	}
+	pc.Members = ml.Members
+	return &pc, nil
+}
+
+// NewGroupKey returns a new random group key for the epoch along with the
+// member list signed by the owner. The owner is always a member.
+func NewGroupKey(privKey crypto.PrivKey, topic string, epoch uint64, members []peer.ID) (*pb.GroupKey, error) {
+	owner, err := peer.IDFromPrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	ml := &pb.MemberList{
+		Topic:     strings.ToLower(topic),
+		Owner:     owner.Pretty(),
+		Epoch:     epoch,
+		Members:   []string{owner.Pretty()},
+		Timestamp: ptypes.TimestampNow(),
+	}
+	seen := map[peer.ID]bool{owner: true}
+	for _, m := range members {
+		if !seen[m] {
+			ml.Members = append(ml.Members, m.Pretty())
+			seen[m] = true
+		}
+	}
+	if len(ml.Members) > MaxPrivateChannelMembers {
+		return nil, fmt.Errorf("private channels may not have more than %d members", MaxPrivateChannelMembers)
+	}
+
+	ser, err := proto.Marshal(ml)
+	if err != nil {
+		return nil, err
+	}
+	ml.Signature, err = privKey.Sign(ser)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, groupKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &pb.GroupKey{MemberList: ml, Key: key}, nil
+}
+
+// SaveGroupKey validates the group key and saves it. If the member list is
+// newer than the saved one it replaces it and the owner becomes the owner
+// in the channel policy. Keys for a private channel are only accepted from
+// the owner who created it.
+func SaveGroupKey(tx database.Tx, gk *pb.GroupKey) error {
+	ml := gk.MemberList
+	if ml == nil {
+		return errors.New("group key is missing member list")
+	}
+	if err := validateMemberList(ml); err != nil {
+		return err
+	}
+	if len(gk.Key) != groupKeySize {
+		return errors.New("invalid group key")
+	}
+
+	existing, err := LoadPrivateChannel(tx, ml.Topic)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		err := tx.Read().Where("topic = ?", ml.Topic).First(&models.Channel{}).Error
+		if err == nil {
+			return errors.New("a public channel with this topic exists")
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	} else if existing.Owner != ml.Owner {
+		return ErrNotOwner
+	}
+
+	if err := tx.Save(&models.ChannelKey{Topic: ml.Topic, Epoch: ml.Epoch, Key: gk.Key}); err != nil {
+		return err
+	}
+	if existing != nil && ml.Epoch <= existing.Epoch {
+		return nil
+	}
+
+	ser, err := proto.Marshal(ml)
+	if err != nil {
+		return err
+	}
+	err = tx.Save(&models.PrivateChannel{
+		Topic:      ml.Topic,
+		Owner:      ml.Owner,
+		Epoch:      ml.Epoch,
+		MemberList: ser,
+	})
+	if err != nil {
+		return err
+	}
+
+	policy, err := LoadPolicy(tx, ml.Topic)
+	if err != nil {
+		return err
+	}
+	if policy.Owner != ml.Owner {
+		policy.Owner = ml.Owner
+		policy.Published = models.ChannelOwnerPolicy{}
+		return SavePolicy(tx, policy)
+	}
+	return nil
+}
+
+// EncryptGroupKey encrypts the group key to the member's public key.
+func EncryptGroupKey(gk *pb.GroupKey, member peer.ID) ([]byte, error) {
+	pubkey, err := member.ExtractPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return net.Encrypt(pubkey, gk)
+}
+
+// DecryptGroupKey decrypts a group key which was encrypted to us.
+func DecryptGroupKey(privKey crypto.PrivKey, ciphertext []byte) (*pb.GroupKey, error) {
+	gk := new(pb.GroupKey)
+	if err := net.Decrypt(privKey, ciphertext, gk); err != nil {
+		return nil, err
+	}
+	return gk, nil
+}
+
+// validateMemberList checks the member list was signed by the owner and
+// that it only contains valid peer IDs.
+func validateMemberList(ml *pb.MemberList) error {
+	if ml.Topic == "" || ml.Topic != strings.ToLower(ml.Topic) {
+		return errors.New("invalid member list topic")
+	}
+	if ml.Epoch == 0 {
+		return errors.New("invalid member list epoch")
+	}
+	if len(ml.Members) > MaxPrivateChannelMembers {
+		return errors.New("too many members in member list")
+	}
+
+	cloneList := proto.Clone(ml).(*pb.MemberList)
+	cloneList.Signature = nil
+	ser, err := proto.Marshal(cloneList)
+	if err != nil {
+		return err
+	}
+	owner, err := peer.Decode(ml.Owner)
+	if err != nil {
+		return err
+	}
+	pubkey, err := owner.ExtractPublicKey()
+	if err != nil {
+		return err
+	}
+	valid, err := pubkey.Verify(ser, ml.Signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid member list signature")
+	}
+
+	ownerIsMember := false
+	for _, m := range ml.Members {
+		if _, err := peer.Decode(m); err != nil {
+			return fmt.Errorf("invalid member %s: %s", m, err)
+		}
+		if m == ml.Owner {
+			ownerIsMember = true
+		}
+	}
+	if !ownerIsMember {
+		return errors.New("owner is not in member list")
+	}
+	return nil
+}
+
+// loadGroup loads the membership and the group keys for each epoch of the
+// channel. If the channel is public the membership is nil.
+func (c *Channel) loadGroup() (*models.PrivateChannel, map[uint64][]byte, error) {
+	var (
+		group *models.PrivateChannel
+		keys  []models.ChannelKey
+	)
+	err := c.db.View(func(tx database.Tx) error {
+		var err error
+		group, err = LoadPrivateChannel(tx, c.topic)
+		if err != nil || group == nil {
+			return err
+		}
+		return tx.Read().Where("topic = ?", c.topic).Find(&keys).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	keyMap := make(map[uint64][]byte, len(keys))
+	for _, k := range keys {
+		keyMap[k.Epoch] = k.Key
+	}
+	return group, keyMap, nil
+}
+
+// seal encrypts the serialized, signed message with the group key for the
+// current epoch and returns the serialized message which is published.
+func seal(group *models.PrivateChannel, keys map[uint64][]byte, ser []byte) ([]byte, error) {
+	key, ok := keys[group.Epoch]
+	if !ok {
+		return nil, errNoGroupKey
+	}
+	ciphertext, err := encryptGroupMessage(key, ser, groupMessageAD(group.Topic, group.Epoch))
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&pb.ChannelMessage{
+		Topic:      group.Topic,
+		KeyEpoch:   group.Epoch,
+		Ciphertext: ciphertext,
+	})
+}
+
+// open returns the signed message inside a message from a private channel.
+// Messages from public channels are returned unchanged.
+func open(cm *pb.ChannelMessage, group *models.PrivateChannel, keys map[uint64][]byte) (*pb.ChannelMessage, error) {
+	if group == nil {
+		if cm.Ciphertext != nil {
+			return nil, errors.New("encrypted message in public channel")
+		}
+		return cm, nil
+	}
+	if cm.Ciphertext == nil {
+		return nil, errors.New("unencrypted message in private channel")
+	}
+	key, ok := keys[cm.KeyEpoch]
+	if !ok {
+		return nil, errNoGroupKey
+	}
+	plaintext, err := decryptGroupMessage(key, cm.Ciphertext, groupMessageAD(cm.Topic, cm.KeyEpoch))
+	if err != nil {
+		return nil, err
+	}
+	inner := new(pb.ChannelMessage)
+	if err := proto.Unmarshal(plaintext, inner); err != nil {
+		return nil, err
+	}
+	if inner.Ciphertext != nil || inner.Topic != cm.Topic {
+		return nil, errors.New("invalid private channel message")
+	}
+	return inner, nil
+}
+
+// groupMessageAD binds the ciphertext to the topic and epoch so it can't
+// be replayed into another channel.
+func groupMessageAD(topic string, epoch uint64) []byte {
+	ad := make([]byte, 8, 8+len(topic))
+	binary.BigEndian.PutUint64(ad, epoch)
+	return append(ad, topic...)
+}
+
+func encryptGroupMessage(key, plaintext, ad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, ad), nil
+}
+
+func decryptGroupMessage(key, ciphertext, ad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], ad)
+}
@@ -0,0 +1,102 @@
+package channels
+
+import (
+	"crypto/rand"
+	"github.com/cpacia/openbazaar3.0/channels/pb"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/golang/protobuf/proto"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"testing"
+)
+
+func TestNewGroupKey(t *testing.T) {
+	priv, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := peer.Decode("12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gk, err := NewGroupKey(priv, "Wholesale", 1, []peer.ID{member, member})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gk.Key) != groupKeySize {
+		t.Errorf("Expected key size %d got %d", groupKeySize, len(gk.Key))
+	}
+	if gk.MemberList.Topic != "wholesale" {
+		t.Errorf("Expected lowercase topic got %s", gk.MemberList.Topic)
+	}
+	if len(gk.MemberList.Members) != 2 || gk.MemberList.Members[0] != gk.MemberList.Owner {
+		t.Errorf("Incorrect members %v", gk.MemberList.Members)
+	}
+	if err := validateMemberList(gk.MemberList); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := proto.Clone(gk.MemberList).(*pb.MemberList)
+	tampered.Members = append(tampered.Members, "12D3KooWKLmVDz6sdzMyX1yQpEdCHB7dtxyEr91wPFNoCXEs2hkv")
+	if err := validateMemberList(tampered); err == nil {
+		t.Error("Expected tampered member list to be invalid")
+	}
+
+	encrypted, err := EncryptGroupKey(gk, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecryptGroupKey(priv, encrypted); err == nil {
+		t.Error("Expected error decrypting key sent to another peer")
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	var (
+		group = &models.PrivateChannel{Topic: "wholesale", Epoch: 2}
+		keys  = map[uint64][]byte{1: make([]byte, groupKeySize), 2: make([]byte, groupKeySize)}
+		msg   = &pb.ChannelMessage{Topic: "wholesale", Message: "hello", PeerID: "12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi"}
+	)
+	rand.Read(keys[2])
+
+	ser, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := seal(group, keys, ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer := new(pb.ChannelMessage)
+	if err := proto.Unmarshal(sealed, outer); err != nil {
+		t.Fatal(err)
+	}
+	if outer.Message != "" || outer.PeerID != "" || outer.KeyEpoch != 2 {
+		t.Errorf("Outer message leaks content: %v", outer)
+	}
+
+	inner, err := open(outer, group, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(inner, msg) {
+		t.Errorf("Expected %v got %v", msg, inner)
+	}
+
+	if _, err := open(outer, nil, nil); err == nil {
+		t.Error("Expected error opening encrypted message in public channel")
+	}
+	if _, err := open(msg, group, keys); err == nil {
+		t.Error("Expected error opening unencrypted message in private channel")
+	}
+	if _, err := open(outer, group, map[uint64][]byte{1: keys[1]}); err == nil {
+		t.Error("Expected error opening message without group key")
+	}
+
+	outer.KeyEpoch = 1
+	keys[1] = keys[2]
+	if _, err := open(outer, group, keys); err == nil {
+		t.Error("Expected error opening message with modified epoch")
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/repo"
+	"github.com/ipfs/go-ipfs/repo/fsrepo"
+	"golang.org/x/crypto/ssh/terminal"
+	"io"
+	"os"
+	"path"
+)
+
+// Backup writes an encrypted backup of the data directory of a node which
+// is not running. The passphrase is read from the OB_BACKUP_PASSPHRASE
+// environment variable or prompted for if it is not set.
+type Backup struct {
+	DataDir string `short:"d" long:"datadir" description:"Directory of the node's data"`
+	Output  string `short:"o" long:"output" description:"The file to write the backup to. If omitted it is written to stdout."`
+}
+
+// Execute writes the backup.
+func (x *Backup) Execute(args []string) error {
+	if x.DataDir == "" {
+		x.DataDir = repo.DefaultHomeDir
+	}
+	if !fsrepo.IsInitialized(path.Join(x.DataDir, "ipfs")) {
+		return errors.New("node is not initialized")
+	}
+
+	passphrase, err := backupPassphrase(true)
+	if err != nil {
+		return err
+	}
+
+	r, err := repo.NewRepo(x.DataDir)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var w io.Writer = os.Stdout
+	if x.Output != "" {
+		f, err := os.OpenFile(x.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return r.Backup(w, passphrase)
+}
+
+// Restore rebuilds a data directory from a backup. The passphrase is read
+// from the OB_BACKUP_PASSPHRASE environment variable or prompted for if it
+// is not set.
+type Restore struct {
+	DataDir string `short:"d" long:"datadir" description:"Directory to restore the node's data into. It must be empty or not exist."`
+	Input   string `short:"i" long:"input" description:"The backup file to restore from" required:"true"`
+}
+
+// Execute restores the backup.
+func (x *Restore) Execute(args []string) error {
+	if x.DataDir == "" {
+		x.DataDir = repo.DefaultHomeDir
+	}
+
+	f, err := os.Open(x.Input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	passphrase, err := backupPassphrase(false)
+	if err != nil {
+		return err
+	}
+
+	r, err := repo.RestoreRepo(x.DataDir, f, passphrase)
+	if err != nil {
+		return err
+	}
+	r.Close()
+
+	fmt.Printf("Restored backup into %s\n", x.DataDir)
+	return nil
+}
+
+// backupPassphrase returns the passphrase from the environment or prompts
+// for it. If confirm is true the passphrase must be entered twice.
+func backupPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(repo.BackupPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	passphrase, err := readPassphrase("Backup passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := readPassphrase("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// readPassphrase prompts on stderr, so it doesn't end up in a backup written
+// to stdout, and reads the passphrase from the terminal without echoing it.
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return string(b), err
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"github.com/cpacia/multiwallet"
+	"github.com/cpacia/openbazaar3.0/accounting"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/repo"
+	iwallet "github.com/cpacia/wallet-interface"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Export writes an accounting ledger from the data directory of a node
+// which is not running.
+type Export struct {
+	DataDir string `short:"d" long:"datadir" description:"Directory of the node's data"`
+	Testnet bool   `short:"t" long:"testnet" description:"Use the test network wallets"`
+	Format  string `short:"f" long:"format" description:"The format of the ledger (csv or json)" default:"csv"`
+	From    string `long:"from" description:"Only export entries on or after this date (RFC3339)"`
+	To      string `long:"to" description:"Only export entries on or before this date (RFC3339)"`
+	Role    string `short:"r" long:"role" description:"Only export entries for orders in which we are the buyer, vendor or moderator"`
+	Output  string `short:"o" long:"output" description:"The file to write the ledger to. If omitted it is written to stdout."`
+}
+
+// Execute writes the ledger.
+func (x *Export) Execute(args []string) error {
+	if x.DataDir == "" {
+		x.DataDir = repo.DefaultHomeDir
+	}
+	format := strings.ToLower(x.Format)
+	if _, err := accounting.ContentType(format); err != nil {
+		return err
+	}
+
+	query := &models.LedgerQuery{
+		Role: models.OrderRole(strings.ToLower(x.Role)),
+	}
+	var err error
+	if x.From != "" {
+		query.From, err = time.Parse(time.RFC3339, x.From)
+		if err != nil {
+			return err
+		}
+	}
+	if x.To != "" {
+		query.To, err = time.Parse(time.RFC3339, x.To)
+		if err != nil {
+			return err
+		}
+	}
+
+	cfg, err := repo.LoadConfig()
+	if err != nil {
+		return err
+	}
+	r, err := repo.NewRepo(x.DataDir)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	enabledWallets := make([]iwallet.CoinType, len(cfg.EnabledWallets))
+	for i, ew := range cfg.EnabledWallets {
+		enabledWallets[i] = iwallet.CoinType(strings.ToUpper(ew))
+	}
+
+	opts := []multiwallet.Option{
+		multiwallet.DataDir(x.DataDir),
+		multiwallet.Wallets(enabledWallets),
+		multiwallet.Testnet(x.Testnet),
+	}
+	mw, err := multiwallet.NewMultiwallet(opts...)
+	if err != nil {
+		return err
+	}
+	defer mw.Close()
+
+	var w io.Writer = os.Stdout
+	if x.Output != "" {
+		f, err := os.Create(x.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	// Entries are written as they are built so the whole ledger is never
+	// held in memory.
+	lw, err := accounting.NewLedgerWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := accounting.StreamLedger(r.DB(), mw, query, lw.Write); err != nil {
+		return err
+	}
+	return lw.Close()
+}
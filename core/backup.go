@@ -0,0 +1,82 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/repo"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// backupSchedule holds the options for writing backups on a timer.
+type backupSchedule struct {
+	dir        string
+	interval   time.Duration
+	keep       int
+	passphrase string
+}
+
+// BackupRepo writes an encrypted backup of the repo to the writer.
+func (n *OpenBazaarNode) BackupRepo(passphrase string, w io.Writer) error {
+	err := n.repo.Backup(w, passphrase)
+	if errors.Is(err, repo.ErrEmptyPassphrase) {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+	return err
+}
+
+// RestoreRepo restores a backup into a new data directory. The restored
+// repo is not used by this node. A node must be started from the data
+// directory to use it.
+func (n *OpenBazaarNode) RestoreRepo(dataDir, passphrase string, backup io.Reader) error {
+	if dataDir == "" {
+		return fmt.Errorf("%w: data directory must be set", coreiface.ErrBadRequest)
+	}
+	if filepath.Clean(dataDir) == filepath.Clean(n.repo.DataDir()) {
+		return fmt.Errorf("%w: cannot restore into the running node's data directory", coreiface.ErrBadRequest)
+	}
+	r, err := repo.RestoreRepo(dataDir, backup, passphrase)
+	if errors.Is(err, repo.ErrEmptyPassphrase) || errors.Is(err, repo.ErrInvalidBackup) || errors.Is(err, repo.ErrDataDirNotEmpty) {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+	r.Close()
+	return nil
+}
+
+// scheduledBackupLoop writes a backup to the backup directory every backup
+// interval until the node shuts down. The first backup is written one
+// interval after the newest backup already in the directory, or right away
+// if there are none.
+func (n *OpenBazaarNode) scheduledBackupLoop() {
+	var (
+		schedule = n.backupSchedule
+		wait     time.Duration
+	)
+	if backups, err := repo.ListBackups(schedule.dir); err == nil && len(backups) > 0 {
+		if fi, err := os.Stat(backups[len(backups)-1]); err == nil {
+			wait = time.Until(fi.ModTime().Add(schedule.interval))
+		}
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			filename, err := n.repo.BackupToDir(schedule.dir, schedule.passphrase, schedule.keep)
+			if err != nil {
+				log.Errorf("Error writing scheduled backup: %s", err)
+			} else {
+				log.Infof("Wrote scheduled backup to %s", filename)
+			}
+			timer.Reset(schedule.interval)
+		case <-n.shutdown:
+			return
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/repo"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOpenBazaarNode_BackupAndRestoreRepo(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	var buf bytes.Buffer
+	if err := mockNode.BackupRepo("", &buf); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+	if err := mockNode.BackupRepo("letmein", &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restoreDir, err := ioutil.TempDir("", "openbazaar-restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(restoreDir)
+
+	err = mockNode.RestoreRepo(mockNode.repo.DataDir(), "letmein", bytes.NewReader(buf.Bytes()))
+	if !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+	err = mockNode.RestoreRepo(restoreDir, "wrong", bytes.NewReader(buf.Bytes()))
+	if !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+	if err := mockNode.RestoreRepo(restoreDir, "letmein", bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenBazaarNode_scheduledBackupLoop(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	dir, err := ioutil.TempDir("", "openbazaar-backups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mockNode.backupSchedule = &backupSchedule{
+		dir:        dir,
+		interval:   time.Millisecond * 50,
+		keep:       2,
+		passphrase: "letmein",
+	}
+	go mockNode.scheduledBackupLoop()
+
+	time.Sleep(time.Second)
+
+	backups, err := repo.ListBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("Expected 2 backups, got %d", len(backups))
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"gorm.io/gorm"
+)
+
+// BlockNode adds the peer to our list of blocked nodes. The block takes effect
+// immediately. Messages from the peer are dropped and the peer is excluded
+// from our followers, channels, chat conversations and ratings. New orders
+// from the peer are automatically rejected.
+func (n *OpenBazaarNode) BlockNode(peerID peer.ID) error {
+	if peerID == n.Identity() {
+		return fmt.Errorf("%w: cannot block self", coreiface.ErrBadRequest)
+	}
+	err := n.updateBlockedNodes(func(blocked []peer.ID) ([]peer.ID, error) {
+		for _, pid := range blocked {
+			if pid == peerID {
+				return nil, fmt.Errorf("%w: peer already blocked", coreiface.ErrBadRequest)
+			}
+		}
+		return append(blocked, peerID), nil
+	})
+	if err != nil {
+		return err
+	}
+	n.eventBus.Emit(&events.PeerBlocked{PeerID: peerID.Pretty()})
+	return nil
+}
+
+// UnblockNode removes the peer from our list of blocked nodes.
+func (n *OpenBazaarNode) UnblockNode(peerID peer.ID) error {
+	err := n.updateBlockedNodes(func(blocked []peer.ID) ([]peer.ID, error) {
+		for i, pid := range blocked {
+			if pid == peerID {
+				return append(blocked[:i], blocked[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("%w: peer not blocked", coreiface.ErrBadRequest)
+	})
+	if err != nil {
+		return err
+	}
+	n.eventBus.Emit(&events.PeerUnblocked{PeerID: peerID.Pretty()})
+	return nil
+}
+
+// GetBlockedNodes returns the list of peers we have blocked.
+func (n *OpenBazaarNode) GetBlockedNodes() ([]peer.ID, error) {
+	var prefs models.UserPreferences
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().First(&prefs).Error
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return prefs.BlockedNodes()
+}
+
+// updateBlockedNodes passes the blocked nodes from the preferences into the
+// update function and saves the result. The ban manager is updated once the
+// change is committed.
+func (n *OpenBazaarNode) updateBlockedNodes(updateFunc func(blocked []peer.ID) ([]peer.ID, error)) error {
+	return n.repo.DB().Update(func(tx database.Tx) error {
+		var prefs models.UserPreferences
+		if err := tx.Read().First(&prefs).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		blocked, err := prefs.BlockedNodes()
+		if err != nil {
+			return err
+		}
+		blocked, err = updateFunc(blocked)
+		if err != nil {
+			return err
+		}
+
+		blockedStrs := make([]string, 0, len(blocked))
+		for _, pid := range blocked {
+			blockedStrs = append(blockedStrs, pid.Pretty())
+		}
+		marshalled, err := json.MarshalIndent(blockedStrs, "", "    ")
+		if err != nil {
+			return err
+		}
+		prefs.Blocked = marshalled
+		prefs.ID = 1
+		if err := tx.Save(&prefs); err != nil {
+			return err
+		}
+
+		tx.RegisterCommitHook(func() {
+			n.banManager.SetBlockedIds(blocked)
+		})
+		return nil
+	})
+}
+
+// isBlocked returns whether the peer ID string belongs to a peer we
+// have blocked.
+func (n *OpenBazaarNode) isBlocked(peerID string) bool {
+	pid, err := peer.Decode(peerID)
+	if err != nil {
+		return false
+	}
+	return n.banManager.IsBanned(pid)
+}
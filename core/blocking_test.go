@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"testing"
+	"time"
+)
+
+func TestOpenBazaarNode_BlockNode(t *testing.T) {
+	node, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer node.repo.DestroyRepo()
+
+	p, err := peer.Decode("12D3KooWLbTBv97L6jvaLkdSRpqhCX3w7PyPDWU7kwJsKJyztAUN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := peer.Decode("12D3KooWBfmETW1ZbkdZbKKPpE3jpjyQ5WBXoDF8y9oE8vMQPKLi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = node.repo.DB().Update(func(tx database.Tx) error {
+		if err := tx.SetFollowers(models.Followers{p.Pretty(), p2.Pretty()}); err != nil {
+			return err
+		}
+		for _, pid := range []peer.ID{p, p2} {
+			err := tx.Save(&models.ChatMessage{
+				MessageID: pid.Pretty(),
+				PeerID:    pid.Pretty(),
+				Message:   "hello",
+				Timestamp: time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := node.eventBus.Subscribe([]interface{}{&events.PeerBlocked{}, &events.PeerUnblocked{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	if err := node.BlockNode(node.Identity()); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request blocking self, got %v", err)
+	}
+
+	if err := node.BlockNode(p); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-sub.Out():
+		blocked, ok := e.(*events.PeerBlocked)
+		if !ok || blocked.PeerID != p.Pretty() {
+			t.Errorf("Incorrect event returned: %v", e)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	if err := node.BlockNode(p); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request blocking peer twice, got %v", err)
+	}
+
+	if !node.banManager.IsBanned(p) {
+		t.Error("Peer not added to ban manager")
+	}
+
+	blocked, err := node.GetBlockedNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocked) != 1 || blocked[0] != p {
+		t.Errorf("Incorrect blocked nodes returned: %v", blocked)
+	}
+
+	followers, err := node.GetMyFollowers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(followers) != 1 || followers[0] != p2.Pretty() {
+		t.Errorf("Blocked peer not removed from followers: %v", followers)
+	}
+
+	convos, err := node.GetChatConversations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(convos) != 1 || convos[0].PeerID != p2.Pretty() {
+		t.Errorf("Blocked peer not removed from chat conversations: %v", convos)
+	}
+
+	if err := node.UnblockNode(p); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-sub.Out():
+		unblocked, ok := e.(*events.PeerUnblocked)
+		if !ok || unblocked.PeerID != p.Pretty() {
+			t.Errorf("Incorrect event returned: %v", e)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	if node.banManager.IsBanned(p) {
+		t.Error("Peer not removed from ban manager")
+	}
+
+	if err := node.UnblockNode(p); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request unblocking peer that is not blocked, got %v", err)
+	}
+
+	followers, err = node.GetMyFollowers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(followers) != 2 {
+		t.Errorf("Expected 2 followers, got %d", len(followers))
+	}
+
+	// Blocking through the preferences should also apply immediately.
+	prefs, err := node.GetPreferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefs.Blocked = []byte(`["` + p2.Pretty() + `"]`)
+	if err := node.SavePreferences(prefs, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-sub.Out():
+		blocked, ok := e.(*events.PeerBlocked)
+		if !ok || blocked.PeerID != p2.Pretty() {
+			t.Errorf("Incorrect event returned: %v", e)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	if !node.banManager.IsBanned(p2) {
+		t.Error("Peer not added to ban manager")
+	}
+}
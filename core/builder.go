@@ -320,6 +320,7 @@ func NewNode(ctx context.Context, cfg *repo.Config) (*OpenBazaarNode, error) {
 	}
 	bm := obnet.NewBanManager(blocked)
 	service := obnet.NewNetworkService(ipfsNode.PeerHost, bm, cfg.Testnet)
+	service.SetRateLimiter(obnet.NewRateLimiter(rateLimitConfig(cfg), bm))
 	tracker := NewFollowerTracker(obRepo, bus, ipfsNode.PeerHost)
 
 	enabledWallets := make([]iwallet.CoinType, len(cfg.EnabledWallets))
@@ -327,7 +328,10 @@ func NewNode(ctx context.Context, cfg *repo.Config) (*OpenBazaarNode, error) {
 		enabledWallets[i] = iwallet.CoinType(strings.ToUpper(ew))
 	}
 
-	erp := wallet.NewExchangeRateProvider(cfg.ExchangeRateProviders)
+	erp, err := wallet.NewExchangeRateProvider(cfg.ExchangeRateProviders, cfg.ExchangeRateMaxDeviation)
+	if err != nil {
+		return nil, err
+	}
 
 	opts := []multiwallet.Option{
 		multiwallet.DataDir(cfg.DataDir),
@@ -383,6 +387,14 @@ func NewNode(ctx context.Context, cfg *repo.Config) (*OpenBazaarNode, error) {
 	}
 
 	obNode.notifier = notifications.NewNotifier(bus, obRepo.DB(), obNode.gateway.NotifyWebsockets)
+	messageTTL, err := messageTTLConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	obNode.backupSchedule, err = backupScheduleConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 	obNode.messenger, err = obnet.NewMessenger(&obnet.MessengerConfig{
 		Service:        service,
 		SNFServers:     snfServers,
@@ -391,6 +403,8 @@ func NewNode(ctx context.Context, cfg *repo.Config) (*OpenBazaarNode, error) {
 		DB:             obRepo.DB(),
 		Testnet:        cfg.Testnet,
 		GetProfileFunc: obNode.GetProfile,
+		EventBus:       bus,
+		MessageTTL:     messageTTL,
 	})
 	if err != nil {
 		return nil, err
@@ -402,6 +416,7 @@ func NewNode(ctx context.Context, cfg *repo.Config) (*OpenBazaarNode, error) {
 		Db:                   obRepo.DB(),
 		Multiwallet:          mw,
 		Messenger:            obNode.messenger,
+		BanManager:           bm,
 		EscrowPrivateKey:     escrowKey,
 		ExchangeRateProvider: erp,
 		EventBus:             bus,
@@ -570,6 +585,7 @@ func (n *OpenBazaarNode) registerHandlers() {
 	n.networkService.RegisterHandler(pb.Message_DISPUTE, n.handleDisputeMessage)
 	n.networkService.RegisterHandler(pb.Message_CHANNEL_REQUEST, n.handleChannelRequest)
 	n.networkService.RegisterHandler(pb.Message_CHANNEL_RESPONSE, n.handleChannelResponse)
+	n.networkService.RegisterHandler(pb.Message_CHANNEL_KEY, n.handleChannelKeyMessage)
 	n.networkService.RegisterHandler(pb.Message_DISPUTE, n.handleDisputeMessage)
 }
 
@@ -663,3 +679,66 @@ func newMessageWithID() *pb.Message {
 		MessageID: hex.EncodeToString(messageID),
 	}
 }
+
+// rateLimitConfig returns the rate limiter options from the config. Any
+// options that are not set use the defaults.
+func rateLimitConfig(cfg *repo.Config) obnet.RateLimitConfig {
+	rlCfg := obnet.DefaultRateLimitConfig()
+	if cfg.RateLimit > 0 {
+		rlCfg.MessagesPerSecond = cfg.RateLimit
+	}
+	if cfg.RateLimitBurst > 0 {
+		rlCfg.Burst = cfg.RateLimitBurst
+	}
+	if cfg.BanScore > 0 {
+		rlCfg.BanThreshold = cfg.BanScore
+	}
+	if cfg.BanDuration > 0 {
+		rlCfg.BanDuration = cfg.BanDuration
+	}
+	return rlCfg
+}
+
+// messageTTLConfig parses the message TTLs in the config, which are formatted
+// as TYPE:duration, into a map keyed by message type.
+func messageTTLConfig(cfg *repo.Config) (map[pb.Message_MessageType]time.Duration, error) {
+	ttls := make(map[pb.Message_MessageType]time.Duration)
+	for _, s := range cfg.MessageTTL {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid messagettl %s", s)
+		}
+		mt, ok := pb.Message_MessageType_value[strings.ToUpper(parts[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown message type %s in messagettl", parts[0])
+		}
+		ttl, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid messagettl duration %s: %s", parts[1], err)
+		}
+		ttls[pb.Message_MessageType(mt)] = ttl
+	}
+	return ttls, nil
+}
+
+// backupScheduleConfig returns the scheduled backup options from the config
+// or nil if scheduled backups are not enabled. The passphrase is read from
+// the environment so that it isn't stored in the config file.
+func backupScheduleConfig(cfg *repo.Config) (*backupSchedule, error) {
+	if cfg.BackupDir == "" {
+		return nil, nil
+	}
+	passphrase := os.Getenv(repo.BackupPassphraseEnv)
+	if passphrase == "" {
+		return nil, fmt.Errorf("%s must be set to enable scheduled backups", repo.BackupPassphraseEnv)
+	}
+	if cfg.BackupInterval <= 0 {
+		return nil, errors.New("backupinterval must be positive")
+	}
+	return &backupSchedule{
+		dir:        cfg.BackupDir,
+		interval:   cfg.BackupInterval,
+		keep:       cfg.BackupKeep,
+		passphrase: passphrase,
+	}, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/cpacia/openbazaar3.0/orders/utils"
 	iwallet "github.com/cpacia/wallet-interface"
 	"github.com/golang/protobuf/ptypes"
+	"gorm.io/gorm"
 )
 
 // CancelOrder is called only by the buyer and sends an ORDER_CANCEL message to the vendor
@@ -27,7 +28,9 @@ func (n *OpenBazaarNode) CancelOrder(orderID models.OrderID, done chan struct{})
 	err := n.repo.DB().View(func(tx database.Tx) error {
 		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
 	})
-	if err != nil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
 		return err
 	}
 
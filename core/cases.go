@@ -0,0 +1,186 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// GetCases returns summaries of all the dispute cases we are moderating that
+// match the query sorted by the time the dispute was opened, newest first.
+func (n *OpenBazaarNode) GetCases(query *models.CaseQuery) ([]models.CaseSummary, error) {
+	if query == nil {
+		query = &models.CaseQuery{}
+	}
+
+	var cases []models.Case
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Find(&cases).Error
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	summaries := make([]models.CaseSummary, 0, len(cases))
+	for i := range cases {
+		if !query.Matches(&cases[i]) {
+			continue
+		}
+		summary, err := models.NewCaseSummary(&cases[i])
+		if models.IsMessageNotExistError(err) {
+			// We have not received a contract for this case yet.
+			continue
+		} else if err != nil {
+			log.Errorf("Error building summary for case %s: %s", cases[i].ID, err)
+			continue
+		}
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Timestamp.Equal(summaries[j].Timestamp) {
+			return summaries[i].CaseID > summaries[j].CaseID
+		}
+		return summaries[i].Timestamp.After(summaries[j].Timestamp)
+	})
+
+	if query.OffsetID != "" {
+		found := false
+		for i, summary := range summaries {
+			if summary.CaseID == query.OffsetID {
+				summaries = summaries[i+1:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: offset case not found", coreiface.ErrNotFound)
+		}
+	}
+
+	if query.Limit > 0 && len(summaries) > query.Limit {
+		summaries = summaries[:query.Limit]
+	}
+	return summaries, nil
+}
+
+// GetCase returns the full view of the dispute case with the given ID. This
+// includes both parties' contracts, their validation errors and the state of
+// the escrow as seen by our wallet.
+func (n *OpenBazaarNode) GetCase(caseID models.OrderID) (*models.CaseView, error) {
+	var disputeCase models.Case
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", caseID.String()).First(&disputeCase).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: case not found", coreiface.ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+
+	summary, err := models.NewCaseSummary(&disputeCase)
+	if models.IsMessageNotExistError(err) {
+		// The case only contains a parked update.
+		return nil, fmt.Errorf("%w: case not found", coreiface.ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+
+	view := &models.CaseView{
+		CaseSummary: *summary,
+		Case:        &disputeCase,
+	}
+
+	m := jsonpb.Marshaler{EmitDefaults: true}
+
+	buyerContract, err := disputeCase.BuyerContractMessage()
+	if err == nil {
+		if view.BuyerContract, err = marshalProtoJSON(m, buyerContract); err != nil {
+			return nil, err
+		}
+	} else if !models.IsMessageNotExistError(err) {
+		return nil, err
+	}
+
+	vendorContract, err := disputeCase.VendorContractMessage()
+	if err == nil {
+		if view.VendorContract, err = marshalProtoJSON(m, vendorContract); err != nil {
+			return nil, err
+		}
+	} else if !models.IsMessageNotExistError(err) {
+		return nil, err
+	}
+
+	view.BuyerValidationErrors, err = disputeCase.ValidationErrors(models.RoleBuyer)
+	if err != nil {
+		return nil, err
+	}
+	view.VendorValidationErrors, err = disputeCase.ValidationErrors(models.RoleVendor)
+	if err != nil {
+		return nil, err
+	}
+
+	disputeClose, err := disputeCase.DisputeCloseMessage()
+	if err == nil {
+		if view.Resolution, err = marshalProtoJSON(m, disputeClose); err != nil {
+			return nil, err
+		}
+	} else if !models.IsMessageNotExistError(err) {
+		return nil, err
+	}
+
+	orderOpen, err := disputeCase.OrderOpenMessage()
+	if err != nil {
+		return nil, err
+	}
+	view.EscrowAddress = orderOpen.Payment.Address
+	view.FundingTransactions = []iwallet.Transaction{}
+
+	wallet, err := n.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
+	if err != nil {
+		// We can still show the case even if we don't support the coin.
+		log.Errorf("Error loading wallet for case %s: %s", caseID, err)
+		return view, nil
+	}
+	txs, err := wallet.GetAddressTransactions(iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CoinType(orderOpen.Payment.Coin)))
+	if err != nil {
+		return nil, err
+	}
+
+	balance := iwallet.NewAmount(0)
+	for _, tx := range txs {
+		funding := false
+		for _, to := range tx.To {
+			if to.Address.String() == orderOpen.Payment.Address {
+				balance = balance.Add(to.Amount)
+				funding = true
+			}
+		}
+		for _, from := range tx.From {
+			if from.Address.String() == orderOpen.Payment.Address {
+				balance = balance.Sub(from.Amount)
+			}
+		}
+		if funding {
+			view.FundingTransactions = append(view.FundingTransactions, tx)
+		}
+	}
+	view.EscrowBalance = balance.String()
+
+	return view, nil
+}
+
+func marshalProtoJSON(m jsonpb.Marshaler, message proto.Message) ([]byte, error) {
+	out, err := m.MarshalToString(message)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"testing"
+)
+
+func TestOpenBazaarNode_GetCases(t *testing.T) {
+	network, err := NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	orderID := openMockDispute(t, network)
+
+	cases, err := network.Nodes()[2].GetCases(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 1 {
+		t.Fatalf("Expected 1 case, got %d", len(cases))
+	}
+	if cases[0].CaseID != orderID {
+		t.Errorf("Expected case ID %s, got %s", orderID, cases[0].CaseID)
+	}
+	if !cases[0].Open {
+		t.Error("Expected case to be open")
+	}
+	if cases[0].BuyerID != network.Nodes()[1].Identity().Pretty() {
+		t.Errorf("Incorrect buyer ID %s", cases[0].BuyerID)
+	}
+	if cases[0].VendorID != network.Nodes()[0].Identity().Pretty() {
+		t.Errorf("Incorrect vendor ID %s", cases[0].VendorID)
+	}
+
+	closed := false
+	cases, err = network.Nodes()[2].GetCases(&models.CaseQuery{Open: &closed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 0 {
+		t.Errorf("Expected 0 closed cases, got %d", len(cases))
+	}
+
+	cases, err = network.Nodes()[2].GetCases(&models.CaseQuery{OffsetID: orderID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 0 {
+		t.Errorf("Expected 0 cases after offset, got %d", len(cases))
+	}
+
+	if _, err := network.Nodes()[2].GetCases(&models.CaseQuery{OffsetID: "abc"}); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found for missing offset, got %v", err)
+	}
+}
+
+func TestOpenBazaarNode_GetCase(t *testing.T) {
+	network, err := NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	orderID := openMockDispute(t, network)
+
+	if _, err := network.Nodes()[2].GetCase("abc"); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	view, err := network.Nodes()[2].GetCase(orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.CaseID != orderID {
+		t.Errorf("Expected case ID %s, got %s", orderID, view.CaseID)
+	}
+	if len(view.BuyerContract) == 0 {
+		t.Error("Buyer contract is empty")
+	}
+	if len(view.BuyerValidationErrors) != 0 {
+		t.Errorf("Unexpected buyer validation errors: %v", view.BuyerValidationErrors)
+	}
+	if view.EscrowAddress == "" {
+		t.Error("Escrow address is empty")
+	}
+	if view.EscrowBalance == "" || view.EscrowBalance == "0" {
+		t.Errorf("Incorrect escrow balance %s", view.EscrowBalance)
+	}
+	if len(view.FundingTransactions) != 1 {
+		t.Errorf("Expected 1 funding transaction, got %d", len(view.FundingTransactions))
+	}
+	if view.Resolution != nil {
+		t.Error("Expected nil resolution for open case")
+	}
+}
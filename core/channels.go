@@ -23,7 +23,7 @@ func (n *OpenBazaarNode) OpenChannel(topic string) error {
 		return fmt.Errorf("%w: channel already open", coreiface.ErrBadRequest)
 	}
 
-	ch, err := channels.NewChannel(topic, n.ipfsNode, n.networkService, n.eventBus, n.repo.DB())
+	ch, err := channels.NewChannel(topic, n.ipfsNode, n.networkService, n.banManager, n.eventBus, n.repo.DB(), n.validateChannelListing)
 	if err != nil {
 		return fmt.Errorf("%w: %s", coreiface.ErrInternalServer, err)
 	}
@@ -114,13 +114,27 @@ func (n *OpenBazaarNode) OpenSavedChannels() error {
 	return nil
 }
 
-// PublishChannelMessage publishes a message to the given channel.
-func (n *OpenBazaarNode) PublishChannelMessage(ctx context.Context, topic, message string) error {
+// PublishChannelMessage publishes a message to the given channel. The message
+// may be a reply, a reaction, or embed a listing. Embedded listings must
+// exist on the network.
+func (n *OpenBazaarNode) PublishChannelMessage(ctx context.Context, topic string, content models.ChannelMessageContent) error {
 	ch, ok := n.channels[topic]
 	if !ok {
 		return fmt.Errorf("%w: channel not open", coreiface.ErrBadRequest)
 	}
-	return ch.Publish(ctx, message)
+	if err := channels.ValidateContent(&content); err != nil {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+	if content.Listing != nil {
+		vendor, err := peer.Decode(content.Listing.VendorID)
+		if err != nil {
+			return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+		}
+		if err := n.validateChannelListing(ctx, vendor, content.Listing.Slug); err != nil {
+			return fmt.Errorf("%w: listing not found: %s", coreiface.ErrNotFound, err)
+		}
+	}
+	return ch.Publish(ctx, content)
 }
 
 // GetChannelMessages returns the messages in the channel.
@@ -132,8 +146,120 @@ func (n *OpenBazaarNode) GetChannelMessages(ctx context.Context, topic string, f
 	return ch.Messages(ctx, from, limit)
 }
 
+// GetChannelPolicy returns the moderation policy for the channel.
+func (n *OpenBazaarNode) GetChannelPolicy(topic string) (*models.ChannelPolicy, error) {
+	var policy *models.ChannelPolicy
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		var err error
+		policy, err = channels.LoadPolicy(tx, topic)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// SetChannelOwner sets the peer whose signed policies and deletions are
+// honored in the channel. Any policy published by the previous owner is
+// discarded.
+func (n *OpenBazaarNode) SetChannelOwner(topic string, owner peer.ID) error {
+	return n.updateChannelPolicy(topic, func(policy *models.ChannelPolicy) error {
+		if policy.Owner != owner.Pretty() {
+			policy.Owner = owner.Pretty()
+			policy.Published = models.ChannelOwnerPolicy{}
+		}
+		return nil
+	})
+}
+
+// SetChannelRateLimit sets the maximum number of messages per minute we
+// will accept from each peer in the channel. Zero removes the limit.
+func (n *OpenBazaarNode) SetChannelRateLimit(topic string, messagesPerMinute int) error {
+	if messagesPerMinute < 0 {
+		return fmt.Errorf("%w: rate limit may not be negative", coreiface.ErrBadRequest)
+	}
+	return n.updateChannelPolicy(topic, func(policy *models.ChannelPolicy) error {
+		policy.RateLimit = messagesPerMinute
+		return nil
+	})
+}
+
+// MuteChannelPeer hides the peer's messages in the channel.
+func (n *OpenBazaarNode) MuteChannelPeer(topic string, peerID peer.ID) error {
+	return n.updateChannelPolicy(topic, func(policy *models.ChannelPolicy) error {
+		for _, p := range policy.Muted {
+			if p == peerID.Pretty() {
+				return nil
+			}
+		}
+		policy.Muted = append(policy.Muted, peerID.Pretty())
+		return nil
+	})
+}
+
+// UnmuteChannelPeer shows the peer's messages in the channel again.
+func (n *OpenBazaarNode) UnmuteChannelPeer(topic string, peerID peer.ID) error {
+	return n.updateChannelPolicy(topic, func(policy *models.ChannelPolicy) error {
+		for i, p := range policy.Muted {
+			if p == peerID.Pretty() {
+				policy.Muted = append(policy.Muted[:i], policy.Muted[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: peer is not muted", coreiface.ErrNotFound)
+	})
+}
+
+// PublishChannelPolicy signs and publishes a new owner policy to the channel.
+// If the channel has no owner this node becomes the owner.
+func (n *OpenBazaarNode) PublishChannelPolicy(ctx context.Context, topic string, policy models.ChannelOwnerPolicy) error {
+	ch, ok := n.channels[topic]
+	if !ok {
+		return fmt.Errorf("%w: channel not open", coreiface.ErrBadRequest)
+	}
+	if err := ch.PublishPolicy(ctx, policy); err != nil {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+	return nil
+}
+
+// DeleteChannelMessages publishes a signed deletion marker for the messages.
+// Only the channel owner may delete messages.
+func (n *OpenBazaarNode) DeleteChannelMessages(ctx context.Context, topic string, ids []cid.Cid) error {
+	ch, ok := n.channels[topic]
+	if !ok {
+		return fmt.Errorf("%w: channel not open", coreiface.ErrBadRequest)
+	}
+	if err := ch.DeleteMessages(ctx, ids); err != nil {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+	return nil
+}
+
+// validateChannelListing is the channels.ListingValidator used to check
+// that listings embedded in channel messages exist.
+func (n *OpenBazaarNode) validateChannelListing(ctx context.Context, vendor peer.ID, slug string) error {
+	_, err := n.GetListingBySlug(ctx, vendor, slug, true)
+	return err
+}
+
+func (n *OpenBazaarNode) updateChannelPolicy(topic string, fn func(policy *models.ChannelPolicy) error) error {
+	return n.repo.DB().Update(func(tx database.Tx) error {
+		policy, err := channels.LoadPolicy(tx, topic)
+		if err != nil {
+			return err
+		}
+		if err := fn(policy); err != nil {
+			return err
+		}
+		return channels.SavePolicy(tx, policy)
+	})
+}
+
 // handleChannelRequest is the handler for the CHANNEL_REQUEST message. It responds to
-// request with an CHANNEL_RESPONSE message using an online message.
+// request with an CHANNEL_RESPONSE message using an online message. Requests for
+// private channels are only answered for members.
 func (n *OpenBazaarNode) handleChannelRequest(from peer.ID, message *pb.Message) error {
 	if message.MessageType != pb.Message_CHANNEL_REQUEST {
 		return errors.New("message is not type CHANNEL_REQUEST")
@@ -146,6 +272,13 @@ func (n *OpenBazaarNode) handleChannelRequest(from peer.ID, message *pb.Message)
 
 	var channelRec models.Channel
 	err := n.repo.DB().View(func(tx database.Tx) error {
+		group, err := channels.LoadPrivateChannel(tx, req.Topic)
+		if err != nil {
+			return err
+		}
+		if group != nil && !group.IsMember(from.Pretty()) {
+			return channels.ErrNotMember
+		}
 		return tx.Read().Where("topic=?", req.Topic).First(&channelRec).Error
 	})
 	if err != nil {
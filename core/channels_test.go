@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"testing"
+	"time"
+)
+
+func TestOpenBazaarNode_ChannelPolicy(t *testing.T) {
+	network, err := NewMocknet(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer network.TearDown()
+
+	var (
+		node  = network.Nodes()[0]
+		other = network.Nodes()[1].Identity()
+	)
+
+	if err := node.SetChannelOwner("General", other); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.SetChannelRateLimit("general", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.SetChannelRateLimit("general", -1); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest got %v", err)
+	}
+	if err := node.MuteChannelPeer("general", other); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.MuteChannelPeer("general", other); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := node.GetChannelPolicy("general")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.Owner != other.Pretty() {
+		t.Errorf("Expected owner %s got %s", other.Pretty(), policy.Owner)
+	}
+	if policy.RateLimit != 10 {
+		t.Errorf("Expected rate limit 10 got %d", policy.RateLimit)
+	}
+	if len(policy.Muted) != 1 || policy.Muted[0] != other.Pretty() {
+		t.Errorf("Expected muted peer %s got %v", other.Pretty(), policy.Muted)
+	}
+
+	if err := node.UnmuteChannelPeer("general", other); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.UnmuteChannelPeer("general", other); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+	policy, err = node.GetChannelPolicy("general")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Muted) != 0 {
+		t.Errorf("Expected no muted peers got %v", policy.Muted)
+	}
+
+	if err := node.PublishChannelPolicy(context.Background(), "general", policy.Published); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for unopened channel got %v", err)
+	}
+}
+
+func TestOpenBazaarNode_PrivateChannel(t *testing.T) {
+	network, err := NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer network.TearDown()
+
+	var (
+		owner    = network.Nodes()[0]
+		member   = network.Nodes()[1]
+		outsider = network.Nodes()[2]
+	)
+
+	memberSub, err := member.eventBus.Subscribe(&events.PrivateChannelUpdated{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outsiderSub, err := outsider.eventBus.Subscribe(&events.PrivateChannelUpdated{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := owner.CreatePrivateChannel("wholesale", []peer.ID{member.Identity()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := owner.CreatePrivateChannel("wholesale", nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest got %v", err)
+	}
+
+	select {
+	case event := <-memberSub.Out():
+		update := event.(*events.PrivateChannelUpdated)
+		if update.Epoch != 1 || update.Owner != owner.Identity().Pretty() || len(update.Members) != 2 {
+			t.Errorf("Incorrect update %v", update)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel key")
+	}
+
+	group, err := member.GetPrivateChannel("wholesale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !group.IsMember(member.Identity().Pretty()) || !group.IsMember(owner.Identity().Pretty()) {
+		t.Errorf("Incorrect members %v", group.Members)
+	}
+
+	if err := member.UpdatePrivateChannelMembers("wholesale", []peer.ID{outsider.Identity()}, nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest got %v", err)
+	}
+	if err := owner.UpdatePrivateChannelMembers("wholesale", nil, []peer.ID{owner.Identity()}); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest got %v", err)
+	}
+	if err := owner.UpdatePrivateChannelMembers("other", nil, nil); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+
+	if err := owner.UpdatePrivateChannelMembers("wholesale", []peer.ID{outsider.Identity()}, []peer.ID{member.Identity()}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-outsiderSub.Out():
+		if update := event.(*events.PrivateChannelUpdated); update.Epoch != 2 {
+			t.Errorf("Expected epoch 2 got %d", update.Epoch)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel key")
+	}
+
+	group, err = owner.GetPrivateChannel("wholesale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Epoch != 2 || group.IsMember(member.Identity().Pretty()) || !group.IsMember(outsider.Identity().Pretty()) {
+		t.Errorf("Incorrect membership after rotation %v", group)
+	}
+
+	select {
+	case event := <-memberSub.Out():
+		t.Errorf("Removed member received key %v", event)
+	case <-time.After(time.Second):
+	}
+
+	if _, err := outsider.GetPrivateChannel("unknown"); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+}
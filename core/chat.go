@@ -3,13 +3,18 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/net"
 	"github.com/cpacia/openbazaar3.0/net/pb"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/ipfs/go-cid"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
@@ -23,6 +28,13 @@ const (
 // not block during the send. The done chan will be closed when the sending is
 // complete if you need this information.
 func (n *OpenBazaarNode) SendChatMessage(to peer.ID, message string, orderID models.OrderID, done chan<- struct{}) error {
+	return n.SendChatMessageWithAttachments(to, message, orderID, nil, done)
+}
+
+// SendChatMessageWithAttachments sends a chat message with the given attachments
+// to the peer. The attachments must have first been added with AddChatAttachment.
+// The decryption key for each attachment is sent inside the chat message.
+func (n *OpenBazaarNode) SendChatMessageWithAttachments(to peer.ID, message string, orderID models.OrderID, attachments []cid.Cid, done chan<- struct{}) error {
 	if len(orderID.String()) > maxOrderIDLength {
 		return errors.New("orderID is too long")
 	}
@@ -37,19 +49,27 @@ func (n *OpenBazaarNode) SendChatMessage(to peer.ID, message string, orderID mod
 		Flag:      pb.ChatMessage_MESSAGE,
 	}
 
-	payload, err := ptypes.MarshalAny(&chatMsg)
-	if err != nil {
-		maybeCloseDone(done)
-		return err
-	}
-
-	err = n.repo.DB().Update(func(tx database.Tx) error {
+	err := n.repo.DB().Update(func(tx database.Tx) error {
 		var prev models.ChatMessage
 		if err := tx.Read().Order("timestamp desc").Where("peer_id = ? AND outgoing = ?", to.Pretty(), true).Last(&prev).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return err
 		}
 
 		msg := newMessageWithID()
+
+		chatAttachments, err := loadChatAttachments(tx, msg.MessageID, attachments)
+		if err != nil {
+			return err
+		}
+		for _, attachment := range chatAttachments {
+			chatMsg.Attachments = append(chatMsg.Attachments, attachment.ToProto())
+		}
+
+		payload, err := ptypes.MarshalAny(&chatMsg)
+		if err != nil {
+			return err
+		}
+
 		msg.MessageType = pb.Message_CHAT
 		msg.Payload = payload
 		msg.Sequence = uint32(prev.Sequence + 1)
@@ -64,6 +84,9 @@ func (n *OpenBazaarNode) SendChatMessage(to peer.ID, message string, orderID mod
 		if err := tx.Save(chatModel); err != nil {
 			return err
 		}
+		if err := saveChatAttachments(tx, chatAttachments); err != nil {
+			return err
+		}
 
 		log.Debugf("Sending CHAT message to %s. MessageID: %s", to, msg.MessageID)
 		return n.messenger.ReliablySendMessage(tx, to, msg, done)
@@ -174,6 +197,9 @@ func (n *OpenBazaarNode) GetChatConversations() ([]models.ChatConversation, erro
 		}
 
 		for _, peer := range ids {
+			if n.isBlocked(peer) {
+				continue
+			}
 			var message models.ChatMessage
 			if err := tx.Read().Order("timestamp desc").Where("peer_id = ?", peer).Last(&message).Error; err != nil {
 				return err
@@ -211,9 +237,15 @@ func (n *OpenBazaarNode) GetChatMessagesByPeer(peer peer.ID, limit int, offsetID
 			if err != nil {
 				return err
 			}
-			return tx.Read().Where("peer_id = ?", peer.Pretty()).Where("timestamp < ?", message.Timestamp).Where("order_id = ?", "").Limit(limit).Order("timestamp desc").Find(&messages).Error
+			if err := tx.Read().Where("peer_id = ?", peer.Pretty()).Where("timestamp < ?", message.Timestamp).Where("order_id = ?", "").Limit(limit).Order("timestamp desc").Find(&messages).Error; err != nil {
+				return err
+			}
+			return populateChatAttachments(tx, messages)
 		}
-		return tx.Read().Where("peer_id = ?", peer.Pretty()).Where("order_id = ?", "").Limit(limit).Order("timestamp desc").Find(&messages).Error
+		if err := tx.Read().Where("peer_id = ?", peer.Pretty()).Where("order_id = ?", "").Limit(limit).Order("timestamp desc").Find(&messages).Error; err != nil {
+			return err
+		}
+		return populateChatAttachments(tx, messages)
 	})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
@@ -231,9 +263,15 @@ func (n *OpenBazaarNode) GetChatMessagesByOrderID(orderID models.OrderID, limit
 			if err != nil {
 				return err
 			}
-			return tx.Read().Where("order_id = ?", orderID.String()).Where("timestamp < ?", message.Timestamp).Order("timestamp desc").Limit(limit).Find(&messages).Error
+			if err := tx.Read().Where("order_id = ?", orderID.String()).Where("timestamp < ?", message.Timestamp).Order("timestamp desc").Limit(limit).Find(&messages).Error; err != nil {
+				return err
+			}
+			return populateChatAttachments(tx, messages)
 		}
-		return tx.Read().Where("order_id = ?", orderID.String()).Order("timestamp desc").Limit(limit).Find(&messages).Error
+		if err := tx.Read().Where("order_id = ?", orderID.String()).Order("timestamp desc").Limit(limit).Find(&messages).Error; err != nil {
+			return err
+		}
+		return populateChatAttachments(tx, messages)
 	})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
@@ -241,9 +279,44 @@ func (n *OpenBazaarNode) GetChatMessagesByOrderID(orderID models.OrderID, limit
 	return messages, nil
 }
 
-// DeleteChatMessage deletes the message with the provided ID.
+// SearchChatMessages returns the chat messages matching the full-text search
+// query ordered by relevance.
+func (n *OpenBazaarNode) SearchChatMessages(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error) {
+	if strings.TrimSpace(query.Query) == "" {
+		return nil, fmt.Errorf("%w: search query is empty", coreiface.ErrBadRequest)
+	}
+	var results []models.ChatSearchResult
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		var err error
+		results, err = tx.SearchChatMessages(query)
+		if err != nil {
+			return err
+		}
+		messages := make([]models.ChatMessage, 0, len(results))
+		for _, result := range results {
+			messages = append(messages, result.ChatMessage)
+		}
+		if err := populateChatAttachments(tx, messages); err != nil {
+			return err
+		}
+		for i := range results {
+			results[i].Attachments = messages[i].Attachments
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DeleteChatMessage deletes the message with the provided ID. Any attachments
+// which are no longer referenced by another message are unpinned.
 func (n *OpenBazaarNode) DeleteChatMessage(messageID string) error {
 	return n.repo.DB().Update(func(tx database.Tx) error {
+		if err := n.deleteChatAttachments(tx, []string{messageID}); err != nil {
+			return err
+		}
 		return tx.Delete("message_id", messageID, nil, &models.ChatMessage{})
 	})
 }
@@ -251,6 +324,13 @@ func (n *OpenBazaarNode) DeleteChatMessage(messageID string) error {
 // DeleteChatConversation deletes all messages from the given peerID.
 func (n *OpenBazaarNode) DeleteChatConversation(peerID peer.ID) error {
 	return n.repo.DB().Update(func(tx database.Tx) error {
+		var messageIDs []string
+		if err := tx.Read().Model(&models.ChatMessage{}).Where("peer_id = ? AND order_id = ?", peerID.Pretty(), "").Pluck("message_id", &messageIDs).Error; err != nil {
+			return err
+		}
+		if err := n.deleteChatAttachments(tx, messageIDs); err != nil {
+			return err
+		}
 		return tx.Delete("peer_id", peerID.Pretty(), map[string]interface{}{"order_id = ?": ""}, &models.ChatMessage{})
 	})
 }
@@ -258,6 +338,13 @@ func (n *OpenBazaarNode) DeleteChatConversation(peerID peer.ID) error {
 // DeleteGroupChatMessages deletes all messages from for the given orderID.
 func (n *OpenBazaarNode) DeleteGroupChatMessages(orderID models.OrderID) error {
 	return n.repo.DB().Update(func(tx database.Tx) error {
+		var messageIDs []string
+		if err := tx.Read().Model(&models.ChatMessage{}).Where("order_id = ?", orderID.String()).Pluck("message_id", &messageIDs).Error; err != nil {
+			return err
+		}
+		if err := n.deleteChatAttachments(tx, messageIDs); err != nil {
+			return err
+		}
 		return tx.Delete("order_id", orderID.String(), nil, &models.ChatMessage{})
 	})
 }
@@ -271,6 +358,7 @@ func (n *OpenBazaarNode) handleChatMessage(from peer.ID, message *pb.Message) er
 
 	chatMsg := new(pb.ChatMessage)
 	if err := ptypes.UnmarshalAny(message.Payload, chatMsg); err != nil {
+		n.networkService.Misbehaving(from, net.ScoreMalformedMessage, "malformed chat message")
 		return err
 	}
 
@@ -282,9 +370,16 @@ func (n *OpenBazaarNode) handleChatMessage(from peer.ID, message *pb.Message) er
 		if err != nil {
 			return err
 		}
+		if err := validateChatAttachments(incomingMsg.Attachments); err != nil {
+			n.networkService.Misbehaving(from, net.ScoreMalformedMessage, "invalid chat attachment")
+			return err
+		}
 		err = n.repo.DB().Update(func(tx database.Tx) error {
 			// Save the incoming message to the DB
-			return tx.Save(incomingMsg)
+			if err := tx.Save(incomingMsg); err != nil {
+				return err
+			}
+			return saveChatAttachments(tx, incomingMsg.Attachments)
 		})
 		if err != nil {
 			return err
@@ -0,0 +1,356 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/interface-go-ipfs-core/path"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	gopath "path"
+	"strings"
+	"time"
+)
+
+const (
+	// maxAttachmentSize is the maximum size of a chat attachment
+	// before encryption.
+	maxAttachmentSize = 10 << 20
+
+	// maxAttachmentsPerMessage is the maximum number of attachments
+	// that can be sent in a single chat message.
+	maxAttachmentsPerMessage = 5
+
+	// maxAttachmentCiphertextSize is the maximum size of an encrypted
+	// chat attachment. AES-GCM adds a 12 byte nonce and a 16 byte tag.
+	maxAttachmentCiphertextSize = maxAttachmentSize + 12 + 16
+
+	attachmentKeySize = 32
+
+	// pendingAttachmentTTL is how long an uploaded attachment is kept if
+	// it is never sent with a message.
+	pendingAttachmentTTL = time.Hour * 24
+
+	// pendingAttachmentCleanupInterval is how often we look for expired
+	// uploads.
+	pendingAttachmentCleanupInterval = time.Hour
+)
+
+// allowedAttachmentTypes are the sniffed MIME types which we allow to be
+// sent as chat attachments.
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":                true,
+	"image/png":                 true,
+	"image/gif":                 true,
+	"image/webp":                true,
+	"application/pdf":           true,
+	"text/plain; charset=utf-8": true,
+}
+
+// AddChatAttachment encrypts the file with a random key and adds it to IPFS.
+// The returned attachment can then be sent by passing its CID into
+// SendChatMessageWithAttachments. The file stays pinned until all the chat
+// messages it is attached to are deleted. If it is not sent within
+// pendingAttachmentTTL it is deleted and unpinned.
+func (n *OpenBazaarNode) AddChatAttachment(filename string, data []byte) (*models.ChatAttachment, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: attachment is empty", coreiface.ErrBadRequest)
+	}
+	if len(data) > maxAttachmentSize {
+		return nil, fmt.Errorf("%w: attachment exceeds max size of %d bytes", coreiface.ErrBadRequest, maxAttachmentSize)
+	}
+	filename = gopath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if filename == "." || filename == "/" || len(filename) > FilenameMaxCharacters {
+		return nil, fmt.Errorf("%w: invalid filename", coreiface.ErrBadRequest)
+	}
+	mimeType := http.DetectContentType(data)
+	if !allowedAttachmentTypes[mimeType] {
+		return nil, fmt.Errorf("%w: attachment type %s is not allowed", coreiface.ErrBadRequest, mimeType)
+	}
+
+	key := make([]byte, attachmentKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	ciphertext, err := encryptAttachment(key, data)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := n.addBytes(context.Background(), ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.ChatAttachment{
+		CID:        id.String(),
+		Key:        key,
+		Filename:   filename,
+		MimeType:   mimeType,
+		Size:       int64(len(data)),
+		UploadedAt: time.Now(),
+	}
+	err = n.repo.DB().Update(func(tx database.Tx) error {
+		return tx.Save(attachment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// GetChatAttachment loads the attachment with the given CID from the network
+// or cache and returns it along with a reader to the decrypted file.
+// Attachments are not fetched when a message is received. Instead they are
+// pinned here once they have been downloaded and decrypted so that they
+// remain available after the sender goes offline.
+func (n *OpenBazaarNode) GetChatAttachment(ctx context.Context, id cid.Cid) (*models.ChatAttachment, io.ReadSeeker, error) {
+	var attachments []models.ChatAttachment
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("cid = ?", id.String()).Find(&attachments).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, nil, fmt.Errorf("%w: attachment not found", coreiface.ErrNotFound)
+	}
+
+	ciphertext, err := n.catLimited(ctx, path.IpfsPath(id), maxAttachmentCiphertextSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The same file may have been attached to messages from different
+	// peers so we try each of the keys we were given for it.
+	for _, attachment := range attachments {
+		plaintext, err := decryptAttachment(attachment.Key, ciphertext)
+		if err != nil {
+			continue
+		}
+		if err := n.pin(context.Background(), path.IpfsPath(id)); err != nil {
+			log.Errorf("Error pinning chat attachment %s: %s", id, err)
+		}
+		return &attachment, bytes.NewReader(plaintext), nil
+	}
+	return nil, nil, errors.New("error decrypting attachment")
+}
+
+// loadChatAttachments loads the attachments with the given CIDs so they
+// can be attached to the message with the given ID.
+func loadChatAttachments(tx database.Tx, messageID string, ids []cid.Cid) ([]models.ChatAttachment, error) {
+	if len(ids) > maxAttachmentsPerMessage {
+		return nil, fmt.Errorf("%w: too many attachments", coreiface.ErrBadRequest)
+	}
+	attachments := make([]models.ChatAttachment, 0, len(ids))
+	for _, id := range ids {
+		var attachment models.ChatAttachment
+		if err := tx.Read().Where("cid = ?", id.String()).First(&attachment).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: attachment %s not found", coreiface.ErrNotFound, id)
+		} else if err != nil {
+			return nil, err
+		}
+		attachment.MessageID = messageID
+		attachment.UploadedAt = time.Time{}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// saveChatAttachments saves the attachments of the message. Any matching
+// attachments which were uploaded but not yet sent are removed as they are
+// now referenced by the message.
+func saveChatAttachments(tx database.Tx, attachments []models.ChatAttachment) error {
+	for _, attachment := range attachments {
+		if err := tx.Delete("cid", attachment.CID, map[string]interface{}{"message_id = ?": ""}, &models.ChatAttachment{}); err != nil {
+			return err
+		}
+		a := attachment
+		if err := tx.Save(&a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// populateChatAttachments loads the attachments for each of the messages.
+func populateChatAttachments(tx database.Tx, messages []models.ChatMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.MessageID)
+	}
+	var attachments []models.ChatAttachment
+	if err := tx.Read().Where("message_id IN ?", ids).Find(&attachments).Error; err != nil {
+		return err
+	}
+	byMessage := make(map[string][]models.ChatAttachment)
+	for _, attachment := range attachments {
+		byMessage[attachment.MessageID] = append(byMessage[attachment.MessageID], attachment)
+	}
+	for i := range messages {
+		messages[i].Attachments = byMessage[messages[i].MessageID]
+	}
+	return nil
+}
+
+// deleteChatAttachments deletes the attachments of the given messages. Once
+// the transaction commits, any files which are no longer attached to a
+// message are unpinned.
+func (n *OpenBazaarNode) deleteChatAttachments(tx database.Tx, messageIDs []string) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	var attachments []models.ChatAttachment
+	if err := tx.Read().Where("message_id IN ?", messageIDs).Find(&attachments).Error; err != nil {
+		return err
+	}
+	for _, messageID := range messageIDs {
+		if err := tx.Delete("message_id", messageID, nil, &models.ChatAttachment{}); err != nil {
+			return err
+		}
+	}
+
+	return n.unpinUnusedChatAttachments(tx, attachments)
+}
+
+// pendingChatAttachmentLoop deletes uploaded attachments which were never
+// sent until the node shuts down.
+func (n *OpenBazaarNode) pendingChatAttachmentLoop() {
+	ticker := time.NewTicker(pendingAttachmentCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := n.deletePendingChatAttachments(time.Now().Add(-pendingAttachmentTTL)); err != nil {
+				log.Errorf("Error deleting pending chat attachments: %s", err)
+			}
+		case <-n.shutdown:
+			return
+		}
+	}
+}
+
+// deletePendingChatAttachments deletes the attachments which were uploaded
+// before the cutoff but never sent with a message.
+func (n *OpenBazaarNode) deletePendingChatAttachments(cutoff time.Time) error {
+	return n.repo.DB().Update(func(tx database.Tx) error {
+		var attachments []models.ChatAttachment
+		if err := tx.Read().Where("message_id = ? AND uploaded_at < ?", "", cutoff).Find(&attachments).Error; err != nil {
+			return err
+		}
+		for _, attachment := range attachments {
+			if err := tx.Delete("cid", attachment.CID, map[string]interface{}{"message_id = ?": ""}, &models.ChatAttachment{}); err != nil {
+				return err
+			}
+		}
+		return n.unpinUnusedChatAttachments(tx, attachments)
+	})
+}
+
+// unpinUnusedChatAttachments unpins the files of the deleted attachments
+// which are no longer attached to a message once the transaction commits.
+func (n *OpenBazaarNode) unpinUnusedChatAttachments(tx database.Tx, attachments []models.ChatAttachment) error {
+	var (
+		unused []cid.Cid
+		seen   = make(map[string]bool)
+	)
+	for _, attachment := range attachments {
+		if seen[attachment.CID] {
+			continue
+		}
+		seen[attachment.CID] = true
+
+		var count int64
+		if err := tx.Read().Model(&models.ChatAttachment{}).Where("cid = ?", attachment.CID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		id, err := cid.Decode(attachment.CID)
+		if err != nil {
+			continue
+		}
+		unused = append(unused, id)
+	}
+
+	if len(unused) > 0 {
+		tx.RegisterCommitHook(func() {
+			for _, id := range unused {
+				if err := n.unpin(context.Background(), path.IpfsPath(id)); err != nil {
+					log.Errorf("Error unpinning chat attachment %s: %s", id, err)
+				}
+			}
+		})
+	}
+	return nil
+}
+
+// validateChatAttachments checks that the attachments of an incoming message
+// are well formed and within the size limits.
+func validateChatAttachments(attachments []models.ChatAttachment) error {
+	if len(attachments) > maxAttachmentsPerMessage {
+		return errors.New("too many attachments")
+	}
+	for _, attachment := range attachments {
+		if _, err := cid.Decode(attachment.CID); err != nil {
+			return fmt.Errorf("invalid attachment cid: %s", err)
+		}
+		if len(attachment.Key) != attachmentKeySize {
+			return errors.New("invalid attachment key")
+		}
+		if attachment.Size <= 0 || attachment.Size > maxAttachmentSize {
+			return errors.New("invalid attachment size")
+		}
+		if len(attachment.Filename) > FilenameMaxCharacters {
+			return errors.New("attachment filename is too long")
+		}
+	}
+	return nil
+}
+
+// encryptAttachment encrypts the data with AES-GCM using the provided key.
+// The nonce is prepended to the ciphertext.
+func encryptAttachment(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// decryptAttachment decrypts ciphertext created by encryptAttachment.
+func decryptAttachment(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("attachment ciphertext is too short")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
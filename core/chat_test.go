@@ -1,10 +1,20 @@
 package core
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-ipfs/core/coreapi"
+	"github.com/ipfs/interface-go-ipfs-core/path"
 	peer "github.com/libp2p/go-libp2p-core/peer"
+	"image"
+	"image/png"
+	"io/ioutil"
 	"testing"
 	"time"
 )
@@ -491,3 +501,226 @@ func TestOpenBazaarNode_ChatSequence(t *testing.T) {
 		}
 	}
 }
+
+func TestOpenBazaarNode_SearchChatMessages(t *testing.T) {
+	network, err := NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	if err := network.Nodes()[0].SendChatMessage(network.Nodes()[1].Identity(), "is the blue shirt available?", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := network.Nodes()[0].SendChatMessage(network.Nodes()[2].Identity(), "I'll take the blue one", "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := network.Nodes()[0].SearchChatMessages(&models.ChatSearchQuery{Query: "blue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results got %d", len(results))
+	}
+
+	results, err = network.Nodes()[0].SearchChatMessages(&models.ChatSearchQuery{Query: "blue", PeerID: network.Nodes()[2].Identity().Pretty()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result got %d", len(results))
+	}
+	if results[0].Message != "I'll take the blue one" {
+		t.Errorf("Incorrect message. Expected %s, got %s", "I'll take the blue one", results[0].Message)
+	}
+
+	if _, err := network.Nodes()[0].SearchChatMessages(&models.ChatSearchQuery{}); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest got %v", err)
+	}
+}
+
+func TestOpenBazaarNode_ChatAttachments(t *testing.T) {
+	network, err := NewMocknet(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 10, 10))); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := network.Nodes()[0].AddChatAttachment("page.html", []byte("<html><body>hi</body></html>")); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for html attachment, got %v", err)
+	}
+	if _, err := network.Nodes()[0].AddChatAttachment("big.png", make([]byte, maxAttachmentSize+1)); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for oversize attachment, got %v", err)
+	}
+
+	attachment, err := network.Nodes()[0].AddChatAttachment("../damaged.png", buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.Filename != "damaged.png" {
+		t.Errorf("Expected filename damaged.png, got %s", attachment.Filename)
+	}
+	if attachment.MimeType != "image/png" {
+		t.Errorf("Expected MIME type image/png, got %s", attachment.MimeType)
+	}
+
+	id, err := cid.Decode(attachment.CID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := network.Nodes()[1].eventBus.Subscribe(&events.ChatMessage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.Nodes()[0].SendChatMessageWithAttachments(network.Nodes()[1].Identity(), "see attached", "", []cid.Cid{id}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var event interface{}
+	select {
+	case event = <-sub.Out():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+	notif, ok := event.(*events.ChatMessage)
+	if !ok {
+		t.Fatal("Failed to type assert ChatMessageNotification")
+	}
+	if len(notif.Attachments) != 1 || notif.Attachments[0].CID != attachment.CID {
+		t.Fatalf("Received incorrect attachments: %v", notif.Attachments)
+	}
+
+	recipientAPI, err := coreapi.NewCoreAPI(network.Nodes()[1].ipfsNode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, pinned, err := recipientAPI.Pin().IsPinned(context.Background(), path.IpfsPath(id)); err != nil {
+		t.Fatal(err)
+	} else if pinned {
+		t.Error("Attachment pinned before it was downloaded")
+	}
+
+	_, reader, err := network.Nodes()[1].GetChatAttachment(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, pinned, err := recipientAPI.Pin().IsPinned(context.Background(), path.IpfsPath(id)); err != nil {
+		t.Fatal(err)
+	} else if !pinned {
+		t.Error("Attachment not pinned after it was downloaded")
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, buf.Bytes()) {
+		t.Error("Decrypted attachment does not match original")
+	}
+
+	messages, err := network.Nodes()[0].GetChatMessagesByPeer(network.Nodes()[1].Identity(), -1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || len(messages[0].Attachments) != 1 {
+		t.Fatalf("Expected one message with one attachment, got %v", messages)
+	}
+
+	if err := network.Nodes()[0].DeleteChatMessage(messages[0].MessageID); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	err = network.Nodes()[0].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Model(&models.ChatAttachment{}).Count(&count).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Expected attachments to be deleted, got %d", count)
+	}
+
+	api, err := coreapi.NewCoreAPI(network.Nodes()[0].ipfsNode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, pinned, err := api.Pin().IsPinned(context.Background(), path.IpfsPath(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned {
+		t.Error("Attachment still pinned after message was deleted")
+	}
+
+	// Uploads which are never sent are removed once they expire.
+	pending, err := network.Nodes()[0].AddChatAttachment("notes.txt", []byte("never sent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingID, err := cid.Decode(pending.CID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := network.Nodes()[0].deletePendingChatAttachments(time.Now().Add(-pendingAttachmentTTL)); err != nil {
+		t.Fatal(err)
+	}
+	if _, pinned, err := api.Pin().IsPinned(context.Background(), path.IpfsPath(pendingID)); err != nil {
+		t.Fatal(err)
+	} else if !pinned {
+		t.Error("Pending attachment unpinned before it expired")
+	}
+
+	if err := network.Nodes()[0].deletePendingChatAttachments(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	err = network.Nodes()[0].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Model(&models.ChatAttachment{}).Count(&count).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Expected expired attachment to be deleted, got %d", count)
+	}
+	if _, pinned, err := api.Pin().IsPinned(context.Background(), path.IpfsPath(pendingID)); err != nil {
+		t.Fatal(err)
+	} else if pinned {
+		t.Error("Expired attachment still pinned")
+	}
+}
+
+func TestEncryptDecryptAttachment(t *testing.T) {
+	key := make([]byte, attachmentKeySize)
+	plaintext := []byte("tracking label")
+
+	ciphertext, err := encryptAttachment(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Error("Ciphertext contains plaintext")
+	}
+
+	decrypted, err := decryptAttachment(key, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Expected %s, got %s", plaintext, decrypted)
+	}
+
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	if _, err := decryptAttachment(key, ciphertext); err == nil {
+		t.Error("Expected error decrypting modified ciphertext")
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/libp2p/go-libp2p-core/crypto"
+	"gorm.io/gorm"
 )
 
 // CompleteOrder builds a OrderComplete message and sends it to the vendor. The ratings slice must
@@ -31,9 +32,11 @@ func (n *OpenBazaarNode) CompleteOrder(orderID models.OrderID, ratings []models.
 		if err != nil {
 			return err
 		}
-		return tx.Read().Where("id = ?", orderID.String()).Find(&order).Error
+		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
 	})
-	if err != nil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
 		return err
 	}
 
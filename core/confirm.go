@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
@@ -10,6 +11,7 @@ import (
 	"github.com/cpacia/openbazaar3.0/orders/utils"
 	iwallet "github.com/cpacia/wallet-interface"
 	"github.com/golang/protobuf/ptypes"
+	"gorm.io/gorm"
 )
 
 // ConfirmOrder sends a ORDER_CONFIRMATION message to the remote peer and updates the node's
@@ -26,7 +28,9 @@ func (n *OpenBazaarNode) ConfirmOrder(orderID models.OrderID, done chan struct{}
 	err := n.repo.DB().View(func(tx database.Tx) error {
 		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
 	})
-	if err != nil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
 		return err
 	}
 
@@ -12,6 +12,7 @@ import (
 	"github.com/ipfs/go-ipfs/core"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"io"
+	"time"
 )
 
 // CoreIface enumerates the interface of the OpenBazaarNode object in the Core package.
@@ -20,6 +21,9 @@ import (
 type CoreIface interface {
 	// Chat
 	SendChatMessage(to peer.ID, message string, orderID models.OrderID, done chan<- struct{}) error
+	SendChatMessageWithAttachments(to peer.ID, message string, orderID models.OrderID, attachments []cid.Cid, done chan<- struct{}) error
+	AddChatAttachment(filename string, data []byte) (*models.ChatAttachment, error)
+	GetChatAttachment(ctx context.Context, id cid.Cid) (*models.ChatAttachment, io.ReadSeeker, error)
 	SendTypingMessage(to peer.ID, orderID models.OrderID) error
 	MarkChatMessagesAsRead(peer peer.ID, orderID models.OrderID) error
 	GetChatConversations() ([]models.ChatConversation, error)
@@ -28,6 +32,7 @@ type CoreIface interface {
 	DeleteChatMessage(messageID string) error
 	DeleteChatConversation(peerID peer.ID) error
 	DeleteGroupChatMessages(orderID models.OrderID) error
+	SearchChatMessages(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error)
 
 	// Orders
 	PurchaseListing(ctx context.Context, purchase *models.Purchase) (orderID models.OrderID, paymentAddress iwallet.Address, paymentAmount models.CurrencyValue, err error)
@@ -37,6 +42,33 @@ type CoreIface interface {
 	ConfirmOrder(orderID models.OrderID, done chan struct{}) error
 	FulfillOrder(orderID models.OrderID, fulfillments []models.Fulfillment, done chan struct{}) error
 	CancelOrder(orderID models.OrderID, done chan struct{}) error
+	CompleteOrder(orderID models.OrderID, ratings []models.Rating, includeIDInRating bool, done chan struct{}) error
+	OpenDispute(orderID models.OrderID, reason string, done chan struct{}) error
+	CloseDispute(disputeCase *models.Case, verdict string, buyerPercentage, vendorPercentage float32, done chan struct{}) error
+	AcceptDisputePayout(orderID models.OrderID, done chan struct{}) error
+	ReleaseEscrowAfterTimeout(orderID models.OrderID, done chan struct{}) error
+	GetOrder(orderID models.OrderID) (*models.Order, error)
+	GetOrderFiatValues(orderID models.OrderID) ([]models.FiatValue, error)
+	GetOrders(query *models.OrderQuery) ([]models.OrderSummary, error)
+	GetSales(query *models.OrderQuery) ([]models.OrderSummary, error)
+	GetPurchases(query *models.OrderQuery) ([]models.OrderSummary, error)
+	GetCases(query *models.CaseQuery) ([]models.CaseSummary, error)
+	GetCase(caseID models.OrderID) (*models.CaseView, error)
+	ExportLedger(query *models.LedgerQuery, format string, w io.Writer) error
+
+	// Blocking
+	BlockNode(peerID peer.ID) error
+	UnblockNode(peerID peer.ID) error
+	GetBlockedNodes() ([]peer.ID, error)
+
+	// Backup
+	BackupRepo(passphrase string, w io.Writer) error
+	RestoreRepo(dataDir, passphrase string, backup io.Reader) error
+
+	// Outbox
+	GetOutgoingMessages() ([]models.OutboxMessage, error)
+	RetryOutgoingMessage(messageID string) error
+	CancelOutgoingMessage(messageID string) error
 
 	// Following
 	FollowNode(peerID peer.ID, done chan<- struct{}) error
@@ -57,6 +89,8 @@ type CoreIface interface {
 	GetMyListingByCID(cid cid.Cid) (*pb.SignedListing, error)
 	GetListingBySlug(ctx context.Context, peerID peer.ID, slug string, useCache bool) (*pb.SignedListing, error)
 	GetListingByCID(ctx context.Context, cid cid.Cid) (*pb.SignedListing, error)
+	GetMyInventory() (models.Inventory, error)
+	GetInventory(ctx context.Context, peerID peer.ID, useCache bool) (models.Inventory, error)
 
 	// Images
 	GetImage(ctx context.Context, cid cid.Cid) (io.ReadSeeker, error)
@@ -86,8 +120,18 @@ type CoreIface interface {
 	OpenChannel(topic string) error
 	CloseChannel(topic string) error
 	ListChannels() []string
-	PublishChannelMessage(ctx context.Context, topic, message string) error
+	PublishChannelMessage(ctx context.Context, topic string, content models.ChannelMessageContent) error
 	GetChannelMessages(ctx context.Context, topic string, from *cid.Cid, limit int) ([]models.ChannelMessage, error)
+	GetChannelPolicy(topic string) (*models.ChannelPolicy, error)
+	SetChannelOwner(topic string, owner peer.ID) error
+	SetChannelRateLimit(topic string, messagesPerMinute int) error
+	MuteChannelPeer(topic string, peerID peer.ID) error
+	UnmuteChannelPeer(topic string, peerID peer.ID) error
+	PublishChannelPolicy(ctx context.Context, topic string, policy models.ChannelOwnerPolicy) error
+	DeleteChannelMessages(ctx context.Context, topic string, ids []cid.Cid) error
+	CreatePrivateChannel(topic string, members []peer.ID) error
+	UpdatePrivateChannelMembers(topic string, add, remove []peer.ID) error
+	GetPrivateChannel(topic string) (*models.PrivateChannel, error)
 
 	// Preferences
 	GetPreferences() (*models.UserPreferences, error)
@@ -97,6 +141,7 @@ type CoreIface interface {
 	Multiwallet() multiwallet.Multiwallet
 	SaveTransactionMetadata(metadata *models.TransactionMetadata) error
 	GetTransactionMetadata(txid iwallet.TransactionID) (models.TransactionMetadata, error)
+	GetWalletTransactions(query *models.TransactionQuery) ([]models.WalletTransaction, error)
 	RequestAddress(ctx context.Context, to peer.ID, coinType iwallet.CoinType) (iwallet.Address, error)
 
 	// Misc
@@ -105,7 +150,9 @@ type CoreIface interface {
 	IPFSNode() *core.IpfsNode
 	Identity() peer.ID
 	ExchangeRates() *wallet.ExchangeRateProvider
+	GetHistoricalRate(base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error)
 	Publish(done chan<- struct{})
 	PingNode(ctx context.Context, peer peer.ID) error
+	GetRateLimitMetrics() models.RateLimitMetrics
 	SubscribeEvent(event interface{}) (events.Subscription, error)
 }
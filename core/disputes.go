@@ -1,21 +1,31 @@
 package core
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/net"
 	npb "github.com/cpacia/openbazaar3.0/net/pb"
 	"github.com/cpacia/openbazaar3.0/orders/pb"
 	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"gorm.io/gorm"
+	"math"
+	"math/big"
+	"time"
 )
 
+// percentageTolerance is how far the buyer and vendor percentages of a dispute
+// payout may add up to away from 100 to allow for values like 33.3 and 66.7.
+const percentageTolerance = 0.01
+
 // OpenDispute sends a disputeOpen message to both the moderator and the other party to the order and
 // updates the order state.
 func (n *OpenBazaarNode) OpenDispute(orderID models.OrderID, reason string, done chan struct{}) error {
@@ -30,9 +40,11 @@ func (n *OpenBazaarNode) OpenDispute(orderID models.OrderID, reason string, done
 
 	var order models.Order
 	err := n.repo.DB().View(func(tx database.Tx) error {
-		return tx.Read().Where("id = ?", orderID.String()).Find(&order).Error
+		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
 	})
-	if err != nil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
 		return err
 	}
 
@@ -119,6 +131,588 @@ func (n *OpenBazaarNode) OpenDispute(orderID models.OrderID, reason string, done
 	})
 }
 
+// CloseDispute is called by the moderator to resolve a dispute. The funds in escrow, less
+// the escrow release fee and the moderator's fee, are split between the buyer and vendor
+// according to the provided percentages. The moderator signs the escrow release with its
+// escrow key and sends a DISPUTE_CLOSE message to both parties. Either party can then
+// use the signatures to release the funds from escrow.
+func (n *OpenBazaarNode) CloseDispute(disputeCase *models.Case, verdict string, buyerPercentage, vendorPercentage float32, done chan struct{}) error {
+	done1, done2 := make(chan struct{}), make(chan struct{})
+	go func() {
+		if done != nil {
+			<-done1
+			<-done2
+			close(done)
+		}
+	}()
+
+	if disputeCase == nil {
+		return fmt.Errorf("%w: case is nil", coreiface.ErrBadRequest)
+	}
+	if buyerPercentage < 0 || vendorPercentage < 0 || math.Abs(float64(buyerPercentage+vendorPercentage)-100) > percentageTolerance {
+		return fmt.Errorf("%w: buyer and vendor percentages must add up to 100", coreiface.ErrBadRequest)
+	}
+	if disputeCase.SerializedDisputeOpen == nil {
+		return fmt.Errorf("%w: case does not have an open dispute", coreiface.ErrBadRequest)
+	}
+	if disputeCase.IsClosed() {
+		return fmt.Errorf("%w: dispute is already closed", coreiface.ErrBadRequest)
+	}
+
+	var contracts []*pb.Contract
+	for _, serialized := range [][]byte{disputeCase.BuyerContract, disputeCase.VendorContract} {
+		if serialized == nil {
+			continue
+		}
+		contract, err := extractContract(serialized)
+		if err != nil {
+			return err
+		}
+		contracts = append(contracts, contract)
+	}
+	if len(contracts) == 0 || contracts[0].OrderOpen == nil {
+		return fmt.Errorf("%w: case does not contain a contract", coreiface.ErrBadRequest)
+	}
+	orderOpen := contracts[0].OrderOpen
+
+	if orderOpen.Payment.Moderator != n.Identity().Pretty() {
+		return errors.New("selected moderator does not match own peerID")
+	}
+
+	buyer, err := peer.Decode(orderOpen.BuyerID.PeerID)
+	if err != nil {
+		return err
+	}
+	vendor, err := peer.Decode(orderOpen.Listings[0].Listing.VendorID.PeerID)
+	if err != nil {
+		return err
+	}
+
+	wallet, err := n.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
+	if err != nil {
+		return err
+	}
+
+	release, err := n.buildDisputeCloseRelease(contracts, wallet, buyerPercentage, vendorPercentage)
+	if err != nil {
+		return err
+	}
+
+	disputeClose := &pb.DisputeClose{
+		Verdict:     verdict,
+		ReleaseInfo: release,
+		Timestamp:   ptypes.TimestampNow(),
+	}
+
+	disputeCloseAny, err := ptypes.MarshalAny(disputeClose)
+	if err != nil {
+		return err
+	}
+
+	m := &npb.OrderMessage{
+		OrderID:     disputeCase.ID.String(),
+		MessageType: npb.OrderMessage_DISPUTE_CLOSE,
+		Message:     disputeCloseAny,
+	}
+
+	if err := utils.SignOrderMessage(m, n.ipfsNode.PrivateKey); err != nil {
+		return err
+	}
+
+	payload, err := ptypes.MarshalAny(m)
+	if err != nil {
+		return err
+	}
+
+	return n.repo.DB().Update(func(tx database.Tx) error {
+		// Apply the close to the case as it is now so that we don't overwrite
+		// any contract or update that arrived after the caller loaded it.
+		var current models.Case
+		err := tx.Read().Where("id = ?", disputeCase.ID.String()).First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: case not found", coreiface.ErrNotFound)
+		} else if err != nil {
+			return err
+		}
+		if current.IsClosed() {
+			return fmt.Errorf("%w: dispute is already closed", coreiface.ErrBadRequest)
+		}
+
+		if err := current.PutDisputeClose(disputeClose); err != nil {
+			return err
+		}
+		if err := tx.Save(&current); err != nil {
+			return err
+		}
+
+		message1 := newMessageWithID()
+		message1.MessageType = npb.Message_ORDER
+		message1.Payload = payload
+
+		if err := n.messenger.ReliablySendMessage(tx, buyer, message1, done1); err != nil {
+			return err
+		}
+
+		message2 := newMessageWithID()
+		message2.MessageType = npb.Message_ORDER
+		message2.Payload = payload
+
+		return n.messenger.ReliablySendMessage(tx, vendor, message2, done2)
+	})
+}
+
+// buildDisputeCloseRelease builds the transaction releasing the funds from escrow
+// and signs it with the moderator's escrow key.
+func (n *OpenBazaarNode) buildDisputeCloseRelease(contracts []*pb.Contract, wallet iwallet.Wallet, buyerPercentage, vendorPercentage float32) (*pb.DisputeClose_ModeratedEscrowRelease, error) {
+	escrowWallet, ok := wallet.(iwallet.Escrow)
+	if !ok {
+		return nil, errors.New("wallet does not support escrow")
+	}
+
+	var (
+		orderOpen     = contracts[0].OrderOpen
+		coinType      = iwallet.CoinType(orderOpen.Payment.Coin)
+		vendorAddress string
+		txids         = make(map[string]bool)
+		txs           []iwallet.Transaction
+	)
+	for _, contract := range contracts {
+		for _, tx := range contract.Transactions {
+			if txids[tx.Txid] {
+				continue
+			}
+			txids[tx.Txid] = true
+			txn, err := wallet.GetTransaction(iwallet.TransactionID(tx.Txid))
+			if err != nil {
+				return nil, fmt.Errorf("error loading order transaction %s: %w", tx.Txid, err)
+			}
+			txs = append(txs, txn)
+		}
+		for _, fulfillment := range contract.OrderFulfillments {
+			if vendorAddress == "" && fulfillment.ReleaseInfo != nil {
+				vendorAddress = fulfillment.ReleaseInfo.ToAddress
+			}
+		}
+	}
+
+	var (
+		txn     iwallet.Transaction
+		totalIn = iwallet.NewAmount(0)
+	)
+	spent := make(map[string]bool)
+	for _, tx := range txs {
+		for _, from := range tx.From {
+			spent[hex.EncodeToString(from.ID)] = true
+		}
+	}
+	for _, tx := range txs {
+		for _, to := range tx.To {
+			if !spent[hex.EncodeToString(to.ID)] && to.Address.String() == orderOpen.Payment.Address {
+				txn.From = append(txn.From, to)
+				totalIn = totalIn.Add(to.Amount)
+			}
+		}
+	}
+
+	escrowReleaseFee := iwallet.NewAmount(orderOpen.Payment.EscrowReleaseFee)
+	if totalIn.Cmp(escrowReleaseFee) <= 0 {
+		return nil, fmt.Errorf("%w: escrow does not contain enough funds to release", coreiface.ErrBadRequest)
+	}
+	available := totalIn.Sub(escrowReleaseFee)
+
+	moderatorAmount, err := n.moderatorFee(available, orderOpen.Payment.Coin)
+	if err != nil {
+		return nil, err
+	}
+	if moderatorAmount.Cmp(available) > 0 {
+		moderatorAmount = available
+	}
+	remaining := available.Sub(moderatorAmount)
+
+	buyerAmount := percentageOf(remaining, buyerPercentage)
+	vendorAmount := remaining.Sub(buyerAmount)
+	if vendorPercentage == 0 {
+		vendorAmount = iwallet.NewAmount(0)
+	}
+
+	release := &pb.DisputeClose_ModeratedEscrowRelease{
+		BuyerAmount:     buyerAmount.String(),
+		VendorAmount:    vendorAmount.String(),
+		ModeratorAmount: moderatorAmount.String(),
+		TransactionFee:  escrowReleaseFee.String(),
+	}
+
+	if buyerAmount.Cmp(iwallet.NewAmount(0)) > 0 {
+		release.BuyerAddress = orderOpen.RefundAddress
+		txn.To = append(txn.To, iwallet.SpendInfo{
+			Address: iwallet.NewAddress(release.BuyerAddress, coinType),
+			Amount:  buyerAmount,
+		})
+	}
+	if vendorAmount.Cmp(iwallet.NewAmount(0)) > 0 {
+		if vendorAddress == "" {
+			return nil, fmt.Errorf("%w: vendor has not provided a payout address", coreiface.ErrBadRequest)
+		}
+		release.VendorAddress = vendorAddress
+		txn.To = append(txn.To, iwallet.SpendInfo{
+			Address: iwallet.NewAddress(release.VendorAddress, coinType),
+			Amount:  vendorAmount,
+		})
+	}
+	if moderatorAmount.Cmp(iwallet.NewAmount(0)) > 0 {
+		addr, err := wallet.NewAddress()
+		if err != nil {
+			return nil, err
+		}
+		release.ModeratorAddress = addr.String()
+		txn.To = append(txn.To, iwallet.SpendInfo{
+			Address: addr,
+			Amount:  moderatorAmount,
+		})
+	}
+
+	script, err := hex.DecodeString(orderOpen.Payment.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	chainCode, err := hex.DecodeString(orderOpen.Payment.Chaincode)
+	if err != nil {
+		return nil, err
+	}
+
+	moderatorKey, err := utils.GenerateEscrowPrivateKey(n.escrowMasterKey, chainCode)
+	if err != nil {
+		return nil, err
+	}
+
+	sigs, err := escrowWallet.SignMultisigTransaction(txn, *moderatorKey, script)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, from := range txn.From {
+		release.FromIDs = append(release.FromIDs, from.ID)
+	}
+
+	for _, sig := range sigs {
+		release.EscrowSignatures = append(release.EscrowSignatures, &pb.Signature{
+			Signature: sig.Signature,
+			Index:     uint32(sig.Index),
+		})
+	}
+
+	return release, nil
+}
+
+// moderatorFee calculates our moderator fee, denominated in the payment coin,
+// for a dispute with the given amount in escrow.
+func (n *OpenBazaarNode) moderatorFee(amount iwallet.Amount, coin string) (iwallet.Amount, error) {
+	profile, err := n.GetMyProfile()
+	if err != nil {
+		return iwallet.NewAmount(0), err
+	}
+	if profile.ModeratorInfo == nil {
+		return iwallet.NewAmount(0), nil
+	}
+
+	var (
+		fee        = profile.ModeratorInfo.Fee
+		percentage = percentageOf(amount, float32(fee.Percentage))
+		fixed      = iwallet.NewAmount(0)
+	)
+	if (fee.FeeType == models.FixedFee || fee.FeeType == models.FixedPlusPercentageFee) && fee.FixedFee != nil {
+		paymentCurrency, err := models.CurrencyDefinitions.Lookup(coin)
+		if err != nil {
+			return iwallet.NewAmount(0), err
+		}
+		fixed, err = n.convertCurrencyAmount(fee.FixedFee, paymentCurrency)
+		if err != nil {
+			return iwallet.NewAmount(0), err
+		}
+	}
+
+	switch fee.FeeType {
+	case models.FixedFee:
+		return fixed, nil
+	case models.PercentageFee:
+		return percentage, nil
+	case models.FixedPlusPercentageFee:
+		return fixed.Add(percentage), nil
+	default:
+		return iwallet.NewAmount(0), errors.New("unknown moderator fee type")
+	}
+}
+
+// convertCurrencyAmount converts the value into the payment currency using the
+// exchange rate provider.
+func (n *OpenBazaarNode) convertCurrencyAmount(value *models.CurrencyValue, paymentCurrency *models.Currency) (iwallet.Amount, error) {
+	if value.Currency.Equal(paymentCurrency) {
+		return value.Amount, nil
+	}
+
+	rate, err := n.exchangeRates.GetRate(paymentCurrency.Code, value.Currency.Code, true)
+	if err != nil {
+		return value.Amount, err
+	}
+
+	rateFloat, ok := new(big.Float).SetString(rate.String())
+	if !ok {
+		return value.Amount, errors.New("error converting exchange rate to float")
+	}
+
+	div := new(big.Float).Quo(rateFloat, big.NewFloat(math.Pow10(int(value.Currency.Divisibility))))
+	div.Quo(big.NewFloat(1), div)
+
+	v, _ := div.Float64()
+
+	converted, err := value.ConvertTo(paymentCurrency, v)
+	if err != nil {
+		return value.Amount, err
+	}
+	return converted.Amount, nil
+}
+
+// percentageOf returns the given percentage of the amount rounded down.
+func percentageOf(amount iwallet.Amount, percentage float32) iwallet.Amount {
+	f, _ := new(big.Float).SetString(amount.String())
+	f.Mul(f, big.NewFloat(float64(percentage)))
+	f.Quo(f, big.NewFloat(100))
+	i, _ := f.Int(nil)
+	return iwallet.NewAmount(i)
+}
+
+// AcceptDisputePayout countersigns the escrow release from the moderator's DISPUTE_CLOSE
+// message and broadcasts the transaction. The transaction is recorded in the order and its
+// ID is sent to the other party in a PAYMENT_SENT message so they learn that the payout
+// was accepted.
+func (n *OpenBazaarNode) AcceptDisputePayout(orderID models.OrderID, done chan struct{}) error {
+	var order models.Order
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	if !order.CanAcceptDisputePayout() {
+		return fmt.Errorf("%w: order is not in a state where the dispute payout can be accepted", coreiface.ErrBadRequest)
+	}
+
+	orderOpen, err := order.OrderOpenMessage()
+	if err != nil {
+		return err
+	}
+
+	disputeClose, err := order.DisputeClosedMessage()
+	if err != nil {
+		return err
+	}
+
+	buyer, err := order.Buyer()
+	if err != nil {
+		return err
+	}
+	vendor, err := order.Vendor()
+	if err != nil {
+		return err
+	}
+
+	var (
+		to               = vendor
+		otherParty       = orderOpen.Listings[0].Listing.VendorID.PeerID
+		otherPartyHandle = orderOpen.Listings[0].Listing.VendorID.Handle
+	)
+	if order.Role() == models.RoleVendor {
+		to = buyer
+		otherParty = orderOpen.BuyerID.PeerID
+		otherPartyHandle = orderOpen.BuyerID.Handle
+	}
+
+	wallet, err := n.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
+	if err != nil {
+		return err
+	}
+
+	return n.repo.DB().Update(func(tx database.Tx) error {
+		wTx, txn, err := n.releaseDisputePayout(&order, wallet, orderOpen, disputeClose.ReleaseInfo)
+		if err != nil {
+			return err
+		}
+
+		if err := order.PutTransaction(txn); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		paymentAny, err := ptypes.MarshalAny(&pb.PaymentSent{
+			TransactionID: txn.ID.String(),
+		})
+		if err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		m := &npb.OrderMessage{
+			OrderID:     order.ID.String(),
+			MessageType: npb.OrderMessage_PAYMENT_SENT,
+			Message:     paymentAny,
+		}
+
+		if err := utils.SignOrderMessage(m, n.ipfsNode.PrivateKey); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		payload, err := ptypes.MarshalAny(m)
+		if err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		message := newMessageWithID()
+		message.MessageType = npb.Message_ORDER
+		message.Payload = payload
+
+		if err := order.PutMessage(m); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		if err := tx.Save(&order); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		if err := n.messenger.ReliablySendMessage(tx, to, message, done); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		tx.RegisterCommitHook(func() {
+			n.eventBus.Emit(&events.DisputeAccepted{
+				OrderID: order.ID.String(),
+				Thumbnail: events.Thumbnail{
+					Tiny:  orderOpen.Listings[0].Listing.Item.Images[0].Tiny,
+					Small: orderOpen.Listings[0].Listing.Item.Images[0].Small,
+				},
+				OherPartyID:      otherParty,
+				OtherPartyHandle: otherPartyHandle,
+				Buyer:            orderOpen.BuyerID.PeerID,
+			})
+			log.Infof("Accepted dispute payout for order %s", order.ID)
+		})
+
+		return wTx.Commit()
+	})
+}
+
+// releaseDisputePayout combines our signatures with the moderator's and builds the
+// transaction releasing the funds from escrow. The wallet transaction must be
+// committed to broadcast it.
+func (n *OpenBazaarNode) releaseDisputePayout(order *models.Order, wallet iwallet.Wallet, orderOpen *pb.OrderOpen, releaseInfo *pb.DisputeClose_ModeratedEscrowRelease) (iwallet.Tx, iwallet.Transaction, error) {
+	escrowWallet, ok := wallet.(iwallet.Escrow)
+	if !ok {
+		return nil, iwallet.Transaction{}, errors.New("wallet for moderated order does not support escrow")
+	}
+
+	txs, err := order.GetTransactions()
+	if err != nil {
+		return nil, iwallet.Transaction{}, err
+	}
+
+	// The signature hash may commit to the input amounts so we must use the
+	// full spend info of the escrow outputs, not just the IDs the moderator
+	// sent us.
+	escrowOutputs := make(map[string]iwallet.SpendInfo)
+	for _, tx := range txs {
+		for _, to := range tx.To {
+			if to.Address.String() == orderOpen.Payment.Address {
+				escrowOutputs[hex.EncodeToString(to.ID)] = to
+			}
+		}
+	}
+
+	var (
+		coinType = iwallet.CoinType(orderOpen.Payment.Coin)
+		txn      iwallet.Transaction
+		totalIn  = iwallet.NewAmount(0)
+		totalOut = iwallet.NewAmount(orderOpen.Payment.EscrowReleaseFee)
+	)
+	for _, id := range releaseInfo.FromIDs {
+		from, ok := escrowOutputs[hex.EncodeToString(id)]
+		if !ok {
+			return nil, txn, fmt.Errorf("%w: dispute payout spends unknown escrow output %s", coreiface.ErrBadRequest, hex.EncodeToString(id))
+		}
+		txn.From = append(txn.From, from)
+		totalIn = totalIn.Add(from.Amount)
+	}
+	for _, out := range []struct {
+		address string
+		amount  string
+	}{
+		{releaseInfo.BuyerAddress, releaseInfo.BuyerAmount},
+		{releaseInfo.VendorAddress, releaseInfo.VendorAmount},
+		{releaseInfo.ModeratorAddress, releaseInfo.ModeratorAmount},
+	} {
+		amount := iwallet.NewAmount(out.amount)
+		if out.address == "" || amount.Cmp(iwallet.NewAmount(0)) <= 0 {
+			continue
+		}
+		txn.To = append(txn.To, iwallet.SpendInfo{
+			Address: iwallet.NewAddress(out.address, coinType),
+			Amount:  amount,
+		})
+		totalOut = totalOut.Add(amount)
+	}
+	if totalIn.Cmp(totalOut) != 0 {
+		return nil, txn, fmt.Errorf("%w: dispute payout of %s does not match escrow total of %s", coreiface.ErrBadRequest, totalOut, totalIn)
+	}
+
+	var moderatorSigs []iwallet.EscrowSignature
+	for _, sig := range releaseInfo.EscrowSignatures {
+		moderatorSigs = append(moderatorSigs, iwallet.EscrowSignature{
+			Index:     int(sig.Index),
+			Signature: sig.Signature,
+		})
+	}
+
+	script, err := hex.DecodeString(orderOpen.Payment.Script)
+	if err != nil {
+		return nil, txn, err
+	}
+
+	chainCode, err := hex.DecodeString(orderOpen.Payment.Chaincode)
+	if err != nil {
+		return nil, txn, err
+	}
+
+	escrowKey, err := utils.GenerateEscrowPrivateKey(n.escrowMasterKey, chainCode)
+	if err != nil {
+		return nil, txn, err
+	}
+
+	ourSigs, err := escrowWallet.SignMultisigTransaction(txn, *escrowKey, script)
+	if err != nil {
+		return nil, txn, err
+	}
+
+	wTx, err := wallet.Begin()
+	if err != nil {
+		return nil, txn, err
+	}
+	txid, err := escrowWallet.BuildAndSend(wTx, txn, [][]iwallet.EscrowSignature{ourSigs, moderatorSigs}, script)
+	if err != nil {
+		wTx.Rollback()
+		return nil, txn, err
+	}
+	txn.ID = txid
+	txn.Timestamp = time.Now()
+	return wTx, txn, nil
+}
+
 // handleOrderMessage is the handler for the ORDER message. It sends it off to the order
 // order processor for processing.
 func (n *OpenBazaarNode) handleDisputeMessage(from peer.ID, message *npb.Message) error {
@@ -134,6 +728,12 @@ func (n *OpenBazaarNode) handleDisputeMessage(from peer.ID, message *npb.Message
 
 	order := new(npb.OrderMessage)
 	if err := ptypes.UnmarshalAny(message.Payload, order); err != nil {
+		n.networkService.Misbehaving(from, net.ScoreMalformedMessage, "malformed dispute message")
+		return err
+	}
+
+	if err := utils.VerifyOrderMessageSignature(order, from); err != nil {
+		n.networkService.Misbehaving(from, net.ScoreInvalidSignature, "invalid dispute message signature")
 		return err
 	}
 
@@ -164,11 +764,29 @@ func (n *OpenBazaarNode) handleDisputeMessage(from peer.ID, message *npb.Message
 			disputeeHandle = orderOpen.BuyerID.Handle
 		}
 
-		validationErrors, err := n.validateDisputeOpen(from, disputeOpen)
+		validationErrors, err := n.validateDisputeOpen(from, order.OrderID, disputeOpen)
 		if err != nil {
 			return err
 		}
 
+		// Watch the escrow address so the wallet tracks the transactions
+		// we will need to spend from when closing the dispute.
+		wallet, err := n.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
+		if err != nil {
+			return err
+		}
+		wtx, err := wallet.Begin()
+		if err != nil {
+			return err
+		}
+		err = wallet.WatchAddress(wtx, iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CoinType(orderOpen.Payment.Coin)))
+		if err != nil {
+			return err
+		}
+		if err := wtx.Commit(); err != nil {
+			return err
+		}
+
 		return n.repo.DB().Update(func(dbtx database.Tx) error {
 			dbtx.RegisterCommitHook(func() {
 				n.eventBus.Emit(&events.CaseOpen{
@@ -200,6 +818,16 @@ func (n *OpenBazaarNode) handleDisputeMessage(from peer.ID, message *npb.Message
 				return fmt.Errorf("received duplicate DISPUTE_OPEN message from %s", from.Pretty())
 			}
 
+			// An update that arrived before the DISPUTE_OPEN must have come from
+			// the other party.
+			if disputeCase.ParkedUpdate != nil {
+				parked, err := extractContract(disputeCase.ParkedUpdate)
+				if err != nil || parked.Role == string(role) {
+					log.Warningf("Discarding parked DISPUTE_UPDATE for case %s sent by the disputer", order.OrderID)
+					disputeCase.ParkedUpdate = nil
+				}
+			}
+
 			err = disputeCase.PutDisputeOpen(disputeOpen)
 			if err != nil {
 				return err
@@ -217,6 +845,19 @@ func (n *OpenBazaarNode) handleDisputeMessage(from peer.ID, message *npb.Message
 			return err
 		}
 
+		var disputeCase models.Case
+		err = n.repo.DB().View(func(dbtx database.Tx) error {
+			return dbtx.Read().Where("id = ?", order.OrderID).First(&disputeCase).Error
+		})
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		role, validationErrors, err := n.validateDisputeUpdate(from, order.OrderID, &disputeCase, disputeUpdate)
+		if err != nil {
+			return err
+		}
+
 		var (
 			disputer       = orderOpen.BuyerID.PeerID
 			disputerHandle = orderOpen.BuyerID.Handle
@@ -253,8 +894,9 @@ func (n *OpenBazaarNode) handleDisputeMessage(from peer.ID, message *npb.Message
 			}
 
 			disputeCase.ID = models.OrderID(order.OrderID)
-
-			// TODO: validate dispute update contract
+			if err := disputeCase.PutValidationErrors(validationErrors, role); err != nil {
+				return err
+			}
 
 			err = disputeCase.PutDisputeUpdate(disputeUpdate)
 			if err != nil {
@@ -266,7 +908,52 @@ func (n *OpenBazaarNode) handleDisputeMessage(from peer.ID, message *npb.Message
 	return nil
 }
 
-func (n *OpenBazaarNode) validateDisputeOpen(from peer.ID, dispute *pb.DisputeOpen) (validationErrors []error, err error) {
+// validateDisputeOpen validates the contract sent by the party opening the dispute.
+func (n *OpenBazaarNode) validateDisputeOpen(from peer.ID, orderID string, dispute *pb.DisputeOpen) (validationErrors []error, err error) {
+	contract, err := extractContract(dispute.Contract)
+	if err != nil {
+		return nil, err
+	}
+	return n.validateDisputeContract(from, orderID, contract, dispute.OpenedBy)
+}
+
+// validateDisputeUpdate validates the contract sent by the party that did not open
+// the dispute. If the DISPUTE_OPEN has not arrived yet we can only check that the
+// update came from the buyer or the vendor. The role of the sender is returned.
+func (n *OpenBazaarNode) validateDisputeUpdate(from peer.ID, orderID string, disputeCase *models.Case, update *pb.DisputeUpdate) (role models.OrderRole, validationErrors []error, err error) {
+	contract, err := extractContract(update.Contract)
+	if err != nil {
+		return "", nil, err
+	}
+	if contract.OrderOpen == nil || contract.OrderOpen.BuyerID == nil {
+		return "", nil, errors.New("dispute update contract is missing the order open")
+	}
+
+	party := pb.DisputeOpen_BUYER
+	role = models.RoleBuyer
+	if contract.OrderOpen.BuyerID.PeerID != from.Pretty() {
+		party = pb.DisputeOpen_VENDOR
+		role = models.RoleVendor
+	}
+
+	openedBy, err := disputeCase.OpenedBy()
+	if err != nil && !errors.Is(err, models.ErrMessageDoesNotExist) {
+		return "", nil, err
+	}
+	if err == nil && openedBy == party {
+		return "", nil, errors.New("dispute update sent by the party that opened the dispute")
+	}
+
+	validationErrors, err = n.validateDisputeContract(from, orderID, contract, party)
+	return role, validationErrors, err
+}
+
+// validateDisputeContract validates a contract sent to us, the moderator, by the
+// given party. An error is returned if the contract is not for this order, was not
+// sent by the party or does not select us as the moderator. Any other problems with
+// the contract are returned as validation errors so they can be presented to the
+// moderator when deciding the case.
+func (n *OpenBazaarNode) validateDisputeContract(from peer.ID, orderID string, contract *pb.Contract, party pb.DisputeOpen_Party) (validationErrors []error, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -275,23 +962,34 @@ func (n *OpenBazaarNode) validateDisputeOpen(from peer.ID, dispute *pb.DisputeOp
 			case error:
 				err = fmt.Errorf("dispute contract missing required field: %w", x)
 			default:
-				err = errors.New("unknown dispute open validation panic")
+				err = errors.New("unknown dispute contract validation panic")
 			}
 		}
 	}()
 
-	orderOpen, err := extractOrderOpen(dispute.Contract)
-	if err != nil {
-		return nil, err
+	orderOpen := contract.OrderOpen
+
+	partyPeer := orderOpen.BuyerID.PeerID
+	partyRole := models.RoleBuyer
+	if party == pb.DisputeOpen_VENDOR {
+		partyPeer = orderOpen.Listings[0].Listing.VendorID.PeerID
+		partyRole = models.RoleVendor
 	}
 
-	openedByPeer := orderOpen.BuyerID.PeerID
-	if dispute.OpenedBy == pb.DisputeOpen_VENDOR {
-		openedByPeer = orderOpen.Listings[0].Listing.VendorID.PeerID
+	if partyPeer != from.Pretty() {
+		return nil, errors.New("dispute contract party peerID does not match peer that sent the message")
 	}
 
-	if openedByPeer != from.Pretty() {
-		return nil, errors.New("dispute open openedBy peerID does not match peer that sent the message")
+	if contract.Role != string(partyRole) {
+		return nil, errors.New("dispute contract role does not match peer that sent the message")
+	}
+
+	orderHash, err := utils.CalcOrderID(orderOpen)
+	if err != nil {
+		return nil, err
+	}
+	if orderHash.B58String() != orderID {
+		return nil, errors.New("dispute contract order open does not match order ID")
 	}
 
 	if orderOpen.Payment.Moderator != n.Identity().Pretty() {
@@ -335,13 +1033,49 @@ func (n *OpenBazaarNode) validateDisputeOpen(from peer.ID, dispute *pb.DisputeOp
 		validationErrors = append(validationErrors, fmt.Errorf("order payment is invalid: %s", err.Error()))
 	}
 
+	for _, tx := range contract.Transactions {
+		// The wallet may not have seen the transaction yet in which case we
+		// can't validate it.
+		txn, err := wal.GetTransaction(iwallet.TransactionID(tx.Txid))
+		if err != nil {
+			continue
+		}
+		if !transactionUsesAddress(txn, orderOpen.Payment.Address) {
+			validationErrors = append(validationErrors, fmt.Errorf("transaction %s does not pay to or spend from the escrow address", tx.Txid))
+		}
+	}
+
 	return validationErrors, nil
 }
 
+// transactionUsesAddress returns whether the transaction pays to or spends from
+// the address.
+func transactionUsesAddress(txn iwallet.Transaction, address string) bool {
+	for _, to := range txn.To {
+		if to.Address.String() == address {
+			return true
+		}
+	}
+	for _, from := range txn.From {
+		if from.Address.String() == address {
+			return true
+		}
+	}
+	return false
+}
+
 func extractOrderOpen(contract []byte) (*pb.OrderOpen, error) {
-	var c pb.Contract
-	if err := proto.Unmarshal(contract, &c); err != nil {
+	c, err := extractContract(contract)
+	if err != nil {
 		return nil, err
 	}
 	return c.OrderOpen, nil
 }
+
+func extractContract(contract []byte) (*pb.Contract, error) {
+	c := new(pb.Contract)
+	if err := proto.Unmarshal(contract, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -2,11 +2,20 @@ package core
 
 import (
 	"context"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/models/factory"
+	npb "github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
 	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"strings"
 	"testing"
 	"time"
 )
@@ -350,4 +359,595 @@ func TestOpenBazaarNode_OpenDispute(t *testing.T) {
 	if case2.VendorContract == nil {
 		t.Error("Moderator vendor contract is nil")
 	}
+	if case2.BuyerValidationErrors == nil {
+		t.Error("Moderator buyer validation errors not recorded")
+	}
+	if case2.VendorValidationErrors == nil {
+		t.Error("Moderator vendor validation errors not recorded")
+	}
+}
+
+func TestOpenBazaarNode_CloseDispute(t *testing.T) {
+	network, err := NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	orderID := openMockDispute(t, network)
+
+	var disputeCase models.Case
+	err = network.Nodes()[2].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&disputeCase).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.Nodes()[2].CloseDispute(&disputeCase, "Buyer wins", 50, 40, nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for invalid percentages, got %v", err)
+	}
+
+	if err := network.Nodes()[2].CloseDispute(&models.Case{ID: "abc"}, "Buyer wins", 33.3, 66.7, nil); errors.Is(err, coreiface.ErrBadRequest) && strings.Contains(err.Error(), "percentages") {
+		t.Errorf("Expected percentages adding up to 100 to be accepted, got %v", err)
+	}
+
+	// The vendor never fulfilled the order so has no payout address.
+	if err := network.Nodes()[2].CloseDispute(&disputeCase, "Vendor wins", 0, 100, nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for missing vendor address, got %v", err)
+	}
+
+	// A case deleted after it was loaded must not be re-created.
+	err = network.Nodes()[2].repo.DB().Update(func(tx database.Tx) error {
+		return tx.Delete("id", orderID.String(), nil, &models.Case{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := network.Nodes()[2].CloseDispute(&disputeCase, "Buyer wins", 100, 0, nil); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found for deleted case, got %v", err)
+	}
+
+	// Changes made to the case after it was loaded must not be overwritten.
+	err = network.Nodes()[2].repo.DB().Update(func(tx database.Tx) error {
+		updated := disputeCase
+		updated.BuyerValidationErrors = []byte(`["late"]`)
+		return tx.Save(&updated)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disputeCloseSub0, err := network.Nodes()[0].eventBus.Subscribe(&events.DisputeClose{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	disputeCloseSub1, err := network.Nodes()[1].eventBus.Subscribe(&events.DisputeClose{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	if err := network.Nodes()[2].CloseDispute(&disputeCase, "Buyer wins", 100, 0, done); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	select {
+	case <-disputeCloseSub0.Out():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	select {
+	case <-disputeCloseSub1.Out():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	if err := network.Nodes()[2].CloseDispute(&disputeCase, "Buyer wins", 100, 0, nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for closed dispute, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		var order models.Order
+		err = network.Nodes()[i].repo.DB().View(func(tx database.Tx) error {
+			return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		disputeClose, err := order.DisputeClosedMessage()
+		if err != nil {
+			t.Fatalf("Node %d: %s", i, err)
+		}
+		if disputeClose.Verdict != "Buyer wins" {
+			t.Errorf("Node %d: incorrect verdict %s", i, disputeClose.Verdict)
+		}
+		if disputeClose.ReleaseInfo.BuyerAmount == "0" || disputeClose.ReleaseInfo.VendorAmount != "0" {
+			t.Errorf("Node %d: incorrect payout split", i)
+		}
+		if order.Status() != models.StatusDisputeClosed {
+			t.Errorf("Node %d: expected status %s, got %s", i, models.StatusDisputeClosed, order.Status())
+		}
+	}
+
+	var closedCase models.Case
+	err = network.Nodes()[2].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&closedCase).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closedCase.IsClosed() {
+		t.Error("Moderator case is not closed")
+	}
+	if string(closedCase.BuyerValidationErrors) != `["late"]` {
+		t.Error("Moderator case update was overwritten")
+	}
+}
+
+func TestOpenBazaarNode_AcceptDisputePayout(t *testing.T) {
+	network, err := NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	orderID := openMockDispute(t, network)
+
+	if err := network.Nodes()[1].AcceptDisputePayout(orderID, nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request accepting payout before dispute close, got %v", err)
+	}
+
+	var disputeCase models.Case
+	err = network.Nodes()[2].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&disputeCase).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disputeCloseSub1, err := network.Nodes()[1].eventBus.Subscribe(&events.DisputeClose{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	if err := network.Nodes()[2].CloseDispute(&disputeCase, "Buyer wins", 100, 0, done); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	select {
+	case <-disputeCloseSub1.Out():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	if err := network.Nodes()[1].AcceptDisputePayout("abc", nil); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	acceptedSub0, err := network.Nodes()[0].eventBus.Subscribe(&events.DisputeAccepted{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	acceptedSub1, err := network.Nodes()[1].eventBus.Subscribe(&events.DisputeAccepted{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet1, err := network.Nodes()[1].multiwallet.WalletForCurrencyCode(iwallet.CtMock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A payout which spends unknown outputs or doesn't add up to the escrow
+	// total must be refused before anything is signed.
+	var buyerOrder models.Order
+	err = network.Nodes()[1].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&buyerOrder).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderOpen, err := buyerOrder.OrderOpenMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	disputeClose, err := buyerOrder.DisputeClosedMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownInput := proto.Clone(disputeClose.ReleaseInfo).(*pb.DisputeClose_ModeratedEscrowRelease)
+	unknownInput.FromIDs = append(unknownInput.FromIDs, []byte{0x01, 0x02})
+	if _, _, err := network.Nodes()[1].releaseDisputePayout(&buyerOrder, wallet1, orderOpen, unknownInput); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for unknown input, got %v", err)
+	}
+	inflated := proto.Clone(disputeClose.ReleaseInfo).(*pb.DisputeClose_ModeratedEscrowRelease)
+	inflated.BuyerAmount = iwallet.NewAmount(inflated.BuyerAmount).Add(iwallet.NewAmount(1)).String()
+	if _, _, err := network.Nodes()[1].releaseDisputePayout(&buyerOrder, wallet1, orderOpen, inflated); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for inflated payout, got %v", err)
+	}
+
+	balance0, balance1, err := wallet1.Balance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	startBalance := balance0.Add(balance1)
+
+	done = make(chan struct{})
+	if err := network.Nodes()[1].AcceptDisputePayout(orderID, done); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	var event0, event1 interface{}
+	select {
+	case event1 = <-acceptedSub1.Out():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	select {
+	case event0 = <-acceptedSub0.Out():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	if id := event1.(*events.DisputeAccepted).OherPartyID; id != network.Nodes()[0].Identity().Pretty() {
+		t.Errorf("Buyer event has incorrect other party %s", id)
+	}
+	if id := event0.(*events.DisputeAccepted).OherPartyID; id != network.Nodes()[1].Identity().Pretty() {
+		t.Errorf("Vendor event has incorrect other party %s", id)
+	}
+
+	var order1 models.Order
+	err = network.Nodes()[1].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&order1).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	released, err := order1.IsDisputePayoutReleased()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !released {
+		t.Error("Buyer order does not record the dispute payout transaction")
+	}
+
+	balance0, balance1, err = wallet1.Balance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endBalance := balance0.Add(balance1); endBalance.Cmp(startBalance) <= 0 {
+		t.Errorf("Buyer wallet balance did not increase. Before %s, after %s", startBalance, endBalance)
+	}
+
+	var order0 models.Order
+	err = network.Nodes()[0].repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&order0).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order0.SerializedPaymentSent == nil {
+		t.Error("Vendor did not save the dispute payout message")
+	}
+
+	if err := network.Nodes()[1].AcceptDisputePayout(orderID, nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request accepting payout twice, got %v", err)
+	}
+}
+
+// openMockDispute runs through a moderated purchase on the mocknet where node 0
+// is the vendor, node 1 the buyer and node 2 the moderator. The buyer funds the
+// order and opens a dispute.
+func openMockDispute(t *testing.T, network *Mocknet) models.OrderID {
+	go network.StartWalletNetwork()
+
+	for _, node := range network.Nodes() {
+		go node.orderProcessor.Start()
+	}
+
+	done := make(chan struct{})
+	if err := network.Nodes()[2].SetProfile(&models.Profile{Name: "Ron Paul"}, done); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	modInfo := &models.ModeratorInfo{
+		AcceptedCurrencies: []string{"MCK"},
+		Fee: models.ModeratorFee{
+			Percentage: 10,
+			FeeType:    models.PercentageFee,
+		},
+	}
+	done = make(chan struct{})
+	if err := network.Nodes()[2].SetSelfAsModerator(context.Background(), modInfo, done); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	orderSub0, err := network.Nodes()[0].eventBus.Subscribe(&events.NewOrder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listing := factory.NewPhysicalListing("tshirt")
+
+	done = make(chan struct{})
+	if err := network.Nodes()[0].SaveListing(listing, done); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	index, err := network.Nodes()[0].GetMyListings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	purchase := factory.NewPurchase()
+	purchase.Items[0].ListingHash = index[0].CID
+	purchase.Moderator = network.Nodes()[2].Identity().Pretty()
+
+	orderID, paymentAddress, paymentAmount, err := network.Nodes()[1].PurchaseListing(context.Background(), purchase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-orderSub0.Out():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	wallet1, err := network.Nodes()[1].multiwallet.WalletForCurrencyCode(iwallet.CtMock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr1, err := wallet1.CurrentAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txSub1, err := network.Nodes()[1].eventBus.Subscribe(&events.TransactionReceived{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.WalletNetwork().GenerateToAddress(addr1, iwallet.NewAmount(100000000000)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-txSub1.Out():
+		txSub1.Close()
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	fundingSub0, err := network.Nodes()[0].eventBus.Subscribe(&events.OrderFunded{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fundingSub1, err := network.Nodes()[1].eventBus.Subscribe(&events.OrderPaymentReceived{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wTx, err := wallet1.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fundingTxid, err := wallet1.Spend(wTx, paymentAddress, paymentAmount.Amount, iwallet.FlNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wTx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-fundingSub0.Out():
+		fundingSub0.Close()
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	select {
+	case <-fundingSub1.Out():
+		fundingSub1.Close()
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	caseUpdateSub, err := network.Nodes()[2].eventBus.Subscribe(&events.CaseUpdate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done = make(chan struct{})
+	if err := network.Nodes()[1].OpenDispute(orderID, "Got scammed", done); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	select {
+	case <-caseUpdateSub.Out():
+		caseUpdateSub.Close()
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	// The moderator started watching the escrow address when the dispute was
+	// opened. Replay the funding transaction so its wallet learns about it.
+	fundingTx, err := wallet1.GetTransaction(fundingTxid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txSub2, err := network.Nodes()[2].eventBus.Subscribe(&events.TransactionReceived{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.WalletNetwork().Wallets()[2].IngestTransaction(fundingTx)
+
+	select {
+	case <-txSub2.Out():
+		txSub2.Close()
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	return orderID
+}
+
+func TestOpenBazaarNode_handleDisputeUpdate(t *testing.T) {
+	network, err := NewMocknet(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	orderID := openMockDispute(t, network)
+
+	var (
+		vendor    = network.Nodes()[0]
+		buyer     = network.Nodes()[1]
+		moderator = network.Nodes()[2]
+	)
+
+	loadContract := func(node *OpenBazaarNode) *pb.Contract {
+		var order models.Order
+		err := node.repo.DB().View(func(tx database.Tx) error {
+			return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ser, err := order.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contract, err := extractContract(ser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return contract
+	}
+
+	sendUpdate := func(signer *OpenBazaarNode, from peer.ID, contract *pb.Contract) error {
+		ser, err := proto.Marshal(contract)
+		if err != nil {
+			return err
+		}
+		updateAny, err := ptypes.MarshalAny(&pb.DisputeUpdate{
+			Timestamp: ptypes.TimestampNow(),
+			Contract:  ser,
+		})
+		if err != nil {
+			return err
+		}
+		m := &npb.OrderMessage{
+			OrderID:     orderID.String(),
+			MessageType: npb.OrderMessage_DISPUTE_UPDATE,
+			Message:     updateAny,
+		}
+		if err := utils.SignOrderMessage(m, signer.ipfsNode.PrivateKey); err != nil {
+			return err
+		}
+		payload, err := ptypes.MarshalAny(m)
+		if err != nil {
+			return err
+		}
+		message := newMessageWithID()
+		message.MessageType = npb.Message_DISPUTE
+		message.Payload = payload
+		return moderator.handleDisputeMessage(from, message)
+	}
+
+	// Clear the vendor's update so we can resend it.
+	err = moderator.repo.DB().Update(func(tx database.Tx) error {
+		return tx.Update("vendor_contract", nil, map[string]interface{}{"id = ?": orderID.String()}, &models.Case{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Update sent by the party that opened the dispute.
+	if err := sendUpdate(buyer, buyer.Identity(), loadContract(buyer)); err == nil {
+		t.Error("Expected error processing update from the disputer")
+	}
+
+	// Update signed by someone other than the sender.
+	if err := sendUpdate(buyer, vendor.Identity(), loadContract(vendor)); err == nil {
+		t.Error("Expected error processing update with invalid signature")
+	}
+
+	// Forged order open.
+	forged := loadContract(vendor)
+	forged.OrderOpen.RefundAddress = "abc"
+	if err := sendUpdate(vendor, vendor.Identity(), forged); err == nil {
+		t.Error("Expected error processing update with forged order open")
+	}
+
+	// Valid update.
+	if err := sendUpdate(vendor, vendor.Identity(), loadContract(vendor)); err != nil {
+		t.Fatal(err)
+	}
+
+	var disputeCase models.Case
+	err = moderator.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&disputeCase).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disputeCase.VendorContract == nil {
+		t.Error("Moderator vendor contract is nil")
+	}
+	if disputeCase.VendorValidationErrors == nil {
+		t.Error("Moderator vendor validation errors not recorded")
+	}
 }
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	npb "github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/ptypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ReleaseEscrowAfterTimeout is used by the vendor to release the funds from a moderated
+// order's escrow once the escrow timeout has passed without the buyer opening a dispute.
+// The funds are sent to our wallet and a PAYMENT_FINALIZED message is sent to the buyer.
+func (n *OpenBazaarNode) ReleaseEscrowAfterTimeout(orderID models.OrderID, done chan struct{}) error {
+	var order models.Order
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	if !order.CanReleaseEscrowAfterTimeout() {
+		return fmt.Errorf("%w: order is not in a state where the escrow can be released", coreiface.ErrBadRequest)
+	}
+
+	orderOpen, err := order.OrderOpenMessage()
+	if err != nil {
+		return err
+	}
+
+	buyer, err := order.Buyer()
+	if err != nil {
+		return err
+	}
+
+	wallet, err := n.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
+	if err != nil {
+		return err
+	}
+
+	return n.repo.DB().Update(func(tx database.Tx) error {
+		wTx, txn, err := n.releaseEscrowAfterTimeout(&order, wallet, orderOpen)
+		if err != nil {
+			return err
+		}
+
+		if err := order.PutTransaction(txn); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		finalizedAny, err := ptypes.MarshalAny(&pb.PaymentFinalized{})
+		if err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		m := &npb.OrderMessage{
+			OrderID:     order.ID.String(),
+			MessageType: npb.OrderMessage_PAYMENT_FINALIZED,
+			Message:     finalizedAny,
+		}
+
+		if err := utils.SignOrderMessage(m, n.ipfsNode.PrivateKey); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		payload, err := ptypes.MarshalAny(m)
+		if err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		message := newMessageWithID()
+		message.MessageType = npb.Message_ORDER
+		message.Payload = payload
+
+		if err := order.PutMessage(m); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		if err := tx.Save(&order); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		if err := n.messenger.ReliablySendMessage(tx, buyer, message, done); err != nil {
+			wTx.Rollback()
+			return err
+		}
+
+		tx.RegisterCommitHook(func() {
+			log.Infof("Released escrow after timeout for order %s", order.ID)
+		})
+
+		return wTx.Commit()
+	})
+}
+
+// releaseEscrowAfterTimeout builds the transaction sending the unspent escrow outputs
+// to our wallet and signs it with the timeout key. The wallet transaction must be
+// committed to broadcast it.
+func (n *OpenBazaarNode) releaseEscrowAfterTimeout(order *models.Order, wallet iwallet.Wallet, orderOpen *pb.OrderOpen) (iwallet.Tx, iwallet.Transaction, error) {
+	var txn iwallet.Transaction
+
+	escrowWallet, ok := wallet.(iwallet.EscrowWithTimeout)
+	if !ok {
+		return nil, txn, fmt.Errorf("%w: wallet does not support escrow timeouts", coreiface.ErrBadRequest)
+	}
+
+	txs, err := order.GetTransactions()
+	if err != nil {
+		return nil, txn, err
+	}
+
+	totalIn := iwallet.NewAmount(0)
+	spent := make(map[string]bool)
+	for _, tx := range txs {
+		for _, from := range tx.From {
+			spent[hex.EncodeToString(from.ID)] = true
+		}
+	}
+	for _, tx := range txs {
+		for _, to := range tx.To {
+			if !spent[hex.EncodeToString(to.ID)] && to.Address.String() == orderOpen.Payment.Address {
+				txn.From = append(txn.From, to)
+				totalIn = totalIn.Add(to.Amount)
+			}
+		}
+	}
+
+	escrowReleaseFee := iwallet.NewAmount(orderOpen.Payment.EscrowReleaseFee)
+	if totalIn.Cmp(escrowReleaseFee) <= 0 {
+		return nil, txn, fmt.Errorf("%w: escrow does not contain enough funds to release", coreiface.ErrBadRequest)
+	}
+
+	payoutAddress, err := wallet.NewAddress()
+	if err != nil {
+		return nil, txn, err
+	}
+	txn.To = append(txn.To, iwallet.SpendInfo{
+		Address: payoutAddress,
+		Amount:  totalIn.Sub(escrowReleaseFee),
+	})
+
+	script, err := hex.DecodeString(orderOpen.Payment.Script)
+	if err != nil {
+		return nil, txn, err
+	}
+
+	chainCode, err := hex.DecodeString(orderOpen.Payment.Chaincode)
+	if err != nil {
+		return nil, txn, err
+	}
+
+	vendorKey, err := utils.GenerateEscrowPrivateKey(n.escrowMasterKey, chainCode)
+	if err != nil {
+		return nil, txn, err
+	}
+
+	wTx, err := wallet.Begin()
+	if err != nil {
+		return nil, txn, err
+	}
+	txid, err := escrowWallet.ReleaseFundsAfterTimeout(wTx, txn, *vendorKey, script)
+	if err != nil {
+		wTx.Rollback()
+		return nil, txn, err
+	}
+	txn.ID = txid
+	txn.Timestamp = time.Now()
+	return wTx, txn, nil
+}
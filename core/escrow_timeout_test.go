@@ -0,0 +1,154 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	"github.com/cpacia/openbazaar3.0/wallet"
+	iwallet "github.com/cpacia/wallet-interface"
+	"testing"
+	"time"
+)
+
+func TestOpenBazaarNode_ReleaseEscrowAfterTimeout(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	saveOrder := func(id models.OrderID, role models.OrderRole, timeout, fundedAge time.Duration) error {
+		orderOpen, err := factory.NewOrder()
+		if err != nil {
+			return err
+		}
+
+		key, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			return err
+		}
+		// The mock wallet embeds the expiry in the script.
+		addr, script, err := wallet.NewMockWallet().CreateMultisigWithTimeout([]btcec.PublicKey{*key.PubKey()}, 1, timeout-fundedAge, *key.PubKey())
+		if err != nil {
+			return err
+		}
+		orderOpen.Payment.Method = pb.OrderOpen_Payment_MODERATED
+		orderOpen.Payment.Address = addr.String()
+		orderOpen.Payment.Script = hex.EncodeToString(script)
+		orderOpen.Listings[0].Listing.Metadata.EscrowTimeoutHours = uint32(timeout / time.Hour)
+
+		order := models.Order{ID: id}
+		order.SetRole(role)
+		if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+			return err
+		}
+		err = order.PutTransaction(iwallet.Transaction{
+			ID: iwallet.TransactionID(id),
+			To: []iwallet.SpendInfo{
+				{
+					ID:      []byte(id),
+					Address: addr,
+					Amount:  iwallet.NewAmount(orderOpen.Payment.Amount),
+				},
+			},
+			Timestamp: time.Now().Add(-fundedAge),
+		})
+		if err != nil {
+			return err
+		}
+		fulfillment := &pb.OrderFulfillment{
+			Fulfillments: []*pb.OrderFulfillment_FulfilledItem{{ItemIndex: 0}},
+		}
+		if err := order.PutMessage(utils.MustWrapOrderMessage(fulfillment)); err != nil {
+			return err
+		}
+		return mockNode.repo.DB().Update(func(tx database.Tx) error {
+			return tx.Save(&order)
+		})
+	}
+
+	tests := []struct {
+		name        string
+		orderID     models.OrderID
+		role        models.OrderRole
+		timeout     time.Duration
+		fundedAge   time.Duration
+		expectedErr error
+	}{
+		{
+			name:      "Release after timeout",
+			orderID:   "expired",
+			role:      models.RoleVendor,
+			timeout:   time.Hour,
+			fundedAge: time.Hour * 2,
+		},
+		{
+			name:        "Timeout not yet passed",
+			orderID:     "notExpired",
+			role:        models.RoleVendor,
+			timeout:     time.Hour * 48,
+			fundedAge:   time.Hour,
+			expectedErr: coreiface.ErrBadRequest,
+		},
+		{
+			name:        "Buyer cannot release",
+			orderID:     "buyer",
+			role:        models.RoleBuyer,
+			timeout:     time.Hour,
+			fundedAge:   time.Hour * 2,
+			expectedErr: coreiface.ErrBadRequest,
+		},
+		{
+			name:        "No escrow timeout",
+			orderID:     "noTimeout",
+			role:        models.RoleVendor,
+			timeout:     0,
+			fundedAge:   time.Hour * 2,
+			expectedErr: coreiface.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		if err := saveOrder(test.orderID, test.role, test.timeout, test.fundedAge); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		err := mockNode.ReleaseEscrowAfterTimeout(test.orderID, nil)
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectedErr, err)
+		}
+	}
+
+	var order models.Order
+	err = mockNode.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", "expired").First(&order).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status() != models.StatusPaymentFinalized {
+		t.Errorf("Expected status %s, got %s", models.StatusPaymentFinalized, order.Status())
+	}
+	txs, err := order.GetTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(txs))
+	}
+	if len(txs[1].From) != 1 || string(txs[1].From[0].ID) != "expired" {
+		t.Error("Release transaction does not spend the escrow output")
+	}
+
+	if err := mockNode.ReleaseEscrowAfterTimeout("expired", nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest releasing twice, got %v", err)
+	}
+	if err := mockNode.ReleaseEscrowAfterTimeout("unknown", nil); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
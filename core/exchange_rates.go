@@ -0,0 +1,73 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/accounting"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// GetHistoricalRate returns the exchange rate from the base currency to the
+// given currency which was saved closest to the timestamp. Rates are saved
+// when orders are opened and when order payments are received.
+func (n *OpenBazaarNode) GetHistoricalRate(base, currency models.CurrencyCode, timestamp time.Time) (*models.ExchangeRateRecord, error) {
+	var record *models.ExchangeRateRecord
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		var err error
+		record, err = accounting.ClosestRate(tx, base, currency, timestamp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%w: no %s/%s exchange rate saved", coreiface.ErrNotFound, strings.TrimPrefix(base.String(), "T"), currency)
+	}
+	return record, nil
+}
+
+// GetOrderFiatValues returns the value of the order total and of each
+// payment using the exchange rates saved when the order was opened and when
+// each payment was received.
+func (n *OpenBazaarNode) GetOrderFiatValues(orderID models.OrderID) ([]models.FiatValue, error) {
+	order, err := n.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	var records []models.ExchangeRateRecord
+	err = n.repo.DB().View(func(tx database.Tx) error {
+		records, err = loadOrderRates(tx, []models.OrderID{orderID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	values, err := order.FiatValues(records)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = []models.FiatValue{}
+	}
+	return values, nil
+}
+
+// loadOrderRates loads the saved exchange rates for the orders sorted by
+// timestamp.
+func loadOrderRates(tx database.Tx, orderIDs []models.OrderID) ([]models.ExchangeRateRecord, error) {
+	ids := make([]string, 0, len(orderIDs))
+	for _, id := range orderIDs {
+		ids = append(ids, id.String())
+	}
+	var records []models.ExchangeRateRecord
+	err := tx.Read().Where("order_id IN ?", ids).Order("timestamp, id").Find(&records).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"testing"
+	"time"
+)
+
+func TestOpenBazaarNode_GetHistoricalRate(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	now := time.Now().UTC()
+	err = mockNode.repo.DB().Update(func(tx database.Tx) error {
+		for i, age := range []time.Duration{time.Hour * 24, time.Hour * 12, time.Hour} {
+			err := tx.Save(&models.ExchangeRateRecord{
+				Base:      "BTC",
+				Currency:  "USD",
+				Rate:      iwallet.NewAmount(1000000 + i).String(),
+				Timestamp: now.Add(-age),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Save(&models.ExchangeRateRecord{
+			Base:      "BTC",
+			Currency:  "EUR",
+			Rate:      "900000",
+			Timestamp: now.Add(-time.Hour * 2),
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		base      models.CurrencyCode
+		currency  models.CurrencyCode
+		timestamp time.Time
+		expected  string
+		notFound  bool
+	}{
+		{base: "BTC", currency: "USD", timestamp: now.Add(-time.Hour * 48), expected: "1000000"},
+		{base: "BTC", currency: "USD", timestamp: now.Add(-time.Hour * 17), expected: "1000001"},
+		{base: "BTC", currency: "USD", timestamp: now.Add(-time.Hour * 19), expected: "1000000"},
+		{base: "BTC", currency: "USD", timestamp: now.Add(-time.Hour * 2), expected: "1000002"},
+		{base: "TBTC", currency: "usd", timestamp: now, expected: "1000002"},
+		{base: "BTC", currency: "EUR", timestamp: now, expected: "900000"},
+		{base: "BTC", currency: "JPY", timestamp: now, notFound: true},
+	}
+
+	for i, test := range tests {
+		record, err := mockNode.GetHistoricalRate(test.base, test.currency, test.timestamp)
+		if test.notFound {
+			if !errors.Is(err, coreiface.ErrNotFound) {
+				t.Errorf("Test %d: expected not found error, got %v", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: %s", i, err)
+			continue
+		}
+		if record.Rate != test.expected {
+			t.Errorf("Test %d: expected rate %s, got %s", i, test.expected, record.Rate)
+		}
+	}
+}
+
+func TestOpenBazaarNode_GetOrderFiatValues(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	orderOpen, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	err = mockNode.repo.DB().Update(func(tx database.Tx) error {
+		order := models.Order{ID: "1234", PaymentAddress: orderOpen.Payment.Address}
+		order.SetRole(models.RoleVendor)
+		if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+			return err
+		}
+		err := order.PutTransaction(iwallet.Transaction{
+			ID: "abc",
+			To: []iwallet.SpendInfo{
+				{
+					Address: iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CtMock),
+					Amount:  iwallet.NewAmount(orderOpen.Payment.Amount),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(&order); err != nil {
+			return err
+		}
+		err = tx.Save(&models.ExchangeRateRecord{
+			Base:      "MCK",
+			Currency:  "USD",
+			Rate:      "250000",
+			Timestamp: now.Add(-time.Hour),
+			OrderID:   order.ID,
+			Event:     models.RateEventOrderOpen,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Save(&models.ExchangeRateRecord{
+			Base:          "MCK",
+			Currency:      "USD",
+			Rate:          "300000",
+			Timestamp:     now,
+			OrderID:       order.ID,
+			Event:         models.RateEventPayment,
+			TransactionID: "abc",
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := mockNode.GetOrderFiatValues("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 values, got %d", len(values))
+	}
+	if values[0].Event != models.RateEventOrderOpen || values[0].Amount != "12480" {
+		t.Errorf("Incorrect order open value: %v", values[0])
+	}
+	if values[1].Event != models.RateEventPayment || values[1].Amount != "14976" {
+		t.Errorf("Incorrect payment value: %v", values[1])
+	}
+
+	summaries, err := mockNode.GetSales(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || len(summaries[0].FiatValues) != 2 {
+		t.Fatalf("Expected summary with fiat values, got %v", summaries)
+	}
+
+	if _, err := mockNode.GetOrderFiatValues("5678"); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
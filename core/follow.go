@@ -141,7 +141,7 @@ func (n *OpenBazaarNode) GetMyFollowers() (models.Followers, error) {
 	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
-	return followers, nil
+	return n.filterBlockedFollowers(followers), nil
 }
 
 // GetMyFollowing returns the following list for this node.
@@ -181,7 +181,7 @@ func (n *OpenBazaarNode) GetFollowers(ctx context.Context, peerID peer.ID, useCa
 			return nil, fmt.Errorf("%w: %s", coreiface.ErrNotFound, err)
 		}
 	}
-	return followers, nil
+	return n.filterBlockedFollowers(followers), nil
 }
 
 // GetFollowing returns the following of the node with the given peer ID.
@@ -208,6 +208,18 @@ func (n *OpenBazaarNode) GetFollowing(ctx context.Context, peerID peer.ID, useCa
 	return following, nil
 }
 
+// filterBlockedFollowers returns the followers list with any peers
+// we have blocked removed.
+func (n *OpenBazaarNode) filterBlockedFollowers(followers models.Followers) models.Followers {
+	filtered := make(models.Followers, 0, len(followers))
+	for _, f := range followers {
+		if !n.isBlocked(f) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
 // handleFollowMessage handles incoming follow messages from the network.
 func (n *OpenBazaarNode) handleFollowMessage(from peer.ID, message *pb.Message) error {
 	defer n.sendAckMessage(message.MessageID, from)
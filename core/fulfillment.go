@@ -11,15 +11,18 @@ import (
 	"github.com/cpacia/openbazaar3.0/orders/utils"
 	iwallet "github.com/cpacia/wallet-interface"
 	"github.com/golang/protobuf/ptypes"
+	"gorm.io/gorm"
 )
 
 // FulfillOrder sends an order fulfillment to the remote peer and updates the order state.
 func (n *OpenBazaarNode) FulfillOrder(orderID models.OrderID, fulfillments []models.Fulfillment, done chan struct{}) error {
 	var order models.Order
 	err := n.repo.DB().View(func(tx database.Tx) error {
-		return tx.Read().Where("id = ?", orderID.String()).Find(&order).Error
+		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
 	})
-	if err != nil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
 		return err
 	}
 
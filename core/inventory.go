@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/database/ffsqlite"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/orders"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	ipath "github.com/ipfs/interface-go-ipfs-core/path"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"os"
+)
+
+// GetMyInventory returns the quantity in stock for each SKU of our listings.
+func (n *OpenBazaarNode) GetMyInventory() (models.Inventory, error) {
+	var (
+		inventory models.Inventory
+		err       error
+	)
+	err = n.repo.DB().View(func(tx database.Tx) error {
+		inventory, err = tx.GetInventory()
+		return err
+	})
+	if os.IsNotExist(err) {
+		return models.Inventory{}, nil
+	}
+	return inventory, err
+}
+
+// GetInventory returns the inventory for the node with the given peer ID.
+// If useCache is set it will return the inventory from the local cache
+// (if it has one) if the inventory file is not found on the network.
+func (n *OpenBazaarNode) GetInventory(ctx context.Context, peerID peer.ID, useCache bool) (models.Inventory, error) {
+	pth, err := n.resolve(ctx, peerID, useCache)
+	if err != nil {
+		return nil, err
+	}
+	inventoryBytes, err := n.cat(ctx, ipath.Join(pth, ffsqlite.InventoryFile))
+	if err != nil {
+		return nil, err
+	}
+	var inventory models.Inventory
+	if err := json.Unmarshal(inventoryBytes, &inventory); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// checkVendorInventory fetches the vendor's inventory and returns an error
+// if any of the items in the order are out of stock. Vendors who do not
+// publish an inventory are assumed to have everything in stock.
+func (n *OpenBazaarNode) checkVendorInventory(ctx context.Context, vendor peer.ID, orderOpen *pb.OrderOpen) error {
+	inventory, err := n.GetInventory(ctx, vendor, true)
+	if err != nil {
+		log.Debugf("Unable to load inventory for vendor %s: %s", vendor, err)
+		return nil
+	}
+	if err := orders.CheckInventory(inventory, orderOpen); errors.Is(err, orders.ErrOutOfStock) {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// inventoryPublishHandler publishes whenever order activity changes our
+// inventory so that buyers see the current stock.
+func (n *OpenBazaarNode) inventoryPublishHandler() {
+	sub, err := n.eventBus.Subscribe(&events.InventoryUpdated{})
+	if err != nil {
+		log.Errorf("Error subscribing to inventory updates: %s", err)
+		return
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-sub.Out():
+			n.Publish(nil)
+		case <-n.shutdown:
+			return
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"testing"
+	"time"
+)
+
+func TestOpenBazaarNode_Inventory(t *testing.T) {
+	network, err := NewMocknet(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.TearDown()
+
+	listing := factory.NewPhysicalListing("tshirt")
+	listing.Item.Skus[0].Quantity = "0"
+
+	done := make(chan struct{})
+	if err := network.Nodes()[0].SaveListing(listing, done); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	myListing, err := network.Nodes()[0].GetMyListingBySlug("tshirt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if myListing.Listing.Item.Skus[0].Quantity != "0" || myListing.Listing.Item.Skus[1].Quantity != "44" {
+		t.Error("Listing quantities not restored from inventory")
+	}
+
+	inventory, err := network.Nodes()[0].GetMyInventory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, ok := inventory.Get("tshirt", "Color:Red,Size:Large")
+	if !ok {
+		t.Fatal("Inventory item not found")
+	}
+	if item.Quantity != "0" {
+		t.Errorf("Expected quantity 0, got %s", item.Quantity)
+	}
+
+	err = network.Nodes()[0].repo.DB().View(func(tx database.Tx) error {
+		storedListing, err := tx.GetListing("tshirt")
+		if err != nil {
+			return err
+		}
+		for _, sku := range storedListing.Listing.Item.Skus {
+			if sku.Quantity != "" {
+				t.Errorf("Expected quantity to be removed from published listing, got %s", sku.Quantity)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.Nodes()[0].DeleteListing("tshirt", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	inventory, err = network.Nodes()[0].GetMyInventory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory) != 0 {
+		t.Errorf("Expected inventory to be deleted, got %d items", len(inventory))
+	}
+}
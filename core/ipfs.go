@@ -22,6 +22,7 @@ import (
 	nameopts "github.com/ipfs/interface-go-ipfs-core/options/namesys"
 	"github.com/ipfs/interface-go-ipfs-core/path"
 	peer "github.com/libp2p/go-libp2p-core/peer"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -41,6 +42,13 @@ const (
 
 // cat fetches a file from IPFS given a path.
 func (n *OpenBazaarNode) cat(ctx context.Context, pth path.Path) ([]byte, error) {
+	return n.catLimited(ctx, pth, 0)
+}
+
+// catLimited fetches a file from IPFS given a path. If maxSize is greater
+// than zero, no more than maxSize bytes are fetched and an error is returned
+// if the file is larger.
+func (n *OpenBazaarNode) catLimited(ctx context.Context, pth path.Path, maxSize int64) ([]byte, error) {
 	catDone := make(chan struct{})
 	ctx, cancel := context.WithTimeout(ctx, catTimeout)
 	defer func() {
@@ -72,7 +80,22 @@ func (n *OpenBazaarNode) cat(ctx context.Context, pth path.Path) ([]byte, error)
 		return nil, errors.New("incorrect type from Unixfs().Get()")
 	}
 
-	return ioutil.ReadAll(r)
+	if maxSize <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	if size, err := r.Size(); err == nil && size > maxSize {
+		return nil, fmt.Errorf("%w: file exceeds max size of %d bytes", coreiface.ErrBadRequest, maxSize)
+	}
+	// The size in the root node is set by whoever created the file
+	// so we also cap the number of bytes actually read.
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: file exceeds max size of %d bytes", coreiface.ErrBadRequest, maxSize)
+	}
+	return data, nil
 }
 
 // add imports the given file into ipfs and returns the cid.
@@ -108,10 +131,17 @@ func (n *OpenBazaarNode) add(ctx context.Context, filePath string) (cid.Cid, err
 // this function currently this is OK since we do a publish immediately after which would
 // re-pin any unpin objects in the data directory.
 func (n *OpenBazaarNode) cid(file []byte) (cid.Cid, error) {
-	api, err := coreapi.NewCoreAPI(n.ipfsNode)
+	cid, err := n.addBytes(context.Background(), file)
 	if err != nil {
-		return cid.Cid{}, err
+		return cid, err
 	}
+
+	return cid, n.unpin(context.Background(), path.IpfsPath(cid))
+}
+
+// addBytes writes the data to a temp file, imports it into ipfs and
+// returns the cid. The file is pinned.
+func (n *OpenBazaarNode) addBytes(ctx context.Context, file []byte) (cid.Cid, error) {
 	b := make([]byte, 20)
 	rand.Read(b)
 	dir := gopath.Join(os.TempDir(), "openbazaar-files")
@@ -125,17 +155,7 @@ func (n *OpenBazaarNode) cid(file []byte) (cid.Cid, error) {
 		return cid.Cid{}, err
 	}
 
-	cid, err := n.add(context.Background(), pth)
-	if err != nil {
-		return cid, err
-	}
-
-	rp, err := api.ResolvePath(context.Background(), path.IpfsPath(cid))
-	if err != nil {
-		return cid, err
-	}
-
-	return cid, api.Pin().Rm(context.Background(), rp, options.Pin.RmRecursive(true))
+	return n.add(ctx, pth)
 }
 
 // pin fetches a file from IPFS given a path and pins it.
@@ -164,6 +184,21 @@ func (n *OpenBazaarNode) pin(ctx context.Context, pth path.Path) error {
 	return api.Pin().Add(ctx, pth)
 }
 
+// unpin removes the recursive pin for the given path.
+func (n *OpenBazaarNode) unpin(ctx context.Context, pth path.Path) error {
+	api, err := coreapi.NewCoreAPI(n.ipfsNode)
+	if err != nil {
+		return err
+	}
+
+	rp, err := api.ResolvePath(ctx, pth)
+	if err != nil {
+		return err
+	}
+
+	return api.Pin().Rm(ctx, rp, options.Pin.RmRecursive(true))
+}
+
 // resolve an IPNS record. This is a multi-step process.
 // If the usecache flag is provided we will attempt to load the record from the database. If
 // it succeeds we will update the cache in a separate goroutine.
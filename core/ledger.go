@@ -0,0 +1,28 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/accounting"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/models"
+	"io"
+)
+
+// ExportLedger writes an accounting ledger of our order payments, refunds,
+// escrow releases, moderator fees and other wallet transactions matching
+// the query to the writer in the given format (csv or json). Entries are
+// written as they are built rather than sorted.
+func (n *OpenBazaarNode) ExportLedger(query *models.LedgerQuery, format string, w io.Writer) error {
+	lw, err := accounting.NewLedgerWriter(w, format)
+	if err != nil {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+	err = accounting.StreamLedger(n.repo.DB(), n.multiwallet, query, lw.Write)
+	if errors.Is(err, accounting.ErrUnknownRole) {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+	return lw.Close()
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"testing"
+)
+
+func TestOpenBazaarNode_ExportLedger(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	orderOpen, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mockNode.repo.DB().Update(func(tx database.Tx) error {
+		order := models.Order{ID: "1234"}
+		order.SetRole(models.RoleVendor)
+		if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+			return err
+		}
+		err := order.PutTransaction(iwallet.Transaction{
+			ID: "abc",
+			To: []iwallet.SpendInfo{
+				{
+					Address: iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CtMock),
+					Amount:  iwallet.NewAmount(orderOpen.Payment.Amount),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Save(&order)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := mockNode.ExportLedger(&models.LedgerQuery{Role: models.RoleVendor}, "json", &buf); err != nil {
+		t.Fatal(err)
+	}
+	var entries []models.LedgerEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Type != models.LedgerPayment || entries[0].OrderID != "1234" || entries[0].Amount != "0.04992221" {
+		t.Errorf("Incorrect ledger: %v", entries)
+	}
+
+	if err := mockNode.ExportLedger(nil, "xml", &buf); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+	if err := mockNode.ExportLedger(&models.LedgerQuery{Role: "shopper"}, "csv", &buf); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/database/ffsqlite"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/orders"
 	"github.com/cpacia/openbazaar3.0/orders/pb"
 	"github.com/cpacia/openbazaar3.0/orders/utils"
 	"github.com/golang/protobuf/jsonpb"
@@ -103,6 +104,9 @@ func (n *OpenBazaarNode) DeleteListing(slug string, done chan<- struct{}) error
 		if err := tx.Delete("slug", slug, nil, &models.Coupon{}); err != nil {
 			return err
 		}
+		if err := orders.DeleteListingInventory(tx, slug); err != nil {
+			return err
+		}
 
 		index, err := tx.GetListingIndex()
 		if err != nil {
@@ -187,6 +191,9 @@ func (n *OpenBazaarNode) GetMyListingBySlug(slug string) (*pb.SignedListing, err
 		if err != nil {
 			return fmt.Errorf("%w: listing not found", coreiface.ErrNotFound)
 		}
+		if err := orders.ApplyListingInventory(tx, listing.Listing); err != nil {
+			return err
+		}
 		if err := tx.Read().Where("slug = ?", slug).Find(&coupons).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
 			return err
 		}
@@ -224,6 +231,9 @@ func (n *OpenBazaarNode) GetMyListingByCID(cid cid.Cid) (*pb.SignedListing, erro
 		if err != nil {
 			return fmt.Errorf("%w: listing not found", coreiface.ErrNotFound)
 		}
+		if err := orders.ApplyListingInventory(tx, listing.Listing); err != nil {
+			return err
+		}
 		if err := tx.Read().Where("slug = ?", slug).Find(&coupons).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
 			return err
 		}
@@ -321,6 +331,10 @@ func (n *OpenBazaarNode) updateAllListings(tx database.Tx, updateFunc func(l *pb
 		}
 		listing := signedListing.Listing
 
+		if err := orders.ApplyListingInventory(tx, listing); err != nil {
+			return false, err
+		}
+
 		updated, err := updateFunc(listing)
 		if err != nil {
 			return false, err
@@ -473,6 +487,11 @@ func (n *OpenBazaarNode) saveListingToDB(dbtx database.Tx, listing *pb.Listing)
 		}
 	}
 
+	// Move the SKU quantities into the inventory.
+	if err := orders.SetListingInventory(dbtx, listing); err != nil {
+		return cid.Cid{}, fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+
 	// Sign listing
 	sl, err := n.signListing(listing)
 	if err != nil {
@@ -127,10 +127,11 @@ func MockNode() (*OpenBazaarNode, error) {
 	}
 
 	node.messenger, err = net.NewMessenger(&net.MessengerConfig{
-		Privkey: ipfsNode.PrivateKey,
-		Service: service,
-		DB:      r.DB(),
-		Context: ipfsNode.Context(),
+		Privkey:  ipfsNode.PrivateKey,
+		Service:  service,
+		DB:       r.DB(),
+		Context:  ipfsNode.Context(),
+		EventBus: node.eventBus,
 	})
 	if err != nil {
 		return nil, err
@@ -141,6 +142,7 @@ func MockNode() (*OpenBazaarNode, error) {
 		Db:                   r.DB(),
 		Multiwallet:          mw,
 		Messenger:            node.messenger,
+		BanManager:           banManager,
 		EscrowPrivateKey:     escrowKey,
 		ExchangeRateProvider: erp,
 		EventBus:             bus,
@@ -272,10 +274,11 @@ func NewMocknet(numNodes int) (*Mocknet, error) {
 		}
 
 		node.messenger, err = net.NewMessenger(&net.MessengerConfig{
-			Privkey: ipfsNode.PrivateKey,
-			Service: service,
-			DB:      r.DB(),
-			Context: ipfsNode.Context(),
+			Privkey:  ipfsNode.PrivateKey,
+			Service:  service,
+			DB:       r.DB(),
+			Context:  ipfsNode.Context(),
+			EventBus: node.eventBus,
 		})
 		if err != nil {
 			return nil, err
@@ -285,6 +288,7 @@ func NewMocknet(numNodes int) (*Mocknet, error) {
 			IdentityPrivateKey:   ipfsNode.PrivateKey,
 			Db:                   r.DB(),
 			Messenger:            node.messenger,
+			BanManager:           banManager,
 			Multiwallet:          mw,
 			EscrowPrivateKey:     escrowKey,
 			ExchangeRateProvider: erp,
@@ -2,7 +2,8 @@ package core
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/orders/pb"
@@ -30,7 +31,7 @@ const (
 func (n *OpenBazaarNode) SetSelfAsModerator(ctx context.Context, modInfo *models.ModeratorInfo, done chan struct{}) error {
 	if (int(modInfo.Fee.FeeType) == 0 || int(modInfo.Fee.FeeType) == 2) && modInfo.Fee.FixedFee == nil {
 		maybeCloseDone(done)
-		return errors.New("fixed fee must be set when using a fixed fee type")
+		return fmt.Errorf("%w: fixed fee must be set when using a fixed fee type", coreiface.ErrBadRequest)
 	}
 
 	err := n.repo.DB().Update(func(tx database.Tx) error {
@@ -98,7 +99,8 @@ func (n *OpenBazaarNode) RemoveSelfAsModerator(ctx context.Context, done chan<-
 		if err != nil {
 			return err
 		}
-		profile.Moderator = true
+		profile.ModeratorInfo = nil
+		profile.Moderator = false
 
 		if err := tx.SetProfile(profile); err != nil {
 			return err
@@ -2,6 +2,8 @@ package core
 
 import (
 	"context"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/models/factory"
 	peer "github.com/libp2p/go-libp2p-core/peer"
@@ -38,6 +40,14 @@ func TestOpenBazaarNode_SetAndRemoveSelfAsModerator(t *testing.T) {
 		t.Fatal("Timeout waiting on channel")
 	}
 
+	profile, err := node.GetMyProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !profile.Moderator || profile.ModeratorInfo == nil {
+		t.Error("Profile not set as moderator")
+	}
+
 	done2 := make(chan struct{})
 	if err := node.RemoveSelfAsModerator(context.Background(), done2); err != nil {
 		t.Fatal(err)
@@ -47,6 +57,19 @@ func TestOpenBazaarNode_SetAndRemoveSelfAsModerator(t *testing.T) {
 	case <-time.After(time.Second * 10):
 		t.Fatal("Timeout waiting on channel")
 	}
+
+	profile, err = node.GetMyProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Moderator || profile.ModeratorInfo != nil {
+		t.Error("Profile still set as moderator")
+	}
+
+	modInfo.Fee.FeeType = models.FixedFee
+	if err := node.SetSelfAsModerator(context.Background(), modInfo, nil); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request for missing fixed fee, got %v", err)
+	}
 }
 
 func TestOpenBazaarNode_GetModerators(t *testing.T) {
@@ -8,7 +8,9 @@ import (
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/net"
 	"github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/ipfs/go-cid"
@@ -257,6 +259,11 @@ func (n *OpenBazaarNode) handleOrderMessage(from peer.ID, message *pb.Message) e
 	}
 	order := new(pb.OrderMessage)
 	if err := ptypes.UnmarshalAny(message.Payload, order); err != nil {
+		n.networkService.Misbehaving(from, net.ScoreMalformedMessage, "malformed order message")
+		return err
+	}
+	if err := utils.VerifyOrderMessageSignature(order, from); err != nil {
+		n.networkService.Misbehaving(from, net.ScoreInvalidSignature, "invalid order message signature")
 		return err
 	}
 
@@ -321,6 +328,12 @@ func (n *OpenBazaarNode) handleStoreMessage(from peer.ID, message *pb.Message) e
 	return nil
 }
 
+// GetRateLimitMetrics returns the counters from the network rate limiter
+// including the misbehavior score of each peer we've heard from recently.
+func (n *OpenBazaarNode) GetRateLimitMetrics() models.RateLimitMetrics {
+	return n.networkService.RateLimitMetrics()
+}
+
 // isDuplicate checks if the message ID exists in the incoming messages database.
 func (n *OpenBazaarNode) isDuplicate(message *pb.Message) bool {
 	err := n.repo.DB().View(func(tx database.Tx) error {
@@ -348,7 +361,7 @@ func (n *OpenBazaarNode) syncMessages() {
 			}
 			var messages []models.OutgoingMessage
 			err = n.repo.DB().View(func(tx database.Tx) error {
-				return tx.Read().Where("recipient = ?", notif.Peer.Pretty()).Find(&messages).Error
+				return tx.Read().Where("recipient = ? AND failed = ?", notif.Peer.Pretty(), false).Find(&messages).Error
 			})
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 				log.Error("syncMessages outgoing messages lookup error: %s", err)
@@ -112,6 +112,10 @@ type OpenBazaarNode struct {
 	// channels holds active chat channels
 	channels map[string]*channels.Channel
 
+	// backupSchedule holds the options for scheduled backups. If it is nil
+	// scheduled backups are disabled.
+	backupSchedule *backupSchedule
+
 	// shutdownTorFunc is used to shutdown the embedded Tor client.
 	shutdownTorFunc func() error
 
@@ -131,6 +135,7 @@ func (n *OpenBazaarNode) Start() {
 		go n.messenger.Start()
 		go n.followerTracker.Start()
 		go n.orderProcessor.Start()
+		go n.inventoryPublishHandler()
 		go n.syncMessages()
 		go func() {
 			n.multiwallet.Start()
@@ -139,6 +144,10 @@ func (n *OpenBazaarNode) Start() {
 		go n.gateway.Serve()
 		go n.notifier.Start()
 		go n.OpenSavedChannels()
+		go n.pendingChatAttachmentLoop()
+		if n.backupSchedule != nil {
+			go n.scheduledBackupLoop()
+		}
 		if err := n.removeDisabledCoinsFromListings(); err != nil && !os.IsNotExist(err) {
 			log.Errorf("Error removing disabled coins from listings: %s", err)
 		}
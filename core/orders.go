@@ -0,0 +1,141 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// GetOrder returns the order with the given ID. The returned order
+// marshals to JSON as the full contract.
+func (n *OpenBazaarNode) GetOrder(orderID models.OrderID) (*models.Order, error) {
+	var order models.Order
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := order.OrderOpenMessage(); err != nil {
+		// The order only contains parked messages.
+		return nil, fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	}
+	return &order, nil
+}
+
+// GetOrders returns summaries of all the orders matching the query sorted
+// by timestamp, newest first.
+func (n *OpenBazaarNode) GetOrders(query *models.OrderQuery) ([]models.OrderSummary, error) {
+	if query == nil {
+		query = &models.OrderQuery{}
+	}
+	if query.Role != "" && query.Role != models.RoleBuyer && query.Role != models.RoleVendor {
+		return nil, fmt.Errorf("%w: unknown role %s", coreiface.ErrBadRequest, query.Role)
+	}
+
+	var (
+		orders []models.Order
+		rates  = make(map[models.OrderID][]models.ExchangeRateRecord)
+	)
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		db := tx.Read()
+		if query.Role != "" {
+			db = db.Where("my_role = ?", string(query.Role))
+		} else {
+			db = db.Where("my_role IN ?", []string{string(models.RoleBuyer), string(models.RoleVendor)})
+		}
+		if err := db.Find(&orders).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		orderIDs := make([]models.OrderID, 0, len(orders))
+		for _, order := range orders {
+			orderIDs = append(orderIDs, order.ID)
+		}
+		records, err := loadOrderRates(tx, orderIDs)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			rates[record.OrderID] = append(rates[record.OrderID], record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.OrderSummary, 0, len(orders))
+	for i := range orders {
+		match, err := query.Matches(&orders[i])
+		if err != nil {
+			log.Errorf("Error matching order %s against query: %s", orders[i].ID, err)
+			continue
+		}
+		if !match {
+			continue
+		}
+		summary, err := models.NewOrderSummary(&orders[i])
+		if err != nil {
+			log.Errorf("Error building summary for order %s: %s", orders[i].ID, err)
+			continue
+		}
+		summary.FiatValues, err = orders[i].FiatValues(rates[orders[i].ID])
+		if err != nil {
+			log.Errorf("Error calculating fiat values for order %s: %s", orders[i].ID, err)
+		}
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Timestamp.Equal(summaries[j].Timestamp) {
+			return summaries[i].OrderID > summaries[j].OrderID
+		}
+		return summaries[i].Timestamp.After(summaries[j].Timestamp)
+	})
+
+	if query.OffsetID != "" {
+		found := false
+		for i, summary := range summaries {
+			if summary.OrderID == query.OffsetID {
+				summaries = summaries[i+1:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: offset order not found", coreiface.ErrNotFound)
+		}
+	}
+
+	if query.Limit > 0 && len(summaries) > query.Limit {
+		summaries = summaries[:query.Limit]
+	}
+	return summaries, nil
+}
+
+// GetSales returns summaries of the orders in which we are the vendor.
+func (n *OpenBazaarNode) GetSales(query *models.OrderQuery) ([]models.OrderSummary, error) {
+	q := models.OrderQuery{}
+	if query != nil {
+		q = *query
+	}
+	q.Role = models.RoleVendor
+	return n.GetOrders(&q)
+}
+
+// GetPurchases returns summaries of the orders in which we are the buyer.
+func (n *OpenBazaarNode) GetPurchases(query *models.OrderQuery) ([]models.OrderSummary, error) {
+	q := models.OrderQuery{}
+	if query != nil {
+		q = *query
+	}
+	q.Role = models.RoleBuyer
+	return n.GetOrders(&q)
+}
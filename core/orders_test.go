@@ -0,0 +1,192 @@
+package core
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/ptypes"
+	"testing"
+	"time"
+)
+
+func TestOpenBazaarNode_GetOrders(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	var (
+		now    = time.Now()
+		orders = []struct {
+			id     models.OrderID
+			role   models.OrderRole
+			title  string
+			age    time.Duration
+			funded bool
+			reject bool
+		}{
+			{id: "order1", role: models.RoleBuyer, title: "Blue shirt size M", age: time.Hour * 3},
+			{id: "order2", role: models.RoleVendor, title: "Red shirt", age: time.Hour * 2, funded: true},
+			{id: "order3", role: models.RoleVendor, title: "Ceramic mug", age: time.Hour, reject: true},
+			{id: "order4", role: models.RoleVendor, title: "Blue mug", age: 0, funded: true},
+		}
+	)
+
+	err = mockNode.repo.DB().Update(func(tx database.Tx) error {
+		for _, o := range orders {
+			orderOpen, err := factory.NewOrder()
+			if err != nil {
+				return err
+			}
+			orderOpen.Listings[0].Listing.Item.Title = o.title
+			orderOpen.Timestamp, err = ptypes.TimestampProto(now.Add(-o.age))
+			if err != nil {
+				return err
+			}
+
+			order := models.Order{ID: o.id}
+			order.SetRole(o.role)
+			if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+				return err
+			}
+			if o.funded {
+				err := order.PutTransaction(iwallet.Transaction{
+					ID: iwallet.TransactionID(o.id),
+					To: []iwallet.SpendInfo{
+						{
+							Address: iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CtMock),
+							Amount:  iwallet.NewAmount(orderOpen.Payment.Amount),
+						},
+					},
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if o.reject {
+				if err := order.PutMessage(utils.MustWrapOrderMessage(&pb.OrderReject{Reason: "sold out"})); err != nil {
+					return err
+				}
+			}
+			if err := tx.Save(&order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tru := true
+	tests := []struct {
+		name     string
+		getFunc  func(query *models.OrderQuery) ([]models.OrderSummary, error)
+		query    *models.OrderQuery
+		expected []models.OrderID
+	}{
+		{
+			name:     "all orders",
+			getFunc:  mockNode.GetOrders,
+			query:    nil,
+			expected: []models.OrderID{"order4", "order3", "order2", "order1"},
+		},
+		{
+			name:     "sales",
+			getFunc:  mockNode.GetSales,
+			query:    &models.OrderQuery{},
+			expected: []models.OrderID{"order4", "order3", "order2"},
+		},
+		{
+			name:     "purchases",
+			getFunc:  mockNode.GetPurchases,
+			query:    &models.OrderQuery{},
+			expected: []models.OrderID{"order1"},
+		},
+		{
+			name:     "open sales",
+			getFunc:  mockNode.GetSales,
+			query:    &models.OrderQuery{Open: &tru},
+			expected: []models.OrderID{"order4", "order2"},
+		},
+		{
+			name:     "status filter",
+			getFunc:  mockNode.GetOrders,
+			query:    &models.OrderQuery{Statuses: []models.OrderStatus{models.StatusAwaitingPayment, models.StatusRejected}},
+			expected: []models.OrderID{"order3", "order1"},
+		},
+		{
+			name:     "search",
+			getFunc:  mockNode.GetOrders,
+			query:    &models.OrderQuery{Search: "blue"},
+			expected: []models.OrderID{"order4", "order1"},
+		},
+		{
+			name:     "date range",
+			getFunc:  mockNode.GetOrders,
+			query:    &models.OrderQuery{From: now.Add(-time.Minute * 150), To: now.Add(-time.Minute * 30)},
+			expected: []models.OrderID{"order3", "order2"},
+		},
+		{
+			name:     "pagination first page",
+			getFunc:  mockNode.GetOrders,
+			query:    &models.OrderQuery{Limit: 2},
+			expected: []models.OrderID{"order4", "order3"},
+		},
+		{
+			name:     "pagination second page",
+			getFunc:  mockNode.GetOrders,
+			query:    &models.OrderQuery{Limit: 2, OffsetID: "order3"},
+			expected: []models.OrderID{"order2", "order1"},
+		},
+	}
+
+	for _, test := range tests {
+		summaries, err := test.getFunc(test.query)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if len(summaries) != len(test.expected) {
+			t.Errorf("%s: expected %d orders, got %d", test.name, len(test.expected), len(summaries))
+			continue
+		}
+		for i, summary := range summaries {
+			if summary.OrderID != test.expected[i] {
+				t.Errorf("%s: expected order %s at position %d, got %s", test.name, test.expected[i], i, summary.OrderID)
+			}
+		}
+	}
+
+	_, err = mockNode.GetOrders(&models.OrderQuery{OffsetID: "abc"})
+	if !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found error for unknown offset ID, got %v", err)
+	}
+
+	_, err = mockNode.GetOrders(&models.OrderQuery{Role: models.RoleModerator})
+	if !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error for moderator role, got %v", err)
+	}
+
+	order, err := mockNode.GetOrder("order2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status() != models.StatusFunded {
+		t.Errorf("Expected status %s, got %s", models.StatusFunded, order.Status())
+	}
+	if _, err := order.MarshalJSON(); err != nil {
+		t.Errorf("Failed to render contract: %s", err)
+	}
+
+	_, err = mockNode.GetOrder("abc")
+	if !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"gorm.io/gorm"
+)
+
+// GetOutgoingMessages returns the messages in the outbox. These are messages
+// we've sent which have not yet been ACKed by the recipient, including order
+// messages which expired and were marked failed.
+func (n *OpenBazaarNode) GetOutgoingMessages() ([]models.OutboxMessage, error) {
+	var messages []models.OutgoingMessage
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Order("timestamp desc").Find(&messages).Error
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	ret := make([]models.OutboxMessage, 0, len(messages))
+	for _, message := range messages {
+		ret = append(ret, message.OutboxMessage())
+	}
+	return ret, nil
+}
+
+// RetryOutgoingMessage immediately tries to send the outgoing message
+// with the given ID rather than waiting for the next retry.
+func (n *OpenBazaarNode) RetryOutgoingMessage(messageID string) error {
+	err := n.messenger.RetryMessage(messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: outgoing message", coreiface.ErrNotFound)
+	}
+	return err
+}
+
+// CancelOutgoingMessage deletes the outgoing message with the given ID
+// from the outbox so it's no longer sent.
+func (n *OpenBazaarNode) CancelOutgoingMessage(messageID string) error {
+	err := n.messenger.CancelMessage(messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: outgoing message", coreiface.ErrNotFound)
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/orders/pb"
 	peer "github.com/libp2p/go-libp2p-core/peer"
@@ -27,7 +28,10 @@ func (n *OpenBazaarNode) GetPreferences() (*models.UserPreferences, error) {
 // SavePreferences saves the preferences in the database and updates the moderators
 // on the store of they are different.
 func (n *OpenBazaarNode) SavePreferences(prefs *models.UserPreferences, done chan struct{}) error {
-	var modsChanged bool
+	var (
+		modsChanged           bool
+		newlyBlocked, removed []peer.ID
+	)
 	err := n.repo.DB().Update(func(tx database.Tx) error {
 		var (
 			currentPrefs  models.UserPreferences
@@ -64,10 +68,15 @@ func (n *OpenBazaarNode) SavePreferences(prefs *models.UserPreferences, done cha
 		}
 
 		// Validate blocked nodes
-		_, err = prefs.BlockedNodes()
+		blocked, err := prefs.BlockedNodes()
 		if err != nil {
 			return fmt.Errorf("%w: invalid block node ID", coreiface.ErrBadRequest)
 		}
+		currentBlocked, err := currentPrefs.BlockedNodes()
+		if err != nil {
+			return err
+		}
+		newlyBlocked, removed = diffPeerIDs(currentBlocked, blocked)
 
 		currencies, err := prefs.PreferredCurrencies()
 		if err != nil {
@@ -83,6 +92,9 @@ func (n *OpenBazaarNode) SavePreferences(prefs *models.UserPreferences, done cha
 		if err := tx.Save(prefs); err != nil {
 			return err
 		}
+		tx.RegisterCommitHook(func() {
+			n.banManager.SetBlockedIds(blocked)
+		})
 		_, err = tx.GetListingIndex()
 		if modsChanged && !os.IsNotExist(err) {
 			modStrs := make([]string, 0, len(mods))
@@ -103,8 +115,36 @@ func (n *OpenBazaarNode) SavePreferences(prefs *models.UserPreferences, done cha
 		maybeCloseDone(done)
 		return err
 	}
+	for _, pid := range newlyBlocked {
+		n.eventBus.Emit(&events.PeerBlocked{PeerID: pid.Pretty()})
+	}
+	for _, pid := range removed {
+		n.eventBus.Emit(&events.PeerUnblocked{PeerID: pid.Pretty()})
+	}
 	if modsChanged {
 		n.Publish(done)
 	}
 	return nil
 }
+
+// diffPeerIDs returns the peer IDs which were added to and removed from the
+// current list.
+func diffPeerIDs(current, updated []peer.ID) (added, removed []peer.ID) {
+	currentMap := make(map[peer.ID]bool)
+	for _, pid := range current {
+		currentMap[pid] = true
+	}
+	updatedMap := make(map[peer.ID]bool)
+	for _, pid := range updated {
+		updatedMap[pid] = true
+		if !currentMap[pid] {
+			added = append(added, pid)
+		}
+	}
+	for _, pid := range current {
+		if !updatedMap[pid] {
+			removed = append(removed, pid)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/channels"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/net"
+	"github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// CreatePrivateChannel creates a private group channel owned by this node.
+// A group key is sent to each member using the messenger and the channel
+// is opened. Members must have ed25519 identity keys.
+func (n *OpenBazaarNode) CreatePrivateChannel(topic string, members []peer.ID) error {
+	if _, ok := n.channels[topic]; ok {
+		return fmt.Errorf("%w: channel already open", coreiface.ErrBadRequest)
+	}
+	err := n.repo.DB().Update(func(tx database.Tx) error {
+		err := tx.Read().Where("topic = ?", strings.ToLower(topic)).First(&models.Channel{}).Error
+		if err == nil {
+			return fmt.Errorf("%w: channel already exists", coreiface.ErrBadRequest)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		group, err := channels.LoadPrivateChannel(tx, topic)
+		if err != nil {
+			return err
+		}
+		if group != nil {
+			return fmt.Errorf("%w: private channel already exists", coreiface.ErrBadRequest)
+		}
+		return n.sendGroupKey(tx, topic, 1, members)
+	})
+	if err != nil {
+		return err
+	}
+	return n.OpenChannel(topic)
+}
+
+// UpdatePrivateChannelMembers adds and removes members of a private channel
+// owned by this node. The group key is rotated on every change so removed
+// members cannot read new messages and new members cannot read messages
+// sent before they joined.
+func (n *OpenBazaarNode) UpdatePrivateChannelMembers(topic string, add, remove []peer.ID) error {
+	return n.repo.DB().Update(func(tx database.Tx) error {
+		group, err := channels.LoadPrivateChannel(tx, topic)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return fmt.Errorf("%w: private channel not found", coreiface.ErrNotFound)
+		}
+		if group.Owner != n.Identity().Pretty() {
+			return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, channels.ErrNotOwner)
+		}
+
+		removed := make(map[string]bool)
+		for _, p := range remove {
+			if p == n.Identity() {
+				return fmt.Errorf("%w: the owner cannot be removed", coreiface.ErrBadRequest)
+			}
+			removed[p.Pretty()] = true
+		}
+		members := make([]peer.ID, 0, len(group.Members)+len(add))
+		for _, m := range group.Members {
+			if removed[m] {
+				continue
+			}
+			pid, err := peer.Decode(m)
+			if err != nil {
+				return err
+			}
+			members = append(members, pid)
+		}
+		return n.sendGroupKey(tx, topic, group.Epoch+1, append(members, add...))
+	})
+}
+
+// GetPrivateChannel returns the membership of the private channel.
+func (n *OpenBazaarNode) GetPrivateChannel(topic string) (*models.PrivateChannel, error) {
+	var group *models.PrivateChannel
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		var err error
+		group, err = channels.LoadPrivateChannel(tx, topic)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("%w: private channel not found", coreiface.ErrNotFound)
+	}
+	return group, nil
+}
+
+// sendGroupKey creates and saves the group key for the epoch and sends it
+// to each of the other members encrypted to their public key.
+func (n *OpenBazaarNode) sendGroupKey(tx database.Tx, topic string, epoch uint64, members []peer.ID) error {
+	gk, err := channels.NewGroupKey(n.ipfsNode.PrivateKey, topic, epoch, members)
+	if err != nil {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+	if err := channels.SaveGroupKey(tx, gk); err != nil {
+		return fmt.Errorf("%w: %s", coreiface.ErrBadRequest, err)
+	}
+
+	for _, m := range gk.MemberList.Members {
+		if m == n.Identity().Pretty() {
+			continue
+		}
+		pid, err := peer.Decode(m)
+		if err != nil {
+			return err
+		}
+		encryptedKey, err := channels.EncryptGroupKey(gk, pid)
+		if err != nil {
+			return fmt.Errorf("%w: member %s: %s", coreiface.ErrBadRequest, m, err)
+		}
+		payload, err := ptypes.MarshalAny(&pb.ChannelKeyMessage{
+			Topic:        gk.MemberList.Topic,
+			EncryptedKey: encryptedKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		msg := newMessageWithID()
+		msg.MessageType = pb.Message_CHANNEL_KEY
+		msg.Payload = payload
+
+		log.Debugf("Sending CHANNEL_KEY message to %s. MessageID: %s", pid, msg.MessageID)
+		if err := n.messenger.ReliablySendMessage(tx, pid, msg, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleChannelKeyMessage is the handler for the CHANNEL_KEY message. It
+// decrypts the group key for a private channel and saves it. The key is
+// only accepted if it was sent by the owner of the channel and we are in
+// the signed member list.
+func (n *OpenBazaarNode) handleChannelKeyMessage(from peer.ID, message *pb.Message) error {
+	defer n.sendAckMessage(message.MessageID, from)
+
+	if n.isDuplicate(message) {
+		return nil
+	}
+
+	keyMsg := new(pb.ChannelKeyMessage)
+	if err := ptypes.UnmarshalAny(message.Payload, keyMsg); err != nil {
+		n.networkService.Misbehaving(from, net.ScoreMalformedMessage, "malformed channel key message")
+		return err
+	}
+	gk, err := channels.DecryptGroupKey(n.ipfsNode.PrivateKey, keyMsg.EncryptedKey)
+	if err != nil {
+		n.networkService.Misbehaving(from, net.ScoreMalformedMessage, "undecryptable channel key")
+		return err
+	}
+	ml := gk.MemberList
+	if ml == nil || ml.Owner != from.Pretty() || ml.Topic != keyMsg.Topic {
+		return errors.New("channel key not sent by channel owner")
+	}
+	isMember := false
+	for _, m := range ml.Members {
+		if m == n.Identity().Pretty() {
+			isMember = true
+		}
+	}
+	if !isMember {
+		return errors.New("channel key member list does not include us")
+	}
+
+	err = n.repo.DB().Update(func(tx database.Tx) error {
+		return channels.SaveGroupKey(tx, gk)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Received CHANNEL_KEY message from %s. Topic: %s, Epoch: %d", from, ml.Topic, ml.Epoch)
+	n.eventBus.Emit(&events.PrivateChannelUpdated{
+		Topic:   ml.Topic,
+		Owner:   ml.Owner,
+		Epoch:   ml.Epoch,
+		Members: ml.Members,
+	})
+	return nil
+}
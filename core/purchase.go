@@ -49,6 +49,10 @@ func (n *OpenBazaarNode) PurchaseListing(ctx context.Context, purchase *models.P
 		return
 	}
 
+	if err = n.checkVendorInventory(ctx, vendorPeerID, orderOpen); err != nil {
+		return
+	}
+
 	paymentAddress = iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CoinType(normalizeCurrencyCode(orderOpen.Payment.Coin)))
 	currency, err := models.CurrencyDefinitions.Lookup(orderOpen.Payment.Coin)
 	if err != nil {
@@ -53,7 +53,7 @@ func (n *OpenBazaarNode) GetRatings(ctx context.Context, peerID peer.ID, useCach
 
 // GetRating fetches the rating from the network given its cid. It will attempt to validating
 // the rating using the signatures embedded in the rating. If they are invalid an error will
-// be returned.
+// be returned. Ratings left by peers we have blocked are treated as not found.
 func (n *OpenBazaarNode) GetRating(ctx context.Context, cid cid.Cid) (*pb.Rating, error) {
 	ratingBytes, err := n.cat(ctx, ipath.IpfsPath(cid))
 	if err != nil {
@@ -66,5 +66,8 @@ func (n *OpenBazaarNode) GetRating(ctx context.Context, cid cid.Cid) (*pb.Rating
 	if err := utils.ValidateRating(&rating); err != nil {
 		return nil, fmt.Errorf("%w: %s", coreiface.ErrNotFound, err)
 	}
+	if rating.BuyerID != nil && n.isBlocked(rating.BuyerID.PeerID) {
+		return nil, fmt.Errorf("%w: rating is from a blocked peer", coreiface.ErrNotFound)
+	}
 	return &rating, nil
 }
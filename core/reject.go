@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
@@ -9,6 +10,7 @@ import (
 	"github.com/cpacia/openbazaar3.0/orders/pb"
 	"github.com/cpacia/openbazaar3.0/orders/utils"
 	"github.com/golang/protobuf/ptypes"
+	"gorm.io/gorm"
 )
 
 // RejectOrder sends a ORDER_REJECT message to the remote peer and updates the node's
@@ -19,7 +21,9 @@ func (n *OpenBazaarNode) RejectOrder(orderID models.OrderID, reason string, done
 	err := n.repo.DB().View(func(tx database.Tx) error {
 		return tx.Read().Where("id = ?", orderID.String()).First(&order).Error
 	})
-	if err != nil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: order not found", coreiface.ErrNotFound)
+	} else if err != nil {
 		return err
 	}
 
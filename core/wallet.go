@@ -1,9 +1,16 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
 	"github.com/cpacia/openbazaar3.0/models"
 	iwallet "github.com/cpacia/wallet-interface"
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+	"time"
 )
 
 // SaveTransactionMetadata saves additional metadata for a wallet transaction into the database.
@@ -14,15 +21,7 @@ func (n *OpenBazaarNode) SaveTransactionMetadata(metadata *models.TransactionMet
 		var order models.Order
 		err := tx.Read().Where("payment_address = ?", metadata.PaymentAddress).First(&order).Error
 		if err == nil {
-			metadata.OrderID = order.ID
-
-			orderOpen, err := order.OrderOpenMessage()
-			if err == nil {
-				metadata.Thumbnail = orderOpen.Listings[0].Listing.Item.Images[0].Tiny
-				if metadata.Memo == "" {
-					metadata.Memo = orderOpen.Listings[0].Listing.Item.Title
-				}
-			}
+			metadata.SetOrder(&order)
 		}
 		return tx.Save(metadata)
 	})
@@ -36,3 +35,117 @@ func (n *OpenBazaarNode) GetTransactionMetadata(txid iwallet.TransactionID) (mod
 	})
 	return metadata, err
 }
+
+// GetWalletTransactions returns the transactions from all the wallets in the
+// multiwallet joined with their saved metadata, newest first.
+func (n *OpenBazaarNode) GetWalletTransactions(query *models.TransactionQuery) ([]models.WalletTransaction, error) {
+	if query == nil {
+		query = &models.TransactionQuery{}
+	}
+	if query.Direction != "" && query.Direction != models.TransactionIncoming && query.Direction != models.TransactionOutgoing {
+		return nil, fmt.Errorf("%w: unknown direction %s", coreiface.ErrBadRequest, query.Direction)
+	}
+
+	coins := make(map[iwallet.CoinType]bool)
+	for _, coin := range query.Coins {
+		found := false
+		for ct := range n.multiwallet {
+			code := strings.ToUpper(ct.CurrencyCode())
+			if code == strings.ToUpper(coin) || code == "T"+strings.ToUpper(coin) {
+				coins[ct] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: unsupported coin %s", coreiface.ErrBadRequest, coin)
+		}
+	}
+
+	var metadata []models.TransactionMetadata
+	err := n.repo.DB().View(func(tx database.Tx) error {
+		return tx.Read().Find(&metadata).Error
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	metadataByTxid := make(map[iwallet.TransactionID]models.TransactionMetadata)
+	for _, md := range metadata {
+		metadataByTxid[md.Txid] = md
+	}
+
+	var transactions []models.WalletTransaction
+	for ct, wal := range n.multiwallet {
+		if len(coins) > 0 && !coins[ct] {
+			continue
+		}
+		chainInfo, err := wal.BlockchainInfo()
+		if err != nil {
+			return nil, err
+		}
+		txs, err := wal.Transactions(-1, "")
+		if err != nil {
+			return nil, err
+		}
+		confirmedThreshold := uint64(6)
+		if def, err := models.CurrencyDefinitions.Lookup(ct.CurrencyCode()); err == nil && def.BlockInterval > 0 {
+			confirmedThreshold = uint64(time.Hour / def.BlockInterval)
+		}
+		for _, tx := range txs {
+			wtx := models.WalletTransaction{
+				Coin:      ct.CurrencyCode(),
+				Txid:      tx.ID.String(),
+				Value:     tx.Value.String(),
+				Direction: models.TransactionIncoming,
+				Timestamp: tx.Timestamp,
+				Height:    tx.Height,
+				Status:    "UNCONFIRMED",
+			}
+			if strings.HasPrefix(wtx.Value, "-") {
+				wtx.Direction = models.TransactionOutgoing
+			}
+			if tx.Height > 0 {
+				wtx.Confirmations = (chainInfo.Height - tx.Height) + 1
+			}
+			if wtx.Confirmations >= confirmedThreshold {
+				wtx.Status = "CONFIRMED"
+			} else if wtx.Confirmations > 0 {
+				wtx.Status = "PENDING"
+			}
+			if md, ok := metadataByTxid[tx.ID]; ok {
+				wtx.Address = md.PaymentAddress
+				wtx.Memo = md.Memo
+				wtx.OrderID = md.OrderID
+				wtx.Thumbnail = md.Thumbnail
+			}
+			if query.Matches(&wtx) {
+				transactions = append(transactions, wtx)
+			}
+		}
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		if transactions[i].Timestamp.Equal(transactions[j].Timestamp) {
+			return transactions[i].Txid > transactions[j].Txid
+		}
+		return transactions[i].Timestamp.After(transactions[j].Timestamp)
+	})
+
+	if query.OffsetID != "" {
+		found := false
+		for i, tx := range transactions {
+			if tx.Txid == query.OffsetID {
+				transactions = transactions[i+1:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: offset transaction not found", coreiface.ErrNotFound)
+		}
+	}
+
+	if query.Limit > 0 && len(transactions) > query.Limit {
+		transactions = transactions[:query.Limit]
+	}
+	return transactions, nil
+}
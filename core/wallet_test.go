@@ -1,12 +1,17 @@
 package core
 
 import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/core/coreiface"
 	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/models/factory"
 	"github.com/cpacia/openbazaar3.0/orders/utils"
+	"github.com/cpacia/openbazaar3.0/wallet"
 	iwallet "github.com/cpacia/wallet-interface"
 	"testing"
+	"time"
 )
 
 func TestOpenBazaarNode_SaveAndGetTransactionMetadata(t *testing.T) {
@@ -65,3 +70,120 @@ func TestOpenBazaarNode_SaveAndGetTransactionMetadata(t *testing.T) {
 		t.Errorf("Expected thumbnail of %s, got %s", orderOpen.Listings[0].Listing.Item.Images[0].Tiny, metadata.Thumbnail)
 	}
 }
+
+func TestOpenBazaarNode_GetWalletTransactions(t *testing.T) {
+	mockNode, err := MockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockNode.DestroyNode()
+
+	w := mockNode.multiwallet[iwallet.CtMock].(*wallet.MockWallet)
+	w.Start()
+
+	sub, err := mockNode.SubscribeEvent(&events.TransactionReceived{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txids []iwallet.TransactionID
+	for i, amount := range []int{100000, 500000} {
+		txn := w.GenerateTransaction(iwallet.NewAmount(amount))
+		txn.Timestamp = time.Now().Add(-time.Hour * time.Duration(2-i))
+		w.IngestTransaction(txn)
+		<-sub.Out()
+		txids = append(txids, txn.ID)
+	}
+
+	dbtx, err := w.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := w.SpendOutputs(dbtx, &wallet.SpendRequest{
+		Outputs:  []wallet.SpendOutput{{Address: iwallet.NewAddress("abc", iwallet.CtMock), Amount: iwallet.NewAmount(50000)}},
+		FeeLevel: iwallet.FlNormal,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = mockNode.repo.DB().Update(func(tx database.Tx) error {
+		return tx.Save(&models.TransactionMetadata{
+			Txid:    txids[0],
+			Memo:    "payment",
+			OrderID: "1234",
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	minAmount := iwallet.NewAmount(200000)
+	tests := []struct {
+		query    *models.TransactionQuery
+		expected []iwallet.TransactionID
+	}{
+		{
+			query:    nil,
+			expected: []iwallet.TransactionID{result.Txid, txids[1], txids[0]},
+		},
+		{
+			query:    &models.TransactionQuery{Coins: []string{"mck"}, Direction: models.TransactionOutgoing},
+			expected: []iwallet.TransactionID{result.Txid},
+		},
+		{
+			query:    &models.TransactionQuery{Direction: models.TransactionIncoming, MinAmount: &minAmount},
+			expected: []iwallet.TransactionID{txids[1]},
+		},
+		{
+			query:    &models.TransactionQuery{From: time.Now().Add(-time.Minute * 90)},
+			expected: []iwallet.TransactionID{result.Txid, txids[1]},
+		},
+		{
+			query:    &models.TransactionQuery{OrderID: "1234"},
+			expected: []iwallet.TransactionID{txids[0]},
+		},
+		{
+			query:    &models.TransactionQuery{OffsetID: result.Txid.String(), Limit: 1},
+			expected: []iwallet.TransactionID{txids[1]},
+		},
+	}
+
+	for i, test := range tests {
+		transactions, err := mockNode.GetWalletTransactions(test.query)
+		if err != nil {
+			t.Errorf("Test %d: %s", i, err)
+			continue
+		}
+		if len(transactions) != len(test.expected) {
+			t.Errorf("Test %d: expected %d transactions, got %d", i, len(test.expected), len(transactions))
+			continue
+		}
+		for j, tx := range transactions {
+			if tx.Txid != test.expected[j].String() {
+				t.Errorf("Test %d: expected transaction %d to be %s, got %s", i, j, test.expected[j], tx.Txid)
+			}
+		}
+	}
+
+	transactions, err := mockNode.GetWalletTransactions(&models.TransactionQuery{OrderID: "1234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 1 || transactions[0].Memo != "payment" || transactions[0].Coin != "MCK" ||
+		transactions[0].Value != "100000" || transactions[0].Direction != models.TransactionIncoming {
+		t.Errorf("Incorrect transaction %v", transactions)
+	}
+
+	if _, err := mockNode.GetWalletTransactions(&models.TransactionQuery{Coins: []string{"BTC"}}); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+	if _, err := mockNode.GetWalletTransactions(&models.TransactionQuery{Direction: "SIDEWAYS"}); !errors.Is(err, coreiface.ErrBadRequest) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+	if _, err := mockNode.GetWalletTransactions(&models.TransactionQuery{OffsetID: "abc"}); !errors.Is(err, coreiface.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
@@ -48,6 +48,12 @@ type PublicData interface {
 	// SetRatingIndex sets the rating index.
 	SetRatingIndex(index models.RatingIndex) error
 
+	// GetInventory returns the public inventory.
+	GetInventory() (models.Inventory, error)
+
+	// SetInventory sets the public inventory.
+	SetInventory(inventory models.Inventory) error
+
 	// SetRating saves the given rating.
 	SetRating(rating *pb.Rating) error
 
@@ -108,6 +114,10 @@ type Tx interface {
 	// successfully.
 	RegisterCommitHook(fn func())
 
+	// SearchChatMessages returns the chat messages matching the full-text
+	// search query ordered by relevance.
+	SearchChatMessages(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error)
+
 	// PublicData provides atomic access to the IPFS data directory.
 	PublicData
 }
@@ -0,0 +1,239 @@
+package ffsqlite
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/models"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	chatSearchTable = "chat_messages_fts"
+
+	// snippetTokens is the approximate number of tokens included in the
+	// snippet returned with each search result.
+	snippetTokens = 16
+
+	// snippetContext is the number of bytes of context included on either
+	// side of the first match when building snippets without FTS5.
+	snippetContext = 48
+
+	// snippetStart and snippetEnd are placed around the matches in FTS5
+	// snippets and replaced with <mark> tags once the snippet text has
+	// been HTML escaped.
+	snippetStart = "\x02"
+	snippetEnd   = "\x03"
+)
+
+// chatSearchTriggerNames are the names of the triggers in chatSearchTriggers.
+var chatSearchTriggerNames = []string{"chat_messages_fts_ai", "chat_messages_fts_ad", "chat_messages_fts_au"}
+
+// chatSearchTriggers keep the full-text index in sync with the chat_messages
+// table. The index is an external content table so the triggers must pass
+// in the old values when rows are deleted or updated.
+var chatSearchTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS chat_messages_fts_ai AFTER INSERT ON chat_messages BEGIN
+		INSERT INTO chat_messages_fts(rowid, message) VALUES (new.rowid, new.message);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS chat_messages_fts_ad AFTER DELETE ON chat_messages BEGIN
+		INSERT INTO chat_messages_fts(chat_messages_fts, rowid, message) VALUES ('delete', old.rowid, old.message);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS chat_messages_fts_au AFTER UPDATE OF message ON chat_messages BEGIN
+		INSERT INTO chat_messages_fts(chat_messages_fts, rowid, message) VALUES ('delete', old.rowid, old.message);
+		INSERT INTO chat_messages_fts(rowid, message) VALUES (new.rowid, new.message);
+	END`,
+}
+
+// migrateChatSearch creates the FTS5 index over the chat messages and
+// indexes any existing messages. FTS5 is only available when built with
+// the sqlite_fts5 tag. Without it the index is not created and searches
+// fall back to a slower LIKE query.
+//
+// A database may be opened by builds with and without FTS5 so the triggers
+// are dropped when FTS5 is not available, otherwise saving a chat message
+// would fail. When FTS5 is available again the triggers are recreated and
+// the index is rebuilt.
+func (t *tx) migrateChatSearch() error {
+	if !t.fts5Enabled() {
+		for _, name := range chatSearchTriggerNames {
+			if err := t.dbtx.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %s", name)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	exists, err := t.hasChatSearchIndex()
+	if err != nil {
+		return err
+	}
+	if exists {
+		var count int64
+		err := t.dbtx.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ?", chatSearchTriggerNames).Scan(&count).Error
+		if err != nil || count == int64(len(chatSearchTriggerNames)) {
+			return err
+		}
+	} else {
+		err = t.dbtx.Exec(fmt.Sprintf(`CREATE VIRTUAL TABLE %s USING fts5(message, content='chat_messages', content_rowid='rowid')`, chatSearchTable)).Error
+		if err != nil {
+			return err
+		}
+	}
+	for _, trigger := range chatSearchTriggers {
+		if err := t.dbtx.Exec(trigger).Error; err != nil {
+			return err
+		}
+	}
+	return t.dbtx.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES ('rebuild')`, chatSearchTable, chatSearchTable)).Error
+}
+
+// fts5Enabled returns whether sqlite was compiled with FTS5.
+func (t *tx) fts5Enabled() bool {
+	var enabled bool
+	err := t.dbtx.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled).Error
+	return err == nil && enabled
+}
+
+func (t *tx) hasChatSearchIndex() (bool, error) {
+	var count int64
+	err := t.dbtx.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", chatSearchTable).Scan(&count).Error
+	return count > 0, err
+}
+
+// SearchChatMessages returns the chat messages matching the full-text
+// search query ordered by relevance.
+func (t *tx) SearchChatMessages(query *models.ChatSearchQuery) ([]models.ChatSearchResult, error) {
+	terms := strings.Fields(query.Query)
+	if len(terms) == 0 {
+		return nil, errors.New("search query is empty")
+	}
+
+	hasIndex := false
+	if t.fts5Enabled() {
+		var err error
+		hasIndex, err = t.hasChatSearchIndex()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit := query.Limit
+	if limit < 1 {
+		limit = -1
+	}
+
+	var (
+		results []models.ChatSearchResult
+		where   []string
+		args    []interface{}
+	)
+	if query.PeerID != "" {
+		where = append(where, "chat_messages.peer_id = ?")
+		args = append(args, query.PeerID)
+	}
+	if query.OrderID != "" {
+		where = append(where, "chat_messages.order_id = ?")
+		args = append(args, query.OrderID.String())
+	}
+
+	if hasIndex {
+		where = append([]string{fmt.Sprintf("%s MATCH ?", chatSearchTable)}, where...)
+		args = append([]interface{}{ftsQuery(terms)}, args...)
+		args = append(args, limit, query.Offset)
+
+		sql := fmt.Sprintf(`SELECT chat_messages.*, snippet(%s, 0, '%s', '%s', '...', %d) AS snippet
+			FROM %s JOIN chat_messages ON chat_messages.rowid = %s.rowid
+			WHERE %s ORDER BY rank, chat_messages.timestamp DESC LIMIT ? OFFSET ?`,
+			chatSearchTable, snippetStart, snippetEnd, snippetTokens, chatSearchTable, chatSearchTable, strings.Join(where, " AND "))
+		if err := t.dbtx.Raw(sql, args...).Scan(&results).Error; err != nil {
+			return nil, err
+		}
+		for i := range results {
+			results[i].Snippet = markSnippet(results[i].Snippet)
+		}
+		return results, nil
+	}
+
+	for _, term := range terms {
+		where = append(where, `chat_messages.message LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+	args = append(args, limit, query.Offset)
+
+	sql := fmt.Sprintf(`SELECT * FROM chat_messages WHERE %s ORDER BY timestamp DESC LIMIT ? OFFSET ?`, strings.Join(where, " AND "))
+	if err := t.dbtx.Raw(sql, args...).Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Message, terms)
+	}
+	return results, nil
+}
+
+// ftsQuery builds an FTS5 query which matches all of the terms. Each term
+// is quoted so that FTS5 operators in the user's input are ignored. The
+// last term is a prefix query.
+func ftsQuery(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+	}
+	quoted[len(quoted)-1] += "*"
+	return strings.Join(quoted, " ")
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// markSnippet HTML escapes a snippet returned by FTS5 and replaces the
+// match markers with <mark> tags.
+func markSnippet(snippet string) string {
+	return strings.NewReplacer(snippetStart, "<mark>", snippetEnd, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// highlightSnippet returns the part of the message around the first match
+// with each of the terms wrapped in <mark> tags. The message text is HTML
+// escaped. It's used to build the snippets when the FTS5 index is not
+// available.
+func highlightSnippet(message string, terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+	re := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+
+	loc := re.FindStringIndex(message)
+	if loc == nil {
+		return html.EscapeString(message)
+	}
+	start, end := loc[0]-snippetContext, loc[1]+snippetContext
+	prefix, suffix := "...", "..."
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(message) {
+		end, suffix = len(message), ""
+	}
+	for start > 0 && !utf8.RuneStart(message[start]) {
+		start--
+	}
+	for end < len(message) && !utf8.RuneStart(message[end]) {
+		end++
+	}
+	var (
+		snippet = message[start:end]
+		marked  strings.Builder
+		last    int
+	)
+	for _, match := range re.FindAllStringIndex(snippet, -1) {
+		marked.WriteString(html.EscapeString(snippet[last:match[0]]))
+		marked.WriteString("<mark>" + html.EscapeString(snippet[match[0]:match[1]]) + "</mark>")
+		last = match[1]
+	}
+	marked.WriteString(html.EscapeString(snippet[last:]))
+	return prefix + marked.String() + suffix
+}
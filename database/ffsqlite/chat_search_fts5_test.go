@@ -0,0 +1,62 @@
+//go:build sqlite_fts5
+// +build sqlite_fts5
+
+package ffsqlite
+
+import (
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestFFSqliteDB_SearchChatMessagesFTS5(t *testing.T) {
+	dataDir := path.Join(os.TempDir(), "openbazaar-test", "ffsqlitedb-search-fts5")
+
+	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	db, err := NewFFMemoryDB(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Update(func(dbtx database.Tx) error {
+		if err := dbtx.Migrate(&models.ChatMessage{}); err != nil {
+			return err
+		}
+		if !dbtx.(*tx).fts5Enabled() {
+			t.Fatal("Expected FTS5 to be enabled")
+		}
+		exists, err := dbtx.(*tx).hasChatSearchIndex()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			t.Fatal("Expected chat search index to exist")
+		}
+		return dbtx.Save(&models.ChatMessage{MessageID: "1", PeerID: "qm123", Message: "<img src=x onerror=alert(1)> blue"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []models.ChatSearchResult
+	err = db.View(func(tx database.Tx) error {
+		results, err = tx.SearchChatMessages(&models.ChatSearchQuery{Query: "blue"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	expected := "&lt;img src=x onerror=alert(1)&gt; <mark>blue</mark>"
+	if results[0].Snippet != expected {
+		t.Errorf("Expected snippet %s, got %s", expected, results[0].Snippet)
+	}
+}
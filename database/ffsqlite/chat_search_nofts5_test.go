@@ -0,0 +1,71 @@
+//go:build !sqlite_fts5
+// +build !sqlite_fts5
+
+package ffsqlite
+
+import (
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestFFSqliteDB_MigrateChatSearchWithoutFTS5(t *testing.T) {
+	dataDir := path.Join(os.TempDir(), "openbazaar-test", "ffsqlitedb-search-nofts5")
+
+	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	db, err := NewFFMemoryDB(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a database created by a build with FTS5. The triggers
+	// can't be used without it so they must be dropped by the migration.
+	err = db.Update(func(dbtx database.Tx) error {
+		if err := dbtx.Migrate(&models.ChatMessage{}); err != nil {
+			return err
+		}
+		if dbtx.(*tx).fts5Enabled() {
+			t.Fatal("Expected FTS5 to be disabled")
+		}
+		if err := dbtx.(*tx).dbtx.Exec("CREATE TABLE chat_messages_fts (message TEXT)").Error; err != nil {
+			return err
+		}
+		for _, trigger := range chatSearchTriggers {
+			if err := dbtx.(*tx).dbtx.Exec(trigger).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Update(func(dbtx database.Tx) error {
+		if err := dbtx.Migrate(&models.ChatMessage{}); err != nil {
+			return err
+		}
+		return dbtx.Save(&models.ChatMessage{MessageID: "1", PeerID: "qm123", Message: "blue"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []models.ChatSearchResult
+	err = db.View(func(dbtx database.Tx) error {
+		results, err = dbtx.SearchChatMessages(&models.ChatSearchQuery{Query: "blue"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Snippet != "<mark>blue</mark>" {
+		t.Errorf("Incorrect results %v", results)
+	}
+}
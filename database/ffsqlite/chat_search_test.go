@@ -0,0 +1,143 @@
+package ffsqlite
+
+import (
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFFSqliteDB_SearchChatMessages(t *testing.T) {
+	dataDir := path.Join(os.TempDir(), "openbazaar-test", "ffsqlitedb-search")
+
+	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	db, err := NewFFMemoryDB(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []models.ChatMessage{
+		{MessageID: "1", PeerID: "qm123", OrderID: "", Message: "Do you have the blue shirt in size M?"},
+		{MessageID: "2", PeerID: "qm123", OrderID: "order1", Message: "The blue one is sold out"},
+		{MessageID: "3", PeerID: "qm456", OrderID: "", Message: "Shipping takes two weeks"},
+	}
+	err = db.Update(func(tx database.Tx) error {
+		if err := tx.Migrate(&models.ChatMessage{}); err != nil {
+			return err
+		}
+		for i, m := range messages {
+			m.Timestamp = time.Now().Add(time.Duration(i) * time.Second)
+			if err := tx.Save(&m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query *models.ChatSearchQuery) []models.ChatSearchResult {
+		var results []models.ChatSearchResult
+		err := db.View(func(tx database.Tx) error {
+			var err error
+			results, err = tx.SearchChatMessages(query)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+
+	tests := []struct {
+		name     string
+		query    *models.ChatSearchQuery
+		expected []string
+	}{
+		{"single term", &models.ChatSearchQuery{Query: "blue"}, []string{"1", "2"}},
+		{"all terms must match", &models.ChatSearchQuery{Query: "blue size"}, []string{"1"}},
+		{"prefix", &models.ChatSearchQuery{Query: "ship"}, []string{"3"}},
+		{"peer filter", &models.ChatSearchQuery{Query: "blue", PeerID: "qm456"}, nil},
+		{"order filter", &models.ChatSearchQuery{Query: "blue", OrderID: "order1"}, []string{"2"}},
+		{"limit", &models.ChatSearchQuery{Query: "blue", Limit: 1}, []string{"*"}},
+		{"operators are ignored", &models.ChatSearchQuery{Query: `blue" OR "shipping`}, nil},
+		{"no match", &models.ChatSearchQuery{Query: "refund"}, nil},
+	}
+	for _, test := range tests {
+		results := search(test.query)
+		if len(results) != len(test.expected) {
+			t.Errorf("%s: expected %d results, got %d", test.name, len(test.expected), len(results))
+			continue
+		}
+		for _, r := range results {
+			found := false
+			for _, id := range test.expected {
+				if id == "*" || id == r.MessageID {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s: unexpected result %s", test.name, r.MessageID)
+			}
+			if !strings.Contains(r.Snippet, "<mark>") {
+				t.Errorf("%s: snippet not highlighted: %s", test.name, r.Snippet)
+			}
+		}
+	}
+
+	// Updates and deletes must be reflected in the results.
+	err = db.Update(func(tx database.Tx) error {
+		if err := tx.Update("message", "Shipping takes one week", map[string]interface{}{"message_id = ?": "3"}, &models.ChatMessage{}); err != nil {
+			return err
+		}
+		return tx.Delete("message_id", "1", nil, &models.ChatMessage{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results := search(&models.ChatSearchQuery{Query: "blue"}); len(results) != 1 || results[0].MessageID != "2" {
+		t.Errorf("Deleted message returned in results: %v", results)
+	}
+	if results := search(&models.ChatSearchQuery{Query: "two weeks"}); len(results) != 0 {
+		t.Errorf("Updated message matched old text: %v", results)
+	}
+	if results := search(&models.ChatSearchQuery{Query: "one week"}); len(results) != 1 {
+		t.Errorf("Updated message did not match new text: %v", results)
+	}
+
+	err = db.View(func(tx database.Tx) error {
+		_, err := tx.SearchChatMessages(&models.ChatSearchQuery{Query: "  "})
+		return err
+	})
+	if err == nil {
+		t.Error("Expected error for empty query")
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		message  string
+		terms    []string
+		expected string
+	}{
+		{"Blue size M", []string{"blue", "m"}, "<mark>Blue</mark> size <mark>M</mark>"},
+		{"no match", []string{"xyz"}, "no match"},
+		{"<b>Blue</b> & <i>m</i>", []string{"blue"}, "&lt;b&gt;<mark>Blue</mark>&lt;/b&gt; &amp; &lt;i&gt;m&lt;/i&gt;"},
+		{"<script>", []string{"xyz"}, "&lt;script&gt;"},
+		{strings.Repeat("a", 100) + " blue " + strings.Repeat("b", 100), []string{"blue"}, "..." + strings.Repeat("a", 47) + " <mark>blue</mark> " + strings.Repeat("b", 47) + "..."},
+	}
+	for _, test := range tests {
+		if snippet := highlightSnippet(test.message, test.terms); snippet != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, snippet)
+		}
+	}
+}
@@ -16,7 +16,8 @@ import (
 )
 
 const (
-	dbName = "openbazaar.db"
+	// DBName is the name of the sqlite database file in the data directory.
+	DBName = "openbazaar.db"
 )
 
 var silentLogger = logger.New(
@@ -38,7 +39,7 @@ type DB struct {
 
 // NewFFSqliteDB instantiates a new db which satisfies the Database interface.
 func NewFFSqliteDB(dataDir string) (database.Database, error) {
-	db, err := gorm.Open(sqlite.Open(path.Join(dataDir, dbName)), &gorm.Config{
+	db, err := gorm.Open(sqlite.Open(path.Join(dataDir, DBName)), &gorm.Config{
 		Logger:            silentLogger,
 		AllowGlobalUpdate: true,
 	})
@@ -256,7 +257,13 @@ func (t *tx) Migrate(model interface{}) error {
 	if !t.isForWrites {
 		return ErrReadOnly
 	}
-	return t.dbtx.AutoMigrate(model)
+	if err := t.dbtx.AutoMigrate(model); err != nil {
+		return err
+	}
+	if _, ok := model.(*models.ChatMessage); ok {
+		return t.migrateChatSearch()
+	}
+	return nil
 }
 
 // RegisterCommitHook registers a callback that is invoked whenever a commit completes
@@ -429,6 +436,31 @@ func (t *tx) SetRatingIndex(index models.RatingIndex) error {
 	return nil
 }
 
+// GetInventory returns the public inventory.
+func (t *tx) GetInventory() (models.Inventory, error) {
+	for x := len(t.commitCache) - 1; x >= 0; x-- {
+		inventory, ok := t.commitCache[x].(models.Inventory)
+		if ok {
+			return inventory, nil
+		}
+	}
+	return t.ffdb.GetInventory()
+}
+
+// SetInventory sets the public inventory.
+func (t *tx) SetInventory(inventory models.Inventory) error {
+	if !t.isForWrites {
+		return ErrReadOnly
+	}
+	current, err := t.ffdb.GetInventory()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	t.rollbackCache = append(t.rollbackCache, current)
+	t.commitCache = append(t.commitCache, inventory)
+	return nil
+}
+
 // SetRating saves the given rating.
 func (t *tx) SetRating(rating *pb.Rating) error {
 	if !t.isForWrites {
@@ -479,6 +511,10 @@ func (t *tx) setInterfaceType(i interface{}) error {
 		if err := t.ffdb.SetRatingIndex(i); err != nil {
 			return err
 		}
+	case models.Inventory:
+		if err := t.ffdb.SetInventory(i); err != nil {
+			return err
+		}
 	case *pb.Rating:
 		if i == nil {
 			return nil
@@ -786,6 +786,69 @@ func TestFFSqliteDB_ratingIndex(t *testing.T) {
 	}
 }
 
+func TestFFSqliteDB_Inventory(t *testing.T) {
+	dataDir := path.Join(os.TempDir(), "openbazaar-test", "ffsqlitedb-inventory")
+
+	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	db, err := NewFFMemoryDB(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		inv1 = models.Inventory{
+			{
+				Slug:     "slug1",
+				Quantity: "1",
+			},
+		}
+		inv2 = models.Inventory{
+			{
+				Slug:     "slug2",
+				Quantity: "2",
+			},
+		}
+	)
+	err = db.Update(func(tx database.Tx) error {
+		if err := tx.SetInventory(inv1); err != nil {
+			return err
+		}
+		if err := tx.SetInventory(inv2); err != nil {
+			return err
+		}
+
+		inventory, err := tx.GetInventory()
+		if err != nil {
+			return err
+		}
+		if inventory[0].Slug != inv2[0].Slug {
+			t.Errorf("Returned incorrect inventory. Expected slug %s, got %s", inv2[0].Slug, inventory[0].Slug)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	var inventory models.Inventory
+	err = db.View(func(tx database.Tx) error {
+		inventory, err = tx.GetInventory()
+		return err
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if inventory[0].Slug != inv2[0].Slug {
+		t.Errorf("Returned incorrect inventory. Expected slug %s, got %s", inv2[0].Slug, inventory[0].Slug)
+	}
+	if inventory[0].Quantity != inv2[0].Quantity {
+		t.Errorf("Returned incorrect inventory. Expected quantity %s, got %s", inv2[0].Quantity, inventory[0].Quantity)
+	}
+}
+
 func TestFFSqliteDB_Images(t *testing.T) {
 	dataDir := path.Join(os.TempDir(), "openbazaar-test", "ffsqlitedb-images")
 
@@ -25,6 +25,8 @@ const (
 	ListingIndexFile = "listings.json"
 	// RatingIndexFile is the filename of the rating index file on disk.
 	RatingIndexFile = "ratings.json"
+	// InventoryFile is the filename of the inventory file on disk.
+	InventoryFile = "inventory.json"
 )
 
 // FlatFileDB represents the IPFS root directory that holds the node's
@@ -268,6 +270,36 @@ func (fdb *FlatFileDB) SetRatingIndex(index models.RatingIndex) error {
 	return ioutil.WriteFile(path.Join(fdb.rootDir, RatingIndexFile), out, os.ModePerm)
 }
 
+// GetInventory loads the inventory from disk and returns it.
+func (fdb *FlatFileDB) GetInventory() (models.Inventory, error) {
+	fdb.mtx.RLock()
+	defer fdb.mtx.RUnlock()
+
+	raw, err := ioutil.ReadFile(path.Join(fdb.rootDir, InventoryFile))
+	if err != nil {
+		return nil, err
+	}
+	var inventory models.Inventory
+	err = json.Unmarshal(raw, &inventory)
+	if err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// SetInventory saves the inventory to disk.
+func (fdb *FlatFileDB) SetInventory(inventory models.Inventory) error {
+	fdb.mtx.Lock()
+	defer fdb.mtx.Unlock()
+
+	out, err := json.MarshalIndent(inventory, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join(fdb.rootDir, InventoryFile), out, os.ModePerm)
+}
+
 // SetRating saves the given rating.
 func (fdb *FlatFileDB) SetRating(rating *pb.Rating) error {
 	fdb.mtx.Lock()
@@ -284,6 +284,53 @@ func TestFlatFileDB_RatingIndex(t *testing.T) {
 	}
 }
 
+func TestFlatFileDB_Inventory(t *testing.T) {
+	dir := path.Join(os.TempDir(), "openbazaar", "inventory_test")
+	fdb, err := NewFlatFileDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	inv := models.Inventory{
+		{
+			Slug:     "test-listing1",
+			Variants: "Color:Red,Size:Large",
+			Quantity: "12",
+		},
+		{
+			Slug:     "test-listing2",
+			Quantity: models.UnlimitedInventory,
+		},
+	}
+	if err := fdb.SetInventory(inv); err != nil {
+		t.Fatal(err)
+	}
+
+	inventory, err := fdb.GetInventory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(inventory) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(inventory))
+	}
+	item, ok := inventory.Get("test-listing1", "color:red,size:large")
+	if !ok {
+		t.Fatal("Inventory item not found")
+	}
+	if item.Quantity != "12" {
+		t.Errorf("Incorrect quantity returned. Expected 12 got %s", item.Quantity)
+	}
+	item, ok = inventory.Get("test-listing2", "")
+	if !ok {
+		t.Fatal("Inventory item not found")
+	}
+	if !item.IsUnlimited() {
+		t.Error("Expected unlimited inventory")
+	}
+}
+
 func TestFlatFileDB_Rating(t *testing.T) {
 	dir := path.Join(os.TempDir(), "openbazaar", "rating_test")
 	fdb, err := NewFlatFileDB(dir)
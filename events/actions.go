@@ -15,6 +15,14 @@ type Unfollow struct {
 	PeerID string `json:"peerID"`
 }
 
+type PeerBlocked struct {
+	PeerID string `json:"peerID"`
+}
+
+type PeerUnblocked struct {
+	PeerID string `json:"peerID"`
+}
+
 type ModeratorAdd struct {
 	Notification
 	PeerID string `json:"peerID"`
@@ -26,13 +34,21 @@ type ModeratorRemove struct {
 }
 
 type ChatMessage struct {
-	MessageID string    `json:"messageID"`
-	PeerID    string    `json:"peerID"`
-	OrderID   string    `json:"orderID"`
-	Timestamp time.Time `json:"timestamp"`
-	Read      bool      `json:"read"`
-	Outgoing  bool      `json:"outgoing"`
-	Message   string    `json:"message"`
+	MessageID   string           `json:"messageID"`
+	PeerID      string           `json:"peerID"`
+	OrderID     string           `json:"orderID"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Read        bool             `json:"read"`
+	Outgoing    bool             `json:"outgoing"`
+	Message     string           `json:"message"`
+	Attachments []ChatAttachment `json:"attachments,omitempty"`
+}
+
+type ChatAttachment struct {
+	CID      string `json:"cid"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType"`
+	Size     int64  `json:"size"`
 }
 
 type ChatRead struct {
@@ -68,11 +84,47 @@ type AddressRequestResponse struct {
 }
 
 type ChannelMessage struct {
-	PeerID    string    `json:"peerID"`
-	Topic     string    `json:"topic"`
-	Timestamp time.Time `json:"timestamp"`
-	Message   string    `json:"message"`
-	Cid       string    `json:"cid"`
+	PeerID    string               `json:"peerID"`
+	Topic     string               `json:"topic"`
+	Timestamp time.Time            `json:"timestamp"`
+	Message   string               `json:"message"`
+	Cid       string               `json:"cid"`
+	ReplyTo   string               `json:"replyTo,omitempty"`
+	Reaction  *ChannelReaction     `json:"reaction,omitempty"`
+	Listing   *ChannelListingEmbed `json:"listing,omitempty"`
+}
+
+type ChannelReaction struct {
+	Target string `json:"target"`
+	Emoji  string `json:"emoji"`
+}
+
+type ChannelListingEmbed struct {
+	VendorID string `json:"vendorID"`
+	Slug     string `json:"slug"`
+}
+
+// ChannelPolicyUpdated is emitted when a new policy from the channel
+// owner is applied.
+type ChannelPolicyUpdated struct {
+	Topic    string `json:"topic"`
+	Sequence uint64 `json:"sequence"`
+}
+
+// PrivateChannelUpdated is emitted when we receive the group key for
+// a new epoch of a private channel which we are a member of.
+type PrivateChannelUpdated struct {
+	Topic   string   `json:"topic"`
+	Owner   string   `json:"owner"`
+	Epoch   uint64   `json:"epoch"`
+	Members []string `json:"members"`
+}
+
+// ChannelMessagesDeleted is emitted when the channel owner deletes
+// messages from the channel.
+type ChannelMessagesDeleted struct {
+	Topic string   `json:"topic"`
+	Cids  []string `json:"cids"`
 }
 
 type ChannelRequestResponse struct {
@@ -66,3 +66,23 @@ type PingReceived struct {
 type PongReceived struct {
 	Peer peer.ID
 }
+
+// OutgoingMessageFailed is an event that gets pushed to the bus
+// when an order related message expires before it is ACKed.
+type OutgoingMessageFailed struct {
+	Notification
+	MessageID   string `json:"messageID"`
+	Recipient   string `json:"recipient"`
+	MessageType string `json:"messageType"`
+	OrderID     string `json:"orderID"`
+	Reason      string `json:"reason"`
+}
+
+// MessageDeliveryUpdate is an event that gets pushed to the bus
+// whenever the delivery status of an outgoing message changes.
+type MessageDeliveryUpdate struct {
+	MessageID   string `json:"messageID"`
+	Recipient   string `json:"recipient"`
+	MessageType string `json:"messageType"`
+	Status      string `json:"status"`
+}
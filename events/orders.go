@@ -28,6 +28,14 @@ type NewOrder struct {
 	Title       string       `json:"title"`
 }
 
+type BlockedOrderRejected struct {
+	Notification
+	BuyerHandle string `json:"buyerHandle"`
+	BuyerID     string `json:"buyerID"`
+	OrderID     string `json:"orderID"`
+	Reason      string `json:"reason"`
+}
+
 type OrderFunded struct {
 	Notification
 	BuyerHandle string       `json:"buyerHandle"`
@@ -180,3 +188,7 @@ type ModeratorDisputeExpiry struct {
 	ExpiresIn uint      `json:"expiresIn"`
 	Thumbnail Thumbnail `json:"thumbnail"`
 }
+
+type InventoryUpdated struct {
+	Slugs []string `json:"slugs"`
+}
@@ -24,5 +24,6 @@ test -z "$(env GO111MODULE=on golangci-lint run --disable-all \
 --enable=gosimple \
 --enable=unconvert \
 --deadline=10m \
+--build-tags=sqlite_fts5 \
 2>&1 | tee /dev/stderr)"
-env GO111MODULE=on go test -timeout 30m ./...
+env GO111MODULE=on go test -tags sqlite_fts5 -timeout 30m ./...
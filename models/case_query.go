@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/ptypes"
+	"time"
+)
+
+// CaseQuery holds the filters used to select dispute cases from the database.
+// Zero values are ignored.
+type CaseQuery struct {
+	// Open restricts the results to either open (true) or closed (false)
+	// cases. Nil returns both.
+	Open *bool `json:"open"`
+
+	// Limit is the maximum number of results to return. A limit less than
+	// one returns all results.
+	Limit int `json:"limit"`
+
+	// OffsetID is the ID of the last case returned in the previous page.
+	// Results will begin with the case immediately after it.
+	OffsetID OrderID `json:"offsetID"`
+}
+
+// Matches returns whether the case matches the filters in the query.
+func (q *CaseQuery) Matches(disputeCase *Case) bool {
+	if q.Open != nil && *q.Open == disputeCase.IsClosed() {
+		return false
+	}
+	return true
+}
+
+// CaseSummary is an abbreviated view of a dispute case used when returning
+// lists of cases.
+type CaseSummary struct {
+	CaseID       OrderID   `json:"caseID"`
+	Open         bool      `json:"open"`
+	Timestamp    time.Time `json:"timestamp"`
+	OpenedBy     string    `json:"openedBy"`
+	Reason       string    `json:"reason"`
+	Titles       []string  `json:"titles"`
+	Thumbnail    string    `json:"thumbnail"`
+	BuyerID      string    `json:"buyerID"`
+	BuyerHandle  string    `json:"buyerHandle"`
+	VendorID     string    `json:"vendorID"`
+	VendorHandle string    `json:"vendorHandle"`
+	PaymentCoin  string    `json:"paymentCoin"`
+	Total        string    `json:"total"`
+
+	// BuyerContract and VendorContract are whether we have received the
+	// respective party's contract.
+	BuyerContract  bool `json:"buyerContract"`
+	VendorContract bool `json:"vendorContract"`
+}
+
+// NewCaseSummary builds a CaseSummary from the provided case.
+func NewCaseSummary(disputeCase *Case) (*CaseSummary, error) {
+	orderOpen, err := disputeCase.OrderOpenMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CaseSummary{
+		CaseID:         disputeCase.ID,
+		Open:           !disputeCase.IsClosed(),
+		Titles:         make([]string, 0, len(orderOpen.Listings)),
+		BuyerContract:  disputeCase.BuyerContract != nil,
+		VendorContract: disputeCase.VendorContract != nil,
+	}
+
+	disputeOpen, err := disputeCase.DisuteOpenMessage()
+	if err == nil {
+		summary.OpenedBy = disputeOpen.OpenedBy.String()
+		summary.Reason = disputeOpen.Reason
+		if disputeOpen.Timestamp != nil {
+			summary.Timestamp, err = ptypes.Timestamp(disputeOpen.Timestamp)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if !IsMessageNotExistError(err) {
+		return nil, err
+	}
+
+	if orderOpen.BuyerID != nil {
+		summary.BuyerID = orderOpen.BuyerID.PeerID
+		summary.BuyerHandle = orderOpen.BuyerID.Handle
+	}
+	if orderOpen.Payment != nil {
+		summary.PaymentCoin = orderOpen.Payment.Coin
+		summary.Total = orderOpen.Payment.Amount
+	}
+	for i, sl := range orderOpen.Listings {
+		if sl.Listing == nil {
+			continue
+		}
+		if i == 0 && sl.Listing.VendorID != nil {
+			summary.VendorID = sl.Listing.VendorID.PeerID
+			summary.VendorHandle = sl.Listing.VendorID.Handle
+		}
+		if sl.Listing.Item == nil {
+			continue
+		}
+		summary.Titles = append(summary.Titles, sl.Listing.Item.Title)
+		if summary.Thumbnail == "" && len(sl.Listing.Item.Images) > 0 {
+			summary.Thumbnail = sl.Listing.Item.Images[0].Tiny
+		}
+	}
+	return summary, nil
+}
+
+// CaseView is the moderator's full view of a dispute case. It presents the
+// buyer's and vendor's contracts side by side along with the problems found
+// when validating them and the current state of the escrow.
+type CaseView struct {
+	CaseSummary
+
+	BuyerContract          json.RawMessage `json:"buyerContract"`
+	VendorContract         json.RawMessage `json:"vendorContract"`
+	BuyerValidationErrors  []string        `json:"buyerValidationErrors"`
+	VendorValidationErrors []string        `json:"vendorValidationErrors"`
+
+	EscrowAddress       string                `json:"escrowAddress"`
+	EscrowBalance       string                `json:"escrowBalance"`
+	FundingTransactions []iwallet.Transaction `json:"fundingTransactions"`
+
+	// Resolution is the moderator's DISPUTE_CLOSE if the case is closed.
+	Resolution json.RawMessage `json:"resolution"`
+
+	// Case is the underlying case model.
+	Case *Case `json:"-"`
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/cpacia/openbazaar3.0/orders/pb"
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 )
 
 type Case struct {
@@ -111,3 +112,87 @@ func (c *Case) PutValidationErrors(validationErrors []error, role OrderRole) err
 	}
 	return nil
 }
+
+// DisputeCloseMessage returns the unmarshalled DisputeClose message if it exists
+// in the case.
+func (c *Case) DisputeCloseMessage() (*pb.DisputeClose, error) {
+	if c.SerializedDisputeClose == nil || len(c.SerializedDisputeClose) == 0 {
+		return nil, ErrMessageDoesNotExist
+	}
+	disputeClose := new(pb.DisputeClose)
+	if err := jsonpb.UnmarshalString(string(c.SerializedDisputeClose), disputeClose); err != nil {
+		return nil, err
+	}
+	return disputeClose, nil
+}
+
+// PutDisputeClose serializes the DisputeClose message and saves it in the case.
+func (c *Case) PutDisputeClose(disputeClose *pb.DisputeClose) error {
+	out, err := marshaler.MarshalToString(disputeClose)
+	if err != nil {
+		return err
+	}
+	c.SerializedDisputeClose = []byte(out)
+	return nil
+}
+
+// IsClosed returns whether the moderator has closed the dispute.
+func (c *Case) IsClosed() bool {
+	return c.SerializedDisputeClose != nil
+}
+
+// BuyerContractMessage returns the unmarshalled contract sent by the buyer if it
+// exists in the case.
+func (c *Case) BuyerContractMessage() (*pb.Contract, error) {
+	return unmarshalContract(c.BuyerContract)
+}
+
+// VendorContractMessage returns the unmarshalled contract sent by the vendor if it
+// exists in the case.
+func (c *Case) VendorContractMessage() (*pb.Contract, error) {
+	return unmarshalContract(c.VendorContract)
+}
+
+// OrderOpenMessage returns the OrderOpen from whichever contract exists in the
+// case, preferring the buyer's.
+func (c *Case) OrderOpenMessage() (*pb.OrderOpen, error) {
+	contract, err := c.BuyerContractMessage()
+	if errors.Is(err, ErrMessageDoesNotExist) {
+		contract, err = c.VendorContractMessage()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if contract.OrderOpen == nil {
+		return nil, ErrMessageDoesNotExist
+	}
+	return contract.OrderOpen, nil
+}
+
+// ValidationErrors returns the validation errors recorded against the contract
+// sent by the party with the given role.
+func (c *Case) ValidationErrors(role OrderRole) ([]string, error) {
+	ser := c.BuyerValidationErrors
+	if role == RoleVendor {
+		ser = c.VendorValidationErrors
+	}
+	validationErrors := []string{}
+	if len(ser) == 0 {
+		return validationErrors, nil
+	}
+	if err := json.Unmarshal(ser, &validationErrors); err != nil {
+		return nil, err
+	}
+	return validationErrors, nil
+}
+
+func unmarshalContract(ser []byte) (*pb.Contract, error) {
+	if len(ser) == 0 {
+		return nil, ErrMessageDoesNotExist
+	}
+	contract := new(pb.Contract)
+	if err := proto.Unmarshal(ser, contract); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// ChannelRule is the type of rule applied to a peer in a channel.
+type ChannelRule string
+
+const (
+	// ChannelRuleMute hides the peer's messages locally.
+	ChannelRuleMute ChannelRule = "mute"
+
+	// ChannelRuleAllow is set by the channel owner. If any peers are
+	// allowed then only those peers may post in the channel.
+	ChannelRuleAllow ChannelRule = "allow"
+
+	// ChannelRuleDeny is set by the channel owner. Denied peers may not
+	// post in the channel.
+	ChannelRuleDeny ChannelRule = "deny"
+)
+
+// ChannelPolicy holds the moderation settings for a channel. Owner,
+// RateLimit and Muted are set locally. Published holds the latest
+// policy signed by the owner.
+type ChannelPolicy struct {
+	Topic string `gorm:"primaryKey" json:"topic"`
+
+	// Owner is the peer whose signed policies and deletion markers
+	// are honored in this channel.
+	Owner string `json:"owner"`
+
+	// RateLimit is the maximum number of messages per minute we will
+	// accept from each peer. Zero means no limit.
+	RateLimit int `json:"rateLimit"`
+
+	Muted []string `gorm:"-" json:"muted"`
+
+	Published ChannelOwnerPolicy `gorm:"embedded;embeddedPrefix:published_" json:"published"`
+}
+
+// ChannelOwnerPolicy is the policy published by the channel owner.
+type ChannelOwnerPolicy struct {
+	Sequence uint64   `json:"sequence"`
+	Allow    []string `gorm:"-" json:"allow"`
+	Deny     []string `gorm:"-" json:"deny"`
+
+	// PoWBits is the number of leading zero bits required in the hash
+	// of each message.
+	PoWBits uint32 `json:"powBits"`
+
+	// RateLimit is the maximum number of messages per minute each peer
+	// may post. Zero means no limit.
+	RateLimit int `json:"rateLimit"`
+}
+
+// ChannelPeerRule is a mute, allow, or deny rule for a peer in a channel.
+type ChannelPeerRule struct {
+	Topic  string      `gorm:"primaryKey"`
+	PeerID string      `gorm:"primaryKey"`
+	Rule   ChannelRule `gorm:"primaryKey"`
+}
+
+// ChannelDeletion records a message deleted by the channel owner.
+type ChannelDeletion struct {
+	Topic     string `gorm:"primaryKey"`
+	Cid       string `gorm:"primaryKey"`
+	Timestamp time.Time
+}
@@ -21,6 +21,33 @@ type ChatMessage struct {
 	Outgoing  bool      `json:"outgoing"`
 	Message   string    `json:"message"`
 	Sequence  int
+
+	Attachments []ChatAttachment `gorm:"-" json:"attachments,omitempty"`
+}
+
+// ChatAttachment is a file attached to a chat message. The file is
+// encrypted with Key and added to IPFS under CID. Attachments which
+// have been uploaded but not yet sent have an empty MessageID and
+// record when they were uploaded so they can be expired.
+type ChatAttachment struct {
+	MessageID  string    `gorm:"primaryKey" json:"-"`
+	CID        string    `gorm:"primaryKey;index;column:cid" json:"cid"`
+	Key        []byte    `json:"-"`
+	Filename   string    `json:"filename"`
+	MimeType   string    `json:"mimeType"`
+	Size       int64     `json:"size"`
+	UploadedAt time.Time `json:"-"`
+}
+
+// ToProto returns the protobuf representation of the attachment.
+func (ca *ChatAttachment) ToProto() *pb.ChatMessage_Attachment {
+	return &pb.ChatMessage_Attachment{
+		Cid:      ca.CID,
+		Key:      ca.Key,
+		Filename: ca.Filename,
+		MimeType: ca.MimeType,
+		Size:     uint64(ca.Size),
+	}
 }
 
 func NewChatMessageFromProto(peerID peer.ID, msg *pb.Message) (*ChatMessage, error) {
@@ -33,13 +60,26 @@ func NewChatMessageFromProto(peerID peer.ID, msg *pb.Message) (*ChatMessage, err
 		return nil, err
 	}
 
+	var attachments []ChatAttachment
+	for _, a := range chtMsg.Attachments {
+		attachments = append(attachments, ChatAttachment{
+			MessageID: msg.MessageID,
+			CID:       a.Cid,
+			Key:       a.Key,
+			Filename:  a.Filename,
+			MimeType:  a.MimeType,
+			Size:      int64(a.Size),
+		})
+	}
+
 	return &ChatMessage{
-		MessageID: msg.MessageID,
-		PeerID:    peerID.Pretty(),
-		Message:   chtMsg.Message,
-		OrderID:   chtMsg.OrderID,
-		Timestamp: time.Unix(chtMsg.Timestamp.Seconds, int64(chtMsg.Timestamp.Nanos)),
-		Sequence:  int(msg.Sequence),
+		MessageID:   msg.MessageID,
+		PeerID:      peerID.Pretty(),
+		Message:     chtMsg.Message,
+		OrderID:     chtMsg.OrderID,
+		Timestamp:   time.Unix(chtMsg.Timestamp.Seconds, int64(chtMsg.Timestamp.Nanos)),
+		Sequence:    int(msg.Sequence),
+		Attachments: attachments,
 	}, nil
 }
 
@@ -48,14 +88,24 @@ func (cm *ChatMessage) GetPeerID() (peer.ID, error) {
 }
 
 func (cm *ChatMessage) ToChatEvent() *events.ChatMessage {
+	var attachments []events.ChatAttachment
+	for _, a := range cm.Attachments {
+		attachments = append(attachments, events.ChatAttachment{
+			CID:      a.CID,
+			Filename: a.Filename,
+			MimeType: a.MimeType,
+			Size:     a.Size,
+		})
+	}
 	return &events.ChatMessage{
-		MessageID: cm.MessageID,
-		Timestamp: cm.Timestamp,
-		PeerID:    cm.PeerID,
-		OrderID:   cm.OrderID,
-		Outgoing:  cm.Outgoing,
-		Read:      cm.Read,
-		Message:   cm.Message,
+		MessageID:   cm.MessageID,
+		Timestamp:   cm.Timestamp,
+		PeerID:      cm.PeerID,
+		OrderID:     cm.OrderID,
+		Outgoing:    cm.Outgoing,
+		Read:        cm.Read,
+		Message:     cm.Message,
+		Attachments: attachments,
 	}
 }
 
@@ -71,8 +121,32 @@ type ChannelMessage struct {
 	PeerID    string    `json:"peerID"`
 	Topic     string    `json:"topic"`
 	Timestamp time.Time `json:"timestamp"`
-	Message   string    `json:"message"`
-	Cid       string    `json:"cid"`
+	ChannelMessageContent
+	Cid string `json:"cid"`
+}
+
+// ChannelMessageContent is the content of a channel message. A message
+// may be a reply to another message, in which case ReplyTo holds the
+// parent's CID. Reactions have no text and reference the target message.
+type ChannelMessageContent struct {
+	Message  string               `json:"message"`
+	ReplyTo  string               `json:"replyTo,omitempty"`
+	Reaction *ChannelReaction     `json:"reaction,omitempty"`
+	Listing  *ChannelListingEmbed `json:"listing,omitempty"`
+}
+
+// ChannelReaction is an emoji reaction to the channel message with the
+// target CID.
+type ChannelReaction struct {
+	Target string `json:"target"`
+	Emoji  string `json:"emoji"`
+}
+
+// ChannelListingEmbed references a vendor's listing so that it can be
+// shown as a card in the channel.
+type ChannelListingEmbed struct {
+	VendorID string `json:"vendorID"`
+	Slug     string `json:"slug"`
 }
 
 type Channel struct {
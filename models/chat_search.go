@@ -0,0 +1,32 @@
+package models
+
+// ChatSearchQuery holds the parameters for a full-text search of the chat
+// history. Zero values are ignored.
+type ChatSearchQuery struct {
+	// Query is the text to search for. Messages must contain every term
+	// in the query. The last term is treated as a prefix so that partial
+	// words match as the user types.
+	Query string `json:"query"`
+
+	// PeerID restricts the results to messages to or from the given peer.
+	PeerID string `json:"peerID"`
+
+	// OrderID restricts the results to messages in the given order's
+	// group chat.
+	OrderID OrderID `json:"orderID"`
+
+	// Limit is the maximum number of results to return. A limit less than
+	// one returns all results.
+	Limit int `json:"limit"`
+
+	// Offset is the number of results to skip.
+	Offset int `json:"offset"`
+}
+
+// ChatSearchResult is a chat message which matched a search query. Snippet
+// holds the part of the message which matched with the matching terms
+// wrapped in <mark> tags.
+type ChatSearchResult struct {
+	ChatMessage
+	Snippet string `json:"snippet"`
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"errors"
+	iwallet "github.com/cpacia/wallet-interface"
+	"math/big"
+	"time"
+)
+
+// ExchangeRateSource reports the result of the last query to an exchange
+// rate provider.
+type ExchangeRateSource struct {
+	// Format is the name of the provider format, for example kraken.
+	Format string `json:"format"`
+
+	// Source is the URL or file path of the provider with any query
+	// string removed.
+	Source string `json:"source"`
+
+	// Currencies is the number of rates used from this provider.
+	Currencies int `json:"currencies"`
+
+	// Rejected lists the currencies for which the provider's rate
+	// deviated too far from the median and was not used.
+	Rejected []CurrencyCode `json:"rejected,omitempty"`
+
+	// Error is set if the last query to the provider failed.
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	// RateEventOrderOpen marks an exchange rate saved when an order was opened.
+	RateEventOrderOpen = "ORDER_OPEN"
+
+	// RateEventPayment marks an exchange rate saved when a payment for an
+	// order was received.
+	RateEventPayment = "PAYMENT"
+)
+
+// ExchangeRateRecord is an exchange rate saved in the rate history. Like the
+// rates returned by the exchange rate provider, Rate is the value of one whole
+// unit of the Base currency denominated in the smallest unit of Currency.
+type ExchangeRateRecord struct {
+	ID        uint         `gorm:"primaryKey" json:"-"`
+	Base      CurrencyCode `gorm:"index" json:"base"`
+	Currency  CurrencyCode `gorm:"index" json:"currency"`
+	Rate      string       `json:"rate"`
+	Timestamp time.Time    `gorm:"index" json:"timestamp"`
+
+	// OrderID, Event and TransactionID record what caused the rate to be
+	// saved.
+	OrderID       OrderID `gorm:"index" json:"orderID,omitempty"`
+	Event         string  `json:"event,omitempty"`
+	TransactionID string  `json:"transactionID,omitempty"`
+}
+
+// Convert returns the value of the amount, denominated in the smallest unit
+// of the Base currency, in the smallest unit of Currency.
+func (r *ExchangeRateRecord) Convert(amount iwallet.Amount) (iwallet.Amount, error) {
+	base, err := CurrencyDefinitions.Lookup(r.Base.String())
+	if err != nil {
+		return iwallet.NewAmount(0), err
+	}
+	rate, ok := new(big.Int).SetString(r.Rate, 10)
+	if !ok {
+		return iwallet.NewAmount(0), errors.New("invalid exchange rate")
+	}
+	x := big.Int(amount)
+	value := new(big.Int).Mul(&x, rate)
+	value.Quo(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(base.Divisibility)), nil))
+	return iwallet.NewAmount(value), nil
+}
+
+// FiatValue is the value of an amount of an order's payment coin in another
+// currency using the exchange rate saved at the time of an order event.
+type FiatValue struct {
+	Currency      CurrencyCode `json:"currency"`
+	Amount        string       `json:"amount"`
+	Rate          string       `json:"rate"`
+	Timestamp     time.Time    `json:"timestamp"`
+	Event         string       `json:"event"`
+	TransactionID string       `json:"transactionID,omitempty"`
+}
+
+// FiatValues converts the order total and each payment into other currencies
+// using the order's saved exchange rates. Records for other orders or for
+// transactions which are not in the order are ignored.
+func (o *Order) FiatValues(records []ExchangeRateRecord) ([]FiatValue, error) {
+	orderOpen, err := o.OrderOpenMessage()
+	if err != nil {
+		return nil, err
+	}
+	if orderOpen.Payment == nil {
+		return nil, nil
+	}
+	transactions, err := o.GetTransactions()
+	if err != nil && !errors.Is(err, ErrMessageDoesNotExist) {
+		return nil, err
+	}
+
+	values := make([]FiatValue, 0, len(records))
+	for i := range records {
+		record := &records[i]
+		if record.OrderID != o.ID {
+			continue
+		}
+		var amount iwallet.Amount
+		switch record.Event {
+		case RateEventOrderOpen:
+			amount = iwallet.NewAmount(orderOpen.Payment.Amount)
+		case RateEventPayment:
+			found := false
+			amount = iwallet.NewAmount(0)
+			for _, tx := range transactions {
+				if tx.ID.String() != record.TransactionID {
+					continue
+				}
+				for _, to := range tx.To {
+					if to.Address.String() == o.PaymentAddress {
+						amount = amount.Add(to.Amount)
+					}
+				}
+				found = true
+			}
+			if !found {
+				continue
+			}
+		default:
+			continue
+		}
+		converted, err := record.Convert(amount)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, FiatValue{
+			Currency:      record.Currency,
+			Amount:        converted.String(),
+			Rate:          record.Rate,
+			Timestamp:     record.Timestamp,
+			Event:         record.Event,
+			TransactionID: record.TransactionID,
+		})
+	}
+	return values, nil
+}
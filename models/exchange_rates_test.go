@@ -0,0 +1,107 @@
+package models
+
+import (
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"testing"
+	"time"
+)
+
+func TestExchangeRateRecord_Convert(t *testing.T) {
+	tests := []struct {
+		record   ExchangeRateRecord
+		amount   iwallet.Amount
+		expected string
+		err      bool
+	}{
+		{
+			record:   ExchangeRateRecord{Base: "BTC", Currency: "USD", Rate: "1286307"},
+			amount:   iwallet.NewAmount(100000000),
+			expected: "1286307",
+		},
+		{
+			record:   ExchangeRateRecord{Base: "BTC", Currency: "USD", Rate: "1286307"},
+			amount:   iwallet.NewAmount(50000),
+			expected: "643",
+		},
+		{
+			record:   ExchangeRateRecord{Base: "TBTC", Currency: "EUR", Rate: "1144457"},
+			amount:   iwallet.NewAmount(200000000),
+			expected: "2288914",
+		},
+		{
+			record: ExchangeRateRecord{Base: "XYZ", Currency: "USD", Rate: "100"},
+			amount: iwallet.NewAmount(1),
+			err:    true,
+		},
+		{
+			record: ExchangeRateRecord{Base: "BTC", Currency: "USD", Rate: "abc"},
+			amount: iwallet.NewAmount(1),
+			err:    true,
+		},
+	}
+
+	for i, test := range tests {
+		value, err := test.record.Convert(test.amount)
+		if test.err {
+			if err == nil {
+				t.Errorf("Test %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: %s", i, err)
+			continue
+		}
+		if value.String() != test.expected {
+			t.Errorf("Test %d: expected %s, got %s", i, test.expected, value)
+		}
+	}
+}
+
+func TestOrder_FiatValues(t *testing.T) {
+	order := Order{ID: "1234", PaymentAddress: "abc"}
+	err := order.PutMessage(utils.MustWrapOrderMessage(&pb.OrderOpen{
+		Payment: &pb.OrderOpen_Payment{
+			Amount:  "10000000",
+			Address: "abc",
+			Coin:    "BTC",
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = order.PutTransaction(iwallet.Transaction{
+		ID: "tx1",
+		To: []iwallet.SpendInfo{
+			{Address: iwallet.NewAddress("abc", iwallet.CtBitcoin), Amount: iwallet.NewAmount(4000000)},
+			{Address: iwallet.NewAddress("change", iwallet.CtBitcoin), Amount: iwallet.NewAmount(9000000)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	records := []ExchangeRateRecord{
+		{Base: "BTC", Currency: "USD", Rate: "1000000", Timestamp: now, OrderID: "1234", Event: RateEventOrderOpen},
+		{Base: "BTC", Currency: "USD", Rate: "1200000", Timestamp: now, OrderID: "1234", Event: RateEventPayment, TransactionID: "tx1"},
+		{Base: "BTC", Currency: "USD", Rate: "1200000", Timestamp: now, OrderID: "1234", Event: RateEventPayment, TransactionID: "unknown"},
+		{Base: "BTC", Currency: "USD", Rate: "1200000", Timestamp: now, OrderID: "5678", Event: RateEventOrderOpen},
+	}
+
+	values, err := order.FiatValues(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 values, got %d", len(values))
+	}
+	if values[0].Event != RateEventOrderOpen || values[0].Amount != "100000" || values[0].Currency != "USD" {
+		t.Errorf("Incorrect order open value: %v", values[0])
+	}
+	if values[1].Event != RateEventPayment || values[1].Amount != "48000" || values[1].TransactionID != "tx1" {
+		t.Errorf("Incorrect payment value: %v", values[1])
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	iwallet "github.com/cpacia/wallet-interface"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// UnlimitedInventory is the quantity used for SKUs which never run out of stock.
+const UnlimitedInventory = "-1"
+
+// InventoryItem is a database model which holds the quantity in stock for a
+// single SKU of one of our listings. The SKU is identified by the listing slug
+// and the combination of variants selected.
+type InventoryItem struct {
+	ID        string `gorm:"primaryKey" json:"-"`
+	Slug      string `gorm:"index" json:"slug"`
+	Variants  string `json:"variants"`
+	ProductID string `json:"productID,omitempty"`
+	Quantity  string `json:"quantity"`
+}
+
+// NewInventoryItem returns a new InventoryItem for the SKU. An empty
+// quantity is treated as unlimited.
+func NewInventoryItem(slug string, sku *pb.Listing_Item_Sku) (*InventoryItem, error) {
+	quantity := sku.Quantity
+	if quantity == "" {
+		quantity = UnlimitedInventory
+	}
+	q, ok := new(big.Int).SetString(quantity, 10)
+	if !ok || (quantity != UnlimitedInventory && q.Sign() < 0) {
+		return nil, fmt.Errorf("invalid sku quantity %s", sku.Quantity)
+	}
+	variants := SkuVariants(sku.Selections)
+	return &InventoryItem{
+		ID:        InventoryID(slug, variants),
+		Slug:      slug,
+		Variants:  variants,
+		ProductID: sku.ProductID,
+		Quantity:  quantity,
+	}, nil
+}
+
+// IsUnlimited returns whether the SKU never runs out of stock.
+func (i *InventoryItem) IsUnlimited() bool {
+	return i.Quantity == UnlimitedInventory
+}
+
+// InStock returns whether the given quantity is available.
+func (i *InventoryItem) InStock(quantity iwallet.Amount) bool {
+	return i.IsUnlimited() || iwallet.NewAmount(i.Quantity).Cmp(quantity) >= 0
+}
+
+// Adjust adds delta to the quantity in stock. The quantity will not fall
+// below zero and unlimited SKUs are left unchanged.
+func (i *InventoryItem) Adjust(delta iwallet.Amount) {
+	if i.IsUnlimited() {
+		return
+	}
+	quantity := iwallet.NewAmount(i.Quantity).Add(delta)
+	if quantity.Cmp(iwallet.NewAmount(0)) < 0 {
+		quantity = iwallet.NewAmount(0)
+	}
+	i.Quantity = quantity.String()
+}
+
+// Inventory is the list of SKUs and their quantities for all of our
+// listings. It is published so that buyers can see what is in stock.
+type Inventory []InventoryItem
+
+// Get returns the inventory item for the listing slug and variants.
+func (inv Inventory) Get(slug, variants string) (*InventoryItem, bool) {
+	id := InventoryID(slug, variants)
+	for i := range inv {
+		if InventoryID(inv[i].Slug, inv[i].Variants) == id {
+			return &inv[i], true
+		}
+	}
+	return nil, false
+}
+
+// SkuVariants returns a canonical string describing the variant selections
+// of a SKU. The selections are sorted by option name and formatted as
+// "Option:Variant" joined by commas. A SKU without selections returns an
+// empty string.
+func SkuVariants(selections []*pb.Listing_Item_Sku_Selection) string {
+	vars := make([]string, 0, len(selections))
+	for _, sel := range selections {
+		vars = append(vars, sel.Option+":"+sel.Variant)
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return strings.ToLower(vars[i]) < strings.ToLower(vars[j])
+	})
+	return strings.Join(vars, ",")
+}
+
+// InventoryID returns the database ID of the inventory item for the listing
+// slug and variants. Variants are compared case insensitively to match the
+// way order options are matched to SKUs.
+func InventoryID(slug, variants string) string {
+	return slug + "/" + strings.ToLower(variants)
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+)
+
+// LedgerEntryType is the type of movement of funds recorded by a ledger entry.
+type LedgerEntryType string
+
+const (
+	// LedgerPayment is a payment into an order's payment address.
+	LedgerPayment LedgerEntryType = "PAYMENT"
+	// LedgerRefund is a refund or a canceled payment returned to the buyer.
+	LedgerRefund LedgerEntryType = "REFUND"
+	// LedgerEscrowRelease is a release of funds from an order's escrow.
+	LedgerEscrowRelease LedgerEntryType = "ESCROW_RELEASE"
+	// LedgerModeratorFee is the fee paid to the moderator from the escrow
+	// when a dispute is closed.
+	LedgerModeratorFee LedgerEntryType = "MODERATOR_FEE"
+	// LedgerTransaction is a wallet transaction which is not part of an order.
+	LedgerTransaction LedgerEntryType = "TRANSACTION"
+)
+
+// LedgerDirection is whether the funds moved to or from us.
+type LedgerDirection string
+
+const (
+	// LedgerIn means we received the funds.
+	LedgerIn LedgerDirection = "IN"
+	// LedgerOut means we sent the funds.
+	LedgerOut LedgerDirection = "OUT"
+)
+
+// LedgerEntry is a single row in an accounting ledger. Amounts are decimal
+// strings in whole units of the coin and of the fiat currency.
+type LedgerEntry struct {
+	Timestamp     time.Time       `json:"timestamp"`
+	Type          LedgerEntryType `json:"type"`
+	Direction     LedgerDirection `json:"direction"`
+	Role          OrderRole       `json:"role,omitempty"`
+	OrderID       OrderID         `json:"orderID,omitempty"`
+	Coin          string          `json:"coin"`
+	Amount        string          `json:"amount"`
+	FiatCurrency  string          `json:"fiatCurrency,omitempty"`
+	FiatValue     string          `json:"fiatValue,omitempty"`
+	Counterparty  string          `json:"counterparty,omitempty"`
+	TransactionID string          `json:"transactionID,omitempty"`
+	Memo          string          `json:"memo,omitempty"`
+}
+
+// LedgerQuery holds the filters used to select ledger entries. Zero values
+// are ignored.
+type LedgerQuery struct {
+	// From and To restrict the entries to the date range.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// Role restricts the entries to orders in which we have the given
+	// role. Wallet transactions which are not part of an order are only
+	// included when no role is set.
+	Role OrderRole `json:"role"`
+}
+
+// Matches returns whether the entry matches the query.
+func (q *LedgerQuery) Matches(entry *LedgerEntry) bool {
+	if !q.From.IsZero() && entry.Timestamp.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && entry.Timestamp.After(q.To) {
+		return false
+	}
+	if q.Role != "" && entry.Role != q.Role {
+		return false
+	}
+	return true
+}
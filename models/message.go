@@ -6,9 +6,29 @@ import (
 	"time"
 )
 
+// DeliveryStatus is the delivery state of an outgoing message.
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusQueued means the message has been saved to the outbox
+	// but hasn't been delivered anywhere yet.
+	DeliveryStatusQueued DeliveryStatus = "QUEUED"
+
+	// DeliveryStatusSentDirect means the message was sent directly to the
+	// recipient but has not yet been ACKed.
+	DeliveryStatusSentDirect DeliveryStatus = "SENT_DIRECT"
+
+	// DeliveryStatusStoredOnSNF means the recipient couldn't be reached and
+	// the message was pushed to its store-and-forward servers.
+	DeliveryStatusStoredOnSNF DeliveryStatus = "STORED_ON_SNF"
+
+	// DeliveryStatusAcked means the recipient ACKed the message.
+	DeliveryStatusAcked DeliveryStatus = "ACKED"
+)
+
 // OutgoingMessage represents a message that we've sent to another
 // peer. It will remain in the database until the remote peer ACKs
-// the message.
+// the message, it's cancelled, or it expires.
 type OutgoingMessage struct {
 	ID                string `gorm:"primaryKey"`
 	Recipient         string `gorm:"index"`
@@ -16,6 +36,22 @@ type OutgoingMessage struct {
 	MessageType       string
 	Timestamp         time.Time
 	LastAttempt       time.Time
+
+	// NextAttempt is when the message is next due to be retried.
+	NextAttempt time.Time `gorm:"index"`
+
+	// Status is the furthest the message has made it towards the recipient.
+	Status DeliveryStatus
+
+	// Attempts is the number of times we've tried to send the message.
+	Attempts int
+
+	// LastError is the error from the most recent send attempt, if any.
+	LastError string
+
+	// Failed is set when a message that must not be silently dropped
+	// expires. Failed messages are no longer retried automatically.
+	Failed bool
 }
 
 func (m *OutgoingMessage) Message() (*pb.Message, error) {
@@ -26,6 +62,38 @@ func (m *OutgoingMessage) Message() (*pb.Message, error) {
 	return msg, nil
 }
 
+// OutboxMessage is the public representation of an OutgoingMessage.
+type OutboxMessage struct {
+	MessageID   string         `json:"messageID"`
+	Recipient   string         `json:"recipient"`
+	MessageType string         `json:"messageType"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Age         string         `json:"age"`
+	Status      DeliveryStatus `json:"status"`
+	Attempts    int            `json:"attempts"`
+	LastAttempt time.Time      `json:"lastAttempt"`
+	NextAttempt time.Time      `json:"nextAttempt"`
+	LastError   string         `json:"lastError"`
+	Failed      bool           `json:"failed"`
+}
+
+// OutboxMessage returns the public representation of the message.
+func (m *OutgoingMessage) OutboxMessage() OutboxMessage {
+	return OutboxMessage{
+		MessageID:   m.ID,
+		Recipient:   m.Recipient,
+		MessageType: m.MessageType,
+		Timestamp:   m.Timestamp,
+		Age:         time.Since(m.Timestamp).Truncate(time.Second).String(),
+		Status:      m.Status,
+		Attempts:    m.Attempts,
+		LastAttempt: m.LastAttempt,
+		NextAttempt: m.NextAttempt,
+		LastError:   m.LastError,
+		Failed:      m.Failed,
+	}
+}
+
 // IncomingMessage represents a message that we've received. We store
 // all received message IDs in the database so we can tell when we've
 // received a duplicate.
@@ -14,3 +14,22 @@ type TransactionMetadata struct {
 	OrderID        OrderID
 	Thumbnail      string
 }
+
+// SetOrder links the metadata to the order. The thumbnail, and the memo if
+// it is empty, are taken from the first listing in the order.
+func (m *TransactionMetadata) SetOrder(order *Order) {
+	m.OrderID = order.ID
+
+	orderOpen, err := order.OrderOpenMessage()
+	if err != nil || len(orderOpen.Listings) == 0 || orderOpen.Listings[0].Listing == nil ||
+		orderOpen.Listings[0].Listing.Item == nil {
+		return
+	}
+	item := orderOpen.Listings[0].Listing.Item
+	if len(item.Images) > 0 {
+		m.Thumbnail = item.Images[0].Tiny
+	}
+	if m.Memo == "" {
+		m.Memo = item.Title
+	}
+}
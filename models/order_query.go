@@ -0,0 +1,186 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// OrderQuery holds the filters used to select orders from the database.
+// Zero values are ignored.
+type OrderQuery struct {
+	// Role restricts the results to orders in which we have the given role.
+	Role OrderRole `json:"role"`
+
+	// Open restricts the results to either open (true) or closed (false)
+	// orders. Nil returns both.
+	Open *bool `json:"open"`
+
+	// Statuses restricts the results to orders with one of the given statuses.
+	Statuses []OrderStatus `json:"statuses"`
+
+	// From and To restrict the results to orders opened within the date range.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// BuyerID and VendorID restrict the results to orders with the given
+	// buyer or vendor.
+	BuyerID  string `json:"buyerID"`
+	VendorID string `json:"vendorID"`
+
+	// Search is a case insensitive search string that is matched against
+	// the titles of the listings in the order as well as the order ID.
+	Search string `json:"search"`
+
+	// Limit is the maximum number of results to return. A limit less than
+	// one returns all results.
+	Limit int `json:"limit"`
+
+	// OffsetID is the ID of the last order returned in the previous page.
+	// Results will begin with the order immediately after it.
+	OffsetID OrderID `json:"offsetID"`
+}
+
+// Matches returns whether the order matches all the non-database filters
+// in the query.
+func (q *OrderQuery) Matches(order *Order) (bool, error) {
+	orderOpen, err := order.OrderOpenMessage()
+	if errors.Is(err, ErrMessageDoesNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if q.Role != "" && order.Role() != q.Role {
+		return false, nil
+	}
+
+	status := order.Status()
+	if q.Open != nil && *q.Open == status.Closed() {
+		return false, nil
+	}
+
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, s := range q.Statuses {
+			if s == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	if !q.From.IsZero() || !q.To.IsZero() {
+		timestamp, err := order.Timestamp()
+		if err != nil {
+			return false, err
+		}
+		if !q.From.IsZero() && timestamp.Before(q.From) {
+			return false, nil
+		}
+		if !q.To.IsZero() && timestamp.After(q.To) {
+			return false, nil
+		}
+	}
+
+	if q.BuyerID != "" && (orderOpen.BuyerID == nil || orderOpen.BuyerID.PeerID != q.BuyerID) {
+		return false, nil
+	}
+
+	if q.VendorID != "" {
+		if len(orderOpen.Listings) == 0 || orderOpen.Listings[0].Listing == nil ||
+			orderOpen.Listings[0].Listing.VendorID == nil || orderOpen.Listings[0].Listing.VendorID.PeerID != q.VendorID {
+			return false, nil
+		}
+	}
+
+	if q.Search != "" {
+		search := strings.ToLower(q.Search)
+		found := strings.Contains(strings.ToLower(order.ID.String()), search)
+		for _, sl := range orderOpen.Listings {
+			if sl.Listing == nil || sl.Listing.Item == nil {
+				continue
+			}
+			if strings.Contains(strings.ToLower(sl.Listing.Item.Title), search) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// OrderSummary is an abbreviated view of an order used when
+// returning lists of orders.
+type OrderSummary struct {
+	OrderID        OrderID     `json:"orderID"`
+	Role           OrderRole   `json:"role"`
+	Status         OrderStatus `json:"status"`
+	Open           bool        `json:"open"`
+	Timestamp      time.Time   `json:"timestamp"`
+	Titles         []string    `json:"titles"`
+	Thumbnail      string      `json:"thumbnail"`
+	BuyerID        string      `json:"buyerID"`
+	VendorID       string      `json:"vendorID"`
+	Moderator      string      `json:"moderator"`
+	PaymentCoin    string      `json:"paymentCoin"`
+	Total          string      `json:"total"`
+	PaymentAddress string      `json:"paymentAddress"`
+	UnderDispute   bool        `json:"underDispute"`
+	FiatValues     []FiatValue `json:"fiatValues"`
+}
+
+// NewOrderSummary builds an OrderSummary from the provided order.
+func NewOrderSummary(order *Order) (*OrderSummary, error) {
+	orderOpen, err := order.OrderOpenMessage()
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := order.Timestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	status := order.Status()
+	summary := &OrderSummary{
+		OrderID:        order.ID,
+		Role:           order.Role(),
+		Status:         status,
+		Open:           !status.Closed(),
+		Timestamp:      timestamp,
+		Titles:         make([]string, 0, len(orderOpen.Listings)),
+		PaymentAddress: order.PaymentAddress,
+		UnderDispute:   order.UnderActiveDispute(),
+	}
+	if orderOpen.BuyerID != nil {
+		summary.BuyerID = orderOpen.BuyerID.PeerID
+	}
+	if orderOpen.Payment != nil {
+		summary.Moderator = orderOpen.Payment.Moderator
+		summary.PaymentCoin = orderOpen.Payment.Coin
+		summary.Total = orderOpen.Payment.Amount
+	}
+	for i, sl := range orderOpen.Listings {
+		if sl.Listing == nil {
+			continue
+		}
+		if i == 0 && sl.Listing.VendorID != nil {
+			summary.VendorID = sl.Listing.VendorID.PeerID
+		}
+		if sl.Listing.Item == nil {
+			continue
+		}
+		summary.Titles = append(summary.Titles, sl.Listing.Item.Title)
+		if summary.Thumbnail == "" && len(sl.Listing.Item.Images) > 0 {
+			summary.Thumbnail = sl.Listing.Item.Images[0].Tiny
+		}
+	}
+	return summary, nil
+}
@@ -1,11 +1,13 @@
 package models
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	npb "github.com/cpacia/openbazaar3.0/net/pb"
 	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
 	iwallet "github.com/cpacia/wallet-interface"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
@@ -61,6 +63,46 @@ const (
 	RoleModerator OrderRole = "moderator"
 )
 
+// OrderStatus is the status of an order as derived from the order
+// messages which have been saved in it.
+type OrderStatus string
+
+const (
+	// StatusUnknown means the order open message has not yet been received.
+	StatusUnknown OrderStatus = "UNKNOWN"
+	// StatusAwaitingPayment means the order is open but not yet fully funded.
+	StatusAwaitingPayment OrderStatus = "AWAITING_PAYMENT"
+	// StatusFunded means the order has been fully funded.
+	StatusFunded OrderStatus = "FUNDED"
+	// StatusFulfilled means the vendor has fulfilled every item in the order.
+	StatusFulfilled OrderStatus = "FULFILLED"
+	// StatusRefunded means the vendor refunded the order prior to fulfillment.
+	StatusRefunded OrderStatus = "REFUNDED"
+	// StatusDisputed means a dispute is currently open.
+	StatusDisputed OrderStatus = "DISPUTED"
+	// StatusDisputeClosed means the moderator has closed the dispute.
+	StatusDisputeClosed OrderStatus = "DISPUTE_CLOSED"
+	// StatusRejected means the vendor rejected the order.
+	StatusRejected OrderStatus = "REJECTED"
+	// StatusCanceled means the buyer canceled the order.
+	StatusCanceled OrderStatus = "CANCELED"
+	// StatusPaymentFinalized means the vendor released the funds from escrow
+	// after the escrow timeout.
+	StatusPaymentFinalized OrderStatus = "PAYMENT_FINALIZED"
+	// StatusCompleted means the buyer has completed the order.
+	StatusCompleted OrderStatus = "COMPLETED"
+)
+
+// Closed returns whether the status is terminal and no further
+// action is expected on the order.
+func (s OrderStatus) Closed() bool {
+	switch s {
+	case StatusCompleted, StatusPaymentFinalized, StatusRejected, StatusCanceled, StatusDisputeClosed:
+		return true
+	}
+	return false
+}
+
 // Order holds the state of all orders. This model is saved in the
 // database indexed by the order ID.
 type Order struct {
@@ -859,6 +901,141 @@ func (o *Order) CanDispute() bool {
 	return true
 }
 
+// CanAcceptDisputePayout returns whether or not this order is in a state where the
+// user can accept the moderator's payout and release the funds from escrow.
+func (o *Order) CanAcceptDisputePayout() bool {
+	// Only buyers and vendors can accept the payout.
+	if o.Role() != RoleBuyer && o.Role() != RoleVendor {
+		return false
+	}
+
+	// The moderator must have closed the dispute.
+	disputeClose, err := o.DisputeClosedMessage()
+	if err != nil || disputeClose.ReleaseInfo == nil {
+		return false
+	}
+
+	// Cannot accept if the funds were already released.
+	released, err := o.IsDisputePayoutReleased()
+	if err != nil || released {
+		return false
+	}
+
+	return true
+}
+
+// IsDisputePayoutReleased returns whether a transaction spending the escrow inputs
+// in the moderator's payout has been recorded in the order.
+func (o *Order) IsDisputePayoutReleased() (bool, error) {
+	disputeClose, err := o.DisputeClosedMessage()
+	if err != nil {
+		return false, err
+	}
+	if disputeClose.ReleaseInfo == nil {
+		return false, nil
+	}
+
+	txs, err := o.GetTransactions()
+	if err != nil && !IsMessageNotExistError(err) {
+		return false, err
+	}
+	for _, tx := range txs {
+		for _, from := range tx.From {
+			for _, id := range disputeClose.ReleaseInfo.FromIDs {
+				if bytes.Equal(from.ID, id) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// CanReleaseEscrowAfterTimeout returns whether or not this order is in a state
+// where the vendor can release the funds from escrow using the timeout key.
+func (o *Order) CanReleaseEscrowAfterTimeout() bool {
+	// Only vendors can release after the timeout.
+	if o.Role() != RoleVendor {
+		return false
+	}
+
+	// The order must be fulfilled and not otherwise closed or disputed.
+	if o.Status() != StatusFulfilled {
+		return false
+	}
+
+	expiry, err := o.EscrowExpiry()
+	if err != nil || expiry.IsZero() {
+		return false
+	}
+	return time.Now().After(expiry)
+}
+
+// EscrowExpiry returns the time at which the escrow timeout for this order expires.
+// The zero time is returned if the order is not moderated, the escrow does not have
+// a timeout, or the order has not been funded.
+func (o *Order) EscrowExpiry() (time.Time, error) {
+	orderOpen, err := o.OrderOpenMessage()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if orderOpen.Payment.Method != pb.OrderOpen_Payment_MODERATED {
+		return time.Time{}, nil
+	}
+
+	txs, err := o.GetTransactions()
+	if err != nil && !IsMessageNotExistError(err) {
+		return time.Time{}, err
+	}
+
+	var fundingTimes []time.Time
+	for _, tx := range txs {
+		for _, to := range tx.To {
+			if to.Address.String() == orderOpen.Payment.Address {
+				fundingTimes = append(fundingTimes, tx.Timestamp)
+				break
+			}
+		}
+	}
+	return utils.EscrowExpiry(orderOpen, fundingTimes), nil
+}
+
+// Status returns the current status of the order as derived from the
+// messages that have been saved in it.
+func (o *Order) Status() OrderStatus {
+	if o.SerializedOrderOpen == nil {
+		return StatusUnknown
+	}
+	switch {
+	case o.SerializedOrderComplete != nil:
+		return StatusCompleted
+	case o.SerializedPaymentFinalized != nil:
+		return StatusPaymentFinalized
+	case o.SerializedOrderReject != nil:
+		return StatusRejected
+	case o.SerializedOrderCancel != nil:
+		return StatusCanceled
+	case o.SerializedDisputeClosed != nil:
+		return StatusDisputeClosed
+	case o.SerializedDisputeOpen != nil:
+		return StatusDisputed
+	}
+
+	fulfilled, err := o.IsFulfilled()
+	if err == nil && fulfilled {
+		return StatusFulfilled
+	}
+	if o.SerializedRefunds != nil {
+		return StatusRefunded
+	}
+	funded, err := o.IsFunded()
+	if err == nil && funded {
+		return StatusFunded
+	}
+	return StatusAwaitingPayment
+}
+
 // UnderActiveDispute returns whether this order is currently being disputed.
 func (o *Order) UnderActiveDispute() bool {
 	if o.SerializedDisputeOpen != nil && o.SerializedDisputeClosed == nil {
@@ -1030,20 +1207,20 @@ func (o *Order) toProtobuf() (*pb.Contract, error) {
 		return nil, err
 	}
 
-	if o.ParkedMessages != nil {
-		parked := new(npb.OrderList)
-		if err := json.Unmarshal(o.ParkedMessages, parked); err != nil {
-			return nil, err
-		}
-		contract.ParkedMessages = parked
+	parked, err := o.GetParkedMessages()
+	if err != nil {
+		return nil, err
+	}
+	if parked != nil {
+		contract.ParkedMessages = &npb.OrderList{Messages: parked}
 	}
 
-	if o.ErroredMessages != nil {
-		errored := new(npb.OrderList)
-		if err := json.Unmarshal(o.ParkedMessages, errored); err != nil {
-			return nil, err
-		}
-		contract.ErroredMessages = errored
+	errored, err := o.GetErroredMessages()
+	if err != nil {
+		return nil, err
+	}
+	if errored != nil {
+		contract.ErroredMessages = &npb.OrderList{Messages: errored}
 	}
 
 	var transactions []*pb.Contract_Transaction
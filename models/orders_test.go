@@ -2017,3 +2017,127 @@ func TestOrder_FundingTotal(t *testing.T) {
 		}
 	}
 }
+
+func TestOrder_Status(t *testing.T) {
+	orderOpen := &pb.OrderOpen{
+		Items: []*pb.OrderOpen_Item{{}},
+		Payment: &pb.OrderOpen_Payment{
+			Amount:  "1000",
+			Address: "aaaaaa",
+		},
+	}
+	fund := func(order *Order) error {
+		return order.PutTransaction(iwallet.Transaction{
+			To: []iwallet.SpendInfo{
+				{
+					Address: iwallet.NewAddress("aaaaaa", iwallet.CtMock),
+					Amount:  iwallet.NewAmount("1000"),
+				},
+			},
+		})
+	}
+	fulfill := func(order *Order) error {
+		if err := fund(order); err != nil {
+			return err
+		}
+		return order.PutMessage(utils.MustWrapOrderMessage(&pb.OrderFulfillment{
+			Fulfillments: []*pb.OrderFulfillment_FulfilledItem{{ItemIndex: 0}},
+		}))
+	}
+
+	tests := []struct {
+		setup    func(order *Order) error
+		expected OrderStatus
+		closed   bool
+	}{
+		{
+			setup: func(order *Order) error {
+				return nil
+			},
+			expected: StatusAwaitingPayment,
+		},
+		{
+			setup:    fund,
+			expected: StatusFunded,
+		},
+		{
+			setup:    fulfill,
+			expected: StatusFulfilled,
+		},
+		{
+			setup: func(order *Order) error {
+				if err := fund(order); err != nil {
+					return err
+				}
+				return order.PutMessage(utils.MustWrapOrderMessage(&pb.Refund{RefundInfo: &pb.Refund_TransactionID{TransactionID: "abc"}}))
+			},
+			expected: StatusRefunded,
+		},
+		{
+			setup: func(order *Order) error {
+				return order.PutMessage(utils.MustWrapOrderMessage(&pb.OrderReject{}))
+			},
+			expected: StatusRejected,
+			closed:   true,
+		},
+		{
+			setup: func(order *Order) error {
+				return order.PutMessage(utils.MustWrapOrderMessage(&pb.OrderCancel{}))
+			},
+			expected: StatusCanceled,
+			closed:   true,
+		},
+		{
+			setup: func(order *Order) error {
+				if err := fulfill(order); err != nil {
+					return err
+				}
+				return order.PutMessage(utils.MustWrapOrderMessage(&pb.DisputeOpen{}))
+			},
+			expected: StatusDisputed,
+		},
+		{
+			setup: func(order *Order) error {
+				if err := order.PutMessage(utils.MustWrapOrderMessage(&pb.DisputeOpen{})); err != nil {
+					return err
+				}
+				return order.PutMessage(utils.MustWrapOrderMessage(&pb.DisputeClose{}))
+			},
+			expected: StatusDisputeClosed,
+			closed:   true,
+		},
+		{
+			setup: func(order *Order) error {
+				if err := fulfill(order); err != nil {
+					return err
+				}
+				return order.PutMessage(utils.MustWrapOrderMessage(&pb.OrderComplete{}))
+			},
+			expected: StatusCompleted,
+			closed:   true,
+		},
+	}
+
+	var empty Order
+	if empty.Status() != StatusUnknown {
+		t.Errorf("Expected status %s, got %s", StatusUnknown, empty.Status())
+	}
+
+	for i, test := range tests {
+		var order Order
+		if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+			t.Fatal(err)
+		}
+		if err := test.setup(&order); err != nil {
+			t.Errorf("Test %d setup failed: %s", i, err)
+			continue
+		}
+		status := order.Status()
+		if status != test.expected {
+			t.Errorf("Test %d: expected status %s, got %s", i, test.expected, status)
+		}
+		if status.Closed() != test.closed {
+			t.Errorf("Test %d: expected closed %t, got %t", i, test.closed, status.Closed())
+		}
+	}
+}
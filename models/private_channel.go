@@ -0,0 +1,33 @@
+package models
+
+// PrivateChannel holds the membership of a private group channel. Messages
+// in the channel are encrypted with a group key which the owner sends to
+// each member. The key is rotated, starting a new epoch, whenever the
+// membership changes.
+type PrivateChannel struct {
+	Topic   string   `gorm:"primaryKey" json:"topic"`
+	Owner   string   `json:"owner"`
+	Epoch   uint64   `json:"epoch"`
+	Members []string `gorm:"-" json:"members"`
+
+	// MemberList is the serialized member list signed by the owner.
+	MemberList []byte `json:"-"`
+}
+
+// IsMember returns whether the peer is a member of the channel.
+func (pc *PrivateChannel) IsMember(peerID string) bool {
+	for _, m := range pc.Members {
+		if m == peerID {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelKey is the group key for one epoch of a private channel. Keys
+// from earlier epochs are kept so the channel history can be decrypted.
+type ChannelKey struct {
+	Topic string `gorm:"primaryKey"`
+	Epoch uint64 `gorm:"primaryKey"`
+	Key   []byte
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RateLimitMetrics holds the counters from the network rate limiter.
+type RateLimitMetrics struct {
+	MessagesAllowed uint64              `json:"messagesAllowed"`
+	MessagesDropped uint64              `json:"messagesDropped"`
+	TemporaryBans   uint64              `json:"temporaryBans"`
+	Peers           []PeerRateLimitInfo `json:"peers"`
+}
+
+// PeerRateLimitInfo holds the rate limiter state for a single peer.
+type PeerRateLimitInfo struct {
+	PeerID          string     `json:"peerID"`
+	Score           float64    `json:"score"`
+	MessagesAllowed uint64     `json:"messagesAllowed"`
+	MessagesDropped uint64     `json:"messagesDropped"`
+	BannedUntil     *time.Time `json:"bannedUntil,omitempty"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	iwallet "github.com/cpacia/wallet-interface"
+	"strings"
+	"time"
+)
+
+// TransactionDirection is whether a wallet transaction paid us or was paid
+// by us.
+type TransactionDirection string
+
+const (
+	// TransactionIncoming is a transaction with a positive net value.
+	TransactionIncoming TransactionDirection = "INCOMING"
+	// TransactionOutgoing is a transaction with a negative net value.
+	TransactionOutgoing TransactionDirection = "OUTGOING"
+)
+
+// TransactionQuery holds the filters used to select wallet transactions.
+// Zero values are ignored.
+type TransactionQuery struct {
+	// Coins restricts the results to the wallets for the given currency
+	// codes.
+	Coins []string `json:"coins"`
+
+	// Direction restricts the results to incoming or outgoing transactions.
+	Direction TransactionDirection `json:"direction"`
+
+	// From and To restrict the results to transactions within the date range.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// MinAmount and MaxAmount restrict the absolute net value of the
+	// transactions in the coin's base unit.
+	MinAmount *iwallet.Amount `json:"minAmount"`
+	MaxAmount *iwallet.Amount `json:"maxAmount"`
+
+	// OrderID restricts the results to transactions for the given order.
+	OrderID OrderID `json:"orderID"`
+
+	// Limit is the maximum number of results to return. A limit less than
+	// one returns all results.
+	Limit int `json:"limit"`
+
+	// OffsetID is the ID of the last transaction returned in the previous
+	// page. Results will begin with the transaction immediately after it.
+	OffsetID string `json:"offsetID"`
+}
+
+// Matches returns whether the transaction matches the query. The coin,
+// limit and offset filters are applied by the caller.
+func (q *TransactionQuery) Matches(tx *WalletTransaction) bool {
+	if q.Direction != "" && tx.Direction != q.Direction {
+		return false
+	}
+	if !q.From.IsZero() && tx.Timestamp.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && tx.Timestamp.After(q.To) {
+		return false
+	}
+	if q.MinAmount != nil || q.MaxAmount != nil {
+		amount := iwallet.NewAmount(strings.TrimPrefix(tx.Value, "-"))
+		if q.MinAmount != nil && amount.Cmp(*q.MinAmount) < 0 {
+			return false
+		}
+		if q.MaxAmount != nil && amount.Cmp(*q.MaxAmount) > 0 {
+			return false
+		}
+	}
+	if q.OrderID != "" && tx.OrderID != q.OrderID {
+		return false
+	}
+	return true
+}
+
+// WalletTransaction is a wallet transaction joined with the metadata we
+// save for it.
+type WalletTransaction struct {
+	Coin          string               `json:"coin"`
+	Txid          string               `json:"txid"`
+	Value         string               `json:"value"`
+	Direction     TransactionDirection `json:"direction"`
+	Status        string               `json:"status"`
+	Timestamp     time.Time            `json:"timestamp"`
+	Confirmations uint64               `json:"confirmations"`
+	Height        uint64               `json:"height"`
+	Address       string               `json:"address"`
+	Memo          string               `json:"memo"`
+	OrderID       OrderID              `json:"orderID"`
+	Thumbnail     string               `json:"thumbnail"`
+}
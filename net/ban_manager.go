@@ -3,10 +3,15 @@ package net
 import (
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	"sync"
+	"time"
 )
 
+// BanManager tracks the peers that have been blocked by the user as well as
+// peers that have been temporarily banned for misbehaving. Temporary bans
+// expire on their own.
 type BanManager struct {
 	blockedIds map[string]bool
+	tempBans   map[string]time.Time
 	*sync.RWMutex
 }
 
@@ -15,7 +20,7 @@ func NewBanManager(blockedIds []peer.ID) *BanManager {
 	for _, pid := range blockedIds {
 		blockedMap[pid.Pretty()] = true
 	}
-	return &BanManager{blockedMap, new(sync.RWMutex)}
+	return &BanManager{blockedMap, make(map[string]time.Time), new(sync.RWMutex)}
 }
 
 func (bm *BanManager) AddBlockedID(peerID peer.ID) {
@@ -62,3 +67,38 @@ func (bm *BanManager) IsBanned(peerID peer.ID) bool {
 	defer bm.RUnlock()
 	return bm.blockedIds[peerID.Pretty()]
 }
+
+// AddTemporaryBan bans the peer until the duration has elapsed. If the peer
+// is already temporarily banned the later expiry is used.
+func (bm *BanManager) AddTemporaryBan(peerID peer.ID, duration time.Duration) time.Time {
+	bm.Lock()
+	defer bm.Unlock()
+	expiry := time.Now().Add(duration)
+	if current, ok := bm.tempBans[peerID.Pretty()]; ok && current.After(expiry) {
+		return current
+	}
+	bm.tempBans[peerID.Pretty()] = expiry
+	return expiry
+}
+
+// TemporaryBanExpiry returns the time the peer's temporary ban expires and
+// whether the peer is currently temporarily banned.
+func (bm *BanManager) TemporaryBanExpiry(peerID peer.ID) (time.Time, bool) {
+	bm.Lock()
+	defer bm.Unlock()
+	expiry, ok := bm.tempBans[peerID.Pretty()]
+	if !ok {
+		return time.Time{}, false
+	}
+	if time.Now().After(expiry) {
+		delete(bm.tempBans, peerID.Pretty())
+		return time.Time{}, false
+	}
+	return expiry, true
+}
+
+// IsTemporarilyBanned returns whether the peer is serving a temporary ban.
+func (bm *BanManager) IsTemporarilyBanned(peerID peer.ID) bool {
+	_, banned := bm.TemporaryBanExpiry(peerID)
+	return banned
+}
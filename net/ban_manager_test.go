@@ -3,6 +3,7 @@ package net
 import (
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	"testing"
+	"time"
 )
 
 func bannedPeers() []peer.ID {
@@ -92,3 +93,29 @@ func TestBanManager_GetBlockedIds(t *testing.T) {
 		}
 	}
 }
+
+func TestBanManager_AddTemporaryBan(t *testing.T) {
+	banned := bannedPeers()
+	bm := NewBanManager(nil)
+
+	bm.AddTemporaryBan(banned[0], time.Hour)
+	bm.AddTemporaryBan(banned[1], -time.Second)
+
+	if !bm.IsTemporarilyBanned(banned[0]) {
+		t.Errorf("Peer %s is not banned", banned[0].Pretty())
+	}
+	if bm.IsTemporarilyBanned(banned[1]) {
+		t.Errorf("Peer %s ban did not expire", banned[1].Pretty())
+	}
+	if bm.IsBanned(banned[0]) {
+		t.Errorf("Temporary ban added peer %s to the blocked list", banned[0].Pretty())
+	}
+
+	expiry, ok := bm.TemporaryBanExpiry(banned[0])
+	if !ok {
+		t.Fatal("Expiry not found")
+	}
+	if bm.AddTemporaryBan(banned[0], time.Minute) != expiry {
+		t.Error("Shorter ban replaced the existing ban")
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	storeandforward "github.com/cpacia/go-store-and-forward"
 	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/net/pb"
 	"github.com/golang/protobuf/proto"
@@ -16,6 +18,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	"gorm.io/gorm"
+	mrand "math/rand"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -23,10 +26,15 @@ import (
 )
 
 const (
-	// RetryInterval is the interval at which retry sending messages
-	// that haven't yet been ACKed.
+	// RetryInterval is the interval at which we check for messages
+	// that haven't yet been ACKed and are due to be retried. It's also
+	// the base delay for the exponential backoff.
 	RetryInterval = time.Minute * 1
 
+	// MaxRetryInterval is the maximum delay between retries of a
+	// message.
+	MaxRetryInterval = time.Hour * 24
+
 	// RequeryInterval is the interval at which re-query the store
 	// and forward servers. We don't want to poll to frequently as
 	// we are also subscribed to push messages from them.
@@ -36,8 +44,14 @@ const (
 	// message before giving up and sending it to the store and forward
 	// servers.
 	SendTimeout = time.Second * 5
+
+	// retryBatchSize is the maximum number of messages that will be
+	// retried each RetryInterval.
+	retryBatchSize = 500
 )
 
+var errSkippedDirectSend = errors.New("peer unreachable")
+
 // Messenger manages the reliable sending of outgoing messages.
 // New messages are saved to the database and continually retried
 // until the recipient receives it.
@@ -47,6 +61,8 @@ type Messenger struct {
 	sk             crypto.PrivKey
 	snfClient      *storeandforward.Client
 	getProfileFunc func(ctx context.Context, peerID peer.ID, useCache bool) (*models.Profile, error)
+	eventBus       events.Bus
+	messageTTL     map[pb.Message_MessageType]time.Duration
 	done           chan struct{}
 	bootstrapDone  chan struct{}
 	mtx            sync.RWMutex
@@ -62,6 +78,16 @@ type MessengerConfig struct {
 	SNFServers     []peer.ID
 	Testnet        bool
 	GetProfileFunc func(ctx context.Context, peerID peer.ID, useCache bool) (*models.Profile, error)
+
+	// EventBus, if set, is used to emit an event when an outgoing
+	// message fails.
+	EventBus events.Bus
+
+	// MessageTTL is how long we will try to deliver a message of the
+	// given type before giving up on it. Types without a TTL are retried
+	// until they are ACKed. ORDER and DISPUTE messages are never dropped
+	// when they expire. Instead they are marked as failed.
+	MessageTTL map[pb.Message_MessageType]time.Duration
 }
 
 // NewMessenger returns a Messenger and starts the retry service.
@@ -85,6 +111,8 @@ func NewMessenger(cfg *MessengerConfig) (*Messenger, error) {
 		sk:             cfg.Privkey,
 		snfClient:      client,
 		getProfileFunc: cfg.GetProfileFunc,
+		eventBus:       cfg.EventBus,
+		messageTTL:     cfg.MessageTTL,
 		done:           make(chan struct{}),
 		bootstrapDone:  bootstrapDone,
 		mtx:            sync.RWMutex{},
@@ -121,21 +149,24 @@ func (m *Messenger) ReliablySendMessage(tx database.Tx, peer peer.ID, message *p
 	// Before we do anything save the message to the database. This way
 	// we can retry sending the message until we know for sure that it
 	// has been delivered.
-	err = tx.Save(&models.OutgoingMessage{
+	om := &models.OutgoingMessage{
 		ID:                message.MessageID,
 		Recipient:         peer.Pretty(),
 		SerializedMessage: ser,
 		MessageType:       message.MessageType.String(),
 		Timestamp:         time.Now(),
 		LastAttempt:       time.Now(),
-	})
-	if err != nil {
+		NextAttempt:       time.Now().Add(nextRetryDelay(0)),
+		Status:            models.DeliveryStatusQueued,
+	}
+	if err := tx.Save(om); err != nil {
 		m.wg.Done()
 		return err
 	}
 
 	// Send the message on commit.
 	tx.RegisterCommitHook(func() {
+		m.emitDeliveryUpdate(om, models.DeliveryStatusQueued)
 		go m.trySendMessage(peer, message, done)
 	})
 
@@ -149,7 +180,68 @@ func (m *Messenger) ProcessACK(tx database.Tx, ack *pb.AckMessage) error {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
-	return tx.Delete("id", ack.AckedMessageID, nil, &models.OutgoingMessage{})
+	var om models.OutgoingMessage
+	if err := tx.Read().Where("id = ?", ack.AckedMessageID).First(&om).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := tx.Delete("id", ack.AckedMessageID, nil, &models.OutgoingMessage{}); err != nil {
+		return err
+	}
+	tx.RegisterCommitHook(func() {
+		m.emitDeliveryUpdate(&om, models.DeliveryStatusAcked)
+	})
+	return nil
+}
+
+// RetryMessage immediately tries to send the outgoing message with the given
+// ID. If the message had failed it is revived and its TTL starts over.
+func (m *Messenger) RetryMessage(messageID string) error {
+	var (
+		message models.OutgoingMessage
+		pmes    *pb.Message
+		pid     peer.ID
+	)
+	err := m.db.Update(func(tx database.Tx) error {
+		if err := tx.Read().Where("id = ?", messageID).First(&message).Error; err != nil {
+			return err
+		}
+		var err error
+		pmes, err = message.Message()
+		if err != nil {
+			return err
+		}
+		pid, err = peer.Decode(message.Recipient)
+		if err != nil {
+			return err
+		}
+		if message.Failed {
+			message.Failed = false
+			message.Timestamp = time.Now()
+		}
+		message.LastAttempt = time.Now()
+		message.NextAttempt = time.Now().Add(nextRetryDelay(message.Attempts))
+		return tx.Save(&message)
+	})
+	if err != nil {
+		return err
+	}
+
+	m.wg.Add(1)
+	go m.trySendMessage(pid, pmes, nil)
+	return nil
+}
+
+// CancelMessage deletes the outgoing message with the given ID so
+// that we no longer try to send it.
+func (m *Messenger) CancelMessage(messageID string) error {
+	return m.db.Update(func(tx database.Tx) error {
+		if err := tx.Read().Where("id = ?", messageID).First(&models.OutgoingMessage{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete("id", messageID, nil, &models.OutgoingMessage{})
+	})
 }
 
 // SendACK sends an ACK for the message with the given ID to the provided
@@ -215,6 +307,14 @@ func (m *Messenger) Start() {
 			p, pmes, err := m.decryptMessage(msg.EncryptedMessage)
 			if err != nil {
 				log.Warningf("Decryption failed for message %x", msg.MessageID)
+				continue
+			}
+			if err := m.ns.admitMessage(p, pmes); err != nil {
+				log.Debugf("Dropping %s message from peer %s: %s", pmes.MessageType, p, err)
+				if err := m.snfClient.AckMessage(context.Background(), msg.MessageID); err != nil {
+					log.Errorf("Error acking message with snf servers: %s", err)
+				}
+				continue
 			}
 			m.ns.handlerMtx.RLock()
 			handler, ok := m.ns.handlers[pmes.MessageType]
@@ -235,9 +335,8 @@ func (m *Messenger) Start() {
 	}
 }
 
-// trySendMessage tries to send the message directly to the peer using a
-// network connection. If that fails, it sends the message over the offline
-// messaging system.
+// trySendMessage tries to send the message and records the outcome of
+// the attempt against the outgoing message.
 func (m *Messenger) trySendMessage(peerID peer.ID, message *pb.Message, done chan<- struct{}) {
 	defer func() {
 		if done != nil {
@@ -246,93 +345,215 @@ func (m *Messenger) trySendMessage(peerID peer.ID, message *pb.Message, done cha
 		m.wg.Done()
 	}()
 
-	ctx, cancel := context.WithTimeout(context.Background(), SendTimeout)
-	defer cancel()
+	m.attemptSend(peerID, message, true)
+}
 
-	if err := m.ns.SendMessage(ctx, peerID, message); err != nil && m.snfClient != nil {
-		log.Debugf("Failed to connect to peer %s. Sending offline message.", peerID.Pretty())
-		// We failed to deliver directly to the peer. Let's send
-		// using the offline system.
-		var record models.StoreAndForwardServers
-		dberr := m.db.View(func(tx database.Tx) error {
-			if err := tx.Read().Where("peer_id=?", peerID.Pretty()).Find(&record).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-				return err
-			}
-			return nil
-		})
-		servers, iberr := record.Servers()
-		if dberr != nil || iberr != nil {
-			log.Errorf("Error loading peers snf server addresses %s", err)
+// retryPeerMessages sends the messages to the peer in order. If the peer
+// can't be reached directly the remaining messages are sent straight to its
+// store-and-forward servers rather than dialing the peer for each one.
+func (m *Messenger) retryPeerMessages(peerID peer.ID, messages []*pb.Message) {
+	defer m.wg.Done()
+
+	tryDirect := true
+	for _, message := range messages {
+		select {
+		case <-m.done:
 			return
+		default:
+		}
+		if m.attemptSend(peerID, message, tryDirect) != models.DeliveryStatusSentDirect {
+			tryDirect = false
 		}
+	}
+}
 
-		if (len(servers) == 0 || record.LastUpdated.Add(time.Hour*48).Before(time.Now())) && m.getProfileFunc != nil {
-			profile, err := m.getProfileFunc(context.Background(), peerID, true)
-			if err != nil {
-				log.Errorf("Error sending offline message: Can't load profile for peer %s", peerID.Pretty())
-				return
-			}
-			if len(profile.StoreAndForwardServers) == 0 {
-				log.Errorf("Error sending offline message: No inbox peers for peer %s", peerID.Pretty())
-				return
-			}
-			servers = []peer.ID{}
-			for _, peerStr := range profile.StoreAndForwardServers {
-				pid, err := peer.Decode(peerStr)
-				if err == nil {
-					servers = append(servers, pid)
-				}
-			}
+// attemptSend sends the message and records the attempt. It returns the
+// resulting delivery status or an empty status if the send failed.
+func (m *Messenger) attemptSend(peerID peer.ID, message *pb.Message, tryDirect bool) models.DeliveryStatus {
+	status, err := m.sendMessage(peerID, message, tryDirect)
+	if err != nil {
+		log.Debugf("Error sending message %s to %s: %s", message.MessageID, peerID.Pretty(), err)
+	}
+
+	// ACKs are not persisted so there is nothing to record.
+	if message.MessageType != pb.Message_ACK {
+		m.recordAttempt(message.MessageID, status, err)
+	}
+	return status
+}
+
+// sendMessage tries to send the message directly to the peer using a
+// network connection. If that fails, or tryDirect is false, it sends the
+// message over the offline messaging system.
+func (m *Messenger) sendMessage(peerID peer.ID, message *pb.Message, tryDirect bool) (models.DeliveryStatus, error) {
+	err := errSkippedDirectSend
+	if tryDirect {
+		ctx, cancel := context.WithTimeout(context.Background(), SendTimeout)
+		defer cancel()
+
+		err = m.ns.SendMessage(ctx, peerID, message)
+		if err == nil {
+			log.Debugf("Message %s direct send successful", message.MessageID)
+			return models.DeliveryStatusSentDirect, nil
 		}
+	}
+	if m.snfClient == nil {
+		return "", err
+	}
 
-		cipherText, err := m.prepEncryptedMessage(peerID, message)
+	log.Debugf("Failed to connect to peer %s. Sending offline message.", peerID.Pretty())
+	// We failed to deliver directly to the peer. Let's send
+	// using the offline system.
+	if offlineErr := m.sendOfflineMessage(peerID, message); offlineErr != nil {
+		return "", fmt.Errorf("direct send failed: %s, offline send failed: %s", err, offlineErr)
+	}
+	return models.DeliveryStatusStoredOnSNF, nil
+}
+
+// sendOfflineMessage encrypts the message and pushes it to the peer's
+// store-and-forward servers.
+func (m *Messenger) sendOfflineMessage(peerID peer.ID, message *pb.Message) error {
+	var record models.StoreAndForwardServers
+	dberr := m.db.View(func(tx database.Tx) error {
+		if err := tx.Read().Where("peer_id=?", peerID.Pretty()).Find(&record).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return nil
+	})
+	if dberr != nil {
+		log.Errorf("Error loading peers snf server addresses %s", dberr)
+		return dberr
+	}
+	servers, err := record.Servers()
+	if err != nil {
+		log.Errorf("Error loading peers snf server addresses %s", err)
+		return err
+	}
+
+	if (len(servers) == 0 || record.LastUpdated.Add(time.Hour*48).Before(time.Now())) && m.getProfileFunc != nil {
+		profile, err := m.getProfileFunc(context.Background(), peerID, true)
 		if err != nil {
-			log.Errorf("Error prepping offline message to %s: %s", peerID.Pretty(), err)
-			return
+			log.Errorf("Error sending offline message: Can't load profile for peer %s", peerID.Pretty())
+			return fmt.Errorf("can't load profile: %s", err)
 		}
+		if len(profile.StoreAndForwardServers) == 0 {
+			log.Errorf("Error sending offline message: No inbox peers for peer %s", peerID.Pretty())
+			return errors.New("peer has no store-and-forward servers")
+		}
+		servers = []peer.ID{}
+		for _, peerStr := range profile.StoreAndForwardServers {
+			pid, err := peer.Decode(peerStr)
+			if err == nil {
+				servers = append(servers, pid)
+			}
+		}
+	}
 
-		successes := uint32(0)
-		var wg sync.WaitGroup
-		wg.Add(len(servers))
-		for _, server := range servers {
-			go func(svr peer.ID) {
-				defer wg.Done()
-				err := m.snfClient.SendMessage(context.Background(), peerID, svr, nil, cipherText, []byte(message.MessageType.String()))
-				if err != nil {
-					log.Warningf("Error pushing offline message %s to server %s: %s", message.MessageID, svr.Pretty(), err)
-					return
-				}
-				atomic.AddUint32(&successes, 1)
-			}(server)
+	cipherText, err := m.prepEncryptedMessage(peerID, message)
+	if err != nil {
+		log.Errorf("Error prepping offline message to %s: %s", peerID.Pretty(), err)
+		return err
+	}
+
+	successes := uint32(0)
+	var wg sync.WaitGroup
+	wg.Add(len(servers))
+	for _, server := range servers {
+		go func(svr peer.ID) {
+			defer wg.Done()
+			err := m.snfClient.SendMessage(context.Background(), peerID, svr, nil, cipherText, []byte(message.MessageType.String()))
+			if err != nil {
+				log.Warningf("Error pushing offline message %s to server %s: %s", message.MessageID, svr.Pretty(), err)
+				return
+			}
+			atomic.AddUint32(&successes, 1)
+		}(server)
+	}
+	wg.Wait()
+	log.Debugf("Message %s sent to %d of %d servers", message.MessageID, successes, len(servers))
+	if len(servers) == 0 {
+		return errors.New("peer has no store-and-forward servers")
+	}
+	if successes == 0 {
+		return errors.New("no store-and-forward servers accepted the message")
+	}
+	return nil
+}
+
+// recordAttempt increments the attempt count of the outgoing message and
+// saves the error from the attempt, if any. If the attempt changed the
+// delivery status an event is emitted.
+func (m *Messenger) recordAttempt(messageID string, status models.DeliveryStatus, sendErr error) {
+	var (
+		om      models.OutgoingMessage
+		changed bool
+	)
+	err := m.db.Update(func(tx database.Tx) error {
+		if err := tx.Read().Where("id = ?", messageID).First(&om).Error; err != nil {
+			return err
+		}
+		om.Attempts++
+		om.LastError = ""
+		if sendErr != nil {
+			om.LastError = sendErr.Error()
 		}
-		wg.Wait()
-		log.Debugf("Message %s sent to %d of %d servers", message.MessageID, successes, len(servers))
+		if status != "" && status != om.Status {
+			om.Status = status
+			changed = true
+		}
+		return tx.Save(&om)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The message was ACKed or cancelled while we were sending.
+		return
+	} else if err != nil {
+		log.Errorf("Error recording attempt for outgoing message %s: %s", messageID, err)
+		return
+	}
+	if changed {
+		m.emitDeliveryUpdate(&om, status)
+	}
+}
+
+// emitDeliveryUpdate emits an event with the new delivery status
+// of the message.
+func (m *Messenger) emitDeliveryUpdate(message *models.OutgoingMessage, status models.DeliveryStatus) {
+	if m.eventBus == nil {
 		return
 	}
-	log.Debugf("Message %s direct send successful", message.MessageID)
+	m.eventBus.Emit(&events.MessageDeliveryUpdate{
+		MessageID:   message.ID,
+		Recipient:   message.Recipient,
+		MessageType: message.MessageType,
+		Status:      string(status),
+	})
 }
 
-// retryAllMessages loads all un-ACKed messages from the database and
-// tries to send them again using an exponential backoff.
+// retryAllMessages loads the un-ACKed messages which are due to be retried
+// and tries to send them again. Each message is then scheduled for its next
+// retry using an exponential backoff.
 func (m *Messenger) retryAllMessages() {
 	// Increment the waitgroup to make sure we don't shutdown before
 	// this process finishes.
 	m.wg.Add(1)
 	defer m.wg.Done()
 
-	m.mtx.RLock()
 	var messages []models.OutgoingMessage
 	err := m.db.View(func(tx database.Tx) error {
-		return tx.Read().Find(&messages).Error
+		return tx.Read().Where("failed = ? AND next_attempt <= ?", false, time.Now()).Order("timestamp").Limit(retryBatchSize).Find(&messages).Error
 	})
 	if err != nil {
 		log.Errorf("Error loading outgoing messages from the database: %s", err)
-		m.mtx.RUnlock()
 		return
 	}
-	m.mtx.RUnlock()
 
-	for _, message := range messages {
+	var (
+		peers  []peer.ID
+		byPeer = make(map[peer.ID][]*pb.Message)
+		due    = make([]*models.OutgoingMessage, 0, len(messages))
+	)
+	for i := range messages {
+		message := &messages[i]
 		pmes := new(pb.Message)
 		if err := proto.Unmarshal(message.SerializedMessage, pmes); err != nil {
 			log.Error("Error unmarshalling outgoing message: %s", err)
@@ -343,17 +564,77 @@ func (m *Messenger) retryAllMessages() {
 			log.Error("Error parsing peer ID in outgoing message: %s", err)
 			continue
 		}
-		if shouldWeRetry(message.Timestamp, message.LastAttempt) {
-			m.wg.Add(1)
-			go m.trySendMessage(pid, pmes, nil)
+		if ttl, ok := m.messageTTL[pmes.MessageType]; ok && ttl > 0 && time.Since(message.Timestamp) > ttl {
+			m.expireMessage(message, pmes)
+			continue
+		}
+		if _, ok := byPeer[pid]; !ok {
+			peers = append(peers, pid)
+		}
+		byPeer[pid] = append(byPeer[pid], pmes)
+		due = append(due, message)
+	}
 
-			err = m.db.Update(func(tx database.Tx) error {
-				return tx.Update("last_attempt", time.Now(), nil, &message)
-			})
-			if err != nil {
-				log.Error("Error updating last attempt for outgoing message: %s", err)
+	err = m.db.Update(func(tx database.Tx) error {
+		for _, message := range due {
+			where := map[string]interface{}{"id = ?": message.ID}
+			if err := tx.Update("last_attempt", time.Now(), where, &models.OutgoingMessage{}); err != nil {
+				return err
 			}
+			if err := tx.Update("next_attempt", time.Now().Add(nextRetryDelay(message.Attempts)), where, &models.OutgoingMessage{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Error scheduling next attempt for outgoing messages: %s", err)
+		return
+	}
+
+	for _, pid := range peers {
+		m.wg.Add(1)
+		go m.retryPeerMessages(pid, byPeer[pid])
+	}
+}
+
+// expireMessage handles an outgoing message that has passed its TTL. Order
+// related messages are marked as failed, and an event is emitted, so that
+// the user can decide what to do. All other messages are deleted.
+func (m *Messenger) expireMessage(message *models.OutgoingMessage, pmes *pb.Message) {
+	if pmes.MessageType != pb.Message_ORDER && pmes.MessageType != pb.Message_DISPUTE {
+		log.Infof("Outgoing %s message %s to %s expired. Deleting.", message.MessageType, message.ID, message.Recipient)
+		err := m.db.Update(func(tx database.Tx) error {
+			return tx.Delete("id", message.ID, nil, &models.OutgoingMessage{})
+		})
+		if err != nil {
+			log.Errorf("Error deleting expired outgoing message %s: %s", message.ID, err)
 		}
+		return
+	}
+
+	log.Warningf("Outgoing %s message %s to %s expired. Marking failed.", message.MessageType, message.ID, message.Recipient)
+	err := m.db.Update(func(tx database.Tx) error {
+		return tx.Update("failed", true, map[string]interface{}{"id = ?": message.ID}, &models.OutgoingMessage{})
+	})
+	if err != nil {
+		log.Errorf("Error marking outgoing message %s failed: %s", message.ID, err)
+		return
+	}
+
+	if m.eventBus != nil {
+		var orderID string
+		orderMsg := new(pb.OrderMessage)
+		if err := ptypes.UnmarshalAny(pmes.Payload, orderMsg); err == nil {
+			orderID = orderMsg.OrderID
+		}
+		m.eventBus.Emit(&events.OutgoingMessageFailed{
+			MessageID:   message.ID,
+			Recipient:   message.Recipient,
+			MessageType: message.MessageType,
+			OrderID:     orderID,
+			Reason:      message.LastError,
+		})
 	}
 }
 
@@ -386,6 +667,10 @@ func (m *Messenger) downloadMessages() {
 			return messages[i].m.Sequence < messages[j].m.Sequence
 		})
 		for _, mwp := range messages {
+			if err := m.ns.admitMessage(mwp.p, mwp.m); err != nil {
+				log.Debugf("Dropping %s message from peer %s: %s", mwp.m.MessageType, mwp.p, err)
+				continue
+			}
 			m.ns.handlerMtx.RLock()
 			handler, ok := m.ns.handlers[mwp.m.MessageType]
 			m.ns.handlerMtx.RUnlock()
@@ -468,41 +753,14 @@ func (m *Messenger) decryptMessage(cipherText []byte) (peer.ID, *pb.Message, err
 	return pid, env.Message, err
 }
 
-// shouldWeRetry calculates an exponential backoff for message retries based
-// on how old the message is and how long since our last attempt.
-func shouldWeRetry(messageTimestamp time.Time, lastTry time.Time) bool {
-	timeSinceMessage := time.Since(messageTimestamp)
-	timeSinceLastTry := time.Since(lastTry)
-
-	switch t := timeSinceMessage; {
-	// Less than 15 minute old message, retry every minute.
-	case t < time.Minute*15 && timeSinceLastTry > time.Minute*1:
-		return true
-	// Less than 1 hour old message, retry every five minutes.
-	case t < time.Hour && timeSinceLastTry > time.Minute*5:
-		return true
-	// Less than 1 day old message, retry every ten minutes.
-	case t < time.Hour*24 && timeSinceLastTry > time.Minute*10:
-		return true
-	// Less than 1 week old message, retry every fifteen minutes.
-	case t < time.Hour*24*7 && timeSinceLastTry > time.Minute*15:
-		return true
-	// Less than 1 month old message, retry every thirty minutes.
-	case t < time.Hour*24*30 && timeSinceLastTry > time.Minute*30:
-		return true
-	// Less than 3 month old message, retry every hour.
-	case t < time.Hour*24*30*3 && timeSinceLastTry > time.Hour:
-		return true
-	// Less than 6 month old message, retry every three hours.
-	case t < time.Hour*24*30*6 && timeSinceLastTry > time.Hour*3:
-		return true
-	// Less than 1 year old message, retry every day.
-	case t < time.Hour*24*30*12 && timeSinceLastTry > time.Hour*24:
-		return true
-	// Older than 1 year old message, retry every week.
-	case t >= time.Hour*24*30*12 && timeSinceLastTry > time.Hour*24*7:
-		return true
-	}
-
-	return false
+// nextRetryDelay returns how long to wait before retrying a message that
+// has been attempted the given number of times. The delay doubles with each
+// attempt up to MaxRetryInterval and is randomized so that retries for many
+// messages don't all fire at once.
+func nextRetryDelay(attempts int) time.Duration {
+	delay := MaxRetryInterval
+	if attempts < 16 && RetryInterval<<uint(attempts) < MaxRetryInterval {
+		delay = RetryInterval << uint(attempts)
+	}
+	return delay/2 + time.Duration(mrand.Int63n(int64(delay/2)))
 }
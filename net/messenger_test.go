@@ -7,6 +7,7 @@ import (
 	"fmt"
 	storeandforward "github.com/cpacia/go-store-and-forward"
 	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/net/pb"
 	"github.com/cpacia/openbazaar3.0/repo"
@@ -18,6 +19,7 @@ import (
 	ma "github.com/multiformats/go-multiaddr"
 	"gorm.io/gorm"
 	"net"
+	"strings"
 	"testing"
 	"time"
 )
@@ -237,6 +239,381 @@ func TestMessenger_retryAllMessages(t *testing.T) {
 	}
 }
 
+func TestMessenger_Outbox(t *testing.T) {
+	mocknet := mocknet.New(context.Background())
+
+	priv1, addr1, err := newPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h1, err := mocknet.AddPeer(priv1, addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	priv2, addr2, err := newPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h2, err := mocknet.AddPeer(priv2, addr2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mocknet.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	service1 := NewNetworkService(h1, NewBanManager(nil), true)
+	service2 := NewNetworkService(h2, NewBanManager(nil), true)
+
+	db1, err := repo.MockDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db1.Close()
+
+	bus := events.NewBus()
+	sub, err := bus.Subscribe(&events.OutgoingMessageFailed{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	messenger, err := NewMessenger(&MessengerConfig{
+		Service:  service1,
+		DB:       db1,
+		Privkey:  priv1,
+		Testnet:  true,
+		Context:  context.Background(),
+		EventBus: bus,
+		MessageTTL: map[pb.Message_MessageType]time.Duration{
+			pb.Message_CHAT:  time.Hour,
+			pb.Message_ORDER: time.Hour,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderPayload, err := ptypes.MarshalAny(&pb.OrderMessage{OrderID: "order1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db1.Update(func(tx database.Tx) error {
+		for _, m := range []*pb.Message{
+			{MessageID: "chat", MessageType: pb.Message_CHAT},
+			{MessageID: "order", MessageType: pb.Message_ORDER, Payload: orderPayload},
+			{MessageID: "ping", MessageType: pb.Message_PING},
+		} {
+			ser, err := proto.Marshal(m)
+			if err != nil {
+				return err
+			}
+			err = tx.Save(&models.OutgoingMessage{
+				ID:                m.MessageID,
+				Recipient:         service2.host.ID().Pretty(),
+				SerializedMessage: ser,
+				MessageType:       m.MessageType.String(),
+				Timestamp:         time.Now().Add(-time.Hour * 2),
+				LastAttempt:       time.Now().Add(-time.Hour),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan struct{})
+	service2.RegisterHandler(pb.Message_ORDER, func(p peer.ID, msg *pb.Message) error {
+		ch <- struct{}{}
+		return nil
+	})
+	service2.RegisterHandler(pb.Message_PING, func(p peer.ID, msg *pb.Message) error {
+		return nil
+	})
+
+	messenger.retryAllMessages()
+
+	select {
+	case e := <-sub.Out():
+		failed, ok := e.(*events.OutgoingMessageFailed)
+		if !ok || failed.MessageID != "order" || failed.OrderID != "order1" {
+			t.Errorf("Incorrect event returned: %v", e)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting on channel")
+	}
+
+	loadMessages := func() map[string]models.OutgoingMessage {
+		var messages []models.OutgoingMessage
+		err := db1.View(func(tx database.Tx) error {
+			return tx.Read().Find(&messages).Error
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret := make(map[string]models.OutgoingMessage)
+		for _, m := range messages {
+			ret[m.ID] = m
+		}
+		return ret
+	}
+
+	messages := loadMessages()
+	if _, ok := messages["chat"]; ok {
+		t.Error("Expired chat message was not deleted")
+	}
+	if !messages["order"].Failed {
+		t.Error("Expired order message was not marked failed")
+	}
+	if messages["ping"].Failed {
+		t.Error("Message without TTL marked failed")
+	}
+
+	if err := messenger.RetryMessage("order"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ch:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting for order message")
+	}
+	messenger.wg.Wait()
+
+	messages = loadMessages()
+	if messages["order"].Failed {
+		t.Error("Retried message still marked failed")
+	}
+	if messages["order"].Attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", messages["order"].Attempts)
+	}
+
+	if err := messenger.CancelMessage("ping"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loadMessages()["ping"]; ok {
+		t.Error("Cancelled message was not deleted")
+	}
+
+	if err := messenger.CancelMessage("ping"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected record not found cancelling missing message, got %v", err)
+	}
+	if err := messenger.RetryMessage("ping"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected record not found retrying missing message, got %v", err)
+	}
+}
+
+func TestMessenger_DeliveryStatus(t *testing.T) {
+	mocknet := mocknet.New(context.Background())
+
+	priv1, addr1, err := newPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h1, err := mocknet.AddPeer(priv1, addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	priv2, addr2, err := newPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h2, err := mocknet.AddPeer(priv2, addr2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The third peer is never linked so it's unreachable.
+	priv3, addr3, err := newPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h3, err := mocknet.AddPeer(priv3, addr3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := mocknet.LinkPeers(h1.ID(), h2.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	service1 := NewNetworkService(h1, NewBanManager(nil), true)
+	service2 := NewNetworkService(h2, NewBanManager(nil), true)
+
+	db1, err := repo.MockDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db1.Close()
+
+	bus := events.NewBus()
+	sub, err := bus.Subscribe(&events.MessageDeliveryUpdate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	messenger, err := NewMessenger(&MessengerConfig{
+		Service:  service1,
+		DB:       db1,
+		Privkey:  priv1,
+		Testnet:  true,
+		Context:  context.Background(),
+		EventBus: bus,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service2.RegisterHandler(pb.Message_PING, func(p peer.ID, msg *pb.Message) error {
+		return nil
+	})
+
+	expectStatus := func(messageID string, status models.DeliveryStatus) {
+		select {
+		case e := <-sub.Out():
+			update, ok := e.(*events.MessageDeliveryUpdate)
+			if !ok || update.MessageID != messageID || update.Status != string(status) {
+				t.Errorf("Expected %s status for %s, got %v", status, messageID, e)
+			}
+		case <-time.After(time.Second * 10):
+			t.Fatal("Timeout waiting on channel")
+		}
+	}
+
+	done := make(chan struct{})
+	err = db1.Update(func(tx database.Tx) error {
+		return messenger.ReliablySendMessage(tx, h2.ID(), &pb.Message{MessageID: "abc", MessageType: pb.Message_PING}, done)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectStatus("abc", models.DeliveryStatusQueued)
+	expectStatus("abc", models.DeliveryStatusSentDirect)
+	<-done
+
+	var om models.OutgoingMessage
+	err = db1.View(func(tx database.Tx) error {
+		return tx.Read().Where("id = ?", "abc").First(&om).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if om.Status != models.DeliveryStatusSentDirect {
+		t.Errorf("Expected status %s, got %s", models.DeliveryStatusSentDirect, om.Status)
+	}
+	if om.Attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", om.Attempts)
+	}
+	if !om.NextAttempt.After(time.Now()) {
+		t.Error("Next attempt not scheduled in the future")
+	}
+
+	err = db1.Update(func(tx database.Tx) error {
+		return messenger.ProcessACK(tx, &pb.AckMessage{AckedMessageID: "abc"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStatus("abc", models.DeliveryStatusAcked)
+
+	// Messages which are not yet due should not be retried. Of the due
+	// messages to the unreachable peer, only the first should be dialed.
+	err = db1.Update(func(tx database.Tx) error {
+		for i, id := range []string{"notdue", "due1", "due2"} {
+			ser, err := proto.Marshal(&pb.Message{MessageID: id, MessageType: pb.Message_PING})
+			if err != nil {
+				return err
+			}
+			nextAttempt := time.Now().Add(-time.Minute)
+			if id == "notdue" {
+				nextAttempt = time.Now().Add(time.Hour)
+			}
+			err = tx.Save(&models.OutgoingMessage{
+				ID:                id,
+				Recipient:         h3.ID().Pretty(),
+				SerializedMessage: ser,
+				MessageType:       pb.Message_PING.String(),
+				Timestamp:         time.Now().Add(time.Duration(i) * time.Second),
+				NextAttempt:       nextAttempt,
+				Status:            models.DeliveryStatusQueued,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messenger.retryAllMessages()
+	messenger.wg.Wait()
+
+	var messages []models.OutgoingMessage
+	err = db1.View(func(tx database.Tx) error {
+		return tx.Read().Find(&messages).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range messages {
+		switch m.ID {
+		case "notdue":
+			if m.Attempts != 0 {
+				t.Error("Message retried before it was due")
+			}
+		case "due1":
+			if m.Attempts != 1 || m.LastError == "" || strings.Contains(m.LastError, errSkippedDirectSend.Error()) {
+				t.Errorf("Unexpected state for first due message: %d attempts, error %s", m.Attempts, m.LastError)
+			}
+			if !m.NextAttempt.After(time.Now()) {
+				t.Error("Next attempt not scheduled in the future")
+			}
+		case "due2":
+			if m.Attempts != 1 || !strings.Contains(m.LastError, errSkippedDirectSend.Error()) {
+				t.Errorf("Expected direct send to be skipped for second due message: %d attempts, error %s", m.Attempts, m.LastError)
+			}
+		}
+	}
+}
+
+func TestNextRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		max      time.Duration
+	}{
+		{0, RetryInterval},
+		{1, RetryInterval * 2},
+		{5, RetryInterval * 32},
+		{20, MaxRetryInterval},
+		{1000, MaxRetryInterval},
+	}
+	for _, test := range tests {
+		for i := 0; i < 10; i++ {
+			delay := nextRetryDelay(test.attempts)
+			if delay < test.max/2 || delay >= test.max {
+				t.Errorf("Delay %s for %d attempts not in range [%s, %s)", delay, test.attempts, test.max/2, test.max)
+			}
+		}
+	}
+}
+
 func TestMessenger_encryptDecrypt(t *testing.T) {
 	mocknet := mocknet.New(context.Background())
 
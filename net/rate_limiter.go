@@ -0,0 +1,245 @@
+package net
+
+import (
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/net/pb"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// ScoreRateLimited is added to a peer's score each time a message
+	// is dropped for exceeding the rate limit.
+	ScoreRateLimited = 1
+
+	// ScoreDuplicateMessage is added to a peer's score when it resends a
+	// message we've just received from it.
+	ScoreDuplicateMessage = 5
+
+	// ScoreMalformedMessage is added to a peer's score when it sends a
+	// message that can't be decoded.
+	ScoreMalformedMessage = 10
+
+	// ScoreInvalidSignature is added to a peer's score when it sends a
+	// message with a signature that fails validation.
+	ScoreInvalidSignature = 25
+
+	// scoreHalfLife is how long it takes for a peer's score to decay
+	// to half its value.
+	scoreHalfLife = time.Minute * 5
+
+	// duplicateWindow is how long a message ID is remembered for duplicate
+	// detection. It's shorter than the messenger's retry interval so that
+	// legitimate retries are not counted.
+	duplicateWindow = time.Second * 30
+
+	// peerIdleTimeout is how long a peer's state is kept after the last
+	// message if it has no score and no ban.
+	peerIdleTimeout = time.Minute * 10
+)
+
+// RateLimitConfig holds the options for the RateLimiter.
+type RateLimitConfig struct {
+	// MessagesPerSecond is the rate at which each peer may send messages
+	// of a given type.
+	MessagesPerSecond float64
+
+	// Burst is the number of messages of a given type a peer may send
+	// at once before being limited.
+	Burst int
+
+	// BanThreshold is the score at which a peer is temporarily banned.
+	BanThreshold float64
+
+	// BanDuration is how long a temporary ban lasts.
+	BanDuration time.Duration
+}
+
+// DefaultRateLimitConfig returns the default rate limiter options.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		MessagesPerSecond: 5,
+		Burst:             50,
+		BanThreshold:      100,
+		BanDuration:       time.Hour,
+	}
+}
+
+// RateLimiter limits the number of messages each peer may send using a token
+// bucket per peer and message type. Peers also accumulate a misbehavior score
+// when they exceed the limits or send bad data. When the score crosses the
+// threshold the peer is temporarily banned in the BanManager.
+type RateLimiter struct {
+	cfg   RateLimitConfig
+	bm    *BanManager
+	peers map[peer.ID]*peerLimits
+
+	allowed uint64
+	dropped uint64
+	bans    uint64
+
+	lastPrune time.Time
+	mtx       sync.Mutex
+}
+
+type peerLimits struct {
+	buckets      map[pb.Message_MessageType]*tokenBucket
+	recent       map[string]time.Time
+	score        float64
+	scoreUpdated time.Time
+	lastSeen     time.Time
+	allowed      uint64
+	dropped      uint64
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter which bans peers using the
+// provided BanManager.
+func NewRateLimiter(cfg RateLimitConfig, bm *BanManager) *RateLimiter {
+	return &RateLimiter{
+		cfg:       cfg,
+		bm:        bm,
+		peers:     make(map[peer.ID]*peerLimits),
+		lastPrune: time.Now(),
+		mtx:       sync.Mutex{},
+	}
+}
+
+// Allow returns whether the message from the peer should be processed. It
+// takes a token from the peer's bucket for the message type and records
+// the message ID for duplicate detection.
+func (rl *RateLimiter) Allow(p peer.ID, message *pb.Message) bool {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	now := time.Now()
+	rl.maybePrune(now)
+
+	pl := rl.peerLimits(p)
+	pl.lastSeen = now
+
+	if received, ok := pl.recent[message.MessageID]; ok && now.Sub(received) < duplicateWindow {
+		rl.addScore(p, pl, ScoreDuplicateMessage, now)
+	}
+	pl.recent[message.MessageID] = now
+
+	bucket, ok := pl.buckets[message.MessageType]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(rl.cfg.Burst), updated: now}
+		pl.buckets[message.MessageType] = bucket
+	}
+	bucket.tokens = math.Min(float64(rl.cfg.Burst), bucket.tokens+now.Sub(bucket.updated).Seconds()*rl.cfg.MessagesPerSecond)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		rl.dropped++
+		pl.dropped++
+		rl.addScore(p, pl, ScoreRateLimited, now)
+		return false
+	}
+	bucket.tokens--
+	rl.allowed++
+	pl.allowed++
+	return true
+}
+
+// Misbehaving adds to the peer's misbehavior score. If the score crosses the
+// ban threshold the peer is temporarily banned.
+func (rl *RateLimiter) Misbehaving(p peer.ID, score float64, reason string) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	log.Debugf("Peer %s misbehaving (+%.0f): %s", p, score, reason)
+	rl.addScore(p, rl.peerLimits(p), score, time.Now())
+}
+
+// Metrics returns the current counters for the rate limiter.
+func (rl *RateLimiter) Metrics() models.RateLimitMetrics {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	now := time.Now()
+	metrics := models.RateLimitMetrics{
+		MessagesAllowed: rl.allowed,
+		MessagesDropped: rl.dropped,
+		TemporaryBans:   rl.bans,
+		Peers:           make([]models.PeerRateLimitInfo, 0, len(rl.peers)),
+	}
+	for p, pl := range rl.peers {
+		info := models.PeerRateLimitInfo{
+			PeerID:          p.Pretty(),
+			Score:           pl.decayedScore(now),
+			MessagesAllowed: pl.allowed,
+			MessagesDropped: pl.dropped,
+		}
+		if expiry, banned := rl.bm.TemporaryBanExpiry(p); banned {
+			info.BannedUntil = &expiry
+		}
+		metrics.Peers = append(metrics.Peers, info)
+	}
+	sort.Slice(metrics.Peers, func(i, j int) bool {
+		return metrics.Peers[i].Score > metrics.Peers[j].Score
+	})
+	return metrics
+}
+
+func (rl *RateLimiter) peerLimits(p peer.ID) *peerLimits {
+	pl, ok := rl.peers[p]
+	if !ok {
+		pl = &peerLimits{
+			buckets: make(map[pb.Message_MessageType]*tokenBucket),
+			recent:  make(map[string]time.Time),
+		}
+		rl.peers[p] = pl
+	}
+	return pl
+}
+
+// addScore must be called with the lock held.
+func (rl *RateLimiter) addScore(p peer.ID, pl *peerLimits, score float64, now time.Time) {
+	pl.score = pl.decayedScore(now) + score
+	pl.scoreUpdated = now
+
+	if pl.score >= rl.cfg.BanThreshold && !rl.bm.IsTemporarilyBanned(p) {
+		expiry := rl.bm.AddTemporaryBan(p, rl.cfg.BanDuration)
+		rl.bans++
+		pl.score = 0
+		log.Warningf("Temporarily banning peer %s for misbehavior until %s", p, expiry.Format(time.RFC3339))
+	}
+}
+
+// maybePrune removes the state for idle peers and expired message IDs. It
+// runs at most once per duplicate window and must be called with the lock held.
+func (rl *RateLimiter) maybePrune(now time.Time) {
+	if now.Sub(rl.lastPrune) < duplicateWindow {
+		return
+	}
+	rl.lastPrune = now
+	for p, pl := range rl.peers {
+		for id, received := range pl.recent {
+			if now.Sub(received) >= duplicateWindow {
+				delete(pl.recent, id)
+			}
+		}
+		if now.Sub(pl.lastSeen) > peerIdleTimeout && pl.decayedScore(now) < 1 && !rl.bm.IsTemporarilyBanned(p) {
+			delete(rl.peers, p)
+		}
+	}
+}
+
+// decayedScore returns the score after applying the decay since the
+// last update.
+func (pl *peerLimits) decayedScore(now time.Time) float64 {
+	if pl.score == 0 {
+		return 0
+	}
+	halfLives := now.Sub(pl.scoreUpdated).Seconds() / scoreHalfLife.Seconds()
+	return pl.score * math.Pow(0.5, halfLives)
+}
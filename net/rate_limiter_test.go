@@ -0,0 +1,88 @@
+package net
+
+import (
+	"github.com/cpacia/openbazaar3.0/net/pb"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	p := bannedPeers()[0]
+	bm := NewBanManager(nil)
+	rl := NewRateLimiter(RateLimitConfig{
+		MessagesPerSecond: 0.001,
+		Burst:             5,
+		BanThreshold:      100,
+		BanDuration:       time.Hour,
+	}, bm)
+
+	for i := 0; i < 5; i++ {
+		msg := &pb.Message{MessageID: strconv.Itoa(i), MessageType: pb.Message_CHAT}
+		if !rl.Allow(p, msg) {
+			t.Fatalf("Message %d was rate limited", i)
+		}
+	}
+
+	if rl.Allow(p, &pb.Message{MessageID: "5", MessageType: pb.Message_CHAT}) {
+		t.Error("Message over the burst was allowed")
+	}
+
+	// Other message types have their own bucket.
+	if !rl.Allow(p, &pb.Message{MessageID: "6", MessageType: pb.Message_FOLLOW}) {
+		t.Error("Message of another type was rate limited")
+	}
+
+	metrics := rl.Metrics()
+	if metrics.MessagesAllowed != 6 {
+		t.Errorf("Expected 6 messages allowed, got %d", metrics.MessagesAllowed)
+	}
+	if metrics.MessagesDropped != 1 {
+		t.Errorf("Expected 1 message dropped, got %d", metrics.MessagesDropped)
+	}
+	if len(metrics.Peers) != 1 {
+		t.Fatalf("Expected 1 peer, got %d", len(metrics.Peers))
+	}
+	if metrics.Peers[0].Score < ScoreRateLimited-0.01 {
+		t.Errorf("Expected score of at least %d, got %f", ScoreRateLimited, metrics.Peers[0].Score)
+	}
+}
+
+func TestRateLimiter_Misbehaving(t *testing.T) {
+	peers := bannedPeers()
+	bm := NewBanManager(nil)
+	rl := NewRateLimiter(RateLimitConfig{
+		MessagesPerSecond: 100,
+		Burst:             100,
+		BanThreshold:      40,
+		BanDuration:       time.Hour,
+	}, bm)
+
+	rl.Misbehaving(peers[0], ScoreInvalidSignature, "test")
+	if bm.IsTemporarilyBanned(peers[0]) {
+		t.Error("Peer banned before reaching the threshold")
+	}
+	rl.Misbehaving(peers[0], ScoreInvalidSignature, "test")
+	if !bm.IsTemporarilyBanned(peers[0]) {
+		t.Error("Peer not banned after reaching the threshold")
+	}
+
+	// Duplicate messages add to the score.
+	msg := &pb.Message{MessageID: "abc", MessageType: pb.Message_CHAT}
+	for i := 0; i < 10; i++ {
+		rl.Allow(peers[1], msg)
+	}
+	if !bm.IsTemporarilyBanned(peers[1]) {
+		t.Error("Peer not banned for duplicate flood")
+	}
+
+	metrics := rl.Metrics()
+	if metrics.TemporaryBans != 2 {
+		t.Errorf("Expected 2 temporary bans, got %d", metrics.TemporaryBans)
+	}
+	for _, info := range metrics.Peers {
+		if info.BannedUntil == nil {
+			t.Errorf("Expected ban expiry for peer %s", info.PeerID)
+		}
+	}
+}
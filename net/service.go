@@ -2,6 +2,8 @@ package net
 
 import (
 	"context"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/net/pb"
 	"github.com/golang/protobuf/proto"
 	ctxio "github.com/jbenet/go-context/io"
@@ -17,6 +19,11 @@ import (
 
 var log = logging.MustGetLogger("NET")
 
+var (
+	errPeerBlocked = errors.New("peer is banned")
+	errRateLimited = errors.New("rate limit exceeded")
+)
+
 type NetworkService struct {
 	ctx       context.Context
 	ctxCancel context.CancelFunc
@@ -31,6 +38,7 @@ type NetworkService struct {
 	handlerMtx sync.RWMutex
 
 	banManager *BanManager
+	limiter    *RateLimiter
 
 	protocolID protocol.ID
 }
@@ -50,6 +58,7 @@ func NewNetworkService(host host.Host, banManager *BanManager, useTestnet bool)
 		handlers:       make(map[pb.Message_MessageType]func(peerID peer.ID, message *pb.Message) error),
 		handlerMtx:     sync.RWMutex{},
 		banManager:     banManager,
+		limiter:        NewRateLimiter(DefaultRateLimitConfig(), banManager),
 		protocolID:     protocol.ID(protocolID),
 	}
 
@@ -70,12 +79,59 @@ func (ns *NetworkService) Close() {
 	ns.ctxCancel()
 }
 
+// SetRateLimiter replaces the rate limiter used for incoming messages.
+func (ns *NetworkService) SetRateLimiter(limiter *RateLimiter) {
+	ns.limiter = limiter
+}
+
+// Misbehaving adds to the misbehavior score of the peer. Peers whose score
+// crosses the threshold are temporarily banned.
+func (ns *NetworkService) Misbehaving(p peer.ID, score float64, reason string) {
+	ns.limiter.Misbehaving(p, score, reason)
+}
+
+// RateLimitMetrics returns the counters from the rate limiter.
+func (ns *NetworkService) RateLimitMetrics() models.RateLimitMetrics {
+	return ns.limiter.Metrics()
+}
+
 func (ns *NetworkService) RegisterHandler(messageType pb.Message_MessageType, handler func(peerID peer.ID, message *pb.Message) error) {
 	ns.handlerMtx.Lock()
 	defer ns.handlerMtx.Unlock()
 	ns.handlers[messageType] = handler
 }
 
+// isBlocked returns whether a message of the given type from the peer should
+// be dropped. Peers serving a temporary ban for misbehavior are dropped
+// entirely. Order messages and ACKs from blocked peers are still handled so
+// that new orders can be rejected and existing orders can be completed.
+func (ns *NetworkService) isBlocked(p peer.ID, messageType pb.Message_MessageType) bool {
+	if ns.banManager == nil {
+		return false
+	}
+	if ns.banManager.IsTemporarilyBanned(p) {
+		return true
+	}
+	if !ns.banManager.IsBanned(p) {
+		return false
+	}
+	return messageType != pb.Message_ORDER && messageType != pb.Message_ACK
+}
+
+// admitMessage returns an error if an incoming message should be dropped
+// because the peer is banned or has exceeded its rate limit. It must be
+// called once for every message before it is handed to a handler, whether
+// it arrived directly or through a store-and-forward server.
+func (ns *NetworkService) admitMessage(p peer.ID, message *pb.Message) error {
+	if ns.isBlocked(p, message.MessageType) {
+		return errPeerBlocked
+	}
+	if !ns.limiter.Allow(p, message) {
+		return errRateLimited
+	}
+	return nil
+}
+
 // HandleNewStream receives new incoming streams from other peers.
 // A stream is not a connection. You may already have an open connection
 // with this peer over which you have been using other protocols. A stream
@@ -95,7 +151,7 @@ func (ns *NetworkService) handleNewMessage(s inet.Stream) {
 	reader := msgio.NewVarintReaderSize(contextReader, inet.MessageSizeMax)
 	remotePeer := s.Conn().RemotePeer()
 
-	if ns.banManager.IsBanned(remotePeer) {
+	if ns.banManager.IsTemporarilyBanned(remotePeer) {
 		log.Debugf("Received new stream request from banned peer %s. Closing.", remotePeer)
 		return
 	}
@@ -120,13 +176,16 @@ func (ns *NetworkService) handleNewMessage(s inet.Stream) {
 		if err := proto.Unmarshal(msgBytes, pmes); err != nil {
 			reader.ReleaseMsg(msgBytes)
 			s.Reset()
+			ns.Misbehaving(remotePeer, ScoreMalformedMessage, "malformed message")
 			return
 		}
 		reader.ReleaseMsg(msgBytes)
-		// Check again
-		if ns.banManager.IsBanned(remotePeer) {
-			log.Debugf("Received message from banned peer %s. Closing.", remotePeer)
+		if err := ns.admitMessage(remotePeer, pmes); errors.Is(err, errPeerBlocked) {
+			log.Debugf("Received %s message from banned peer %s. Closing.", pmes.MessageType, remotePeer)
 			return
+		} else if err != nil {
+			log.Debugf("Rate limit exceeded for %s messages from peer %s. Dropping.", pmes.MessageType, remotePeer)
+			continue
 		}
 
 		ns.handlerMtx.RLock()
@@ -2,10 +2,12 @@ package net
 
 import (
 	"context"
+	"errors"
 	"github.com/cpacia/openbazaar3.0/net/pb"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"testing"
+	"time"
 )
 
 func TestNetworkService(t *testing.T) {
@@ -34,3 +36,30 @@ func TestNetworkService(t *testing.T) {
 
 	<-ch
 }
+
+func TestNetworkService_admitMessage(t *testing.T) {
+	mocknet, err := mocknet.FullMeshLinked(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bm := NewBanManager(nil)
+	service := NewNetworkService(mocknet.Hosts()[0], bm, true)
+	service.SetRateLimiter(NewRateLimiter(RateLimitConfig{
+		MessagesPerSecond: 0.001,
+		Burst:             1,
+		BanThreshold:      1,
+		BanDuration:       time.Hour,
+	}, bm))
+
+	p := mocknet.Hosts()[1].ID()
+	if err := service.admitMessage(p, &pb.Message{MessageID: "1", MessageType: pb.Message_CHAT}); err != nil {
+		t.Errorf("Expected message to be admitted, got %v", err)
+	}
+	if err := service.admitMessage(p, &pb.Message{MessageID: "2", MessageType: pb.Message_CHAT}); !errors.Is(err, errRateLimited) {
+		t.Errorf("Expected rate limited error, got %v", err)
+	}
+	if err := service.admitMessage(p, &pb.Message{MessageID: "3", MessageType: pb.Message_FOLLOW}); !errors.Is(err, errPeerBlocked) {
+		t.Errorf("Expected peer blocked error, got %v", err)
+	}
+}
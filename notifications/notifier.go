@@ -21,6 +21,18 @@ type channelMessageWrapper struct {
 	ChannelMessage interface{} `json:"channelMessage"`
 }
 
+type channelPolicyWrapper struct {
+	ChannelPolicy interface{} `json:"channelPolicy"`
+}
+
+type channelDeletionWrapper struct {
+	ChannelDeletion interface{} `json:"channelDeletion"`
+}
+
+type privateChannelWrapper struct {
+	PrivateChannel interface{} `json:"privateChannel"`
+}
+
 type chatMessageWrapper struct {
 	ChatMessage interface{} `json:"chatMessage"`
 }
@@ -33,6 +45,18 @@ type messageTypingWrapper struct {
 	MessageTyping interface{} `json:"messageTyping"`
 }
 
+type messageDeliveryWrapper struct {
+	MessageDelivery interface{} `json:"messageDelivery"`
+}
+
+type peerBlockedWrapper struct {
+	PeerBlocked interface{} `json:"peerBlocked"`
+}
+
+type peerUnblockedWrapper struct {
+	PeerUnblocked interface{} `json:"peerUnblocked"`
+}
+
 type walletWrapper struct {
 	Wallet interface{} `json:"wallet"`
 }
@@ -66,6 +90,8 @@ func NewNotifier(bus events.Bus, db database.Database, notifyFunc func(interface
 func (n *Notifier) Start() {
 	notifications := []interface{}{
 		&events.NewOrder{},
+		&events.BlockedOrderRejected{},
+		&events.OutgoingMessageFailed{},
 		&events.OrderFunded{},
 		&events.OrderPaymentReceived{},
 		&events.OrderConfirmation{},
@@ -94,6 +120,10 @@ func (n *Notifier) Start() {
 		&events.ChatRead{},
 		&events.ChatTyping{},
 		&events.ChannelMessage{},
+		&events.ChannelPolicyUpdated{},
+		&events.ChannelMessagesDeleted{},
+		&events.PrivateChannelUpdated{},
+		&events.MessageDeliveryUpdate{},
 	}
 
 	chatSub, err := n.bus.Subscribe(chats)
@@ -112,6 +142,16 @@ func (n *Notifier) Start() {
 		log.Errorf("Error subscribing to events: %s", err)
 	}
 
+	blocking := []interface{}{
+		&events.PeerBlocked{},
+		&events.PeerUnblocked{},
+	}
+
+	blockingSub, err := n.bus.Subscribe(blocking)
+	if err != nil {
+		log.Errorf("Error subscribing to events: %s", err)
+	}
+
 	wallet := []interface{}{
 		&events.BlockReceived{},
 		&events.TransactionReceived{},
@@ -157,12 +197,20 @@ func (n *Notifier) Start() {
 			switch event.(type) {
 			case *events.ChannelMessage:
 				i = channelMessageWrapper{event}
+			case *events.ChannelPolicyUpdated:
+				i = channelPolicyWrapper{event}
+			case *events.ChannelMessagesDeleted:
+				i = channelDeletionWrapper{event}
+			case *events.PrivateChannelUpdated:
+				i = privateChannelWrapper{event}
 			case *events.ChatMessage:
 				i = chatMessageWrapper{event}
 			case *events.ChatRead:
 				i = messageReadWrapper{event}
 			case *events.ChatTyping:
 				i = messageTypingWrapper{event}
+			case *events.MessageDeliveryUpdate:
+				i = messageDeliveryWrapper{event}
 			}
 
 			if err := n.notifyFunc(i); err != nil {
@@ -179,6 +227,18 @@ func (n *Notifier) Start() {
 				i = statusWrapper{"error publishing"}
 			}
 
+			if err := n.notifyFunc(i); err != nil {
+				log.Errorf("Error sending notification: %s", err)
+			}
+		case event := <-blockingSub.Out():
+			var i interface{}
+			switch event.(type) {
+			case *events.PeerBlocked:
+				i = peerBlockedWrapper{event}
+			case *events.PeerUnblocked:
+				i = peerUnblockedWrapper{event}
+			}
+
 			if err := n.notifyFunc(i); err != nil {
 				log.Errorf("Error sending notification: %s", err)
 			}
@@ -222,6 +282,7 @@ func (n *Notifier) Start() {
 			notificationSub.Close()
 			publishSub.Close()
 			chatSub.Close()
+			blockingSub.Close()
 			walletSub.Close()
 			return
 		}
@@ -242,6 +303,12 @@ func convertToNotification(event interface{}) string {
 	case *events.NewOrder:
 		e.Typ = "NewOrder"
 		e.ID = id
+	case *events.BlockedOrderRejected:
+		e.Typ = "BlockedOrderRejected"
+		e.ID = id
+	case *events.OutgoingMessageFailed:
+		e.Typ = "OutgoingMessageFailed"
+		e.ID = id
 	case *events.OrderFunded:
 		e.Typ = "OrderFunded"
 		e.ID = id
@@ -33,6 +33,31 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	_, err = parser.AddCommand("export",
+		"export an accounting ledger",
+		"The export command writes a CSV or JSON ledger of order payments, refunds, escrow releases, "+
+			"moderator fees and wallet transactions. The node should not be running.",
+		&cmd.Export{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = parser.AddCommand("backup",
+		"write an encrypted backup",
+		"The backup command writes an encrypted archive of the database, public data, config and keys "+
+			"which can be restored with the restore command. The node should not be running. The passphrase is read "+
+			"from the OB_BACKUP_PASSPHRASE environment variable or prompted for.",
+		&cmd.Backup{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = parser.AddCommand("restore",
+		"restore an encrypted backup",
+		"The restore command rebuilds a data directory from a backup written by the backup command, "+
+			"the API or a scheduled backup.",
+		&cmd.Restore{})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	if _, err := parser.Parse(); err != nil {
 		os.Exit(1)
@@ -0,0 +1,161 @@
+package orders
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	npb "github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"math/big"
+)
+
+func (op *OrderProcessor) processDisputeCloseMessage(dbtx database.Tx, order *models.Order, peer peer.ID, message *npb.OrderMessage) (interface{}, error) {
+	disputeClose := new(pb.DisputeClose)
+	if err := ptypes.UnmarshalAny(message.Message, disputeClose); err != nil {
+		return nil, err
+	}
+	dup, err := isDuplicate(disputeClose, order.SerializedDisputeClosed)
+	if err != nil {
+		return nil, err
+	}
+	if order.SerializedDisputeClosed != nil && !dup {
+		log.Errorf("Duplicate DISPUTE_CLOSE message does not match original for order: %s", order.ID)
+		return nil, ErrChangedMessage
+	} else if dup {
+		return nil, nil
+	}
+
+	if order.SerializedOrderComplete != nil {
+		log.Errorf("Received DISPUTE_CLOSE message for order %s after ORDER_COMPLETION", order.ID)
+		return nil, ErrUnexpectedMessage
+	}
+
+	if order.SerializedPaymentFinalized != nil {
+		log.Errorf("Received DISPUTE_CLOSE message for order %s after PAYMENT_FINALIZED", order.ID)
+		return nil, ErrUnexpectedMessage
+	}
+
+	orderOpen, err := order.OrderOpenMessage()
+	if models.IsMessageNotExistError(err) {
+		return nil, order.ParkMessage(message)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The other party's DISPUTE_OPEN may not have arrived yet.
+	if order.SerializedDisputeOpen == nil {
+		return nil, order.ParkMessage(message)
+	}
+
+	if orderOpen.Payment.Moderator == "" || orderOpen.Payment.Method != pb.OrderOpen_Payment_MODERATED {
+		return nil, errors.New("dispute close processed for non-moderated order")
+	}
+
+	if orderOpen.Payment.Moderator != peer.Pretty() {
+		return nil, errors.New("dispute close not sent by the order's moderator")
+	}
+
+	if err := op.validateDisputeClosePayout(order, orderOpen, disputeClose.ReleaseInfo); err != nil {
+		return nil, err
+	}
+
+	var (
+		otherParty       = orderOpen.Listings[0].Listing.VendorID.PeerID
+		otherPartyHandle = orderOpen.Listings[0].Listing.VendorID.Handle
+	)
+	if order.Role() == models.RoleVendor {
+		otherParty = orderOpen.BuyerID.PeerID
+		otherPartyHandle = orderOpen.BuyerID.Handle
+	}
+
+	event := &events.DisputeClose{
+		OrderID: order.ID.String(),
+		Thumbnail: events.Thumbnail{
+			Tiny:  orderOpen.Listings[0].Listing.Item.Images[0].Tiny,
+			Small: orderOpen.Listings[0].Listing.Item.Images[0].Small,
+		},
+		OtherPartyID:     otherParty,
+		OtherPartyHandle: otherPartyHandle,
+		Buyer:            orderOpen.BuyerID.PeerID,
+	}
+
+	log.Infof("Received DISPUTE_CLOSE message for order %s", order.ID)
+
+	return event, order.PutMessage(message)
+}
+
+// validateDisputeClosePayout checks that the moderator's payout pays the buyer and
+// vendor to the addresses in the contract and does not spend more than the order
+// was funded with.
+func (op *OrderProcessor) validateDisputeClosePayout(order *models.Order, orderOpen *pb.OrderOpen, releaseInfo *pb.DisputeClose_ModeratedEscrowRelease) error {
+	if releaseInfo == nil {
+		return errors.New("dispute close is missing release info")
+	}
+	if len(releaseInfo.FromIDs) == 0 {
+		return errors.New("dispute close release does not spend any inputs")
+	}
+	if len(releaseInfo.EscrowSignatures) == 0 {
+		return errors.New("dispute close release is missing moderator signatures")
+	}
+
+	var (
+		total   = iwallet.NewAmount(0)
+		amounts = make(map[string]iwallet.Amount)
+	)
+	for name, s := range map[string]string{
+		"buyer":     releaseInfo.BuyerAmount,
+		"vendor":    releaseInfo.VendorAmount,
+		"moderator": releaseInfo.ModeratorAmount,
+		"fee":       releaseInfo.TransactionFee,
+	} {
+		if s == "" {
+			s = "0"
+		}
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok || i.Sign() < 0 {
+			return errors.New("dispute close contains invalid " + name + " amount")
+		}
+		amounts[name] = iwallet.NewAmount(i)
+		total = total.Add(amounts[name])
+	}
+
+	zero := iwallet.NewAmount(0)
+	if amounts["buyer"].Cmp(zero) > 0 && releaseInfo.BuyerAddress != orderOpen.RefundAddress {
+		return errors.New("dispute close does not pay the buyer to the refund address")
+	}
+
+	if amounts["vendor"].Cmp(zero) > 0 {
+		fulfillments, err := order.OrderFulfillmentMessages()
+		if err != nil && !models.IsMessageNotExistError(err) {
+			return err
+		}
+		found := false
+		for _, fulfillment := range fulfillments {
+			if fulfillment.ReleaseInfo != nil && fulfillment.ReleaseInfo.ToAddress == releaseInfo.VendorAddress {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("dispute close does not pay the vendor to the vendor's payout address")
+		}
+	}
+
+	if amounts["moderator"].Cmp(zero) > 0 && releaseInfo.ModeratorAddress == "" {
+		return errors.New("dispute close is missing moderator address")
+	}
+
+	fundingTotal, err := order.FundingTotal()
+	if err != nil {
+		return err
+	}
+	if total.Cmp(fundingTotal) > 0 {
+		return errors.New("dispute close pays out more than the order was funded with")
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package orders
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	npb "github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"reflect"
+	"testing"
+)
+
+func TestOrderProcessor_processDisputeCloseMessage(t *testing.T) {
+	op, teardown, err := newMockOrderProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	_, vendorPub, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorPeer, err := peer.IDFromPublicKey(vendorPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, moderatorPub, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moderatorPeer, err := peer.IDFromPublicKey(moderatorPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderID := "1234"
+
+	var (
+		vendorHandle   = "xyz"
+		smallImageHash = "aaaa"
+		tinyImageHash  = "bbbb"
+		paymentAddress = "abc"
+		refundAddress  = "def"
+	)
+	orderOpen := &pb.OrderOpen{
+		Listings: []*pb.SignedListing{
+			{
+				Listing: &pb.Listing{
+					Item: &pb.Listing_Item{
+						Images: []*pb.Listing_Item_Image{
+							{
+								Small: smallImageHash,
+								Tiny:  tinyImageHash,
+							},
+						},
+					},
+					VendorID: &pb.ID{
+						PeerID: vendorPeer.Pretty(),
+						Handle: vendorHandle,
+					},
+				},
+			},
+		},
+		BuyerID: &pb.ID{
+			PeerID: op.identity.Pretty(),
+		},
+		RefundAddress: refundAddress,
+		Payment: &pb.OrderOpen_Payment{
+			Address:   paymentAddress,
+			Coin:      iwallet.CtMock,
+			Moderator: moderatorPeer.Pretty(),
+			Method:    pb.OrderOpen_Payment_MODERATED,
+		},
+	}
+
+	newDisputeClose := func(buyerAddress, buyerAmount string) *npb.OrderMessage {
+		disputeClose := &pb.DisputeClose{
+			Verdict: "Buyer wins",
+			ReleaseInfo: &pb.DisputeClose_ModeratedEscrowRelease{
+				EscrowSignatures: []*pb.Signature{{Signature: []byte{0x01}}},
+				FromIDs:          [][]byte{{0x02}},
+				BuyerAddress:     buyerAddress,
+				BuyerAmount:      buyerAmount,
+				VendorAmount:     "0",
+				ModeratorAddress: "ghi",
+				ModeratorAmount:  "100",
+				TransactionFee:   "100",
+			},
+		}
+		return &npb.OrderMessage{
+			OrderID:     orderID,
+			MessageType: npb.OrderMessage_DISPUTE_CLOSE,
+			Message:     mustBuildAny(disputeClose),
+		}
+	}
+
+	disputeOpenAny, err := ptypes.MarshalAny(&pb.DisputeOpen{OpenedBy: pb.DisputeOpen_BUYER})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openAndFund := func(order *models.Order) error {
+		order.ID = models.OrderID(orderID)
+		order.SetRole(models.RoleBuyer)
+		err := order.PutMessage(&npb.OrderMessage{
+			Signature:   []byte("abc"),
+			Message:     mustBuildAny(orderOpen),
+			MessageType: npb.OrderMessage_ORDER_OPEN,
+		})
+		if err != nil {
+			return err
+		}
+		err = order.PutMessage(&npb.OrderMessage{
+			Signature:   []byte("abc"),
+			Message:     disputeOpenAny,
+			MessageType: npb.OrderMessage_DISPUTE_OPEN,
+		})
+		if err != nil {
+			return err
+		}
+		return order.PutTransaction(iwallet.Transaction{
+			ID: "1111",
+			To: []iwallet.SpendInfo{
+				{
+					Address: iwallet.NewAddress(paymentAddress, iwallet.CtMock),
+					Amount:  iwallet.NewAmount(1000),
+				},
+			},
+		})
+	}
+
+	tests := []struct {
+		setup         func(order *models.Order) error
+		message       *npb.OrderMessage
+		expectedError error
+		expectError   bool
+		expectedEvent interface{}
+	}{
+		{
+			// Normal case where order open and dispute open exist.
+			setup:         openAndFund,
+			message:       newDisputeClose(refundAddress, "800"),
+			expectedError: nil,
+			expectedEvent: &events.DisputeClose{
+				OrderID: orderID,
+				Thumbnail: events.Thumbnail{
+					Tiny:  tinyImageHash,
+					Small: smallImageHash,
+				},
+				OtherPartyID:     vendorPeer.Pretty(),
+				OtherPartyHandle: vendorHandle,
+				Buyer:            op.identity.Pretty(),
+			},
+		},
+		{
+			// OrderComplete already exists.
+			setup: func(order *models.Order) error {
+				order.SerializedOrderComplete = []byte{0x00}
+				return nil
+			},
+			message:       newDisputeClose(refundAddress, "800"),
+			expectedError: ErrUnexpectedMessage,
+			expectedEvent: nil,
+		},
+		{
+			// PaymentFinalized already exists.
+			setup: func(order *models.Order) error {
+				order.SerializedPaymentFinalized = []byte{0x00}
+				return nil
+			},
+			message:       newDisputeClose(refundAddress, "800"),
+			expectedError: ErrUnexpectedMessage,
+			expectedEvent: nil,
+		},
+		{
+			// Duplicate dispute close.
+			setup: func(order *models.Order) error {
+				return order.PutMessage(newDisputeClose(refundAddress, "800"))
+			},
+			message:       newDisputeClose(refundAddress, "800"),
+			expectedError: nil,
+			expectedEvent: nil,
+		},
+		{
+			// Changed duplicate dispute close.
+			setup: func(order *models.Order) error {
+				return order.PutMessage(newDisputeClose(refundAddress, "700"))
+			},
+			message:       newDisputeClose(refundAddress, "800"),
+			expectedError: ErrChangedMessage,
+			expectedEvent: nil,
+		},
+		{
+			// Out of order.
+			setup: func(order *models.Order) error {
+				return nil
+			},
+			message:       newDisputeClose(refundAddress, "800"),
+			expectedError: nil,
+			expectedEvent: nil,
+		},
+		{
+			// Dispute open has not arrived yet.
+			setup: func(order *models.Order) error {
+				if err := openAndFund(order); err != nil {
+					return err
+				}
+				order.SerializedDisputeOpen = nil
+				return nil
+			},
+			message:       newDisputeClose(refundAddress, "800"),
+			expectedError: nil,
+			expectedEvent: nil,
+		},
+		{
+			// Buyer paid to the wrong address.
+			setup:         openAndFund,
+			message:       newDisputeClose("jkl", "800"),
+			expectError:   true,
+			expectedEvent: nil,
+		},
+		{
+			// Payout exceeds the funded amount.
+			setup:         openAndFund,
+			message:       newDisputeClose(refundAddress, "900"),
+			expectError:   true,
+			expectedEvent: nil,
+		},
+	}
+
+	for i, test := range tests {
+		order := &models.Order{}
+		if err := test.setup(order); err != nil {
+			t.Errorf("Test %d setup error: %s", i, err)
+			continue
+		}
+		err := op.db.Update(func(tx database.Tx) error {
+			event, err := op.processDisputeCloseMessage(tx, order, moderatorPeer, test.message)
+			if test.expectError {
+				if err == nil {
+					return fmt.Errorf("expected error, got nil")
+				}
+			} else if err != test.expectedError {
+				return fmt.Errorf("incorrect error returned. Expected %t, got %t", test.expectedError, err)
+			}
+			if !reflect.DeepEqual(event, test.expectedEvent) {
+				fmt.Println(event)
+				fmt.Println(test.expectedEvent)
+				return fmt.Errorf("incorrect event returned")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Error executing db update in test %d: %s", i, err)
+		}
+	}
+
+	// Dispute close from a peer other than the moderator.
+	order := &models.Order{}
+	if err := openAndFund(order); err != nil {
+		t.Fatal(err)
+	}
+	err = op.db.Update(func(tx database.Tx) error {
+		_, err := op.processDisputeCloseMessage(tx, order, vendorPeer, newDisputeClose(refundAddress, "800"))
+		return err
+	})
+	if err == nil {
+		t.Error("Expected error processing dispute close from non-moderator")
+	}
+}
@@ -0,0 +1,191 @@
+package orders
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+const escrowTimeoutCheckInterval = time.Hour
+
+// escrowTimeoutWarnings are the points, measured as the time remaining until the
+// escrow timeout expires, at which we emit a warning. The zero threshold is
+// emitted once the timeout has expired.
+var escrowTimeoutWarnings = []time.Duration{
+	time.Hour * 24 * 15,
+	time.Hour * 24 * 5,
+	time.Hour * 24,
+	0,
+}
+
+// checkEscrowTimeouts loads all fulfilled moderated orders and open cases and
+// emits a warning for each one approaching (or past) its escrow timeout.
+//
+// Vendors are notified so they can release the funds once the timeout expires,
+// buyers are notified that their window to open a dispute is closing, and
+// moderators are notified that an open case is about to be released by the vendor.
+func (op *OrderProcessor) checkEscrowTimeouts() {
+	var (
+		orders []models.Order
+		cases  []models.Case
+	)
+	err := op.db.View(func(dbtx database.Tx) error {
+		if err := dbtx.Read().Find(&orders).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := dbtx.Read().Find(&cases).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Error loading orders to check escrow timeouts: %s", err)
+		return
+	}
+
+	for _, order := range orders {
+		if order.Role() != models.RoleBuyer && order.Role() != models.RoleVendor {
+			continue
+		}
+		if order.Status() != models.StatusFulfilled {
+			continue
+		}
+		orderOpen, err := order.OrderOpenMessage()
+		if err != nil {
+			continue
+		}
+		if !op.walletSupportsEscrowTimeout(orderOpen) {
+			continue
+		}
+		expiry, err := order.EscrowExpiry()
+		if err != nil {
+			log.Errorf("Error calculating escrow expiry for order %s: %s", order.ID, err)
+			continue
+		}
+		if expiry.IsZero() {
+			continue
+		}
+
+		remaining, ok := op.nextEscrowTimeoutWarning(order.ID.String(), expiry)
+		if !ok {
+			continue
+		}
+
+		thumbnail := events.Thumbnail{
+			Tiny:  orderOpen.Listings[0].Listing.Item.Images[0].Tiny,
+			Small: orderOpen.Listings[0].Listing.Item.Images[0].Small,
+		}
+		if order.Role() == models.RoleVendor {
+			op.bus.Emit(&events.VendorDisputeTimeout{
+				OrderID:   order.ID.String(),
+				ExpiresIn: hoursRemaining(remaining),
+				Thumbnail: thumbnail,
+			})
+		} else {
+			op.bus.Emit(&events.BuyerDisputeExpiry{
+				OrderID:   order.ID.String(),
+				ExpiresIn: hoursRemaining(remaining),
+				Thumbnail: thumbnail,
+			})
+		}
+	}
+
+	for _, disputeCase := range cases {
+		if disputeCase.IsClosed() {
+			continue
+		}
+		contractBytes := disputeCase.BuyerContract
+		if contractBytes == nil {
+			contractBytes = disputeCase.VendorContract
+		}
+		if contractBytes == nil {
+			continue
+		}
+		contract := new(pb.Contract)
+		if err := proto.Unmarshal(contractBytes, contract); err != nil || contract.OrderOpen == nil {
+			continue
+		}
+		if !op.walletSupportsEscrowTimeout(contract.OrderOpen) {
+			continue
+		}
+
+		var fundingTimes []time.Time
+		for _, tx := range contract.Transactions {
+			ts, err := ptypes.Timestamp(tx.Timestamp)
+			if err != nil {
+				continue
+			}
+			fundingTimes = append(fundingTimes, ts)
+		}
+		expiry := utils.EscrowExpiry(contract.OrderOpen, fundingTimes)
+		if expiry.IsZero() {
+			continue
+		}
+
+		remaining, ok := op.nextEscrowTimeoutWarning(disputeCase.ID.String(), expiry)
+		if !ok {
+			continue
+		}
+
+		op.bus.Emit(&events.ModeratorDisputeExpiry{
+			CaseID:    disputeCase.ID.String(),
+			ExpiresIn: hoursRemaining(remaining),
+			Thumbnail: events.Thumbnail{
+				Tiny:  contract.OrderOpen.Listings[0].Listing.Item.Images[0].Tiny,
+				Small: contract.OrderOpen.Listings[0].Listing.Item.Images[0].Small,
+			},
+		})
+	}
+}
+
+// nextEscrowTimeoutWarning returns the time remaining until the expiry if a new
+// warning threshold has been crossed for the given ID since the last warning.
+//
+// The thresholds already warned about are only tracked in memory so a warning
+// may be repeated once after a restart.
+func (op *OrderProcessor) nextEscrowTimeoutWarning(id string, expiry time.Time) (time.Duration, bool) {
+	op.escrowTimeoutMtx.Lock()
+	defer op.escrowTimeoutMtx.Unlock()
+
+	remaining := time.Until(expiry)
+	for i := len(escrowTimeoutWarnings) - 1; i >= 0; i-- {
+		threshold := escrowTimeoutWarnings[i]
+		if remaining > threshold {
+			continue
+		}
+		last, ok := op.escrowTimeoutWarned[id]
+		if ok && last <= threshold {
+			return 0, false
+		}
+		op.escrowTimeoutWarned[id] = threshold
+		if remaining < 0 {
+			remaining = 0
+		}
+		return remaining, true
+	}
+	return 0, false
+}
+
+func (op *OrderProcessor) walletSupportsEscrowTimeout(orderOpen *pb.OrderOpen) bool {
+	if orderOpen.Payment == nil || orderOpen.Payment.Method != pb.OrderOpen_Payment_MODERATED {
+		return false
+	}
+	wallet, err := op.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
+	if err != nil {
+		return false
+	}
+	_, ok := wallet.(iwallet.EscrowWithTimeout)
+	return ok
+}
+
+func hoursRemaining(d time.Duration) uint {
+	return uint(d / time.Hour)
+}
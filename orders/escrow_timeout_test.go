@@ -0,0 +1,156 @@
+package orders
+
+import (
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"testing"
+	"time"
+)
+
+func TestOrderProcessor_checkEscrowTimeouts(t *testing.T) {
+	op, teardown, err := newMockOrderProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	newOrderOpen := func() (*pb.OrderOpen, error) {
+		orderOpen, err := factory.NewOrder()
+		if err != nil {
+			return nil, err
+		}
+		orderOpen.Payment.Method = pb.OrderOpen_Payment_MODERATED
+		orderOpen.Listings[0].Listing.Metadata.EscrowTimeoutHours = 24 * 20
+		return orderOpen, nil
+	}
+
+	saveOrder := func(id models.OrderID, role models.OrderRole, fundedAge time.Duration) error {
+		orderOpen, err := newOrderOpen()
+		if err != nil {
+			return err
+		}
+		order := models.Order{ID: id}
+		order.SetRole(role)
+		if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+			return err
+		}
+		err = order.PutTransaction(iwallet.Transaction{
+			ID: iwallet.TransactionID(id),
+			To: []iwallet.SpendInfo{
+				{
+					Address: iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CtMock),
+					Amount:  iwallet.NewAmount(orderOpen.Payment.Amount),
+				},
+			},
+			Timestamp: time.Now().Add(-fundedAge),
+		})
+		if err != nil {
+			return err
+		}
+		fulfillment := &pb.OrderFulfillment{
+			Fulfillments: []*pb.OrderFulfillment_FulfilledItem{{ItemIndex: 0}},
+		}
+		if err := order.PutMessage(utils.MustWrapOrderMessage(fulfillment)); err != nil {
+			return err
+		}
+		return op.db.Update(func(tx database.Tx) error {
+			return tx.Save(&order)
+		})
+	}
+
+	// Expired.
+	if err := saveOrder("vendorOrder", models.RoleVendor, time.Hour*24*21); err != nil {
+		t.Fatal(err)
+	}
+	// Expires in three days.
+	if err := saveOrder("buyerOrder", models.RoleBuyer, time.Hour*24*17); err != nil {
+		t.Fatal(err)
+	}
+	// Not yet within any of the warning thresholds.
+	if err := saveOrder("newOrder", models.RoleBuyer, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expires in ten days.
+	orderOpen, err := newOrderOpen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := ptypes.TimestampProto(time.Now().Add(-time.Hour * 24 * 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract, err := proto.Marshal(&pb.Contract{
+		OrderOpen: orderOpen,
+		Transactions: []*pb.Contract_Transaction{
+			{
+				Txid:      "abc",
+				Value:     orderOpen.Payment.Amount,
+				Timestamp: ts,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = op.db.Update(func(tx database.Tx) error {
+		return tx.Save(&models.Case{ID: "case", BuyerContract: contract})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := op.bus.Subscribe([]interface{}{
+		&events.VendorDisputeTimeout{},
+		&events.BuyerDisputeExpiry{},
+		&events.ModeratorDisputeExpiry{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	op.checkEscrowTimeouts()
+
+	received := make(map[string]uint)
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-sub.Out():
+			switch e := event.(type) {
+			case *events.VendorDisputeTimeout:
+				received[e.OrderID] = e.ExpiresIn
+			case *events.BuyerDisputeExpiry:
+				received[e.OrderID] = e.ExpiresIn
+			case *events.ModeratorDisputeExpiry:
+				received[e.CaseID] = e.ExpiresIn
+			}
+		case <-time.After(time.Second * 10):
+			t.Fatal("Timeout waiting on channel")
+		}
+	}
+
+	if expiresIn, ok := received["vendorOrder"]; !ok || expiresIn != 0 {
+		t.Errorf("Expected vendor timeout with zero hours remaining, got %d", expiresIn)
+	}
+	if expiresIn, ok := received["buyerOrder"]; !ok || expiresIn != 71 {
+		t.Errorf("Expected buyer expiry with 71 hours remaining, got %d", expiresIn)
+	}
+	if expiresIn, ok := received["case"]; !ok || expiresIn != 239 {
+		t.Errorf("Expected moderator expiry with 239 hours remaining, got %d", expiresIn)
+	}
+
+	// Warnings should not be repeated for the same threshold.
+	op.checkEscrowTimeouts()
+	select {
+	case event := <-sub.Out():
+		t.Errorf("Unexpected event %v", event)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
@@ -0,0 +1,103 @@
+package orders
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// saveRateSnapshot saves the exchange rates from the order's payment coin to
+// the pricing currencies of its listings and to our local currency in the
+// rate history. Fetching a rate may make network requests so only cached
+// rates are used inside the database transaction. Any rates which are not
+// cached are fetched after the transaction commits. Exchange rate data is not
+// needed to process the order so failing to fetch a rate is logged rather
+// than returned.
+func (op *OrderProcessor) saveRateSnapshot(dbtx database.Tx, order *models.Order, event, txid string) error {
+	if op.erp == nil {
+		return nil
+	}
+	orderOpen, err := order.OrderOpenMessage()
+	if err != nil {
+		return err
+	}
+	if orderOpen.Payment == nil {
+		return nil
+	}
+
+	var prefs models.UserPreferences
+	if err := dbtx.Read().First(&prefs).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var (
+		base       = models.CurrencyCode(strings.TrimPrefix(strings.ToUpper(orderOpen.Payment.Coin), "T"))
+		currencies []models.CurrencyCode
+		seen       = map[models.CurrencyCode]bool{base: true}
+	)
+	addCurrency := func(code string) {
+		cc := models.CurrencyCode(strings.ToUpper(code))
+		if cc != "" && !seen[cc] {
+			currencies = append(currencies, cc)
+			seen[cc] = true
+		}
+	}
+	for _, sl := range orderOpen.Listings {
+		if sl.Listing != nil && sl.Listing.Metadata != nil && sl.Listing.Metadata.PricingCurrency != nil {
+			addCurrency(sl.Listing.Metadata.PricingCurrency.Code)
+		}
+	}
+	addCurrency(prefs.LocalCurrency)
+
+	var (
+		now     = time.Now().UTC()
+		missing []*models.ExchangeRateRecord
+	)
+	for _, cc := range currencies {
+		record := &models.ExchangeRateRecord{
+			Base:          base,
+			Currency:      cc,
+			Timestamp:     now,
+			OrderID:       order.ID,
+			Event:         event,
+			TransactionID: txid,
+		}
+		rate, ok := op.erp.GetCachedRate(base, cc)
+		if !ok {
+			missing = append(missing, record)
+			continue
+		}
+		record.Rate = rate.String()
+		if err := dbtx.Save(record); err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		dbtx.RegisterCommitHook(func() {
+			go op.fillRateSnapshot(missing)
+		})
+	}
+	return nil
+}
+
+// fillRateSnapshot fetches the rates for snapshot records which were not
+// cached and saves them.
+func (op *OrderProcessor) fillRateSnapshot(records []*models.ExchangeRateRecord) {
+	for _, record := range records {
+		rate, err := op.erp.GetRate(record.Base, record.Currency, false)
+		if err != nil {
+			log.Warningf("Error fetching %s/%s exchange rate for order %s: %s", record.Base, record.Currency, record.OrderID, err)
+			continue
+		}
+		record.Rate = rate.String()
+		err = op.db.Update(func(tx database.Tx) error {
+			return tx.Save(record)
+		})
+		if err != nil {
+			log.Errorf("Error saving %s/%s exchange rate for order %s: %s", record.Base, record.Currency, record.OrderID, err)
+		}
+	}
+}
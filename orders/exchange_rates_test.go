@@ -0,0 +1,132 @@
+package orders
+
+import (
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	"testing"
+	"time"
+)
+
+func TestOrderProcessor_saveRateSnapshot(t *testing.T) {
+	op, teardown, err := newMockOrderProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	orderOpen, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := &models.Order{ID: "1234"}
+	if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUSD, err := op.erp.GetRate("MCK", "USD", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedEUR, err := op.erp.GetRate("MCK", "EUR", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []models.ExchangeRateRecord
+	err = op.db.Update(func(tx database.Tx) error {
+		var prefs models.UserPreferences
+		if err := tx.Read().First(&prefs).Error; err != nil {
+			return err
+		}
+		prefs.LocalCurrency = "eur"
+		if err := tx.Save(&prefs); err != nil {
+			return err
+		}
+		if err := op.saveRateSnapshot(tx, order, models.RateEventOrderOpen, ""); err != nil {
+			return err
+		}
+		if err := op.saveRateSnapshot(tx, order, models.RateEventPayment, "abc"); err != nil {
+			return err
+		}
+		return tx.Read().Order("id").Find(&records).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.ExchangeRateRecord{
+		{Base: "MCK", Currency: "USD", Rate: expectedUSD.String(), OrderID: "1234", Event: models.RateEventOrderOpen},
+		{Base: "MCK", Currency: "EUR", Rate: expectedEUR.String(), OrderID: "1234", Event: models.RateEventOrderOpen},
+		{Base: "MCK", Currency: "USD", Rate: expectedUSD.String(), OrderID: "1234", Event: models.RateEventPayment, TransactionID: "abc"},
+		{Base: "MCK", Currency: "EUR", Rate: expectedEUR.String(), OrderID: "1234", Event: models.RateEventPayment, TransactionID: "abc"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(records))
+	}
+	for i, record := range records {
+		if record.Timestamp.IsZero() {
+			t.Errorf("Record %d: timestamp not set", i)
+		}
+		record.ID = 0
+		record.Timestamp = expected[i].Timestamp
+		if record != expected[i] {
+			t.Errorf("Record %d: expected %v, got %v", i, expected[i], record)
+		}
+	}
+}
+
+func TestOrderProcessor_saveRateSnapshotUncached(t *testing.T) {
+	op, teardown, err := newMockOrderProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	orderOpen, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := &models.Order{ID: "1234"}
+	if err := order.PutMessage(utils.MustWrapOrderMessage(orderOpen)); err != nil {
+		t.Fatal(err)
+	}
+
+	// With a cold cache nothing is fetched inside the transaction.
+	var count int64
+	err = op.db.Update(func(tx database.Tx) error {
+		if err := op.saveRateSnapshot(tx, order, models.RateEventOrderOpen, ""); err != nil {
+			return err
+		}
+		return tx.Read().Model(&models.ExchangeRateRecord{}).Count(&count).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Expected no records inside the transaction, got %d", count)
+	}
+
+	// The rate is filled in once the transaction commits.
+	var records []models.ExchangeRateRecord
+	for i := 0; i < 50; i++ {
+		err = op.db.View(func(tx database.Tx) error {
+			return tx.Read().Find(&records).Error
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+	expectedUSD, err := op.erp.GetRate("MCK", "USD", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Currency != "USD" || records[0].Rate != expectedUSD.String() || records[0].Event != models.RateEventOrderOpen {
+		t.Errorf("Incorrect records %v", records)
+	}
+}
@@ -0,0 +1,229 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
+	iwallet "github.com/cpacia/wallet-interface"
+	"gorm.io/gorm"
+)
+
+// ErrOutOfStock is returned when an order requests more of a SKU than
+// the vendor has in stock.
+var ErrOutOfStock = errors.New("out of stock")
+
+// SetListingInventory replaces the inventory for the listing with the
+// quantities set on its SKUs. A listing without options is tracked as a
+// single SKU with no variants.
+//
+// The quantities are then removed from the listing. The inventory changes
+// as orders come in so it is published separately rather than being signed
+// into the listing.
+func SetListingInventory(dbtx database.Tx, listing *pb.Listing) error {
+	skus := listing.Item.Skus
+	if len(listing.Item.Options) == 0 {
+		sku := &pb.Listing_Item_Sku{}
+		if len(skus) > 0 {
+			sku.Quantity = skus[0].Quantity
+			sku.ProductID = skus[0].ProductID
+		}
+		skus = []*pb.Listing_Item_Sku{sku}
+	}
+
+	var items []*models.InventoryItem
+	for _, sku := range skus {
+		item, err := models.NewInventoryItem(listing.Slug, sku)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+
+	if err := dbtx.Delete("slug", listing.Slug, nil, &models.InventoryItem{}); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := dbtx.Save(item); err != nil {
+			return err
+		}
+	}
+
+	for _, sku := range listing.Item.Skus {
+		sku.Quantity = ""
+	}
+	return updateInventoryFile(dbtx)
+}
+
+// ApplyListingInventory sets the quantities from the inventory on the SKUs
+// of our own listing. It is the inverse of SetListingInventory.
+func ApplyListingInventory(dbtx database.Tx, listing *pb.Listing) error {
+	var items []models.InventoryItem
+	if err := dbtx.Read().Where("slug = ?", listing.Slug).Find(&items).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	inventory := models.Inventory(items)
+
+	for _, sku := range listing.Item.Skus {
+		variants := ""
+		if len(listing.Item.Options) > 0 {
+			variants = models.SkuVariants(sku.Selections)
+		}
+		if item, ok := inventory.Get(listing.Slug, variants); ok {
+			sku.Quantity = item.Quantity
+		}
+	}
+	return nil
+}
+
+// DeleteListingInventory deletes the inventory for the listing.
+func DeleteListingInventory(dbtx database.Tx, slug string) error {
+	if err := dbtx.Delete("slug", slug, nil, &models.InventoryItem{}); err != nil {
+		return err
+	}
+	return updateInventoryFile(dbtx)
+}
+
+// CheckInventory returns ErrOutOfStock if the inventory does not have
+// enough of each item in the order. Items for listings which are not
+// tracked in the inventory are assumed to be in stock.
+func CheckInventory(inventory models.Inventory, order *pb.OrderOpen) error {
+	quantities, err := inventoryQuantities(order)
+	if err != nil {
+		return err
+	}
+	for _, q := range quantities {
+		item, ok := inventory.Get(q.slug, q.variants)
+		if !ok {
+			continue
+		}
+		if !item.InStock(q.quantity) {
+			return fmt.Errorf("%w: %s %s", ErrOutOfStock, q.slug, q.variants)
+		}
+	}
+	return nil
+}
+
+// DecrementInventory removes the items in the order from our inventory.
+// It returns the slugs of the listings that were updated.
+func DecrementInventory(dbtx database.Tx, order *pb.OrderOpen) ([]string, error) {
+	return adjustInventory(dbtx, order, false)
+}
+
+// RestoreInventory adds the items in the order back to our inventory.
+// It returns the slugs of the listings that were updated.
+func RestoreInventory(dbtx database.Tx, order *pb.OrderOpen) ([]string, error) {
+	return adjustInventory(dbtx, order, true)
+}
+
+// restoreOrderInventory restores the inventory for a funded order that
+// will no longer be filled. Unfunded orders were never taken out of the
+// inventory so there is nothing to restore.
+func (op *OrderProcessor) restoreOrderInventory(dbtx database.Tx, order *models.Order, orderOpen *pb.OrderOpen) error {
+	funded, err := order.IsFunded()
+	if err != nil || !funded {
+		return err
+	}
+	slugs, err := RestoreInventory(dbtx, orderOpen)
+	if err != nil {
+		return err
+	}
+	if len(slugs) > 0 {
+		dbtx.RegisterCommitHook(func() {
+			op.bus.Emit(&events.InventoryUpdated{Slugs: slugs})
+		})
+	}
+	return nil
+}
+
+func adjustInventory(dbtx database.Tx, order *pb.OrderOpen, restore bool) ([]string, error) {
+	quantities, err := inventoryQuantities(order)
+	if err != nil {
+		return nil, err
+	}
+
+	var slugs []string
+	for _, q := range quantities {
+		var item models.InventoryItem
+		err := dbtx.Read().Where("id = ?", models.InventoryID(q.slug, q.variants)).First(&item).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if item.IsUnlimited() {
+			continue
+		}
+
+		delta := iwallet.NewAmount(0).Sub(q.quantity)
+		if restore {
+			delta = q.quantity
+		}
+		item.Adjust(delta)
+
+		if err := dbtx.Save(&item); err != nil {
+			return nil, err
+		}
+		slugs = append(slugs, item.Slug)
+	}
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+	return slugs, updateInventoryFile(dbtx)
+}
+
+type skuQuantity struct {
+	slug     string
+	variants string
+	quantity iwallet.Amount
+}
+
+// inventoryQuantities returns the total quantity ordered of each SKU in
+// the order.
+func inventoryQuantities(order *pb.OrderOpen) ([]*skuQuantity, error) {
+	var (
+		quantities []*skuQuantity
+		byID       = make(map[string]*skuQuantity)
+	)
+	for i, item := range order.Items {
+		listing, err := utils.ExtractListing(item.ListingHash, order.Listings)
+		if err != nil {
+			return nil, err
+		}
+		sku, err := getSelectedSku(listing, item.Options)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %s", i, err)
+		}
+		variants := models.SkuVariants(sku.Selections)
+		id := models.InventoryID(listing.Slug, variants)
+
+		q, ok := byID[id]
+		if !ok {
+			q = &skuQuantity{
+				slug:     listing.Slug,
+				variants: variants,
+				quantity: iwallet.NewAmount(0),
+			}
+			byID[id] = q
+			quantities = append(quantities, q)
+		}
+		q.quantity = q.quantity.Add(iwallet.NewAmount(item.Quantity))
+	}
+	return quantities, nil
+}
+
+// updateInventoryFile writes the inventory from the database to the public
+// inventory file.
+func updateInventoryFile(dbtx database.Tx) error {
+	var items []models.InventoryItem
+	if err := dbtx.Read().Order("id").Find(&items).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if items == nil {
+		items = []models.InventoryItem{}
+	}
+	return dbtx.SetInventory(items)
+}
@@ -0,0 +1,141 @@
+package orders
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/cpacia/openbazaar3.0/models/factory"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"github.com/cpacia/openbazaar3.0/repo"
+	"github.com/golang/protobuf/proto"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	db, err := repo.MockDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listing := proto.Clone(order.Listings[0].Listing).(*pb.Listing)
+
+	err = db.Update(func(tx database.Tx) error {
+		return SetListingInventory(tx, listing)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sku := range listing.Item.Skus {
+		if sku.Quantity != "" {
+			t.Errorf("Expected quantity to be removed from listing, got %s", sku.Quantity)
+		}
+	}
+
+	getItem := func() *models.InventoryItem {
+		var inventory models.Inventory
+		err := db.View(func(tx database.Tx) error {
+			inventory, err = tx.GetInventory()
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(inventory) != len(order.Listings[0].Listing.Item.Skus) {
+			t.Fatalf("Expected %d inventory items, got %d", len(order.Listings[0].Listing.Item.Skus), len(inventory))
+		}
+		item, ok := inventory.Get(listing.Slug, "color:red,size:large")
+		if !ok {
+			t.Fatal("Inventory item not found")
+		}
+		return item
+	}
+
+	if item := getItem(); item.Quantity != "12" {
+		t.Errorf("Expected quantity 12, got %s", item.Quantity)
+	}
+
+	err = db.Update(func(tx database.Tx) error {
+		_, err := DecrementInventory(tx, order)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := getItem(); item.Quantity != "11" {
+		t.Errorf("Expected quantity 11, got %s", item.Quantity)
+	}
+
+	err = db.Update(func(tx database.Tx) error {
+		_, err := RestoreInventory(tx, order)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := getItem(); item.Quantity != "12" {
+		t.Errorf("Expected quantity 12, got %s", item.Quantity)
+	}
+
+	err = db.View(func(tx database.Tx) error {
+		return ApplyListingInventory(tx, listing)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, sku := range listing.Item.Skus {
+		if sku.Quantity != order.Listings[0].Listing.Item.Skus[i].Quantity {
+			t.Errorf("Expected quantity %s, got %s", order.Listings[0].Listing.Item.Skus[i].Quantity, sku.Quantity)
+		}
+	}
+
+	var inventory models.Inventory
+	err = db.View(func(tx database.Tx) error {
+		inventory, err = tx.GetInventory()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckInventory(inventory, order); err != nil {
+		t.Errorf("Unexpected error checking inventory: %s", err)
+	}
+
+	order.Items[0].Quantity = "13"
+	if err := CheckInventory(inventory, order); !errors.Is(err, ErrOutOfStock) {
+		t.Errorf("Expected out of stock error, got %v", err)
+	}
+
+	err = db.Update(func(tx database.Tx) error {
+		_, err := DecrementInventory(tx, order)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := getItem(); item.Quantity != "0" {
+		t.Errorf("Expected quantity 0, got %s", item.Quantity)
+	}
+
+	err = db.Update(func(tx database.Tx) error {
+		return DeleteListingInventory(tx, listing.Slug)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.View(func(tx database.Tx) error {
+		inventory, err = tx.GetInventory()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory) != 0 {
+		t.Errorf("Expected empty inventory, got %d items", len(inventory))
+	}
+}
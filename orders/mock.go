@@ -88,6 +88,7 @@ func newMockOrderProcessor() (*OrderProcessor, func(), error) {
 			IdentityPrivateKey:   ipfsNode.PrivateKey,
 			Db:                   r.DB(),
 			Messenger:            messenger,
+			BanManager:           banManager,
 			Multiwallet:          mw,
 			ExchangeRateProvider: erp,
 			EventBus:             events.NewBus(),
@@ -77,6 +77,9 @@ func (op *OrderProcessor) processOrderCancelMessage(dbtx database.Tx, order *mod
 		log.Infof("Processed own ORDER_CANCEL for orderID: %s", order.ID)
 	} else if order.Role() == models.RoleVendor {
 		log.Infof("Received ORDER_CANCEL message for order %s", order.ID)
+		if err := op.restoreOrderInventory(dbtx, order, orderOpen); err != nil {
+			return nil, err
+		}
 	}
 
 	return event, order.PutMessage(message)
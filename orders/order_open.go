@@ -23,6 +23,10 @@ import (
 	"strings"
 )
 
+// blockedBuyerRejectReason is the reason given to buyers when their order is
+// automatically rejected because the vendor has blocked them.
+const blockedBuyerRejectReason = "vendor is not accepting orders from this buyer"
+
 func (op *OrderProcessor) processOrderOpenMessage(dbtx database.Tx, order *models.Order, peer peer.ID, message *npb.OrderMessage) (interface{}, error) {
 	order.ID = models.OrderID(message.OrderID)
 
@@ -49,54 +53,24 @@ func (op *OrderProcessor) processOrderOpenMessage(dbtx database.Tx, order *model
 	}
 	order.Open = true
 
-	var validationError bool
-	// If the validation fails and we are the vendor, we send a REJECT message back
-	// to the buyer. The reject message also gets saved with this order.
-	if err := op.validateOrderOpen(dbtx, orderOpen, order.ID, order.Role()); err != nil {
+	var (
+		validationError bool
+		blocked         bool
+	)
+	if order.Role() == models.RoleVendor && op.isBlocked(orderOpen.BuyerID.PeerID) {
+		// If the buyer is blocked we automatically reject the order. The buyer
+		// is sent the reason so they aren't left waiting on a response.
+		log.Infof("Rejecting ORDER_OPEN message for order %s from blocked peer %s", order.ID, orderOpen.BuyerID.PeerID)
+		if err := op.sendAutoReject(dbtx, order, peer, pb.OrderReject_USER_REJECT, blockedBuyerRejectReason); err != nil {
+			return nil, err
+		}
+		blocked = true
+	} else if err := op.validateOrderOpen(dbtx, orderOpen, order.ID, order.Role()); err != nil {
+		// If the validation fails and we are the vendor, we send a REJECT message back
+		// to the buyer. The reject message also gets saved with this order.
 		log.Errorf("ORDER_OPEN message for order %s from %s failed to validate: %s", order.ID, orderOpen.BuyerID.PeerID, err)
 		if order.Role() == models.RoleVendor {
-			reject := pb.OrderReject{
-				Type:      pb.OrderReject_VALIDATION_ERROR,
-				Reason:    err.Error(),
-				Timestamp: ptypes.TimestampNow(),
-			}
-
-			rejectAny, err := ptypes.MarshalAny(&reject)
-			if err != nil {
-				return nil, err
-			}
-
-			resp := npb.OrderMessage{
-				OrderID:     order.ID.String(),
-				MessageType: npb.OrderMessage_ORDER_REJECT,
-				Message:     rejectAny,
-			}
-
-			if err := utils.SignOrderMessage(&resp, op.identityPrivateKey); err != nil {
-				return nil, err
-			}
-
-			payload, err := ptypes.MarshalAny(&resp)
-			if err != nil {
-				return nil, err
-			}
-
-			messageID := make([]byte, 20)
-			if _, err := rand.Read(messageID); err != nil {
-				return nil, err
-			}
-
-			message := npb.Message{
-				MessageType: npb.Message_ORDER,
-				MessageID:   hex.EncodeToString(messageID),
-				Payload:     payload,
-			}
-
-			if err := op.messenger.ReliablySendMessage(dbtx, peer, &message, nil); err != nil {
-				return nil, err
-			}
-
-			if err := order.PutMessage(&resp); err != nil {
+			if err := op.sendAutoReject(dbtx, order, peer, pb.OrderReject_VALIDATION_ERROR, err.Error()); err != nil {
 				return nil, err
 			}
 		}
@@ -107,7 +81,14 @@ func (op *OrderProcessor) processOrderOpenMessage(dbtx database.Tx, order *model
 
 	var event interface{}
 	// TODO: do we want to emit an event in the case of a validation error?
-	if !validationError && op.identity != peer {
+	if blocked {
+		event = &events.BlockedOrderRejected{
+			BuyerHandle: orderOpen.BuyerID.Handle,
+			BuyerID:     orderOpen.BuyerID.PeerID,
+			OrderID:     message.OrderID,
+			Reason:      blockedBuyerRejectReason,
+		}
+	} else if !validationError && op.identity != peer {
 		event = &events.NewOrder{
 			BuyerHandle: orderOpen.BuyerID.Handle,
 			BuyerID:     orderOpen.BuyerID.PeerID,
@@ -134,6 +115,12 @@ func (op *OrderProcessor) processOrderOpenMessage(dbtx database.Tx, order *model
 		order.PaymentAddress = orderOpen.Payment.Address
 	}
 
+	if !blocked && !validationError {
+		if err := op.saveRateSnapshot(dbtx, order, models.RateEventOrderOpen, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	wallet, err := op.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
 	if err != nil {
 		return nil, err
@@ -703,3 +690,62 @@ func getSelectedSku(listing *pb.Listing, options []*pb.OrderOpen_Item_Option) (*
 	}
 	return nil, errors.New("selected sku not found in listing")
 }
+
+// sendAutoReject sends an ORDER_REJECT message to the buyer on behalf of the
+// vendor and saves it with the order.
+func (op *OrderProcessor) sendAutoReject(dbtx database.Tx, order *models.Order, peer peer.ID, rejectType pb.OrderReject_RejectType, reason string) error {
+	reject := pb.OrderReject{
+		Type:      rejectType,
+		Reason:    reason,
+		Timestamp: ptypes.TimestampNow(),
+	}
+
+	rejectAny, err := ptypes.MarshalAny(&reject)
+	if err != nil {
+		return err
+	}
+
+	resp := npb.OrderMessage{
+		OrderID:     order.ID.String(),
+		MessageType: npb.OrderMessage_ORDER_REJECT,
+		Message:     rejectAny,
+	}
+
+	if err := utils.SignOrderMessage(&resp, op.identityPrivateKey); err != nil {
+		return err
+	}
+
+	payload, err := ptypes.MarshalAny(&resp)
+	if err != nil {
+		return err
+	}
+
+	messageID := make([]byte, 20)
+	if _, err := rand.Read(messageID); err != nil {
+		return err
+	}
+
+	message := npb.Message{
+		MessageType: npb.Message_ORDER,
+		MessageID:   hex.EncodeToString(messageID),
+		Payload:     payload,
+	}
+
+	if err := op.messenger.ReliablySendMessage(dbtx, peer, &message, nil); err != nil {
+		return err
+	}
+
+	return order.PutMessage(&resp)
+}
+
+// isBlocked returns whether the peer ID is on our block list.
+func (op *OrderProcessor) isBlocked(peerID string) bool {
+	if op.banManager == nil {
+		return false
+	}
+	pid, err := peer.Decode(peerID)
+	if err != nil {
+		return false
+	}
+	return op.banManager.IsBanned(pid)
+}
@@ -202,6 +202,73 @@ func TestOrderProcessor_processOrderOpenMessage(t *testing.T) {
 	}
 }
 
+func TestOrderProcessor_processOrderOpenMessageBlockedBuyer(t *testing.T) {
+	op, teardown, err := newMockOrderProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	err = op.db.Update(func(tx database.Tx) error {
+		sl := factory.NewSignedListing()
+		return tx.SetListing(sl)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderOpen, err := factory.NewOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyer, err := peer.Decode(orderOpen.BuyerID.PeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op.banManager.AddBlockedID(buyer)
+
+	orderMsg := &npb.OrderMessage{
+		OrderID:     "1234",
+		MessageType: npb.OrderMessage_ORDER_OPEN,
+		Message:     mustBuildAny(orderOpen),
+	}
+
+	order := &models.Order{}
+	err = op.db.Update(func(tx database.Tx) error {
+		event, err := op.processOrderOpenMessage(tx, order, buyer, orderMsg)
+		if err != nil {
+			return err
+		}
+		expected := &events.BlockedOrderRejected{
+			BuyerHandle: orderOpen.BuyerID.Handle,
+			BuyerID:     orderOpen.BuyerID.PeerID,
+			OrderID:     "1234",
+			Reason:      blockedBuyerRejectReason,
+		}
+		if !reflect.DeepEqual(event, expected) {
+			t.Errorf("Incorrect event returned. Expected %v, got %v", expected, event)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reject, err := order.OrderRejectMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reject.Type != pb.OrderReject_USER_REJECT {
+		t.Errorf("Expected reject type %s, got %s", pb.OrderReject_USER_REJECT, reject.Type)
+	}
+	if reject.Reason != blockedBuyerRejectReason {
+		t.Errorf("Expected reject reason %s, got %s", blockedBuyerRejectReason, reject.Reason)
+	}
+	if order.SerializedOrderOpen == nil {
+		t.Error("Failed to save order open message to the order")
+	}
+}
+
 func Test_convertCurrencyAmount(t *testing.T) {
 	erp, err := wallet.NewMockExchangeRates()
 	if err != nil {
@@ -57,6 +57,9 @@ func (op *OrderProcessor) processOrderRejectMessage(dbtx database.Tx, order *mod
 		log.Infof("Received ORDER_REJECT message for order %s", order.ID)
 	} else if order.Role() == models.RoleVendor {
 		log.Infof("Processed own ORDER_REJECT for orderID: %s", order.ID)
+		if err := op.restoreOrderInventory(dbtx, order, orderOpen); err != nil {
+			return nil, err
+		}
 	}
 
 	return event, order.PutMessage(message)
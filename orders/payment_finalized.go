@@ -0,0 +1,171 @@
+package orders
+
+import (
+	"errors"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	npb "github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+func (op *OrderProcessor) processPaymentFinalizedMessage(dbtx database.Tx, order *models.Order, peer peer.ID, message *npb.OrderMessage) (interface{}, error) {
+	paymentFinalized := new(pb.PaymentFinalized)
+	if err := ptypes.UnmarshalAny(message.Message, paymentFinalized); err != nil {
+		return nil, err
+	}
+	dup, err := isDuplicate(paymentFinalized, order.SerializedPaymentFinalized)
+	if err != nil {
+		return nil, err
+	}
+	if order.SerializedPaymentFinalized != nil && !dup {
+		log.Errorf("Duplicate PAYMENT_FINALIZED message does not match original for order: %s", order.ID)
+		return nil, ErrChangedMessage
+	} else if dup {
+		return nil, nil
+	}
+
+	if order.SerializedOrderComplete != nil {
+		log.Errorf("Received PAYMENT_FINALIZED message for order %s after ORDER_COMPLETE", order.ID)
+		return nil, ErrUnexpectedMessage
+	}
+
+	if order.SerializedOrderCancel != nil {
+		log.Errorf("Received PAYMENT_FINALIZED message for order %s after ORDER_CANCEL", order.ID)
+		return nil, ErrUnexpectedMessage
+	}
+
+	if order.SerializedOrderReject != nil {
+		log.Errorf("Received PAYMENT_FINALIZED message for order %s after ORDER_REJECT", order.ID)
+		return nil, ErrUnexpectedMessage
+	}
+
+	if order.SerializedDisputeOpen != nil {
+		log.Errorf("Received PAYMENT_FINALIZED message for order %s after DISPUTE_OPEN", order.ID)
+		return nil, ErrUnexpectedMessage
+	}
+
+	orderOpen, err := order.OrderOpenMessage()
+	if models.IsMessageNotExistError(err) {
+		return nil, order.ParkMessage(message)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if orderOpen.Payment.Method != pb.OrderOpen_Payment_MODERATED {
+		return nil, errors.New("payment finalized processed for non-moderated order")
+	}
+
+	if orderOpen.Listings[0].Listing.VendorID.PeerID != peer.Pretty() {
+		return nil, errors.New("payment finalized not sent by the order's vendor")
+	}
+
+	// The vendor can only release the funds after fulfilling the order. The
+	// fulfillment may not have arrived yet.
+	fulfilled, err := order.IsFulfilled()
+	if err != nil {
+		return nil, err
+	}
+	if !fulfilled {
+		return nil, order.ParkMessage(message)
+	}
+
+	// Our wallet may not have seen the transaction releasing the funds yet. If
+	// not we park the message and process it again when the transaction arrives.
+	released, err := op.isEscrowReleased(order, orderOpen)
+	if err != nil {
+		return nil, err
+	}
+	if !released {
+		log.Debugf("Received PAYMENT_FINALIZED message for order %s before escrow release transaction", order.ID)
+		return nil, order.ParkMessage(message)
+	}
+
+	log.Infof("Received PAYMENT_FINALIZED message for order %s", order.ID)
+
+	event := &events.VendorFinalizedPayment{
+		OrderID: order.ID.String(),
+	}
+	return event, order.PutMessage(message)
+}
+
+// isEscrowReleased returns whether a transaction spending from the order's escrow
+// address exists. If the transaction is not yet recorded in the order, we check the
+// wallet for it and record it if found.
+func (op *OrderProcessor) isEscrowReleased(order *models.Order, orderOpen *pb.OrderOpen) (bool, error) {
+	spendsFromEscrow := func(tx iwallet.Transaction) bool {
+		for _, from := range tx.From {
+			if from.Address.String() == orderOpen.Payment.Address {
+				return true
+			}
+		}
+		return false
+	}
+
+	txs, err := order.GetTransactions()
+	if err != nil && !models.IsMessageNotExistError(err) {
+		return false, err
+	}
+	for _, tx := range txs {
+		if spendsFromEscrow(tx) {
+			return true, nil
+		}
+	}
+
+	wallet, err := op.multiwallet.WalletForCurrencyCode(orderOpen.Payment.Coin)
+	if err != nil {
+		return false, err
+	}
+	addrTxs, err := wallet.GetAddressTransactions(iwallet.NewAddress(orderOpen.Payment.Address, iwallet.CoinType(orderOpen.Payment.Coin)))
+	if err != nil {
+		return false, err
+	}
+	for _, tx := range addrTxs {
+		if !spendsFromEscrow(tx) {
+			continue
+		}
+		if err := order.PutTransaction(tx); err != nil && !models.IsDuplicateTransactionError(err) {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// processParkedPaymentFinalized processes a PAYMENT_FINALIZED message that was parked
+// because we had not yet seen the transaction releasing the funds from escrow.
+func (op *OrderProcessor) processParkedPaymentFinalized(dbtx database.Tx, order *models.Order) error {
+	if order.SerializedPaymentFinalized != nil || order.Role() != models.RoleBuyer {
+		return nil
+	}
+
+	parkedMessages, err := order.GetParkedMessages()
+	if err != nil {
+		return err
+	}
+
+	for _, parked := range parkedMessages {
+		if parked.MessageType != npb.OrderMessage_PAYMENT_FINALIZED {
+			continue
+		}
+		vendor, err := order.Vendor()
+		if err != nil {
+			return err
+		}
+		event, err := op.processMessage(dbtx, order, vendor, parked)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			dbtx.RegisterCommitHook(func() {
+				op.bus.Emit(event)
+			})
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package orders
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/events"
+	"github.com/cpacia/openbazaar3.0/models"
+	npb "github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	iwallet "github.com/cpacia/wallet-interface"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"reflect"
+	"testing"
+)
+
+func TestOrderProcessor_processPaymentFinalizedMessage(t *testing.T) {
+	op, teardown, err := newMockOrderProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	_, vendorPub, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorPeer, err := peer.IDFromPublicKey(vendorPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderID := "1234"
+	paymentAddress := "abc"
+
+	orderOpen := &pb.OrderOpen{
+		Listings: []*pb.SignedListing{
+			{
+				Listing: &pb.Listing{
+					VendorID: &pb.ID{
+						PeerID: vendorPeer.Pretty(),
+					},
+				},
+			},
+		},
+		Items: []*pb.OrderOpen_Item{{}},
+		BuyerID: &pb.ID{
+			PeerID: op.identity.Pretty(),
+		},
+		Payment: &pb.OrderOpen_Payment{
+			Address: paymentAddress,
+			Coin:    iwallet.CtMock,
+			Method:  pb.OrderOpen_Payment_MODERATED,
+		},
+	}
+
+	paymentFinalizedMsg := &npb.OrderMessage{
+		OrderID:     orderID,
+		MessageType: npb.OrderMessage_PAYMENT_FINALIZED,
+		Message:     mustBuildAny(&pb.PaymentFinalized{}),
+	}
+
+	openAndFulfill := func(order *models.Order) error {
+		order.ID = models.OrderID(orderID)
+		order.SetRole(models.RoleBuyer)
+		err := order.PutMessage(&npb.OrderMessage{
+			Signature:   []byte("abc"),
+			Message:     mustBuildAny(orderOpen),
+			MessageType: npb.OrderMessage_ORDER_OPEN,
+		})
+		if err != nil {
+			return err
+		}
+		return order.PutMessage(&npb.OrderMessage{
+			Signature: []byte("abc"),
+			Message: mustBuildAny(&pb.OrderFulfillment{
+				Fulfillments: []*pb.OrderFulfillment_FulfilledItem{{ItemIndex: 0}},
+			}),
+			MessageType: npb.OrderMessage_ORDER_FULFILLMENT,
+		})
+	}
+
+	openFulfillAndRelease := func(order *models.Order) error {
+		if err := openAndFulfill(order); err != nil {
+			return err
+		}
+		return order.PutTransaction(iwallet.Transaction{
+			ID: "1111",
+			From: []iwallet.SpendInfo{
+				{
+					ID:      []byte{0x01},
+					Address: iwallet.NewAddress(paymentAddress, iwallet.CtMock),
+					Amount:  iwallet.NewAmount(1000),
+				},
+			},
+		})
+	}
+
+	tests := []struct {
+		setup         func(order *models.Order) error
+		peer          peer.ID
+		expectedError error
+		expectError   bool
+		expectedEvent interface{}
+		expectParked  bool
+	}{
+		{
+			// Normal case where the escrow release was recorded.
+			setup:         openFulfillAndRelease,
+			peer:          vendorPeer,
+			expectedError: nil,
+			expectedEvent: &events.VendorFinalizedPayment{
+				OrderID: orderID,
+			},
+		},
+		{
+			// Escrow release transaction has not been seen yet.
+			setup:         openAndFulfill,
+			peer:          vendorPeer,
+			expectedError: nil,
+			expectedEvent: nil,
+			expectParked:  true,
+		},
+		{
+			// Out of order.
+			setup: func(order *models.Order) error {
+				return nil
+			},
+			peer:          vendorPeer,
+			expectedError: nil,
+			expectedEvent: nil,
+			expectParked:  true,
+		},
+		{
+			// Duplicate payment finalized.
+			setup: func(order *models.Order) error {
+				return order.PutMessage(paymentFinalizedMsg)
+			},
+			peer:          vendorPeer,
+			expectedError: nil,
+			expectedEvent: nil,
+		},
+		{
+			// Changed duplicate payment finalized.
+			setup: func(order *models.Order) error {
+				order.SerializedPaymentFinalized = []byte{0x00}
+				return nil
+			},
+			peer:          vendorPeer,
+			expectedError: ErrChangedMessage,
+			expectedEvent: nil,
+		},
+		{
+			// OrderComplete already exists.
+			setup: func(order *models.Order) error {
+				order.SerializedOrderComplete = []byte{0x00}
+				return nil
+			},
+			peer:          vendorPeer,
+			expectedError: ErrUnexpectedMessage,
+			expectedEvent: nil,
+		},
+		{
+			// DisputeOpen already exists.
+			setup: func(order *models.Order) error {
+				order.SerializedDisputeOpen = []byte{0x00}
+				return nil
+			},
+			peer:          vendorPeer,
+			expectedError: ErrUnexpectedMessage,
+			expectedEvent: nil,
+		},
+		{
+			// Sent by someone other than the vendor.
+			setup:         openFulfillAndRelease,
+			peer:          op.identity,
+			expectError:   true,
+			expectedEvent: nil,
+		},
+	}
+
+	for i, test := range tests {
+		order := &models.Order{}
+		if err := test.setup(order); err != nil {
+			t.Errorf("Test %d setup error: %s", i, err)
+			continue
+		}
+		err := op.db.Update(func(tx database.Tx) error {
+			event, err := op.processPaymentFinalizedMessage(tx, order, test.peer, paymentFinalizedMsg)
+			if test.expectError {
+				if err == nil {
+					return fmt.Errorf("expected error, got nil")
+				}
+			} else if err != test.expectedError {
+				return fmt.Errorf("incorrect error returned. Expected %t, got %t", test.expectedError, err)
+			}
+			if !reflect.DeepEqual(event, test.expectedEvent) {
+				return fmt.Errorf("incorrect event returned")
+			}
+			parked, err := order.GetParkedMessages()
+			if err != nil {
+				return err
+			}
+			if test.expectParked && len(parked) != 1 {
+				return fmt.Errorf("expected message to be parked")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Error executing db update in test %d: %s", i, err)
+		}
+	}
+}
@@ -35,6 +35,12 @@ func (op *OrderProcessor) processPaymentSentMessage(dbtx database.Tx, order *mod
 		return nil, err
 	}
 
+	// After the dispute is closed the PAYMENT_SENT message is used by the other
+	// party to tell us they accepted the moderator's payout.
+	if order.SerializedDisputeClosed != nil {
+		return op.processDisputePayoutSent(dbtx, order, orderOpen, wallet, payment)
+	}
+
 	txs, err := order.GetTransactions()
 	if err != nil && !models.IsMessageNotExistError(err) {
 		return nil, err
@@ -68,3 +74,44 @@ func (op *OrderProcessor) processPaymentSentMessage(dbtx database.Tx, order *mod
 	}
 	return event, nil
 }
+
+// processDisputePayoutSent handles a PAYMENT_SENT message informing us that the other
+// party countersigned and broadcast the moderator's escrow release.
+func (op *OrderProcessor) processDisputePayoutSent(dbtx database.Tx, order *models.Order, orderOpen *pb.OrderOpen, wallet iwallet.Wallet, payment *pb.PaymentSent) (interface{}, error) {
+	// If this fails it's OK as the processor's unfunded order checking loop will
+	// pick up the transaction at it's next interval.
+	tx, err := wallet.GetTransaction(iwallet.TransactionID(payment.TransactionID))
+	if err == nil {
+		for _, from := range tx.From {
+			if from.Address.String() == order.PaymentAddress {
+				if err := op.processOutgoingPayment(dbtx, order, tx); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	var (
+		otherParty       = orderOpen.Listings[0].Listing.VendorID.PeerID
+		otherPartyHandle = orderOpen.Listings[0].Listing.VendorID.Handle
+	)
+	if order.Role() == models.RoleVendor {
+		otherParty = orderOpen.BuyerID.PeerID
+		otherPartyHandle = orderOpen.BuyerID.Handle
+	}
+
+	log.Infof("Received dispute payout acceptance for order %s", order.ID)
+
+	event := &events.DisputeAccepted{
+		OrderID: order.ID.String(),
+		Thumbnail: events.Thumbnail{
+			Tiny:  orderOpen.Listings[0].Listing.Item.Images[0].Tiny,
+			Small: orderOpen.Listings[0].Listing.Item.Images[0].Small,
+		},
+		OherPartyID:      otherParty,
+		OtherPartyHandle: otherPartyHandle,
+		Buyer:            orderOpen.BuyerID.PeerID,
+	}
+	return event, nil
+}
@@ -124,6 +124,55 @@ func Test_processPaymentSentMessage(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			// Other party accepted the dispute payout.
+			setup: func(order *models.Order) error {
+				order.ID = "1234"
+				order.PaymentAddress = addr.String()
+				err := order.PutMessage(&npb.OrderMessage{
+					Signature: []byte("abc"),
+					Message: mustBuildAny(&pb.OrderOpen{
+						Listings: []*pb.SignedListing{
+							{
+								Listing: &pb.Listing{
+									Item: &pb.Listing_Item{
+										Images: []*pb.Listing_Item_Image{{Tiny: "bbbb", Small: "aaaa"}},
+									},
+									VendorID: &pb.ID{PeerID: "vendor", Handle: "@vendor"},
+								},
+							},
+						},
+						BuyerID: &pb.ID{PeerID: "buyer"},
+						Payment: &pb.OrderOpen_Payment{
+							Coin:   "MCK",
+							Amount: "1000",
+						},
+					}),
+				})
+				if err != nil {
+					return err
+				}
+				return order.PutMessage(&npb.OrderMessage{
+					Signature:   []byte("abc"),
+					Message:     mustBuildAny(&pb.DisputeClose{Verdict: "Buyer wins"}),
+					MessageType: npb.OrderMessage_DISPUTE_CLOSE,
+				})
+			},
+			expectedError: nil,
+			expectedEvent: &events.DisputeAccepted{
+				OrderID:          "1234",
+				Thumbnail:        events.Thumbnail{Tiny: "bbbb", Small: "aaaa"},
+				OherPartyID:      "vendor",
+				OtherPartyHandle: "@vendor",
+				Buyer:            "buyer",
+			},
+			checkTxs: func(order *models.Order) error {
+				if order.SerializedPaymentSent == nil {
+					return errors.New("failed to save payment sent message")
+				}
+				return nil
+			},
+		},
 		{
 			// Out of order.
 			setup: func(order *models.Order) error {
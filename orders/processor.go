@@ -11,6 +11,7 @@ import (
 	"github.com/cpacia/openbazaar3.0/models"
 	"github.com/cpacia/openbazaar3.0/net"
 	npb "github.com/cpacia/openbazaar3.0/net/pb"
+	"github.com/cpacia/openbazaar3.0/orders/utils"
 	"github.com/cpacia/openbazaar3.0/wallet"
 	iwallet "github.com/cpacia/wallet-interface"
 	"github.com/golang/protobuf/jsonpb"
@@ -22,6 +23,7 @@ import (
 	"github.com/op/go-logging"
 	"gorm.io/gorm"
 	"sort"
+	"sync"
 	"time"
 )
 
@@ -40,6 +42,7 @@ type Config struct {
 	IdentityPrivateKey   crypto.PrivKey
 	EscrowPrivateKey     *btcec.PrivateKey
 	Messenger            *net.Messenger
+	BanManager           *net.BanManager
 	Multiwallet          multiwallet.Multiwallet
 	ExchangeRateProvider *wallet.ExchangeRateProvider
 	EventBus             events.Bus
@@ -52,12 +55,16 @@ type OrderProcessor struct {
 	identityPrivateKey crypto.PrivKey
 	db                 database.Database
 	messenger          *net.Messenger
+	banManager         *net.BanManager
 	multiwallet        multiwallet.Multiwallet
 	escrowPrivateKey   *btcec.PrivateKey
 	erp                *wallet.ExchangeRateProvider
 	bus                events.Bus
 	calcCIDFunc        func(file []byte) (cid.Cid, error)
 	shutdown           chan struct{}
+
+	escrowTimeoutWarned map[string]time.Duration
+	escrowTimeoutMtx    sync.Mutex
 }
 
 // NewOrderProcessor initializes and returns a new OrderProcessor
@@ -67,19 +74,36 @@ func NewOrderProcessor(cfg *Config) *OrderProcessor {
 		identityPrivateKey: cfg.IdentityPrivateKey,
 		db:                 cfg.Db,
 		messenger:          cfg.Messenger,
+		banManager:         cfg.BanManager,
 		multiwallet:        cfg.Multiwallet,
 		escrowPrivateKey:   cfg.EscrowPrivateKey,
 		erp:                cfg.ExchangeRateProvider,
 		bus:                cfg.EventBus,
 		calcCIDFunc:        cfg.CalcCIDFunc,
 		shutdown:           make(chan struct{}),
+
+		escrowTimeoutWarned: make(map[string]time.Duration),
 	}
 }
 
 // Start begins listening for transactions from the wallets that pertain to our
-// orders. When we find one we record the payment.
+// orders. When we find one we record the payment. It also periodically checks
+// for orders approaching their escrow timeout.
 func (op *OrderProcessor) Start() {
 	go op.checkForMorePayments()
+	go func() {
+		op.checkEscrowTimeouts()
+		escrowTicker := time.NewTicker(escrowTimeoutCheckInterval)
+		for {
+			select {
+			case <-escrowTicker.C:
+				op.checkEscrowTimeouts()
+			case <-op.shutdown:
+				escrowTicker.Stop()
+				return
+			}
+		}
+	}()
 	ticker := time.NewTicker(rescanTransactionsInterval)
 	for _, wallet := range op.multiwallet {
 		go func(w iwallet.Wallet) {
@@ -224,7 +248,7 @@ func (op *OrderProcessor) ProcessACK(tx database.Tx, om *models.OutgoingMessage)
 
 // processMessage passes the message off to the appropriate handler.
 func (op *OrderProcessor) processMessage(dbtx database.Tx, order *models.Order, peer peer.ID, message *npb.OrderMessage) (event interface{}, err error) {
-	err = verifyOrderMessageSignature(peer, message)
+	err = utils.VerifyOrderMessageSignature(message, peer)
 	if err != nil {
 		return nil, err
 	}
@@ -249,6 +273,10 @@ func (op *OrderProcessor) processMessage(dbtx database.Tx, order *models.Order,
 		event, err = op.processOrderCompleteMessage(dbtx, order, peer, message)
 	case npb.OrderMessage_DISPUTE_OPEN:
 		event, err = op.processDisputeOpenMessage(dbtx, order, peer, message)
+	case npb.OrderMessage_DISPUTE_CLOSE:
+		event, err = op.processDisputeCloseMessage(dbtx, order, peer, message)
+	case npb.OrderMessage_PAYMENT_FINALIZED:
+		event, err = op.processPaymentFinalizedMessage(dbtx, order, peer, message)
 
 	default:
 		return nil, errors.New("unknown order message type")
@@ -271,25 +299,3 @@ func isDuplicate(message proto.Message, serialized []byte) (bool, error) {
 
 	return bytes.Equal([]byte(ser), serialized), nil
 }
-
-func verifyOrderMessageSignature(peer peer.ID, message *npb.OrderMessage) error {
-	peerPubkey, err := peer.ExtractPublicKey()
-
-	msgCpy := *message
-	msgCpy.Signature = nil
-
-	ser, err := proto.Marshal(&msgCpy)
-	if err != nil {
-		return err
-	}
-
-	valid, err := peerPubkey.Verify(ser, message.Signature)
-	if err != nil {
-		return err
-	}
-
-	if !valid {
-		return errors.New("invalid signature")
-	}
-	return nil
-}
@@ -34,6 +34,11 @@ func (op *OrderProcessor) processRefundMessage(dbtx database.Tx, order *models.O
 		return nil, err
 	}
 
+	previousRefunds, err := order.Refunds()
+	if err != nil && !models.IsMessageNotExistError(err) {
+		return nil, err
+	}
+
 	if err := order.PutMessage(message); err != nil {
 		if models.IsDuplicateTransactionError(err) {
 			return nil, nil
@@ -69,6 +74,12 @@ func (op *OrderProcessor) processRefundMessage(dbtx database.Tx, order *models.O
 		log.Infof("Received REFUND message for order %s", order.ID)
 	} else if order.Role() == models.RoleVendor {
 		log.Infof("Processed own REFUND for order %s", order.ID)
+		// A rejected order has already had its inventory restored.
+		if len(previousRefunds) == 0 && order.SerializedOrderReject == nil {
+			if err := op.restoreOrderInventory(dbtx, order, orderOpen); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	event := &events.Refund{
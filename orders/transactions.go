@@ -35,6 +35,10 @@ func (op *OrderProcessor) processWalletTransaction(transaction iwallet.Transacti
 				return err
 			}
 
+			if err := backfillTransactionMetadata(tx, &order, transaction.ID, to.Address.String()); err != nil {
+				return err
+			}
+
 			if err := tx.Save(&order); err != nil {
 				return err
 			}
@@ -64,9 +68,32 @@ func (op *OrderProcessor) processWalletTransaction(transaction iwallet.Transacti
 	}
 }
 
+// backfillTransactionMetadata saves metadata linking a payment to its order
+// unless metadata was already saved for the transaction.
+func backfillTransactionMetadata(dbtx database.Tx, order *models.Order, txid iwallet.TransactionID, address string) error {
+	var existing models.TransactionMetadata
+	err := dbtx.Read().Where("txid = ?", txid.String()).First(&existing).Error
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	metadata := models.TransactionMetadata{
+		Txid:           txid,
+		PaymentAddress: address,
+	}
+	metadata.SetOrder(order)
+	return dbtx.Save(&metadata)
+}
+
 // processIncomingPayment processes payments into an order's payment address.
 func (op *OrderProcessor) processIncomingPayment(dbtx database.Tx, order *models.Order, tx iwallet.Transaction) error {
-	err := order.PutTransaction(tx)
+	wasFunded, err := order.IsFunded()
+	if err != nil {
+		return err
+	}
+
+	err = order.PutTransaction(tx)
 	if models.IsDuplicateTransactionError(err) {
 		log.Debugf("Received duplicate transaction %s", tx.ID.String())
 		return nil
@@ -74,6 +101,10 @@ func (op *OrderProcessor) processIncomingPayment(dbtx database.Tx, order *models
 		return err
 	}
 
+	if err := op.saveRateSnapshot(dbtx, order, models.RateEventPayment, tx.ID.String()); err != nil {
+		return err
+	}
+
 	funded, err := order.IsFunded()
 	if err != nil {
 		return err
@@ -153,7 +184,18 @@ func (op *OrderProcessor) processIncomingPayment(dbtx database.Tx, order *models
 
 	case models.RoleVendor:
 		if funded {
-			// TODO: mark vendor inventory downwards is not wasFunded.
+			status := order.Status()
+			if !wasFunded && status != models.StatusRejected && status != models.StatusCanceled {
+				slugs, err := DecrementInventory(dbtx, orderOpen)
+				if err != nil {
+					return err
+				}
+				if len(slugs) > 0 {
+					dbtx.RegisterCommitHook(func() {
+						op.bus.Emit(&events.InventoryUpdated{Slugs: slugs})
+					})
+				}
+			}
 
 			if err := op.sendRatingSignatures(dbtx, order, orderOpen); err != nil {
 				log.Errorf("Error sending rating signature message: %s", err)
@@ -193,10 +235,16 @@ func (op *OrderProcessor) processOutgoingPayment(dbtx database.Tx, order *models
 		log.Debugf("Received duplicate transaction %s", tx.ID.String())
 		return nil
 	}
+	if err != nil {
+		return err
+	}
 	dbtx.RegisterCommitHook(func() {
 		op.bus.Emit(&events.SpendFromPaymentAddress{Transaction: tx})
 	})
-	return err
+	if err := op.processParkedPaymentFinalized(dbtx, order); err != nil {
+		log.Errorf("Error processing parked PAYMENT_FINALIZED message for order %s: %s", order.ID, err)
+	}
+	return nil
 }
 
 // checkForMorePayments loads open orders from the database and checks to see if it can find any more
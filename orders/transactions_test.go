@@ -163,6 +163,16 @@ func TestOrderProcessor_processWalletTransaction(t *testing.T) {
 				if !funded {
 					return errors.New("failed to set order as funded")
 				}
+				var metadata models.TransactionMetadata
+				err = op.db.View(func(tx database.Tx) error {
+					return tx.Read().Where("txid = ?", "5678").First(&metadata).Error
+				})
+				if err != nil {
+					return err
+				}
+				if metadata.OrderID != "1234" || metadata.PaymentAddress != "abcd" || metadata.Memo == "" || metadata.Thumbnail == "" {
+					return errors.New("failed to backfill transaction metadata")
+				}
 				return nil
 			},
 		},
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"github.com/cpacia/openbazaar3.0/orders/pb"
+	"time"
+)
+
+// EscrowTimeout returns the escrow timeout for the order. This is the longest
+// escrow timeout of the listings in the order. Zero means the escrow does not
+// have a timeout.
+func EscrowTimeout(orderOpen *pb.OrderOpen) time.Duration {
+	var hours uint32
+	for _, sl := range orderOpen.Listings {
+		if sl.Listing == nil || sl.Listing.Metadata == nil {
+			continue
+		}
+		if sl.Listing.Metadata.EscrowTimeoutHours > hours {
+			hours = sl.Listing.Metadata.EscrowTimeoutHours
+		}
+	}
+	return time.Hour * time.Duration(hours)
+}
+
+// EscrowExpiry returns the time at which the escrow timeout expires and the vendor
+// can release the funds using the timeout key. The timeout runs from the most recent
+// of the provided funding transaction times. The zero time is returned if the escrow
+// does not have a timeout or has not been funded.
+func EscrowExpiry(orderOpen *pb.OrderOpen, fundingTimes []time.Time) time.Time {
+	timeout := EscrowTimeout(orderOpen)
+	if timeout == 0 || len(fundingTimes) == 0 {
+		return time.Time{}
+	}
+	var latest time.Time
+	for _, t := range fundingTimes {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest.Add(timeout)
+}
@@ -1,11 +1,17 @@
 package utils
 
 import (
+	"errors"
 	"github.com/cpacia/openbazaar3.0/net/pb"
 	"github.com/golang/protobuf/proto"
 	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
 )
 
+// ErrInvalidSignature is returned when the signature on an order message
+// does not validate.
+var ErrInvalidSignature = errors.New("invalid signature")
+
 // SignOrderMessage puts a signature on an order message using the IPFS private
 // key. The protobuf serialization of the message object without the signature
 // is what is signed.
@@ -24,3 +30,30 @@ func SignOrderMessage(message *pb.OrderMessage, privKey crypto.PrivKey) error {
 	message.Signature = sig
 	return nil
 }
+
+// VerifyOrderMessageSignature checks that the signature on the order message was
+// created by the provided peer.
+func VerifyOrderMessageSignature(message *pb.OrderMessage, peer peer.ID) error {
+	peerPubkey, err := peer.ExtractPublicKey()
+	if err != nil {
+		return err
+	}
+
+	msgCpy := proto.Clone(message).(*pb.OrderMessage)
+	msgCpy.Signature = nil
+
+	ser, err := proto.Marshal(msgCpy)
+	if err != nil {
+		return err
+	}
+
+	valid, err := peerPubkey.Verify(ser, message.Signature)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
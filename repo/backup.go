@@ -0,0 +1,632 @@
+package repo
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/database/ffsqlite"
+	"github.com/cpacia/openbazaar3.0/models"
+	config "github.com/ipfs/go-ipfs-config"
+	"github.com/ipfs/go-ipfs/repo/fsrepo"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// backupMagic is the first bytes of every backup file.
+	backupMagic = "OBBACKUP"
+
+	// backupVersion is the version of the backup file format.
+	backupVersion = 1
+
+	// backupSaltLen is the length of the scrypt salt.
+	backupSaltLen = 16
+
+	// backupHeaderLen is the length of the magic, version and salt at the
+	// start of every backup.
+	backupHeaderLen = len(backupMagic) + 1 + backupSaltLen
+
+	// backupChunkSize is the size of the chunks the archive is split into
+	// for encryption. Each chunk is encrypted separately so that backups
+	// can be written and restored without holding them in memory.
+	backupChunkSize = 64 << 10
+
+	// backupFilePrefix and backupFileExt make up the name of backups
+	// written by BackupToDir. The timestamp between them sorts in the
+	// order the backups were written.
+	backupFilePrefix = "openbazaar-backup-"
+	backupFileExt    = ".obbak"
+	backupTimeFormat = "20060102T150405.000Z"
+
+	// ipfsConfigPath is the path of the IPFS config file within the backup.
+	ipfsConfigPath = "ipfs/config"
+
+	// BackupPassphraseEnv is the environment variable holding the passphrase
+	// used to encrypt scheduled backups and, if set, by the backup and
+	// restore commands.
+	BackupPassphraseEnv = "OB_BACKUP_PASSPHRASE"
+)
+
+var (
+	// ErrEmptyPassphrase is returned when a backup is made or restored
+	// without a passphrase.
+	ErrEmptyPassphrase = errors.New("backup passphrase must not be empty")
+
+	// ErrInvalidBackup is returned when a backup cannot be decrypted with
+	// the passphrase or is not a valid backup.
+	ErrInvalidBackup = errors.New("invalid backup")
+
+	// ErrDataDirNotEmpty is returned when restoring into a data directory
+	// which is not empty.
+	ErrDataDirNotEmpty = errors.New("data directory is not empty")
+)
+
+// Backup writes an encrypted archive of the repo to the writer. The archive
+// holds a snapshot of the database, the public data directory, the
+// openbazaar.conf file and the IPFS config and keystore. The database and
+// public data are copied inside the same database transaction so they are
+// consistent with each other.
+//
+// The archive is encrypted with AES-GCM using a key derived from the
+// passphrase with scrypt. It is streamed to the writer as it is built.
+func (r *Repo) Backup(w io.Writer, passphrase string) error {
+	if passphrase == "" {
+		return ErrEmptyPassphrase
+	}
+
+	tmpDir, err := ioutil.TempDir("", "openbazaar-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Copy the database and public data to the temp directory so the
+	// database isn't locked while the archive is written.
+	var (
+		snapshot   = path.Join(tmpDir, ffsqlite.DBName)
+		publicCopy = path.Join(tmpDir, "public")
+	)
+	err = r.db.View(func(tx database.Tx) error {
+		if err := tx.Read().Exec("VACUUM INTO ?", snapshot).Error; err != nil {
+			return err
+		}
+		return copyDir(r.db.PublicDataPath(), publicCopy)
+	})
+	if err != nil {
+		return err
+	}
+
+	ew, err := newBackupWriter(w, passphrase)
+	if err != nil {
+		return err
+	}
+	var (
+		gz = gzip.NewWriter(ew)
+		tw = tar.NewWriter(gz)
+	)
+	if err := archiveFile(tw, snapshot, ffsqlite.DBName); err != nil {
+		return err
+	}
+	if err := archiveDir(tw, publicCopy, "public"); err != nil {
+		return err
+	}
+	for _, name := range []string{versionFileName, defaultConfigFilename, ipfsConfigPath} {
+		err := archiveFile(tw, path.Join(r.dataDir, name), name)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := archiveDir(tw, path.Join(r.dataDir, "ipfs", "keystore"), "ipfs/keystore"); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return ew.Close()
+}
+
+// BackupToDir writes a backup to a new timestamped file in the directory
+// and returns its path. The oldest backups in the directory are then deleted
+// so that at most keep remain. If keep is less than one no backups are
+// deleted.
+func (r *Repo) BackupToDir(dir, passphrase string, keep int) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	tmp, err := ioutil.TempFile(dir, backupFilePrefix+"*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := r.Backup(tmp, passphrase); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	filename := path.Join(dir, backupFilePrefix+time.Now().UTC().Format(backupTimeFormat)+backupFileExt)
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return "", err
+	}
+
+	if keep < 1 {
+		return filename, nil
+	}
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return filename, err
+	}
+	for len(backups) > keep {
+		if err := os.Remove(backups[0]); err != nil {
+			return filename, err
+		}
+		backups = backups[1:]
+	}
+	return filename, nil
+}
+
+// ListBackups returns the paths of the backups written to the directory by
+// BackupToDir, oldest first.
+func ListBackups(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasPrefix(fi.Name(), backupFilePrefix) || !strings.HasSuffix(fi.Name(), backupFileExt) {
+			continue
+		}
+		backups = append(backups, path.Join(dir, fi.Name()))
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// RestoreRepo rebuilds a repo in the data directory from a backup made with
+// Backup. The data directory must be empty or not exist. The IPFS repo is
+// initialized fresh from the backed up config using the identity key in the
+// database, and the database is migrated to the current schema.
+//
+// The backup is extracted as it is decrypted so if the restore fails the
+// contents of the data directory are removed.
+func RestoreRepo(dataDir string, backup io.Reader, passphrase string) (*Repo, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	files, err := ioutil.ReadDir(dataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(files) > 0 {
+		return nil, ErrDataDirNotEmpty
+	}
+
+	r, err := restoreRepo(dataDir, backup, passphrase)
+	if err != nil {
+		files, _ := ioutil.ReadDir(dataDir)
+		for _, fi := range files {
+			os.RemoveAll(path.Join(dataDir, fi.Name()))
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func restoreRepo(dataDir string, backup io.Reader, passphrase string) (*Repo, error) {
+	archive, err := newBackupReader(backup, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	ipfsDir := path.Join(dataDir, "ipfs")
+	if err := checkWriteable(ipfsDir); err != nil {
+		return nil, err
+	}
+	ipfsConfig, err := extractBackup(archive, dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := ffsqlite.NewFFSqliteDB(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := autoMigrateDatabase(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	var identityKey models.Key
+	err = db.View(func(tx database.Tx) error {
+		return tx.Read().Where("name = ?", "identity").First(&identityKey).Error
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: identity key not found: %s", ErrInvalidBackup, err)
+	}
+
+	if err := initializeRestoredIPFS(ipfsDir, ipfsConfig, identityKey.Value); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := CheckAndSetUlimit(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	r := &Repo{
+		dataDir: dataDir,
+		db:      db,
+	}
+	if _, err := os.Stat(path.Join(dataDir, versionFileName)); os.IsNotExist(err) {
+		if err := r.writeVersion(defaultRepoVersion); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// initializeRestoredIPFS initializes the IPFS repo with the backed up config
+// and the identity derived from our identity key. As with a new repo the
+// identity is removed from the config once the IPNS keyspace is initialized.
+func initializeRestoredIPFS(ipfsDir string, ipfsConfig, identityKey []byte) error {
+	var conf config.Config
+	if err := json.Unmarshal(ipfsConfig, &conf); err != nil {
+		return fmt.Errorf("%w: invalid ipfs config: %s", ErrInvalidBackup, err)
+	}
+	identity, err := IdentityFromKey(identityKey)
+	if err != nil {
+		return err
+	}
+	conf.Identity = identity
+	if err := fsrepo.Init(ipfsDir, &conf); err != nil {
+		return err
+	}
+	if err := initializeIpnsKeyspace(ipfsDir, identityKey); err != nil {
+		return err
+	}
+	return cleanIdentityFromConfig(ipfsDir)
+}
+
+// extractBackup writes the files in the archive into the data directory and
+// returns the IPFS config. The IPFS config is not written as the IPFS repo
+// would then appear to be initialized.
+func extractBackup(archive io.Reader, dataDir string) ([]byte, error) {
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBackup, err)
+	}
+	defer gz.Close()
+
+	var (
+		tr         = tar.NewReader(gz)
+		ipfsConfig []byte
+		foundDB    bool
+	)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidBackup, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.ToSlash(filepath.Clean(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("%w: illegal path %s", ErrInvalidBackup, hdr.Name)
+		}
+
+		switch name {
+		case ipfsConfigPath:
+			ipfsConfig, err = ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case ffsqlite.DBName:
+			foundDB = true
+		}
+
+		dest := filepath.Join(dataDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+			return nil, err
+		}
+		if err := writeFile(dest, tr, os.FileMode(hdr.Mode)&os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+	// Read to the end so that the final chunk is authenticated.
+	if _, err := io.Copy(ioutil.Discard, gz); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBackup, err)
+	}
+	if !foundDB || ipfsConfig == nil {
+		return nil, fmt.Errorf("%w: backup is missing the database or ipfs config", ErrInvalidBackup)
+	}
+	return ipfsConfig, nil
+}
+
+// writeFile writes the contents of the reader to a new file at the path.
+func writeFile(filePath string, r io.Reader, perm os.FileMode) error {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// copyDir copies all the regular files in the directory to dest. A directory
+// which doesn't exist is skipped.
+func copyDir(dir, dest string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(dir, func(filePath string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		f, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return writeFile(target, f, fi.Mode().Perm())
+	})
+}
+
+// archiveFile adds the file at the path to the archive under the name.
+func archiveFile(tw *tar.Writer, filePath, name string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// archiveDir adds all the regular files in the directory to the archive
+// under the prefix. A directory which doesn't exist is skipped.
+func archiveDir(tw *tar.Writer, dir, prefix string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(dir, func(filePath string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		return archiveFile(tw, filePath, path.Join(prefix, filepath.ToSlash(rel)))
+	})
+}
+
+// backupKey derives the encryption key from the passphrase.
+func backupKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+}
+
+// newBackupAEAD derives the key from the passphrase and the salt in the
+// header and returns the cipher used to encrypt the chunks.
+func newBackupAEAD(passphrase string, header []byte) (cipher.AEAD, error) {
+	key, err := backupKey(passphrase, header[len(backupMagic)+1:])
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// backupNonce returns the nonce for the chunk at the index. The key is unique
+// to each backup because of the random salt so the nonce only needs to be
+// unique within the backup. The last byte marks the final chunk so that a
+// truncated backup is detected.
+func backupNonce(aead cipher.AEAD, index uint32, final bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint32(nonce[len(nonce)-5:], index)
+	if final {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// backupWriter encrypts the archive as it is written. The output is the
+// header (magic, version and salt) followed by the encrypted chunks. Every
+// chunk but the last holds backupChunkSize bytes of the archive. The header
+// is authenticated as additional data with every chunk.
+type backupWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	index  uint32
+	buf    []byte
+}
+
+func newBackupWriter(w io.Writer, passphrase string) (*backupWriter, error) {
+	header := make([]byte, backupHeaderLen)
+	copy(header, backupMagic)
+	header[len(backupMagic)] = backupVersion
+	if _, err := rand.Read(header[len(backupMagic)+1:]); err != nil {
+		return nil, err
+	}
+	aead, err := newBackupAEAD(passphrase, header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &backupWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		buf:    make([]byte, 0, backupChunkSize),
+	}, nil
+}
+
+func (bw *backupWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// A full chunk is only sealed once there is more data so that
+		// Close always has a chunk to mark as final.
+		if len(bw.buf) == backupChunkSize {
+			if err := bw.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(bw.buf[len(bw.buf):backupChunkSize], p)
+		bw.buf = bw.buf[:len(bw.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close writes the final chunk.
+func (bw *backupWriter) Close() error {
+	return bw.seal(true)
+}
+
+func (bw *backupWriter) seal(final bool) error {
+	if bw.index == math.MaxUint32 {
+		return errors.New("backup is too large")
+	}
+	if _, err := bw.w.Write(bw.aead.Seal(nil, backupNonce(bw.aead, bw.index, final), bw.buf, bw.header)); err != nil {
+		return err
+	}
+	bw.index++
+	bw.buf = bw.buf[:0]
+	return nil
+}
+
+// backupReader decrypts a backup written by backupWriter as it is read. An
+// error is returned if any chunk fails to authenticate or the backup ends
+// before the final chunk.
+type backupReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	header []byte
+	index  uint32
+	chunk  []byte
+	buf    []byte
+	done   bool
+}
+
+func newBackupReader(r io.Reader, passphrase string) (*backupReader, error) {
+	header := make([]byte, backupHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(backupMagic)]) != backupMagic {
+		return nil, fmt.Errorf("%w: not an openbazaar backup", ErrInvalidBackup)
+	}
+	if header[len(backupMagic)] != backupVersion {
+		return nil, fmt.Errorf("%w: unsupported backup version %d", ErrInvalidBackup, header[len(backupMagic)])
+	}
+	aead, err := newBackupAEAD(passphrase, header)
+	if err != nil {
+		return nil, err
+	}
+	return &backupReader{
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		header: header,
+		chunk:  make([]byte, backupChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (br *backupReader) Read(p []byte) (int, error) {
+	for len(br.buf) == 0 {
+		if br.done {
+			return 0, io.EOF
+		}
+		if err := br.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, br.buf)
+	br.buf = br.buf[n:]
+	return n, nil
+}
+
+func (br *backupReader) open() error {
+	n, err := io.ReadFull(br.r, br.chunk)
+	final := err == io.EOF || err == io.ErrUnexpectedEOF
+	if err != nil && !final {
+		return err
+	}
+	if !final {
+		_, err := br.r.Peek(1)
+		final = err == io.EOF
+	}
+	plaintext, err := br.aead.Open(br.chunk[:0], backupNonce(br.aead, br.index, final), br.chunk[:n], br.header)
+	if err != nil {
+		return fmt.Errorf("%w: incorrect passphrase or corrupt backup", ErrInvalidBackup)
+	}
+	br.index++
+	br.buf = plaintext
+	br.done = final
+	return nil
+}
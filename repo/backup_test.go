@@ -0,0 +1,172 @@
+package repo
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"github.com/cpacia/openbazaar3.0/database"
+	"github.com/cpacia/openbazaar3.0/models"
+	"github.com/ipfs/go-ipfs/repo/fsrepo"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestRepo_BackupAndRestore(t *testing.T) {
+	r, err := MockRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.DestroyRepo()
+
+	err = r.DB().Update(func(tx database.Tx) error {
+		if err := tx.SetProfile(&models.Profile{Name: "Ron Swanson"}); err != nil {
+			return err
+		}
+		return tx.Save(&models.TransactionMetadata{Txid: "abc", Memo: "backup"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Random data doesn't compress so the backup is split into several
+	// encrypted chunks.
+	image := make([]byte, backupChunkSize*3)
+	if _, err := rand.Read(image); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(r.DB().PublicDataPath(), "images", "original", "photo.jpg"), image, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Backup(&buf, ""); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Errorf("Expected empty passphrase error, got %v", err)
+	}
+	if err := r.Backup(&buf, "letmein"); err != nil {
+		t.Fatal(err)
+	}
+
+	restoreDir, err := ioutil.TempDir("", "openbazaar-restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(restoreDir)
+
+	if _, err := RestoreRepo(restoreDir, bytes.NewReader(buf.Bytes()), "wrong"); !errors.Is(err, ErrInvalidBackup) {
+		t.Errorf("Expected invalid backup error, got %v", err)
+	}
+
+	// Dropping the final chunk must be detected and the partially
+	// restored files removed.
+	truncated := buf.Bytes()[:backupHeaderLen+2*(backupChunkSize+16)]
+	if _, err := RestoreRepo(restoreDir, bytes.NewReader(truncated), "letmein"); !errors.Is(err, ErrInvalidBackup) {
+		t.Errorf("Expected invalid backup error, got %v", err)
+	}
+	if files, err := ioutil.ReadDir(restoreDir); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 0 {
+		t.Errorf("Expected restore directory to be emptied, got %d files", len(files))
+	}
+
+	restored, err := RestoreRepo(restoreDir, bytes.NewReader(buf.Bytes()), "letmein")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+
+	if !fsrepo.IsInitialized(path.Join(restoreDir, "ipfs")) {
+		t.Error("IPFS repo not initialized")
+	}
+
+	var (
+		originalKey, restoredKey models.Key
+		metadata                 models.TransactionMetadata
+		profile                  *models.Profile
+	)
+	err = r.DB().View(func(tx database.Tx) error {
+		return tx.Read().Where("name = ?", "identity").First(&originalKey).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = restored.DB().View(func(tx database.Tx) error {
+		if err := tx.Read().Where("name = ?", "identity").First(&restoredKey).Error; err != nil {
+			return err
+		}
+		if err := tx.Read().Where("txid = ?", "abc").First(&metadata).Error; err != nil {
+			return err
+		}
+		profile, err = tx.GetProfile()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(originalKey.Value, restoredKey.Value) {
+		t.Error("Restored identity key does not match")
+	}
+	restoredImage, err := ioutil.ReadFile(path.Join(restored.DB().PublicDataPath(), "images", "original", "photo.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(restoredImage, image) {
+		t.Error("Restored public data does not match")
+	}
+	if metadata.Memo != "backup" {
+		t.Errorf("Expected memo backup, got %s", metadata.Memo)
+	}
+	if profile.Name != "Ron Swanson" {
+		t.Errorf("Expected profile name Ron Swanson, got %s", profile.Name)
+	}
+
+	if _, err := RestoreRepo(restoreDir, bytes.NewReader(buf.Bytes()), "letmein"); !errors.Is(err, ErrDataDirNotEmpty) {
+		t.Errorf("Expected data directory not empty error, got %v", err)
+	}
+
+	// Any file in the data directory prevents a restore, not just an
+	// initialized repo.
+	otherDir, err := ioutil.TempDir("", "openbazaar-restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(otherDir)
+	if err := ioutil.WriteFile(path.Join(otherDir, "notes.txt"), []byte("keep me"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RestoreRepo(otherDir, bytes.NewReader(buf.Bytes()), "letmein"); !errors.Is(err, ErrDataDirNotEmpty) {
+		t.Errorf("Expected data directory not empty error, got %v", err)
+	}
+}
+
+func TestRepo_BackupToDir(t *testing.T) {
+	r, err := MockRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.DestroyRepo()
+
+	dir, err := ioutil.TempDir("", "openbazaar-backups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var written []string
+	for i := 0; i < 3; i++ {
+		filename, err := r.BackupToDir(dir, "letmein", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		written = append(written, filename)
+	}
+
+	backups, err := ListBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0] != written[1] || backups[1] != written[2] {
+		t.Errorf("Expected backups %v, got %v", written[1:], backups)
+	}
+}
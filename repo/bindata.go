@@ -76,7 +76,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _bindataSampleopenbazaarConf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x5a\x5b\x73\x1b\xb7\x92\x7e\xe7\xaf\xe8\x87\x9c\x3a\xbb\x55\xf2\x50\xa2\x2d\xf9\xc2\xd5\x56\xd1\x96\x6c\xeb\x44\x91\x58\x12\x7d\x89\xde\xc0\x41\x0f\x07\x2b\x0c\x30\x01\x30\xa4\x98\xad\xcd\x6f\xdf\xea\x06\x30\x43\x2a\x76\x1e\x4e\x59\x15\xfb\xc1\x12\x2e\xdd\x8d\xbe\x7e\xdd\xe3\x29\x3c\xfb\xa1\x7f\x46\x53\x38\x13\x41\x80\xc7\x10\x94\x59\xf9\xd1\x0f\x67\x30\x9a\xc2\xa2\x46\x90\xca\x61\x19\xac\xdb\x42\xb0\xe0\x83\x75\x08\x92\x19\x77\x65\x0d\xc2\x43\xa8\x11\x6c\x8b\x66\x29\x7e\x17\xc2\xf1\xde\x52\x78\x3c\x00\xd5\x56\x1e\x1a\x0c\x82\x96\x0e\x40\x18\x39\x9a\x42\xdb\x2d\xb5\x2a\xf9\x54\x91\x19\x60\x25\x3a\x1d\x40\x79\xf8\x63\x5c\xec\x90\xb2\x06\xe6\xd7\xb7\x17\x5f\xe1\xfa\x16\xfd\x01\xfc\x74\x79\xfd\x6e\x76\x39\x9b\xcf\xcf\x66\x8b\xd9\xf8\xba\x45\xf3\xb6\x3f\xf7\x45\x19\x69\x37\xfe\x60\x34\x85\x3f\xc6\x97\x6a\xe9\x84\xdb\x8e\x67\x6d\xab\x55\x29\x82\xb2\x06\x6e\xbb\xb6\xb5\x2e\x3c\xba\xf6\x8b\x28\xe1\xfa\x96\x65\x83\x9f\x6a\xdb\xe0\x78\x8f\xfd\x68\x0a\x73\x2d\xcc\xeb\x02\xe0\xdc\xac\x95\xb3\xa6\x41\x13\x60\x2d\x9c\x12\x4b\x8d\x1e\x84\x43\xc0\x87\x56\x18\x89\x12\xbc\x25\x5d\x6c\xa1\x11\x5b\x58\x22\x74\x1e\x65\x01\x70\x75\xbd\x38\x7f\x93\xe5\x1b\x4d\x01\xbf\x4b\x28\x6c\x5b\x55\x0a\xad\xb7\xf0\x8f\xcf\xb3\x9b\x8b\xd9\xdb\xcb\xf3\x7f\x1c\xc0\xb2\x0b\x89\x6c\xe7\x03\xd1\x15\x65\x89\xde\xa3\x84\x8d\x0a\xf5\x68\x0a\x3f\xe5\xc3\x50\xa3\xc3\x02\x60\xa6\xbd\x3d\x80\x3f\x48\x9f\xbd\x6c\xc1\xee\xab\x6f\x47\x67\x64\x06\x32\x87\x54\xee\x74\x4f\xff\xa3\x1f\xef\x52\x53\xb8\xc2\xb0\xb1\xee\xfe\x69\xdd\xf6\x93\x47\x08\xe8\x83\xc1\x40\xaf\x4b\x3f\x9e\x1e\xf1\x9e\x51\x6b\x74\x5e\x68\x98\xeb\x6e\xc5\x96\x9f\x6b\xb1\x85\xff\xf8\x34\x37\xf3\xff\x04\xd1\x05\xdb\x88\x90\x0c\x41\xca\x88\x1e\xae\x95\x0f\x68\x80\x7c\x08\xec\x32\x08\x65\x48\x74\xda\xc1\x87\x80\xce\x08\x0d\x17\x73\x10\x52\x3a\xf4\x1e\x2a\x67\x1b\xf0\xd1\xe5\x50\x82\xc4\xb5\x2a\xd1\x17\xb0\xa8\x95\x07\xdb\xb2\x47\x4a\xe5\xa3\xed\x15\x0b\x69\x6c\xd7\x9a\x36\xca\xf8\xab\xed\xd8\x8b\x7c\x8b\xa5\xaa\xb6\x60\x0d\x82\x75\xd0\x50\xec\xf9\x8d\x70\x4d\x66\x84\x9e\x2c\x9b\x64\xb3\x06\x2a\xeb\x40\x99\xd2\x36\xca\xac\xc0\x44\x55\x8f\xa6\x50\x5a\x63\xb0\x24\xae\x2c\x03\x7a\xdc\x21\x40\x7e\x4a\x7e\xa5\x0c\x08\x58\x0b\xad\x24\x34\x9d\x0e\x8a\x4e\x10\xc1\x46\xb0\x7c\xcc\x97\xd6\x4e\xc7\xaa\x7d\x31\x3e\x2c\xf8\xef\x38\x94\xed\xf8\xc5\xe1\xe1\xd1\xe3\x13\x27\xe3\x37\x6f\xbe\xbb\xb9\x7f\xfd\xf5\xe1\xe1\xf1\x98\x63\xe3\xdb\x14\xf2\x7e\x4a\x17\x2b\x11\x70\x23\xb6\xbd\xae\x59\xd8\x56\xe3\x03\x7a\x58\xda\x50\xb3\x51\x2e\xe6\xef\x6f\xfb\x93\xb3\xf9\x05\xdb\x79\x3f\x53\x8d\xa6\xbc\x61\xd7\xe8\x78\xc7\x8b\xa6\x57\x0b\x6b\x69\x87\x83\xaf\x93\x86\xbe\xaf\x9f\xc4\x6c\x78\xe2\xd1\xe4\x25\x3f\xf2\x28\xab\x61\x42\x2f\x78\x6b\x6d\xf0\x41\xb4\x3b\x06\xa0\xd8\x67\x23\x04\x0b\xff\x63\x95\x61\x69\x92\xf1\x0a\xb8\x36\xe0\x83\x70\x21\xae\x5a\x89\xb0\x51\x5a\x43\x23\xee\x71\x34\x05\xdb\x85\x95\x25\x63\xef\x98\x98\xe8\xd0\xe1\x25\xb3\x72\xa2\x85\x16\xd1\x79\x56\x41\x47\x91\x51\x63\x43\x67\xa4\xf2\x25\xbf\xde\x86\x1a\x49\x1d\xf1\xd8\x23\x01\x46\xd3\x81\xd0\xf0\xb8\x87\x82\xff\xf6\x16\x1e\xb7\x93\x76\x7c\x34\x39\x7b\xfe\xb3\xb5\x5f\xe6\x77\xcf\x1f\xde\x5e\xdd\x7c\x78\x78\x51\xd5\x37\xcb\xea\xd7\x59\xf9\xf5\x53\x5d\xde\xd5\x8b\xbb\xc9\xe5\xbb\xfb\x7f\xbd\x7c\x71\xff\xaf\xaf\x1f\xaa\xdf\x5f\x2f\x3e\x5f\x2e\x48\x27\xb7\x5c\x54\x48\xbc\xca\xba\x8d\x70\x12\x3c\xba\x35\x8b\xbc\xa3\x1a\x87\x25\xaa\x35\x42\x83\xde\x8b\x15\x7a\xd8\xd4\xe4\xf4\x55\xa5\x95\xc1\x02\xe6\x88\xee\xe2\x8c\xbd\x88\xa3\x46\xa1\xe4\x84\x18\xd5\xb5\x44\xca\x38\xf9\x6d\xad\xb3\x95\xd2\x91\x25\x3f\x9e\x15\xeb\xe3\xd1\x58\xe2\x32\x97\xd1\x94\xf3\x6c\x54\x9a\xaa\x62\x2a\x2e\x85\x31\x36\x64\x9d\x47\x7d\x2b\xcf\x44\x72\x7c\xed\xbe\x20\x90\xa0\xbf\x75\xe8\xb6\x14\xf0\xa3\x69\xef\x8c\x83\x39\xa5\xdd\x18\x6d\x85\x1c\x5e\xc7\x29\x84\xb8\x16\xa3\xa9\x37\x55\xa4\x77\xfa\xef\xaa\xf8\x07\xe7\xd8\x29\x2c\xac\x7b\xda\x1c\x7e\xfa\x43\xff\x8c\xa6\xf0\xbd\x3f\x5f\x66\x37\x57\x17\x57\x1f\xe0\xd9\x33\x38\x9b\x5d\x7d\x38\xbf\x81\xbb\xeb\xab\x73\xfa\x35\xed\x8c\xa6\xb0\x83\x1a\x3a\x4e\xba\x39\x5f\x50\xc8\xc0\xc5\x19\x27\x5e\x41\xce\x83\xde\xc7\x34\x7b\x51\xc1\xd6\x76\xfb\x3e\x82\x3b\x84\x28\xe5\xa7\x5a\x88\x6b\xce\xde\x25\x66\xff\x2c\x35\x0a\x77\x40\xf7\x1d\x38\xdc\x2f\x2d\x09\x5d\xb4\xe8\x1a\x61\xd0\x04\x4d\x80\xa3\x6d\x63\x8c\xd0\x8d\x14\xc8\x24\x15\xf9\xd9\x5a\x79\xb5\xd4\x48\xbb\x31\xbe\xed\xa3\x04\x93\x04\x25\x47\x55\x26\xa0\x91\x94\x4e\x82\xe5\x54\x41\x56\x0e\x16\x1a\xe1\xa9\x8c\xb0\x3c\x83\x28\x2c\x60\x84\x25\x57\xe7\x9f\xcf\x6f\x52\x9e\xda\xd1\x15\x45\x8e\xed\x02\x74\x9e\x68\x2e\xac\x2b\xe0\xca\x86\xfc\x5e\x12\x63\x34\x85\x4a\x39\x1f\xe2\xdd\x82\x19\x66\xa0\x53\x5a\x53\xa9\x55\xe7\x50\xe6\xd4\x25\xe9\x16\xae\xd1\x6d\x81\x28\x6a\x8c\xd7\xba\x36\xbf\x82\x62\xab\x2c\x95\x44\x13\xb8\x7e\xf3\x36\xca\xbf\x94\x29\x3e\xe3\x97\x4f\xb7\x0b\x90\xa8\x31\x60\x7c\x27\x63\xdc\x1e\xfb\xa6\xa0\x8d\x2f\xa4\xa4\x59\xc0\x19\x1d\x66\x5d\xd5\xf8\xe8\x74\x8c\xe9\xca\xba\x72\xd7\xe2\x59\xa9\x74\xb0\xaa\xd0\xa1\x09\x83\xad\x0a\x2e\xfa\x7c\x4f\x5b\x3a\x64\xb6\x5c\xd7\x29\xbe\x0e\xc0\x3a\x89\x8e\xff\x85\xd2\x2a\xe3\x59\xe4\x5a\xac\xc9\x0b\xd7\x28\x29\x31\xd1\x8a\xb4\xe0\x6d\xf1\xe3\x83\x27\xc5\x7b\xd3\xa7\xab\xa8\x07\x61\x00\x9b\x25\x4a\x02\x98\xb4\x2f\x05\x36\xd6\x50\x76\x7d\xd8\xc6\x52\xdc\x63\x11\xce\xb4\xdf\xa8\x55\x54\xc2\x72\x01\x26\x12\xbd\x57\x32\x52\x62\x86\x1c\x70\xb4\x87\x0f\xa5\xee\xbc\x5a\xa3\xde\x32\x3d\x4a\xc1\x7d\xb4\xb0\xef\xba\x0c\xf8\xac\x8b\x40\xea\xac\x13\x2c\x6c\x79\xbf\x23\x3c\x01\xe8\x36\x0c\xb2\xed\x95\xce\xda\xba\x6e\x55\x47\xe9\x89\xe9\xec\xea\x6c\x60\x32\x9a\x0e\x6c\x28\xcf\x3b\xac\xb8\x1d\xea\x84\xde\x61\xa2\x3c\x81\x7e\x68\x9d\x5a\x8b\x80\x05\x5c\x7f\xab\x46\xa7\xaa\x34\x9a\x42\x23\x24\x0e\x4a\xd8\x7f\x0c\x74\x46\x53\xd0\x07\xa1\xef\x53\x58\x8a\x58\x35\x5c\x67\x0c\xad\xec\x2a\x65\x89\xb5\xe2\x26\x8b\x22\x8d\x50\x7d\x96\x2b\x2a\xe3\x07\xe7\xe8\x29\x5c\x91\x20\xb7\xa9\x08\xc0\x33\xc6\x4c\x95\xd5\xda\x6e\x48\xb2\x08\x73\x9f\xae\x2f\x35\x5d\xb3\x24\xf0\x52\x81\x43\xdf\x5a\x93\xc0\xf0\x46\xa8\xc0\xe9\x98\xe1\x41\x23\x58\x6f\x17\xf3\xab\x5b\xae\xc0\xaa\x47\xe1\xca\x83\x80\xe0\x84\x44\x5b\x55\x04\x72\x30\x6c\x10\x63\x6e\x14\x65\xd9\x39\x51\x6e\x89\x38\xfd\xce\xb5\xbb\xaf\xda\xbe\x45\x94\xa4\x5f\xd5\x1a\xff\x5b\x67\x5d\xd7\x9c\x32\xb6\x3b\x8b\x88\x9e\x0f\x51\xa0\xdb\x2a\x32\x9e\x77\x4b\xdf\x2d\x63\x84\xb7\xce\x2e\xc5\x52\x6f\x61\x23\x0c\x57\x05\x99\xc0\x43\x0c\xe1\x88\x44\x48\x38\x76\x19\x62\x92\x7e\xa4\xb3\x4b\xcc\x0f\x12\xa0\x85\x5b\xed\x2a\x61\xf7\x89\xd4\x96\xab\x10\x7d\x8c\x04\x61\x1f\x6a\xb0\xb1\xf1\x15\xf4\x5a\x61\xe4\x46\xc9\x50\xc7\xd6\x83\x5e\xd2\xfa\xe8\x26\x04\x8a\x3f\xdd\x5c\xe6\x6c\x55\xc5\xc8\xab\x85\x59\x21\x38\x11\x48\x81\xbf\x50\x86\xa6\xf4\x6c\x5d\x93\x2b\xdb\x5b\x15\x28\x35\xcd\xd6\xe8\xc4\x0a\x77\x80\x71\xbe\x4c\x77\x5b\x67\xd7\x4a\xa2\x3b\xad\x43\x68\xfd\x9b\xf1\x38\xa8\xf2\x1e\xdd\x4e\xd3\x59\x58\xb7\x1a\x8b\x56\xed\xea\x93\x0a\xeb\x4e\x1a\x75\xa8\x05\x25\xf5\xaa\x33\x1c\x4c\x42\xab\xb0\x25\x36\x14\xd5\x3d\xf8\x67\x3d\x92\xc9\xe2\x6f\x31\xaf\x28\xb3\x8a\x86\xab\xbc\x35\x7a\x9b\x1e\xdc\xb6\x68\x24\x08\x28\x6d\xc3\x6d\x79\x7a\x51\xe7\xd1\x81\x58\xd1\x4a\xc6\x8d\xc3\xf0\x62\x48\xf6\xc5\x68\xda\x89\x74\xf5\x34\xfd\xfb\x24\xe1\x46\x86\xf9\x5b\xa2\x2d\xb7\xa3\x1b\xe5\x6b\x52\x0e\x1a\x36\xcb\xed\xed\x65\x06\x13\x24\xda\x90\xdd\x86\x08\xab\xd5\xaa\x26\x84\xe2\x30\x2a\x46\x22\x39\x9f\x1a\x10\x47\x4e\x63\x1c\x57\x0c\x71\x89\xa4\x00\x87\x8d\x0d\xe4\xed\x65\xad\x0c\x92\x3f\x57\x42\xe9\xce\x61\x76\x4b\x62\x4e\xfe\x4d\x85\x99\x74\x40\x05\x93\xda\xe4\x60\x77\x21\x17\xd9\xbf\xb4\x26\x38\xab\x87\xe8\x3a\xa0\xd4\xaf\x3b\xc6\x39\xd2\x09\xd5\x0b\xb0\x11\x5a\xc7\x02\xe2\xbd\x8e\xbe\xb1\x18\xb8\x6d\x73\x7d\x36\x18\xc1\x96\xd0\xde\xf6\x2d\x3a\xbb\x87\x08\x35\xe7\xa0\xbe\x0d\x2d\x91\xcb\xa4\x84\x7b\xdc\x02\xb5\x1c\x64\x20\x8a\x28\x16\x86\x76\x55\xa5\x4a\xaa\x12\x91\x29\xad\xd0\xb1\xd3\x31\xd1\x1a\x07\x3b\xf6\x5e\x17\xb4\x1a\xf7\xef\x71\xfb\xe7\xed\x7b\xdc\xe6\x9c\x38\xf8\x43\xea\x3b\x60\x29\xbc\x2a\x41\x74\xa1\x86\xd2\x21\x01\x23\x25\xb4\x67\x19\xb2\xe1\x92\x39\xb2\x75\x3b\xcf\x2d\x4a\x47\x5d\x4b\x48\xe3\x33\xc6\x6d\x44\x50\x84\x01\xf4\x91\x62\xf8\xa5\xa4\x1d\xaa\x93\xfb\x77\x18\x71\x3a\x1b\xb0\x24\xe1\x7b\x93\x46\x2b\x17\x70\x11\xfe\xe9\xa3\x0a\xc9\x49\x76\x7d\x64\x60\xc3\x68\x69\x9f\x28\x61\x47\x02\x0d\x06\xb4\x2d\x85\xae\xad\x0f\x91\x11\x6d\x84\xd4\xcd\xb5\xce\xae\x9c\x68\x52\x13\x15\x07\x66\xd9\xc8\xb3\xf9\x05\x0f\x1e\xc5\x3d\xf5\x5f\xf9\x51\x59\x17\xad\xf0\x7e\x63\x9d\x84\x25\x92\x53\x65\x28\x4a\xdb\x35\x3e\x00\x9a\xd2\x12\xda\xb9\xfd\x38\x9b\x1c\x9f\x40\x2d\x7c\x0d\xb6\x4a\x83\x20\x51\x06\x82\x1b\x99\xc4\x10\x05\x32\x39\x66\xd2\x46\xf2\x95\xc4\x68\x53\x53\x27\xaa\x02\x78\x15\x3c\x77\xac\x8c\x32\xa2\xfb\x30\x02\x66\xc7\x29\xe0\x0b\xd5\x33\x56\x3e\x89\x2e\x0c\xcb\xeb\xf0\xb7\x0e\x7d\x18\x9c\x93\xe8\xe6\xeb\x9d\x79\x46\x12\x72\xcc\xf5\xfc\x72\x15\x63\xd9\x73\x6f\x5c\xda\xa6\x15\x2e\xba\x75\xbf\x19\xa1\x25\x0f\x15\x47\x53\xd1\x2a\xca\x87\x46\x34\x78\x2a\xb4\x2a\x91\x97\x32\xd5\xd3\x63\x7c\xf5\xea\xc5\xab\xd7\xaf\xa4\x98\xbc\x3a\x7c\xf1\xf2\xe8\xf8\x48\x1e\xe2\xf1\x49\xf5\x4a\x96\x27\x93\xd7\x93\x97\x2f\x9f\x9f\x1c\x3e\x97\x87\xf2\x44\x88\xe5\x52\xca\x93\x89\x38\x3a\xc2\xea\xe5\xe4\x48\x1e\x1d\xbf\x98\xc8\x57\x9c\x87\x3d\xbd\x4a\x68\x1e\xa7\x05\x6a\xf5\x29\x94\x06\xff\xe5\x76\x4a\x18\xf6\x8a\xd2\xda\x7b\xc5\xde\x4d\xdd\xc1\x23\x5f\x5d\x70\x5f\xd1\x3a\xd5\x08\xb7\x8d\xc7\x45\xaa\x64\x21\x99\x84\x7e\xee\xbd\x84\x3d\x20\xfd\xd6\x8f\xfe\x86\xa1\x4b\xf4\x58\x86\x95\x7b\x26\x24\x4f\x82\x2f\x48\x15\x9c\xa0\xe8\xe0\xbf\xd1\x11\x88\x46\xcc\xd6\x91\xeb\x5a\xe8\x2e\x75\x78\xca\x27\xd3\x52\x25\xee\x02\x95\x55\x76\x5b\x11\xdd\x54\xa5\x82\xe3\x2c\x41\xd1\xe8\x08\x4d\x43\x86\xd3\x94\x0c\x53\xaa\x8f\xb3\xf7\xf8\x1c\xe2\xdf\x9b\x3a\xa6\xb2\xed\xe3\xf0\xef\x3d\x40\xf9\x68\xcf\xa8\xc3\xd3\x5f\xbf\x5e\xdd\xdf\x35\xef\x7f\xbf\xfb\xf0\xbe\xb9\xfb\x78\x55\xdf\x7d\xbc\x6a\x86\xb5\xbb\xba\x9c\xdc\x34\x77\xcd\xfb\xfb\xbb\x55\xee\x04\xc8\x67\x03\x52\x77\x92\x67\x2d\xe5\x4e\x5b\x88\xfe\x00\xda\x38\xb4\x6e\x7a\xef\xa1\xb4\x84\x52\xb5\xa7\x93\x57\xc5\x8b\xe3\xe2\xe4\x65\x71\xf4\xf2\x78\x77\xfd\xf9\xa4\x98\x3c\x7f\x5d\x1c\x1d\xbe\x2e\x8e\x8e\x39\xf5\xbe\xbb\xbe\xb9\xe5\x19\x36\x57\x1b\x09\xcb\x6d\xfe\x52\x40\x6d\x62\x1e\x9f\xf2\x58\x27\xec\xa5\xbe\x60\xa1\x12\xda\x13\x5f\x63\x4b\xeb\x12\xae\xb9\xd8\x4f\x73\xb1\x6a\xf4\x73\x9b\x04\xaf\xb8\xc9\x14\x04\x0d\x53\xad\x27\xa0\x92\x67\x7b\x07\x69\x7c\xa6\xb8\x6d\x89\x53\x5c\xb2\x4a\x86\x5a\x59\xa4\x98\x70\x12\x13\x0e\x53\x34\xb2\xb5\xca\x04\x4f\xaa\x2b\xeb\x7c\x22\xf6\x54\xaa\xda\x8e\xa6\x79\x1c\xf4\x4f\x9f\xc0\x7f\x6c\x5c\x42\xc4\x30\x4c\x9e\x10\x4b\x12\xbb\xc2\x40\x85\x71\x15\xa1\x08\x39\x73\x1a\x63\xa5\x89\x02\x8f\xb3\x8a\xd1\x34\x3e\x22\x89\xff\x44\x5d\xc0\x17\xae\x9a\x7f\x0f\x32\x39\x37\x3d\xe0\x1e\x18\xc6\x32\x1e\x63\x9c\x0c\xa6\xcc\x0e\x7a\x1c\x06\xaa\x5d\xcb\x4d\x76\x32\x9a\xcc\xd7\x78\xe0\x17\xb3\x22\x55\x2c\x4a\x81\x79\x14\x97\x3a\x47\x94\x50\x76\xce\xa1\x29\x09\x6a\x53\x4d\x12\x65\x9d\x9b\xf5\x62\x94\xfc\x35\x92\x3b\x7d\xfb\xee\xe3\xe3\x95\xc5\xbb\x47\x2b\x97\x7f\x5a\xb9\x3b\x7f\x37\x9a\xee\x2f\x9d\x2f\x3e\x3e\x89\xf9\xe2\xf0\x75\x66\x24\xbc\x4f\xc3\xd7\xdb\x88\xc3\xfe\x3e\x83\xf6\x80\x90\x44\x7b\x26\x8c\x7c\xb6\x3f\x17\x4e\x6d\xfc\x9f\x03\xd8\x56\x15\xba\x34\xc0\x8d\x7d\xce\xee\x45\x55\x62\x3f\x1b\x1f\xc6\xeb\x8f\xc7\xbf\x4b\x04\x91\xe7\x65\x9d\xaf\x87\x81\x6c\x1c\x8e\x61\xa2\x9a\x07\xcf\x3b\xb3\xf5\x50\x5b\x8f\xdf\x21\xe5\x30\x38\x85\xeb\xe8\xaa\xfb\x13\xec\x50\xe3\x96\xbf\xd1\x34\x94\xae\xcb\x7b\x8a\x73\x9e\x68\x27\xc8\x95\x7b\xd5\xd6\x6e\xd0\xc5\xa6\x24\xd5\x95\x02\x6e\x7a\xf8\xac\x7c\x56\x8e\xaf\x6d\xa7\xb9\x0e\xf4\xdf\x13\x97\x18\x31\x08\x43\xeb\xa5\x7d\x88\x23\x6d\x01\xda\x06\x6a\x1d\x23\xe5\x38\xdf\xb2\xdc\xbd\x09\x9f\x90\x06\xdf\xa5\xd5\xd8\x8c\x0a\x58\x59\x2b\x41\xa2\xd0\x74\x31\x7d\xc5\x8d\x8e\xba\x33\xa4\xee\x87\xfa\xdf\x30\x5e\x1c\x89\x8b\x9d\x91\x64\x94\x86\x83\x28\x66\xb1\x0c\x4e\xdb\xce\xb5\x36\xf6\xd1\x0e\xd3\xa7\x5c\x16\x83\xf9\x3e\x4e\xe8\x03\x29\x0a\xeb\x48\x29\xb3\xcc\xd0\x01\x29\xb5\x12\x6d\xe5\xf2\xe0\xcd\xe7\x1a\xe5\x4d\x45\x4b\xfd\x88\xfd\xed\xf9\x6d\x39\x09\xb7\x66\xfd\xf9\x06\x9b\x9f\xbd\x3f\xfb\x45\xfd\x7c\x79\x87\x3f\x57\x9f\x6e\xea\xcd\x57\xb1\xb9\xfb\x22\x94\xfd\xcd\xcf\x9f\xaf\x8f\x36\x4f\x12\x99\x67\xb8\xec\x56\x4f\x12\x65\x4c\x19\xb4\x5d\xad\xc8\x79\x34\xae\x51\x13\x26\xfe\xcc\x1f\xb7\xf8\xd7\x68\xa5\xff\x95\x74\x90\xfa\xa5\xca\x1e\x10\xc8\x50\x25\x1e\xc0\x46\x38\x72\xba\x03\x40\xe7\xac\x3b\x80\xd2\x29\x06\x4d\xff\x37\x9a\x12\x4d\xbe\x7f\x4a\x57\xfe\xe2\x7f\x10\x68\xbb\xea\xfb\x21\x6d\x57\x7f\xfa\xf6\x3c\xd6\x76\xd5\x7f\xf1\xe3\x8f\xae\xf9\x33\x50\xfa\xd8\x49\x3e\xf2\x71\xb1\x98\xf7\xdf\x72\x12\x12\xf6\x05\xc4\x3b\x69\x79\x27\x63\xf0\x90\x67\xc8\xf7\xfc\x31\x67\xf8\x1c\x9b\x50\x54\xff\xf1\xe8\x11\x1d\x65\xe2\x44\x83\x8e\x92\x27\xf1\xe8\xae\xff\x14\x2f\x02\x03\x85\x37\xe3\x71\xdf\x95\xbc\xf9\xaf\x74\x95\xa4\xff\xef\x31\x6b\x72\xdc\xd2\x5a\x1c\xf5\xa7\xce\xb7\x60\xa4\xca\x07\x4f\x4f\x0e\x4f\x38\x74\xbe\x38\x15\x10\xde\xcd\x3f\xf5\xdc\x53\xd6\x1a\xbe\x6c\x71\x4b\x40\x59\xa3\xed\xf2\xed\x71\x68\xda\x9d\xff\xbe\x50\xd0\xfa\xe8\xff\x03\x00\x00\xff\xff\xcb\x6f\xd0\x92\x76\x22\x00\x00")
+var _bindataSampleopenbazaarConf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\xc5\x5a\x5b\x73\xdb\x46\xd2\x7d\xf7\xaf\xc0\x43\xb6\x76\xb7\x4a\x26\x25\x5a\x37\xcb\xcb\xef\x2b\xea\x62\x5b\xb1\x22\xb1\x44\xca\x72\xf4\x92\x1a\x02\x43\x02\x11\x08\x20\xb8\x48\x62\xb6\x36\xbf\x7d\xcf\xe9\x9e\x01\x48\xd9\xce\xc3\x96\x5d\x91\xad\x12\x39\x98\xe9\xe9\xe9\xeb\xe9\x1e\xbc\x09\x5e\x7e\xd3\x9f\x17\x6f\x82\x53\x53\x9b\xa0\xb2\x75\x9d\x64\x8b\x0a\xdf\xbf\xf1\x06\xa0\x38\x8d\x6d\x10\x25\xa5\x0d\xeb\xbc\x5c\x05\x75\x1e\x54\xf8\x80\x21\xd9\xb8\x09\xe3\xc0\x54\x41\x8d\x39\x79\x61\xb3\x99\xf9\xdd\x98\x52\x9e\xcd\x4c\x65\xb7\x82\xa4\x98\x57\xc1\xd2\xd6\x86\x43\x5b\x81\xc9\x22\x50\x2c\x9a\x59\x9a\x84\x32\xab\xe7\x37\xb0\x73\xd3\xa4\x75\x90\x54\xc1\x1f\xfd\xde\x1a\xa9\x3c\x0b\xc6\x57\x93\xf3\x4f\xc1\xd5\xc4\x56\x5b\xc1\x0f\x17\x57\x27\xa3\x8b\xd1\x78\x7c\x3a\x9a\x8e\xfa\x57\x98\x77\xdc\xce\xbb\x4d\xb2\x28\x7f\xac\xb6\x40\xf2\x8f\xfe\x45\x32\x2b\x4d\xb9\xea\x8f\x8a\x02\x7b\x99\x3a\xc1\x84\x49\x53\x14\x79\x59\x3f\x5b\xf6\x93\x09\x41\x5c\x78\x0b\x7e\x88\xf3\xa5\xed\x6f\x6c\x0f\x6a\xe3\xd4\x64\xaf\x7b\x41\x70\x96\x3d\x24\x65\x9e\x2d\x6d\x56\x07\x0f\xa6\x4c\xcc\x2c\xb5\x55\x60\x20\x0c\xfb\x54\x60\xb9\x8d\x82\x2a\xa7\x2c\x56\xc1\xd2\xac\x82\x99\x0d\x9a\xca\x46\x58\x78\x79\x35\x3d\x3b\xf2\xfc\x81\xa0\xfd\x2a\xa1\x7a\x55\x80\xdb\x34\x5d\x05\x7f\xfb\x38\xba\x3e\x1f\x1d\x5f\x9c\xfd\x6d\x2b\x98\x35\xb5\x23\xdb\x54\x35\xe9\x9a\x30\xb4\x15\x68\x07\x8f\x49\x1d\x83\xe0\x0f\x7e\x72\x10\xdb\xd2\x62\xc7\x51\x5a\xe5\x5b\xc1\x1f\x94\x67\xcb\x1b\x54\xb7\x21\xbe\x35\x99\x51\x0d\x54\x07\xf4\x3c\xdc\x90\xff\x8b\x6f\x6f\x52\x6f\x82\x4b\x5b\x3f\xe6\xe5\xfd\xf7\x35\xdb\x9b\x0a\xd2\xb4\x55\x9d\xd9\x9a\xa7\x73\x1f\x87\x3b\xf2\x2c\x4b\x1e\x6c\x59\x99\x14\xaa\x6d\x16\xa2\x79\xe8\x78\x15\xfc\xe3\x66\x9c\x8d\xff\x19\x98\xa6\xce\x97\x30\x19\x55\x04\x85\xa1\x16\x9e\x26\x55\x6d\xb3\x80\x36\x14\xe4\xb3\xda\x24\x19\x59\xe7\x13\xfb\x54\xdb\x32\x03\xbd\xf3\x71\x60\xa2\xa8\x84\x72\x82\x79\x99\x2f\xe1\x20\x62\x72\x90\x7e\x64\x1f\x12\x28\xad\x07\x73\x87\x52\xf2\x42\x2c\x32\x4a\x2a\xd5\x7d\x22\x4c\x66\x79\x53\x64\x85\xf2\xf8\x73\xde\x88\x15\x55\x85\x0d\x93\x39\xd8\xc8\xe0\x62\x65\xb0\xa4\xef\x55\x8f\xa6\x5c\xfa\x8d\xb0\x1a\x9a\x75\xbc\x81\xe6\x1c\xb3\x92\x2c\xcc\x97\x10\x6d\x90\xa9\xa8\x41\x2f\xcc\xb3\x0c\x3e\x8c\x5d\x85\x07\x0b\xf1\x74\x04\x68\xa7\xb4\xab\x24\x0b\x0c\x2c\x32\x4d\x22\x98\x5a\x5a\x27\x9c\x41\x82\x90\x06\xf9\x93\x7d\x39\x36\xec\x27\xc5\x6e\x7f\xbb\x27\xff\xfa\x75\x58\xf4\x77\xb7\xb7\x77\x9e\xcf\xd8\xef\x1f\x1d\x7d\xf5\xe1\xe6\xf2\xd7\xdb\xdb\x7b\x7d\xf1\x8d\x2f\x53\xf0\xcf\x5d\xb8\x58\x98\xda\x3e\x42\x38\x5e\xd6\xc2\x6c\x91\xda\x27\x9c\x65\x96\xd7\xb1\x28\xe5\x7c\xfc\x76\xd2\xce\x1c\x8d\xcf\x45\xcf\x9b\x91\x0a\xe4\xf8\x20\x87\x35\xc8\x93\xca\x2c\x5b\xb1\x88\x94\xd6\x76\xa8\x62\x27\xa1\xaf\xcb\xc7\x6d\xd6\x1d\x71\x67\x70\x20\x87\xdc\xf1\x62\x18\xf0\x04\xc7\x79\x5e\x57\xb5\x29\xd6\x14\x40\xdf\x17\x25\x40\x95\xbf\xe6\xd8\x84\xdc\x38\xe5\xf5\x82\xab\x0c\x41\xd7\x94\xb5\x8e\xe6\x91\x85\xdf\xa7\x29\xcc\xe3\xde\x82\x5c\xde\xd4\x8b\x9c\xca\x5e\x53\x31\xe9\x70\xf2\x4c\xb6\x2a\xb1\x57\x61\x61\xf1\x22\x82\x86\x9e\x11\xdb\x25\xe7\xc0\x00\x43\x39\x3d\x84\x66\x29\x0e\x9d\xf6\x8c\x01\x8c\xb7\x84\xba\xc3\x3d\xf5\xe4\x5f\xab\xe1\x7e\x31\x28\x70\xe2\xd3\x57\x1f\xf2\xfc\x76\x7c\xf7\xea\xe9\xf8\xf2\xfa\xdd\xd3\xee\x3c\xbe\x9e\xcd\x7f\x1e\x85\x9f\x6e\xe2\xf0\x2e\x9e\xde\x0d\x2e\x4e\xee\x7f\x3c\xd8\xbd\xff\xf1\xd3\xbb\xf9\xef\xaf\xa7\x1f\x2f\xa6\x94\xc9\x44\x92\x0a\xd9\x83\x38\x61\x02\x08\xa5\xb6\x7c\x10\x96\xd7\x44\x83\x2c\x64\xe1\xba\xc8\x28\x55\x65\x16\x90\xdb\x63\x4c\xa3\x9f\xcf\xd3\x24\x43\xd8\x1b\x83\xf9\xf3\x53\xb1\x22\xf1\x9a\x04\xab\x18\x10\x55\x5c\xd0\x1e\x22\x8e\x3f\x5b\x51\xe6\xf3\x24\xd5\x2d\xe5\xf0\x22\xd8\x4a\xa7\x6a\x8a\xf3\xbb\x80\x1e\xe3\xac\x0a\x2d\x99\x6b\x28\x0e\x4d\x96\xe5\xb5\x97\xb9\xca\x1b\x8e\x4d\x22\xde\xbf\xd6\x4f\x50\x93\xd1\xdf\x1a\x5b\xae\xe8\xf0\xa0\xe8\x8d\xb1\x53\x27\x02\x71\x96\xe6\x26\xea\x4e\x27\x21\x84\xbb\x42\x03\x55\x36\x57\x7a\xc3\xff\x55\xc4\xdf\x3c\x8e\x4f\x11\x69\xbe\x6b\x0c\x1f\x7e\xd3\x1f\x10\xfc\xda\xcf\xed\xe8\xfa\xf2\xfc\xf2\x1d\x8e\x10\x9c\x8e\x2e\xdf\x9d\x5d\x07\x77\x57\x97\x67\xfc\xea\x9e\x60\xed\x1a\x6a\x68\x24\xe8\xfa\x78\x41\x97\x09\xce\x4f\x25\xf0\x1a\x1a\x0f\xd4\xa7\x61\xf6\x7c\x1e\xac\x10\xc6\x37\x6c\xc4\xae\x11\x62\xc8\x77\xb9\xd0\x3e\x48\xf4\x0e\xad\xb7\xcf\x30\xb5\xa6\xdc\xe2\xfa\x12\x66\xbf\x99\x5a\x1c\xba\x28\x2c\x02\x4f\x06\x10\x91\x12\x70\x14\x85\xfa\x08\x57\x38\x47\x26\x57\xb4\xb3\x87\xa4\x4a\x60\x75\x7c\xaa\xfe\x9d\x3f\x0b\x30\x8e\x51\x1a\x6a\x92\x21\x8f\x44\x0c\x27\x98\xcd\x50\x41\x2d\xe3\xe3\xd2\x54\x4c\x23\xc2\x4f\xc7\x8a\x30\xa8\xb0\xe4\xf2\xec\x23\xe4\xa6\x71\x6a\x4d\x56\xf4\x1c\x44\x28\x90\x22\x4d\x10\xeb\x05\x97\xf0\x1b\x77\x5e\xb2\x01\xaa\xf3\xa4\x04\x05\x59\xdb\x93\x0d\x3d\xd0\x81\xe8\xe6\xc9\xa2\x29\x71\x34\x17\xba\x22\xae\xc2\x6a\x60\x51\x52\xc4\xa9\x64\x59\x53\xf8\x53\xd0\xb7\xc2\x30\x89\x20\x17\xc9\xdf\xf2\x18\xcb\xfe\x8c\x27\x3d\xc6\x4f\x37\x93\x29\xf2\x74\x6a\x6b\xab\xe7\x14\x8c\xdb\x62\x5f\xe7\xb4\x7a\x42\x06\xcd\x5e\x70\xca\xc9\x22\xab\xd8\x3e\x9b\xad\x3e\x0d\x9b\x08\xd7\x35\xee\x85\xca\x89\xf3\x39\x42\x53\x56\x77\xba\xea\x49\xd2\x97\x75\x69\xce\x49\xd9\x4a\xf2\x3a\xfd\x6b\x0b\xc9\x3f\x82\xea\xf8\x17\x52\x01\xf4\x10\x96\x63\xf3\x40\x2b\x7c\xc0\xf9\x12\xd5\x61\x04\x80\x9e\xf7\xbe\xbd\xf3\x38\x7f\x5f\xb6\xe1\x4a\xe5\x60\xa0\x8c\xe5\xcc\x46\x04\x98\x7c\x1e\x19\xbb\x84\x82\x10\x5d\x9f\x56\x9a\x8a\x5b\x2c\x22\x91\xf6\x0b\xb9\x8a\x29\xcc\x27\x60\x92\x68\xad\x52\x90\x92\x6c\x28\x0e\xc7\x67\xf6\x29\x4c\xa1\xb5\x07\x0b\xc5\x92\x1e\x43\x70\xeb\x2d\x62\xbb\xa5\x07\x7c\x79\xa9\x40\xea\xb4\x31\xc2\x6c\x78\xbf\xc6\x3c\x01\x74\x51\x77\xbc\x6d\xa4\xce\x38\x2f\x9b\x45\xac\xdc\x73\xd3\xd1\xe5\x69\xb7\x09\x28\xb6\xdb\x30\xce\x97\x76\x2e\xe5\x10\x76\x59\xdb\x04\x8c\x03\xf4\x43\x0c\xc9\x03\x30\x01\x32\xf8\x97\x72\xb4\xcb\x4a\xa0\x08\xc0\x63\x3b\x21\x6c\x1e\x26\x68\xb2\x94\x4e\x0f\x6b\xbe\x77\x6e\x69\x34\x6b\x94\x4d\x96\x71\x64\x5d\x28\x33\x1b\x27\x52\x64\xd1\xd3\x88\xea\x3d\x5f\x2a\x8c\x6f\x8f\xe5\xc9\xc8\xc4\x25\x81\xe0\xa5\x60\xa6\x79\x9e\xa6\xf9\x23\x39\x53\x98\xfb\xfd\xea\xd2\xac\x81\xed\x01\xbc\xcc\x11\x22\xab\x02\x3b\x29\x18\x7e\x34\x49\x2d\xe1\x58\xe0\x01\x60\x12\x79\x39\x1f\x5f\x4e\x24\x03\x27\x2d\x0a\xc7\x7f\x13\x00\xd4\x44\x16\x10\x82\x20\x07\x96\x67\xad\xc6\x46\x58\x48\x53\x9a\x70\x45\xe2\xfc\x2e\xb9\xbb\xcd\xda\xc0\x17\xa8\xec\x68\x0b\x45\x56\xfd\xd6\xc0\x60\x96\x43\xc1\x76\xa7\x8a\xe8\x65\x12\x1d\x1d\xab\x65\xe3\x71\x33\xab\x9a\x99\x7a\x38\x9c\x63\x86\x49\x08\x11\x26\x93\xac\x10\x39\xf0\xa0\x2e\xac\x48\x84\xcc\x89\xc9\x70\x13\xf7\x91\x73\x11\x14\xdd\x81\x4c\x90\x9a\x72\xb1\x2e\x84\xf5\x23\xb2\x2c\x87\x14\xc4\xc6\xc8\x88\xd8\xd0\x12\xce\xa9\xa7\xe0\x69\xf1\xe7\x31\x89\xea\x58\x4b\x0f\x9e\xa4\xa8\xd4\x4c\x26\xe0\x22\x54\x59\x72\xed\x5c\x3d\x2f\x36\x19\xb6\x2b\x61\xd0\x10\xe0\x99\x41\xe9\x5f\xc9\x3c\xf2\xa7\x20\x98\x71\xd6\xf8\x2f\x47\x69\xae\x85\x37\xf1\x93\xc0\x30\x1d\xe7\x74\xa9\x66\xe6\x6b\x48\x7c\x4b\x82\xda\xc2\x86\xf7\xa8\x5b\xef\x4b\x20\xdb\x8c\x91\x8e\x10\xad\x17\x8c\x82\x9b\xeb\x8b\x36\x6a\x1b\x26\x0c\xa5\x24\x69\x82\x39\x02\x5f\x7d\x7a\x3d\x4e\x6a\x92\x1a\xc1\x9b\x00\xa2\x3c\x3a\x57\xc3\x24\xe0\xeb\x98\x30\xc1\x8f\x93\xab\x4b\x86\x9f\xd9\xaf\xcc\xcf\xcc\xa1\xe2\x4f\x3c\x1a\xb4\x8f\xe8\x0c\xfd\x87\x54\x06\x68\x27\x75\x45\x7f\xd6\x0c\x7d\x3c\x3d\xd9\xd2\x84\x9f\x94\x2f\x17\x9a\x7b\x01\x85\x9a\x42\x4a\xe9\x11\xc3\xa3\x13\x21\x73\xaa\xaa\x24\x6a\xad\x67\x69\xa3\x04\x81\x93\x92\xa4\x14\x36\xf7\x4b\x2a\xd7\x36\xb8\xa6\xa0\x45\x76\x09\x9e\xb3\x7e\xe4\x7c\x8f\x09\x3d\x91\xd9\x4a\x6b\xc2\x3a\x66\x28\x76\x4a\x22\xe5\xa5\x79\xd2\x35\x2c\x34\xc9\x45\x69\x79\x4a\x35\xdb\xf5\x89\x30\xc7\x07\xa4\xcb\x72\x18\xd7\x75\x51\x1d\xf5\xfb\xa8\x7c\xef\x91\xdd\x3a\xe5\xf4\xf2\x72\xd1\x37\x45\xf2\xb5\x85\xad\xea\x8e\x3c\x09\x4c\xee\xb5\xa3\xf8\xb4\xe4\x48\xff\xe1\x55\xdf\xaf\xff\xa5\x74\xa7\xfb\x22\x41\x35\x80\x0d\x6a\x3a\x24\xa4\xb6\xfb\xda\x41\xea\x4f\x85\xd1\xff\x2f\xa0\x84\xe1\xa7\xe3\xe9\xcd\xe4\x74\x0b\x7f\xce\x6e\xae\xbf\x46\x97\x06\x70\xd4\x2f\x4c\x1d\xf7\xeb\xbc\xaf\x96\xfc\x6b\x25\x16\xfa\x35\xd1\x0d\x51\xbd\xad\x3b\x36\x11\xde\x5a\x3e\x2f\x6d\x6a\x68\xf5\xf3\x26\x93\xa8\x8e\xda\xb0\x5e\x91\x18\xd3\x4b\x5b\x85\x8a\x43\x53\xfb\xfa\x4d\x13\x1c\xa4\xa3\x11\x64\x0e\x0e\xd2\x95\x7a\xde\x66\x54\x6b\x2b\x01\x40\x3d\xd8\x17\x2c\x3d\x52\x6b\x11\xc4\x20\xed\x01\x2b\xb0\xa8\x35\x23\xb7\x82\x8d\x24\x4b\xea\x3f\x79\x0a\x6d\x76\x49\x93\x25\xe2\x02\x0d\x22\x2a\x73\x1a\x2e\xa7\xf1\xd4\xf2\x60\xb8\xf7\x27\x5c\xf8\x4d\x48\x1c\xde\xb3\xc9\x04\x7c\x4a\xf0\xeb\x4c\x13\x22\xb7\x22\x55\x50\x73\x3b\xb2\x13\x67\xab\x8d\xdd\x66\x0d\x70\xdf\x70\x6f\x9b\x7b\x8e\xb5\x40\x45\xd8\x45\x69\x4d\x4b\x37\xc1\x32\xa9\x90\xcf\xcc\x43\xc2\x42\x23\xcc\xcb\x36\x12\x21\xf8\x7a\xd4\x25\x4e\xa4\x3b\x48\x39\x22\xd8\x55\x52\x6a\x4a\x4f\xb7\xd1\x16\xfc\x55\x4a\x76\x62\xc1\x64\x91\x11\x41\x02\xa7\x34\xda\x17\xec\xaa\xbc\x9e\xe3\xe0\x31\x26\xf6\xd2\xed\x4a\x1e\xd7\xfa\x2e\x0f\x02\xa6\x1b\x97\x8a\xce\x2e\x8b\xbc\x34\x65\xc2\xac\x8b\x62\xd0\x4a\xe9\xda\x4e\x8c\x90\x3d\x68\x10\x3d\x8d\xb4\xb2\x6e\xb8\xb3\xbd\xed\x02\xaf\x7b\x3a\xdc\x89\x79\xf6\x16\x1e\xb4\xa2\x56\x87\xad\x25\x6c\x34\x59\x9d\xa4\x7a\x58\x54\xb5\x45\xc2\xb6\xe1\xe8\xe4\x43\xa5\xa5\x21\xa2\xe3\x74\x7a\xe1\x6b\x02\x04\x21\x0d\x8f\x9f\x9b\x43\x60\xe6\x40\x14\x2e\x9c\x3c\x12\x37\xe7\x05\xb2\xdf\x8a\x76\x18\x9c\x3d\x15\x09\x51\xf6\xd5\xf5\x29\x50\x3c\x4d\xf5\xf4\x7c\x32\xbe\x99\x9e\x51\x94\xeb\x4c\x11\x71\x29\x46\x46\x88\x3a\xcf\xaa\xda\x9a\x48\x2b\x62\x3e\x5d\x9a\xf2\x9e\x82\x30\x70\xb4\xae\x1b\x1a\x32\x4e\xd1\x12\xfc\x89\x88\x61\x0d\x8c\x25\xe5\xac\x3a\x2e\x73\xe2\x2d\x1e\x70\x34\x3e\xef\x75\x3b\xd6\x75\x3a\x3c\x79\x3f\x9a\x1e\x1d\x0c\xb6\xe3\xcd\xe1\xb7\x57\x17\x17\x57\xb7\x47\x3b\xfb\x87\x22\xc0\xdb\x32\xa1\xc5\x20\x02\x66\x61\xb9\x2a\xe8\x91\x33\x80\x9e\xa6\xf0\xe9\xbb\xb4\x45\xde\x36\x45\xf4\x49\x07\xd5\xb5\x9c\xd0\x61\x8f\xef\x60\x32\x6c\xbc\x40\xb9\x5a\x29\x3d\x5a\x56\x24\x32\xe5\xde\xda\xc2\x7d\x54\x99\xdc\xc3\xd9\x35\xc3\xac\x8d\x4a\x9f\xd7\x33\xe3\xfb\x07\x41\x61\xaa\xaa\x88\x4b\x53\xb5\x85\xde\xd5\xf1\x2f\xc7\xd0\xe6\xcd\xf8\x97\xf1\x68\x32\x19\xbf\xbf\x1e\x4d\xce\xd6\x3b\xc4\xa0\xe3\x7b\xc4\xa2\x17\x15\x26\x01\x0f\x9b\x14\x6b\xa4\xdd\x08\x12\xd6\x12\x65\x61\xa4\x86\x47\x76\xd8\xd5\x6d\x83\x9e\xe3\xb0\x7d\xe8\x0f\x3b\x1c\xec\xc6\xed\x20\x4f\x38\x3c\xa0\x60\x47\x88\x10\x74\x6e\xa1\x6a\xb3\xb6\x88\x45\x8a\x42\xf2\x5b\x70\xc4\x37\x54\xba\xae\x7e\x27\x5a\x30\xd1\x18\xb7\x74\xe8\xfe\x7e\x17\x1c\xca\x36\xde\x5f\x02\x43\x7d\x9f\xf6\x31\xa9\x62\x0a\xc7\x66\xa2\xa9\xc9\xe4\xc2\x57\xd9\x64\xad\x83\xfd\x1d\xf4\x8c\x93\x45\xcc\xd2\x1d\xa2\x12\xc1\xa0\x84\xa2\xf1\x75\xa5\xb8\xc7\xf7\x02\x38\xa5\xf7\x43\x92\x06\x0b\x96\x39\x03\x17\x1c\x3c\x01\x8e\x22\xe0\x82\xbf\x35\xa5\xf5\x78\x8d\x9b\x13\xf8\xb1\x62\xa5\x0c\x58\x49\x12\x71\xe1\xf1\x5a\x2f\x42\x8c\x29\xcf\xea\x32\x4f\x3b\xd8\xc9\x68\x89\x6a\x42\xc2\x6b\x54\x9a\xa4\x65\xe0\x11\x94\xb4\xb2\xaa\xaa\xd4\xe5\xaa\x6e\xb7\x95\x2f\x5c\x33\xab\x5d\x08\x54\x1d\x79\xdb\xbb\x56\xc3\xaf\x63\x01\x94\x6d\x7f\x36\xb4\x52\x3f\x46\x70\x9f\x95\x40\xb3\xca\x45\x2e\x61\x86\x4f\x93\xb9\x84\x68\xb7\x29\x47\x38\xad\xb3\x65\x0c\xf6\x38\xaa\xcf\x41\xe6\xf3\xc7\x18\xf4\x09\xad\xb3\x07\xd7\x90\x83\xb1\x57\xb0\x58\xd3\x80\xa1\x10\xae\x04\xdb\x4c\xc0\x77\x1b\xc6\xa9\x38\xa7\x0e\xaf\x5d\x9c\x97\x42\x6d\xd8\xce\xab\xdd\xbd\x92\x34\x34\x04\x80\xd5\x5d\x37\x84\x82\x91\x93\x52\x3a\x2c\x20\x37\xd7\x48\x2b\xa6\xc4\xb2\x90\xcc\xb7\x2a\x55\x2d\x23\xae\xd6\x7f\xaf\x54\x84\x34\x92\x75\x1b\xe9\xb6\x91\x36\xc2\x26\x51\x36\x55\x08\x67\xb3\x80\xd0\x3b\x45\x22\xab\x75\x23\x3e\xa8\x5d\x9b\x13\xdb\x2e\x4a\xb3\x74\xdd\x45\xbd\x49\xf2\x4a\x76\xe1\x77\x0a\xa8\xc5\x40\xef\x0e\xe5\x65\xc1\xd8\x05\xe0\xc2\x9b\x02\x1a\x95\xef\xd1\xf0\x71\x6c\x9f\x18\xed\x72\xb6\x01\x26\xef\x47\x83\xbd\xfd\x20\x36\x10\x59\x3e\x77\xb9\xd3\x84\x35\xeb\x70\x4f\xa2\xf3\x82\xc8\x19\xa6\x93\x46\x1b\x24\x65\x23\xe4\x2a\xf8\x12\x52\x7b\x45\xf4\xcd\x1a\x42\xc2\xb3\x9a\x8f\xb4\x86\x5c\x85\x70\xcb\x42\x4f\x84\x4f\xd6\x11\x21\xc9\x6f\x69\x01\xbc\xab\xba\x33\x4e\xd2\xf5\xcb\x9b\xec\x25\x39\x14\x9f\x6b\xf7\xf3\x00\x5d\x78\xf7\x4d\x63\x48\xbe\x30\xa5\x9a\x75\xfb\x50\x7b\x2e\x72\xdb\xf6\xe2\x0d\xf0\x29\xe3\x61\x66\x96\x76\x08\xa4\x11\x5a\x19\xf2\x54\x87\x7b\xf6\xf0\x70\xf7\xf0\xf5\x61\x64\x06\x87\xdb\xbb\x07\x3b\x7b\x3b\xd1\xb6\xdd\xdb\x9f\x1f\x46\xe1\xfe\xe0\xf5\xe0\xe0\xe0\xd5\xfe\xf6\xab\x68\x3b\xda\x37\x66\x36\x8b\xa2\xfd\x81\xd9\xd9\xb1\xf3\x83\xc1\x4e\xb4\xb3\xb7\x3b\x88\x0e\x25\x0e\xb3\x77\x0f\x93\x90\x7b\xa6\x9a\x3d\x70\xba\x52\x67\xbf\xd2\x67\xc4\x0c\x5a\x45\x98\xe7\xf7\x89\x58\x37\x6b\x8e\x67\xb6\x3a\x95\x86\x1b\x0a\x19\xa4\xea\x95\x4e\x37\xae\xc4\xab\x9d\x4a\xf8\xb9\xb5\x12\xb1\x00\xf7\xad\xbd\x13\xeb\x6e\x23\xd4\x62\xa5\xdf\xb2\xa1\x42\x5a\x52\x70\x6b\x59\xda\x12\x31\x74\xf6\xab\x86\x40\x1a\x1a\xad\x75\x57\x24\xa0\xc6\x65\x44\x7c\x53\xd5\x32\x1b\xa1\xdc\x03\x50\x12\xb3\x35\x6a\xa6\x89\x4b\x38\x1d\x66\x70\xf9\x2e\xe0\x1d\x80\x0f\xf5\x7a\x29\xad\xc7\xe1\xfe\xad\xaa\x35\x94\xad\x9e\xbb\x7f\x6b\x01\x49\xa5\xfa\x54\x19\x0e\x7f\xfe\x74\x79\x7f\xb7\x7c\xfb\xfb\xdd\xbb\xb7\xcb\xbb\xf7\x97\x31\x7e\x97\xdd\xd8\x5d\x1c\x0e\xae\x97\xf8\x7c\x7f\xb7\xf0\x2d\x32\xda\x2c\xd1\x2d\xfb\x99\x7a\x09\x11\xae\xf5\x4b\x79\x87\x5d\xe8\x6d\xee\xb2\xb5\x1e\x86\x25\x00\xda\x62\x38\x38\xec\xed\xee\xf5\xf6\x0f\x7a\x3b\x07\x7b\xeb\xe3\xaf\x06\xbd\xc1\xab\xd7\xbd\x9d\x6d\xfc\xee\x49\xe8\x3d\xb9\xba\x9e\xc8\xe5\xae\x64\x9b\x88\x55\xa0\xbb\x42\x67\xff\xd4\xdf\x2b\xca\x7d\x47\xbd\x11\xfa\xa0\xa6\x39\x02\x0b\xf7\xcd\x72\x60\x52\x57\xf0\x9f\x6f\x86\x39\xcd\x1a\xed\x85\x86\xeb\x3b\x48\xf7\xd5\xb0\x6e\x76\xb9\x9e\xf5\x99\xbf\xf4\xda\x72\xf7\x4a\x89\xf4\xf3\xf4\x7a\x93\x5a\xf1\x3d\x08\xcf\x92\x06\x1c\xb7\x89\xb8\x29\xac\xa2\x00\xf8\xad\x2b\x07\x4d\xdd\x0c\x6d\x36\x22\x7d\xbc\x78\xe3\xef\x49\xfe\x5e\xb9\xae\x98\x76\xf4\x6a\x05\x44\x42\x9e\x15\x94\x63\x7b\x6e\x6b\x26\xc6\x85\x42\x11\x1a\xb3\xbb\xdf\x71\xad\x76\xb9\xe7\xc1\xf9\xf5\x10\x8e\xfd\xef\xd4\x1e\xbb\x95\xac\xf9\xd7\x20\x93\xb3\xac\xed\x44\x75\x1b\x6a\x1a\x57\x1f\x6f\x14\x84\x76\xd5\x6c\x77\xd3\x08\x78\xcc\xee\xb3\x53\x5a\xe4\x97\xc9\x4d\x98\x46\x45\x66\x2c\x86\xc0\xb6\x1f\xa1\x2d\x55\x8c\xb8\x4e\x06\x7b\x50\xcc\x49\x2c\x43\x5c\x17\xbb\xf7\xc2\xd9\xab\x92\x1b\x1e\x9f\xbc\x7f\x3e\x32\x3d\x79\x36\x72\xf1\xd9\xc8\xdd\xd9\xc9\x8b\x37\x9b\x43\x67\xd3\xf7\xdf\x45\x7d\x7a\x2b\x39\x82\x91\xbd\x75\xb7\x92\x13\xc5\x61\x7f\x9d\x42\x5b\x40\x48\xd6\x5e\xc2\xfe\x5f\x6e\x5e\x98\xba\xfe\xf6\xe7\x0e\x9c\xf3\xbe\xc1\xdd\x6c\x6a\x03\x70\x7d\x61\xe2\xda\x7d\x9b\xf7\xce\xcf\xef\x45\xf9\xea\x89\xbf\x48\xe2\x85\x78\x5b\x19\xea\xad\x91\x75\x54\xfd\x8d\xec\xda\xa5\x73\x2d\x55\xf5\x97\x49\x69\x55\xf8\x60\x5d\x5b\x6b\xfd\x6a\x97\xe5\xa3\xbc\xbc\xb0\xd4\xda\x8a\x7e\x2e\x57\xbd\x0e\x72\xf9\x26\x6e\x81\x48\x59\x6a\x51\xe2\xf2\x4a\x2f\xb8\x6e\xe1\x33\xa6\x38\xe1\x54\x71\xde\xa4\x92\x07\xda\x17\x6d\x66\x56\x31\x88\x40\xeb\x59\xfe\xa4\x05\x95\x01\x84\xaa\x59\x3c\x2a\x65\xbd\xf8\xc9\xa5\xa3\x68\x2a\x87\x34\xb4\xe0\xc6\xa8\x76\x1c\x4c\xb0\xc8\x73\xbe\xe8\x81\xe8\x84\x85\xee\xf5\x26\x35\xd4\xb5\xdb\xdb\xf6\xb6\xfb\x0b\xca\xd3\x72\xd2\xac\xdd\xd5\x29\x37\xe2\x44\x1a\xc5\x3c\x38\x2d\x9a\xb2\xc8\xb5\xc1\x8c\xda\x56\xdf\x71\x12\x36\x64\xdf\xe7\x01\xbd\x23\x45\xb7\x56\x4a\x7e\x4b\x0f\x1d\x2c\x43\x2b\x69\x27\xa5\xbf\x91\xaa\x7c\x8e\x02\xff\x1c\x6a\xef\x9e\x8f\xcf\x26\xe1\xa0\x9e\x64\x0f\x1f\xaf\xed\xf2\x43\x55\x9d\xfe\x94\x7c\xb8\xb8\xb3\x1f\xe6\x37\xd7\xf1\xe3\x27\xf3\x78\x77\x6b\x92\xfc\xb7\x6a\xfc\xea\x61\xe7\xf1\xbb\x78\xe6\xa9\x9d\x35\x8b\xef\xe2\x65\x42\x19\xda\x5f\x2c\x68\x3c\x29\xec\x32\x25\x26\xfe\x28\x6f\x7d\xc8\x57\xd5\xd2\xbf\x23\x4e\x64\xbd\x34\xcf\xb7\x08\x32\xe0\x42\x5b\x08\x94\x25\x8d\x6e\x2b\xb0\x65\xc9\x7b\xc5\xb0\x4c\x04\x34\xfd\x87\x7d\xb0\x7c\x21\xeb\x87\x5c\xf2\x27\xaf\xd6\x61\x5e\x5b\x0f\xe1\xf3\x67\x2f\x65\xf5\x31\xd8\xbe\x0a\x23\x6f\x23\xf9\xf7\x23\xdc\x5b\x40\xb4\x91\xf7\xd3\xe9\xb8\x7d\xc9\xc1\x21\xe1\xaa\x17\xe8\x1a\x37\xbc\x16\x31\xe4\xf6\xa3\x8b\xf7\xf2\x96\x43\xf7\x9e\x92\x43\x51\xed\x5b\x15\xcf\xe8\x24\x99\x36\xd5\x39\xb5\xed\xf5\xb4\xef\xa8\x01\x3f\x12\x28\x1c\xf5\xfb\x6d\x55\x72\xf4\x2f\xb7\x94\xdc\xff\x5f\x5f\x24\xd9\x2f\x38\xa6\x3d\x44\x57\xf9\xf6\x04\xa9\xca\xc4\xe1\xfe\xf6\xfe\x4e\xd7\xe9\x39\x19\xdf\xb4\xbb\xbb\xa8\xd5\xbd\xf2\x21\x25\x01\xa3\x46\xd1\xf8\xd5\xfd\x7a\x59\xac\xbd\xd7\xd7\xe3\xf8\x8b\xff\x02\x30\x85\x9e\xff\x8f\x29\x00\x00")
 
 func bindataSampleopenbazaarConfBytes() ([]byte, error) {
 	return bindataRead(
@@ -93,10 +93,10 @@ func bindataSampleopenbazaarConf() (*asset, error) {
 
 	info := bindataFileInfo{
 		name:        "sample-openbazaar.conf",
-		size:        8822,
+		size:        10639,
 		md5checksum: "",
 		mode:        os.FileMode(436),
-		modTime:     time.Unix(1627226834, 0),
+		modTime:     time.Unix(1792196321, 0),
 	}
 
 	a := &asset{bytes: bytes, info: info}
@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -60,40 +61,49 @@ var (
 //
 // See loadConfig for details on the configuration load process.
 type Config struct {
-	ShowVersion            bool     `short:"v" long:"version" description:"Display version information and exit"`
-	ConfigFile             string   `short:"C" long:"configfile" description:"Path to configuration file"`
-	DataDir                string   `short:"d" long:"datadir" description:"Directory to store data"`
-	LogDir                 string   `long:"logdir" description:"Directory to log output."`
-	LogLevel               string   `short:"l" long:"loglevel" description:"set the logging level [debug, info, notice, warning, error, critical]" default:"info"`
-	BoostrapAddrs          []string `long:"bootstrapaddr" description:"Override the default bootstrap addresses with the provided values"`
-	SwarmAddrs             []string `long:"swarmaddr" description:"Override the default swarm addresses with the provided values"`
-	GatewayAddr            string   `long:"gatewayaddr" description:"Override the default gateway address with the provided value"`
-	StoreAndForwardServers []string `long:"snfserver" description:"A peerID of a store and forward server to use for receiving messages while offline."`
-	Testnet                bool     `short:"t" long:"testnet" description:"Use the test network"`
-	DisableNATPortMap      bool     `long:"noupnp" description:"Disable use of upnp."`
-	IPNSQuorum             uint     `long:"ipnsquorum" description:"The size of the IPNS quorum to use. Smaller is faster but less up-to-date." default:"2"`
-	IPNSResolver           string   `long:"ipnsresolver" description:"If a URL is provided here the node will resolve IPNS records by querying this server instead of using the peer-to-peer network."`
-	NoIPNSPubsub           bool     `long:"noipnsps" description:"Disable use of IPNS pubsub."`
-	ExchangeRateProviders  []string `long:"exchangerateprovider" description:"API URL to use for exchange rates. Must conform to the BitcoinAverage format." default:"https://ticker.openbazaar.org/api"`
-	UseSSL                 bool     `long:"ssl" description:"Use SSL on the API"`
-	SSLCertFile            string   `long:"sslcertfile" description:"Path to the SSL certificate file"`
-	SSLKeyFile             string   `long:"sslkeyfile" description:"Path to the SSL key file"`
-	APIUsername            string   `short:"u" long:"apiusername" description:"The username to use with the API authentication"`
-	APIPassword            string   `short:"P" long:"apipassword" description:"The password to use with the API authentication"`
-	APICookie              string   `long:"apicookie" description:"A cookie to use for authentication in addition or in place of the un/pw. If set the cookie must be put in the request header."`
-	APIAllowedIPs          []string `long:"allowedip" description:"Only allow API connections from these IP addresses"`
-	APIAllowAllOrigins     bool     `long:"apiallowallorigins" description:"Cors option to allow all origins on the API."`
-	APIPublicGateway       bool     `long:"publicgateway" description:"When this option is used only public GET methods will be allowed in the API"`
-	Profile                string   `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
-	CPUProfile             string   `long:"cpuprofile" description:"Write CPU profile to the specified file"`
-	IPFSOnly               bool     `long:"ipfsonly" description:"Disable all OpenBazaar functionality except the IPFS networking."`
-	EnabledWallets         []string `long:"enabledwallet" description:"Only enable wallets in this list. Available wallets: [BTC, BCH, LTC, ZEC, ETH]"`
-	UserAgentComment       string   `long:"uacomment" description:"Comment to add to the user agent."`
-	EnableSNFServer        bool     `long:"enablesnfserver" description:"Enable this node to operate as a store-and-forward server."`
-	SNFServerPeers         []string `long:"snfpeer" description:"A list of other store-and-forward servers to replicate snf data to. This is only used when the snf server is enabled."`
-	Tor                    bool     `long:"tor" description:"Proxy all incoming and outgoing connections over the Tor network exclusively."`
-	DualStack              bool     `long:"dualstack" description:"Listen for incoming connections via Tor in addition to via the clearnet. This mode is not private."`
-	DHTClientOnly          bool     `long:"dhtclientonly" description:"Disable participating in serving data in the DHT. This should be used if your node is undialable."`
+	ShowVersion              bool          `short:"v" long:"version" description:"Display version information and exit"`
+	ConfigFile               string        `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir                  string        `short:"d" long:"datadir" description:"Directory to store data"`
+	LogDir                   string        `long:"logdir" description:"Directory to log output."`
+	LogLevel                 string        `short:"l" long:"loglevel" description:"set the logging level [debug, info, notice, warning, error, critical]" default:"info"`
+	BoostrapAddrs            []string      `long:"bootstrapaddr" description:"Override the default bootstrap addresses with the provided values"`
+	SwarmAddrs               []string      `long:"swarmaddr" description:"Override the default swarm addresses with the provided values"`
+	GatewayAddr              string        `long:"gatewayaddr" description:"Override the default gateway address with the provided value"`
+	StoreAndForwardServers   []string      `long:"snfserver" description:"A peerID of a store and forward server to use for receiving messages while offline."`
+	Testnet                  bool          `short:"t" long:"testnet" description:"Use the test network"`
+	DisableNATPortMap        bool          `long:"noupnp" description:"Disable use of upnp."`
+	IPNSQuorum               uint          `long:"ipnsquorum" description:"The size of the IPNS quorum to use. Smaller is faster but less up-to-date." default:"2"`
+	IPNSResolver             string        `long:"ipnsresolver" description:"If a URL is provided here the node will resolve IPNS records by querying this server instead of using the peer-to-peer network."`
+	NoIPNSPubsub             bool          `long:"noipnsps" description:"Disable use of IPNS pubsub."`
+	ExchangeRateProviders    []string      `long:"exchangerateprovider" description:"Source to use for exchange rates. Format is format:location where format is one of openbazaar, coingecko, kraken or file, for example kraken:https://api.kraken.com/0/public/Ticker?pair=XBTUSD or file:/path/to/rates.json. A URL without a format must conform to the BitcoinAverage format. The median of all sources is used." default:"https://ticker.openbazaar.org/api"`
+	ExchangeRateMaxDeviation float64       `long:"exchangeratemaxdeviation" description:"The fraction by which an exchange rate source may deviate from the median before it is rejected. Zero disables rejection." default:"0.1"`
+	UseSSL                   bool          `long:"ssl" description:"Use SSL on the API"`
+	SSLCertFile              string        `long:"sslcertfile" description:"Path to the SSL certificate file"`
+	SSLKeyFile               string        `long:"sslkeyfile" description:"Path to the SSL key file"`
+	APIUsername              string        `short:"u" long:"apiusername" description:"The username to use with the API authentication"`
+	APIPassword              string        `short:"P" long:"apipassword" description:"The password to use with the API authentication"`
+	APICookie                string        `long:"apicookie" description:"A cookie to use for authentication in addition or in place of the un/pw. If set the cookie must be put in the request header."`
+	APIAllowedIPs            []string      `long:"allowedip" description:"Only allow API connections from these IP addresses"`
+	APIAllowAllOrigins       bool          `long:"apiallowallorigins" description:"Cors option to allow all origins on the API."`
+	APIPublicGateway         bool          `long:"publicgateway" description:"When this option is used only public GET methods will be allowed in the API"`
+	Profile                  string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
+	CPUProfile               string        `long:"cpuprofile" description:"Write CPU profile to the specified file"`
+	IPFSOnly                 bool          `long:"ipfsonly" description:"Disable all OpenBazaar functionality except the IPFS networking."`
+	EnabledWallets           []string      `long:"enabledwallet" description:"Only enable wallets in this list. Available wallets: [BTC, BCH, LTC, ZEC, ETH]"`
+	UserAgentComment         string        `long:"uacomment" description:"Comment to add to the user agent."`
+	EnableSNFServer          bool          `long:"enablesnfserver" description:"Enable this node to operate as a store-and-forward server."`
+	SNFServerPeers           []string      `long:"snfpeer" description:"A list of other store-and-forward servers to replicate snf data to. This is only used when the snf server is enabled."`
+	Tor                      bool          `long:"tor" description:"Proxy all incoming and outgoing connections over the Tor network exclusively."`
+	DualStack                bool          `long:"dualstack" description:"Listen for incoming connections via Tor in addition to via the clearnet. This mode is not private."`
+	DHTClientOnly            bool          `long:"dhtclientonly" description:"Disable participating in serving data in the DHT. This should be used if your node is undialable."`
+	RateLimit                float64       `long:"ratelimit" description:"The number of messages per second each peer may send of each message type." default:"5"`
+	RateLimitBurst           int           `long:"ratelimitburst" description:"The number of messages of each type a peer may send at once before the rate limit applies." default:"50"`
+	BanScore                 float64       `long:"banscore" description:"The misbehavior score at which a peer is temporarily banned." default:"100"`
+	BanDuration              time.Duration `long:"banduration" description:"How long a misbehaving peer is banned for." default:"1h"`
+	MessageTTL               []string      `long:"messagettl" description:"How long to try delivering outgoing messages of the given type before giving up. Format is TYPE:duration, for example CHAT:720h. ORDER and DISPUTE messages are marked failed instead of deleted."`
+	BackupDir                string        `long:"backupdir" description:"If set an encrypted backup of the repo is written to this directory every backup interval. The passphrase is read from the OB_BACKUP_PASSPHRASE environment variable."`
+	BackupInterval           time.Duration `long:"backupinterval" description:"How often to write a scheduled backup." default:"24h"`
+	BackupKeep               int           `long:"backupkeep" description:"The number of scheduled backups to keep. Older backups are deleted. Zero keeps all of them." default:"7"`
 }
 
 // LoadConfig initializes and parses the config using a config file and command
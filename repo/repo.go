@@ -465,10 +465,12 @@ func autoMigrateDatabase(db database.Database) error {
 		&models.OutgoingMessage{},
 		&models.IncomingMessage{},
 		&models.ChatMessage{},
+		&models.ChatAttachment{},
 		&models.NotificationRecord{},
 		&models.FollowerStat{},
 		&models.FollowSequence{},
 		&models.Coupon{},
+		&models.InventoryItem{},
 		&models.Event{},
 		&models.Order{},
 		&models.TransactionMetadata{},
@@ -476,6 +478,12 @@ func autoMigrateDatabase(db database.Database) error {
 		&models.StoreAndForwardServers{},
 		&models.Case{},
 		&models.Channel{},
+		&models.ChannelPolicy{},
+		&models.ChannelPeerRule{},
+		&models.ChannelDeletion{},
+		&models.PrivateChannel{},
+		&models.ChannelKey{},
+		&models.ExchangeRateRecord{},
 	}
 
 	return db.Update(func(tx database.Tx) error {
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"errors"
+	iwallet "github.com/cpacia/wallet-interface"
+)
+
+// BumpMethod is the method used to increase the fee of an unconfirmed
+// transaction.
+type BumpMethod string
+
+const (
+	// BumpRBF replaces the transaction with one spending the same inputs
+	// and paying a higher fee out of the change.
+	BumpRBF BumpMethod = "RBF"
+	// BumpCPFP spends one of our outputs of the transaction in a child
+	// transaction which pays enough fee for both.
+	BumpCPFP BumpMethod = "CPFP"
+)
+
+var (
+	// ErrUtxoNotFound is returned when a selected outpoint is not an
+	// unspent output of the wallet.
+	ErrUtxoNotFound = errors.New("utxo not found")
+
+	// ErrCannotBump is returned when the fee of a transaction cannot be
+	// increased with the requested method.
+	ErrCannotBump = errors.New("transaction fee cannot be bumped")
+)
+
+// Utxo is an unspent output controlled by the wallet.
+type Utxo struct {
+	Outpoint string         `json:"outpoint"`
+	Address  string         `json:"address"`
+	Amount   iwallet.Amount `json:"amount"`
+	Height   uint64         `json:"height"`
+}
+
+// SpendOutput is a single output of a spend.
+type SpendOutput struct {
+	Address iwallet.Address
+	Amount  iwallet.Amount
+}
+
+// SpendRequest describes a transaction paying one or more outputs.
+type SpendRequest struct {
+	Outputs []SpendOutput
+
+	// Utxos is an optional list of hex encoded outpoints to spend. If it
+	// is empty the wallet selects the inputs itself.
+	Utxos []string
+
+	// FeeLevel is interpreted the same way as in iwallet.Wallet's Spend.
+	// A positive value is a custom fee rate in the coin's base unit per
+	// byte.
+	FeeLevel iwallet.FeeLevel
+
+	// DryRun builds the transaction without preparing it for broadcast.
+	DryRun bool
+}
+
+// SpendResult is the transaction built for a SpendRequest or a fee bump.
+type SpendResult struct {
+	Txid  iwallet.TransactionID
+	Fee   iwallet.Amount
+	RawTx []byte
+}
+
+// CoinControl is an optional interface that the wallet may implement to
+// give the user control over how transactions are built. The database Tx
+// rules for Commit() and Rollback() are the same as for iwallet.Wallet's
+// Spend.
+type CoinControl interface {
+	// ListUnspent returns the unspent outputs of the wallet.
+	ListUnspent() ([]Utxo, error)
+
+	// SpendOutputs builds a transaction paying all the outputs. If the
+	// request is a dry run the transaction is returned but the dbtx is
+	// not used.
+	SpendOutputs(dbtx iwallet.Tx, req *SpendRequest) (*SpendResult, error)
+
+	// BumpFee increases the fee of an unconfirmed transaction to the fee
+	// level using the given method.
+	BumpFee(dbtx iwallet.Tx, txid iwallet.TransactionID, method BumpMethod, feeLevel iwallet.FeeLevel) (*SpendResult, error)
+}
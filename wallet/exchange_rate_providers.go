@@ -0,0 +1,244 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cpacia/openbazaar3.0/models"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// provider is an interface to a specific exchange rate API.
+type provider interface {
+	// fetchRates returns the rates for each currency denominated in
+	// the reserve currency.
+	fetchRates() (map[models.CurrencyCode]float64, error)
+
+	// name returns the format and source of the provider for reporting.
+	name() (string, string)
+}
+
+// providerFormats is the registry of supported exchange rate formats. Each
+// entry builds a provider from the location in the source string.
+var providerFormats = map[string]func(location string, client *http.Client) provider{
+	"openbazaar": func(location string, client *http.Client) provider {
+		return &openBazaarAPI{location, client}
+	},
+	"coingecko": func(location string, client *http.Client) provider {
+		return &coinGeckoAPI{location, client}
+	},
+	"kraken": func(location string, client *http.Client) provider {
+		return &krakenAPI{location, client}
+	},
+	"file": func(location string, client *http.Client) provider {
+		return &fileProvider{location}
+	},
+}
+
+// newProvider parses a source of the form format:location. Sources which
+// don't start with a registered format are treated as openbazaar URLs.
+func newProvider(source string, client *http.Client) (provider, error) {
+	format, location := "openbazaar", source
+	if parts := strings.SplitN(source, ":", 2); len(parts) == 2 {
+		if _, ok := providerFormats[strings.ToLower(parts[0])]; ok {
+			format, location = strings.ToLower(parts[0]), parts[1]
+		}
+	}
+	if location == "" {
+		return nil, fmt.Errorf("invalid exchange rate provider %s", source)
+	}
+	if format != "file" {
+		u, err := url.Parse(location)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid exchange rate provider url %s", location)
+		}
+	}
+	return providerFormats[format](location, client), nil
+}
+
+// redactURL removes the query string, which may contain an API key, from
+// a provider URL before it is reported.
+func redactURL(location string) string {
+	u, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery = ""
+	u.User = nil
+	return u.String()
+}
+
+// getJSON fetches the URL and decodes the JSON response into v.
+func getJSON(client *http.Client, location string, v interface{}) error {
+	resp, err := client.Get(location)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// validRate returns whether the rate can be used.
+func validRate(rate float64) bool {
+	return rate > 0 && !math.IsInf(rate, 0) && !math.IsNaN(rate)
+}
+
+// openBazaarAPI is an implementation of the provider interface which connects to the openbazaar.org API.
+type openBazaarAPI struct {
+	url    string
+	client *http.Client
+}
+
+type apiRate struct {
+	Last float64 `json:"last"`
+}
+
+func (b *openBazaarAPI) name() (string, string) {
+	return "openbazaar", redactURL(b.url)
+}
+
+// fetchRates returns a rate map using the BitcoinAverage style API format:
+// {"USD": {"last": 12863.08}}.
+func (b *openBazaarAPI) fetchRates() (map[models.CurrencyCode]float64, error) {
+	apiRates := make(map[string]apiRate)
+	if err := getJSON(b.client, b.url, &apiRates); err != nil {
+		return nil, err
+	}
+
+	rates := make(map[models.CurrencyCode]float64)
+	for cc, rate := range apiRates {
+		if validRate(rate.Last) {
+			rates[models.CurrencyCode(strings.ToUpper(cc))] = rate.Last
+		}
+	}
+	return rates, nil
+}
+
+// coinGeckoAPI is an implementation of the provider interface which uses the
+// CoinGecko exchange_rates API format.
+type coinGeckoAPI struct {
+	url    string
+	client *http.Client
+}
+
+func (c *coinGeckoAPI) name() (string, string) {
+	return "coingecko", redactURL(c.url)
+}
+
+// fetchRates returns a rate map using the CoinGecko style API format:
+// {"rates": {"usd": {"value": 12863.08}}}.
+func (c *coinGeckoAPI) fetchRates() (map[models.CurrencyCode]float64, error) {
+	var resp struct {
+		Rates map[string]struct {
+			Value float64 `json:"value"`
+		} `json:"rates"`
+	}
+	if err := getJSON(c.client, c.url, &resp); err != nil {
+		return nil, err
+	}
+
+	rates := make(map[models.CurrencyCode]float64)
+	for cc, rate := range resp.Rates {
+		if validRate(rate.Value) {
+			rates[models.CurrencyCode(strings.ToUpper(cc))] = rate.Value
+		}
+	}
+	return rates, nil
+}
+
+// krakenAPI is an implementation of the provider interface which uses the
+// Kraken public ticker API format.
+type krakenAPI struct {
+	url    string
+	client *http.Client
+}
+
+func (k *krakenAPI) name() (string, string) {
+	return "kraken", redactURL(k.url)
+}
+
+// fetchRates returns a rate map using the Kraken style API format:
+// {"error": [], "result": {"XXBTZUSD": {"c": ["12863.08", "0.1"]}}}. Pairs
+// quoted in BTC, such as XETHXXBT, are inverted.
+func (k *krakenAPI) fetchRates() (map[models.CurrencyCode]float64, error) {
+	var resp struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			C []string `json:"c"`
+		} `json:"result"`
+	}
+	if err := getJSON(k.client, k.url, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, errors.New(strings.Join(resp.Error, ", "))
+	}
+
+	rates := make(map[models.CurrencyCode]float64)
+	for pair, ticker := range resp.Result {
+		if len(ticker.C) == 0 {
+			continue
+		}
+		last, err := strconv.ParseFloat(ticker.C[0], 64)
+		if err != nil || !validRate(last) {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(pair, "XXBT"):
+			rates[krakenAsset(pair[4:])] = last
+		case strings.HasPrefix(pair, "XBT"):
+			rates[krakenAsset(pair[3:])] = last
+		case strings.HasSuffix(pair, "XXBT"):
+			rates[krakenAsset(pair[:len(pair)-4])] = 1 / last
+		case strings.HasSuffix(pair, "XBT"):
+			rates[krakenAsset(pair[:len(pair)-3])] = 1 / last
+		}
+	}
+	return rates, nil
+}
+
+// krakenAsset converts a Kraken asset name, such as ZUSD or XETH, into a
+// currency code.
+func krakenAsset(asset string) models.CurrencyCode {
+	if len(asset) == 4 && (asset[0] == 'X' || asset[0] == 'Z') {
+		asset = asset[1:]
+	}
+	return models.CurrencyCode(asset)
+}
+
+// fileProvider is an implementation of the provider interface which reads
+// the rates from a static JSON file. It's intended for air-gapped setups
+// where the file is updated by some other means. The file maps each currency
+// code to its rate in the reserve currency: {"USD": 12863.08}.
+type fileProvider struct {
+	path string
+}
+
+func (f *fileProvider) name() (string, string) {
+	return "file", f.path
+}
+
+func (f *fileProvider) fetchRates() (map[models.CurrencyCode]float64, error) {
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	fileRates := make(map[string]float64)
+	if err := json.Unmarshal(data, &fileRates); err != nil {
+		return nil, err
+	}
+
+	rates := make(map[models.CurrencyCode]float64)
+	for cc, rate := range fileRates {
+		if validRate(rate) {
+			rates[models.CurrencyCode(strings.ToUpper(cc))] = rate
+		}
+	}
+	return rates, nil
+}
@@ -0,0 +1,232 @@
+package wallet
+
+import (
+	"encoding/json"
+	"github.com/cpacia/openbazaar3.0/models"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func newJSONServer(t *testing.T, v interface{}) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestExchangeRateProvider_Formats(t *testing.T) {
+	obServer := newJSONServer(t, map[string]apiRate{
+		"USD": {Last: 12863.08},
+		"eur": {Last: 11444.58},
+	})
+	geckoServer := newJSONServer(t, map[string]interface{}{
+		"rates": map[string]interface{}{
+			"usd": map[string]interface{}{"value": 12863.08},
+			"eur": map[string]interface{}{"value": 11444.58},
+		},
+	})
+	krakenServer := newJSONServer(t, map[string]interface{}{
+		"error": []string{},
+		"result": map[string]interface{}{
+			"XXBTZUSD": map[string]interface{}{"c": []string{"12863.08", "0.1"}},
+			"XBTEUR":   map[string]interface{}{"c": []string{"11444.58", "0.1"}},
+			"XETHXXBT": map[string]interface{}{"c": []string{"0.025", "1.5"}},
+		},
+	})
+	krakenErrServer := newJSONServer(t, map[string]interface{}{
+		"error": []string{"EQuery:Unknown asset pair"},
+	})
+
+	dir, err := ioutil.TempDir("", "rates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ratesFile := path.Join(dir, "rates.json")
+	if err := ioutil.WriteFile(ratesFile, []byte(`{"USD": 12863.08, "EUR": 11444.58, "BAD": -1}`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source         string
+		expectedFormat string
+		expectedRates  map[models.CurrencyCode]float64
+		expectErr      bool
+	}{
+		{
+			source:         obServer.URL,
+			expectedFormat: "openbazaar",
+			expectedRates:  map[models.CurrencyCode]float64{"USD": 12863.08, "EUR": 11444.58},
+		},
+		{
+			source:         "openbazaar:" + obServer.URL,
+			expectedFormat: "openbazaar",
+			expectedRates:  map[models.CurrencyCode]float64{"USD": 12863.08, "EUR": 11444.58},
+		},
+		{
+			source:         "coingecko:" + geckoServer.URL,
+			expectedFormat: "coingecko",
+			expectedRates:  map[models.CurrencyCode]float64{"USD": 12863.08, "EUR": 11444.58},
+		},
+		{
+			source:         "kraken:" + krakenServer.URL + "?pair=XBTUSD",
+			expectedFormat: "kraken",
+			expectedRates:  map[models.CurrencyCode]float64{"USD": 12863.08, "EUR": 11444.58, "ETH": 40},
+		},
+		{
+			source:         "kraken:" + krakenErrServer.URL,
+			expectedFormat: "kraken",
+			expectErr:      true,
+		},
+		{
+			source:         "file:" + ratesFile,
+			expectedFormat: "file",
+			expectedRates:  map[models.CurrencyCode]float64{"USD": 12863.08, "EUR": 11444.58},
+		},
+		{
+			source:         "file:" + path.Join(dir, "missing.json"),
+			expectedFormat: "file",
+			expectErr:      true,
+		},
+	}
+
+	for i, test := range tests {
+		p, err := newProvider(test.source, http.DefaultClient)
+		if err != nil {
+			t.Errorf("Test %d: %s", i, err)
+			continue
+		}
+		if format, _ := p.name(); format != test.expectedFormat {
+			t.Errorf("Test %d: expected format %s, got %s", i, test.expectedFormat, format)
+		}
+		rates, err := p.fetchRates()
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: %s", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(rates, test.expectedRates) {
+			t.Errorf("Test %d: expected rates %v, got %v", i, test.expectedRates, rates)
+		}
+	}
+}
+
+func TestExchangeRateProvider_Median(t *testing.T) {
+	server1 := newJSONServer(t, map[string]apiRate{"USD": {Last: 100}, "EUR": {Last: 90}})
+	server2 := newJSONServer(t, map[string]apiRate{"USD": {Last: 101}})
+	server3 := newJSONServer(t, map[string]apiRate{"USD": {Last: 1000}, "EUR": {Last: 91}})
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	provider, err := NewExchangeRateProvider([]string{
+		server1.URL,
+		"coingecko:" + failing.URL,
+		server2.URL,
+		server3.URL + "?key=secret",
+	}, DefaultMaxDeviation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := provider.GetAllRates("BTC", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates["USD"].String() != "10049" {
+		t.Errorf("Expected USD rate of 10049, got %s", rates["USD"])
+	}
+	if rates["EUR"].String() != "9049" {
+		t.Errorf("Expected EUR rate of 9049, got %s", rates["EUR"])
+	}
+
+	sources := provider.Sources()
+	if len(sources) != 4 {
+		t.Fatalf("Expected 4 sources, got %d", len(sources))
+	}
+	if sources[0].Currencies != 2 || len(sources[0].Rejected) != 0 || sources[0].Error != "" {
+		t.Errorf("Incorrect source 0: %v", sources[0])
+	}
+	if sources[1].Format != "coingecko" || sources[1].Error == "" || sources[1].Currencies != 0 {
+		t.Errorf("Incorrect source 1: %v", sources[1])
+	}
+	if sources[2].Currencies != 1 || len(sources[2].Rejected) != 0 {
+		t.Errorf("Incorrect source 2: %v", sources[2])
+	}
+	if sources[3].Currencies != 1 || !reflect.DeepEqual(sources[3].Rejected, []models.CurrencyCode{"USD"}) {
+		t.Errorf("Incorrect source 3: %v", sources[3])
+	}
+	if sources[3].Source != server3.URL {
+		t.Errorf("Expected redacted source %s, got %s", server3.URL, sources[3].Source)
+	}
+}
+
+func TestExchangeRateProvider_TwoSourcesDisagree(t *testing.T) {
+	server1 := newJSONServer(t, map[string]apiRate{"USD": {Last: 100}})
+	server2 := newJSONServer(t, map[string]apiRate{"USD": {Last: 200}})
+
+	provider, err := NewExchangeRateProvider([]string{server1.URL, server2.URL}, DefaultMaxDeviation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := provider.GetAllRates("BTC", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates["USD"].String() != "14999" {
+		t.Errorf("Expected USD rate of 14999, got %s", rates["USD"])
+	}
+
+	for i, source := range provider.Sources() {
+		if source.Currencies != 1 || len(source.Rejected) != 0 {
+			t.Errorf("Incorrect source %d: %v", i, source)
+		}
+	}
+}
+
+func TestExchangeRateProvider_AllFail(t *testing.T) {
+	provider, err := NewExchangeRateProvider([]string{"file:/does/not/exist.json"}, DefaultMaxDeviation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.GetAllRates("BTC", true); err == nil {
+		t.Error("Expected error when all providers fail")
+	}
+	sources := provider.Sources()
+	if len(sources) != 1 || sources[0].Error == "" {
+		t.Errorf("Expected source error, got %v", sources)
+	}
+}
+
+func TestNewExchangeRateProvider_Invalid(t *testing.T) {
+	tests := []struct {
+		sources      []string
+		maxDeviation float64
+	}{
+		{sources: []string{"ftp://example.com/rates"}, maxDeviation: DefaultMaxDeviation},
+		{sources: []string{"kraken:"}, maxDeviation: DefaultMaxDeviation},
+		{sources: []string{"coingecko:not a url"}, maxDeviation: DefaultMaxDeviation},
+		{sources: []string{"file:"}, maxDeviation: DefaultMaxDeviation},
+		{sources: []string{"https://example.com/api"}, maxDeviation: -1},
+	}
+	for i, test := range tests {
+		if _, err := NewExchangeRateProvider(test.sources, test.maxDeviation); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
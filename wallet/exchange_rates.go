@@ -1,7 +1,6 @@
 package wallet
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/cpacia/openbazaar3.0/models"
@@ -9,7 +8,7 @@ import (
 	iwallet "github.com/cpacia/wallet-interface"
 	"math"
 	"math/big"
-	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -21,34 +20,57 @@ import (
 // ratio of BTC/BCH and use it to calculate the BCH USD price.
 const ReserveCurrency = models.CurrencyCode("BTC")
 
+// DefaultMaxDeviation is the default fraction by which a provider's rate
+// may differ from the median before it is rejected.
+const DefaultMaxDeviation = 0.1
+
+// minSourcesForRejection is the number of providers which must report a
+// currency before outliers are rejected. With only two sources there is no
+// way to tell which one is wrong.
+const minSourcesForRejection = 3
+
 // ExchangeRateProvider provides exchange rate data to be used by OpenBazaar.
 // It gives the exchange rate from any listed cryptocurrency into any other
-// currency.
+// currency. Rates are queried from all providers in parallel and the median
+// rate for each currency is used.
 type ExchangeRateProvider struct {
-	cache       map[models.CurrencyCode]map[models.CurrencyCode]iwallet.Amount
-	lastQueried map[models.CurrencyCode]time.Time
-	mtx         sync.Mutex
-	providers   []provider
+	cache        map[models.CurrencyCode]map[models.CurrencyCode]iwallet.Amount
+	lastQueried  map[models.CurrencyCode]time.Time
+	sources      []models.ExchangeRateSource
+	maxDeviation float64
+	mtx          sync.Mutex
+	providers    []provider
 }
 
-// NewExchangeRateProvider returns a new ExchangeRateProvider. If proxy is
-// not nil the http connection to the API server will use the proxy. The
-// provided sources must conform to the BitcoinAverage API specification.
-func NewExchangeRateProvider(sources []string) *ExchangeRateProvider {
+// NewExchangeRateProvider returns a new ExchangeRateProvider. Each source is
+// formatted as format:location, for example kraken:https://api.kraken.com/0/public/Ticker
+// or file:/path/to/rates.json. Sources without a known format prefix must
+// conform to the BitcoinAverage API specification. Rates which deviate from
+// the median by more than maxDeviation are rejected. If maxDeviation is zero
+// no rates are rejected.
+func NewExchangeRateProvider(sources []string, maxDeviation float64) (*ExchangeRateProvider, error) {
+	if maxDeviation < 0 {
+		return nil, errors.New("max deviation may not be negative")
+	}
 	e := ExchangeRateProvider{
-		cache:       make(map[models.CurrencyCode]map[models.CurrencyCode]iwallet.Amount),
-		lastQueried: make(map[models.CurrencyCode]time.Time),
-		mtx:         sync.Mutex{},
+		cache:        make(map[models.CurrencyCode]map[models.CurrencyCode]iwallet.Amount),
+		lastQueried:  make(map[models.CurrencyCode]time.Time),
+		maxDeviation: maxDeviation,
+		mtx:          sync.Mutex{},
 	}
 
 	client := proxyclient.NewHttpClient()
 	client.Timeout = time.Minute
 
 	for _, src := range sources {
-		e.providers = append(e.providers, &openBazaarAPI{src, client})
+		p, err := newProvider(src, client)
+		if err != nil {
+			return nil, err
+		}
+		e.providers = append(e.providers, p)
 	}
 
-	return &e
+	return &e, nil
 }
 
 // GetRate returns the rate for a given currency converting from the provided base currency.
@@ -76,6 +98,21 @@ func (e *ExchangeRateProvider) GetRate(base models.CurrencyCode, to models.Curre
 	return amount, nil
 }
 
+// GetCachedRate returns the rate for a given currency from the cache without
+// querying the providers. The bool is false if the rate is not cached or the
+// cache has expired. This is safe to call while holding a database lock.
+func (e *ExchangeRateProvider) GetCachedRate(base models.CurrencyCode, to models.CurrencyCode) (iwallet.Amount, bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	base = models.CurrencyCode(strings.TrimPrefix(strings.ToUpper(base.String()), "T"))
+	if e.lastQueried[base].Add(time.Minute * 10).Before(time.Now()) {
+		return iwallet.NewAmount(0), false
+	}
+	amount, ok := e.cache[base][to]
+	return amount, ok
+}
+
 // GetUSDRate returns the USD exchange rate for the given coin.
 func (e *ExchangeRateProvider) GetUSDRate(coinType iwallet.CoinType) (iwallet.Amount, error) {
 	return e.GetRate(models.CurrencyCode(coinType.CurrencyCode()), models.CurrencyCode("USD"), false)
@@ -101,48 +138,102 @@ func (e *ExchangeRateProvider) GetAllRates(base models.CurrencyCode, breakCache
 	return rates, nil
 }
 
-// fetchRatesFromProviders queries the exchange rate sources serially until it gets a response back.
-func (e *ExchangeRateProvider) fetchRatesFromProviders(base models.CurrencyCode) (map[models.CurrencyCode]iwallet.Amount, error) {
-	for _, provider := range e.providers {
-		rates, err := provider.fetchRates(base)
-		if err == nil {
-			return rates, nil
-		}
-	}
-	return nil, errors.New("all exchange rate providers failed")
-}
+// Sources returns the result of the last query to each provider.
+func (e *ExchangeRateProvider) Sources() []models.ExchangeRateSource {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
 
-// provider is an interface to a specific exchange rate API.
-type provider interface {
-	fetchRates(baseCurrency models.CurrencyCode) (map[models.CurrencyCode]iwallet.Amount, error)
+	sources := make([]models.ExchangeRateSource, len(e.sources))
+	copy(sources, e.sources)
+	return sources
 }
 
-// openBazaarAPI is an implementation of the provider interface which connects to the openbazaar.org API.
-type openBazaarAPI struct {
-	url    string
-	client *http.Client
-}
+// fetchRatesFromProviders queries the exchange rate sources in parallel and
+// converts the median of their rates into the base currency.
+func (e *ExchangeRateProvider) fetchRatesFromProviders(base models.CurrencyCode) (map[models.CurrencyCode]iwallet.Amount, error) {
+	results := make([]map[models.CurrencyCode]float64, len(e.providers))
+	sources := make([]models.ExchangeRateSource, len(e.providers))
+
+	var wg sync.WaitGroup
+	wg.Add(len(e.providers))
+	for i, p := range e.providers {
+		go func(i int, p provider) {
+			defer wg.Done()
+			sources[i].Format, sources[i].Source = p.name()
+			rates, err := p.fetchRates()
+			if err != nil {
+				sources[i].Error = err.Error()
+				return
+			}
+			results[i] = rates
+		}(i, p)
+	}
+	wg.Wait()
 
-type apiRate struct {
-	Last float64 `json:"last"`
+	rates := aggregateRates(results, sources, e.maxDeviation)
+	e.sources = sources
+	if len(rates) == 0 {
+		return nil, errors.New("all exchange rate providers failed")
+	}
+	return convertRates(base, rates)
 }
 
-// fetchRates returns a rate map for the given base currency as does the conversion from the
-// reserve currency as necessary.
-func (b *openBazaarAPI) fetchRates(base models.CurrencyCode) (map[models.CurrencyCode]iwallet.Amount, error) {
-	rates := make(map[string]apiRate)
-
-	resp, err := b.client.Get(b.url)
-	if err != nil {
-		return nil, err
+// aggregateRates returns the median rate for each currency. Rates which
+// deviate from the median by more than maxDeviation are recorded as rejected
+// in the provider's source and the median of the remaining rates is used.
+// Outliers are only rejected when at least minSourcesForRejection providers
+// report the currency, otherwise the median of the available rates is used.
+func aggregateRates(results []map[models.CurrencyCode]float64, sources []models.ExchangeRateSource, maxDeviation float64) map[models.CurrencyCode]float64 {
+	all := make(map[models.CurrencyCode][]float64)
+	for _, rates := range results {
+		for cc, rate := range rates {
+			all[cc] = append(all[cc], rate)
+		}
 	}
 
-	decoder := json.NewDecoder(resp.Body)
+	aggregated := make(map[models.CurrencyCode]float64)
+	for cc, values := range all {
+		m := median(values)
+		reject := maxDeviation > 0 && len(values) >= minSourcesForRejection
+		var accepted []float64
+		for i, rates := range results {
+			rate, ok := rates[cc]
+			if !ok {
+				continue
+			}
+			if reject && math.Abs(rate-m)/m > maxDeviation {
+				sources[i].Rejected = append(sources[i].Rejected, cc)
+				continue
+			}
+			accepted = append(accepted, rate)
+			sources[i].Currencies++
+		}
+		if len(accepted) > 0 {
+			aggregated[cc] = median(accepted)
+		}
+	}
+	for i := range sources {
+		sort.Slice(sources[i].Rejected, func(j, k int) bool {
+			return sources[i].Rejected[j] < sources[i].Rejected[k]
+		})
+	}
+	return aggregated
+}
 
-	if err := decoder.Decode(&rates); err != nil {
-		return nil, err
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
 	}
+	return sorted[mid]
+}
 
+// convertRates converts the rates, which are denominated in the reserve
+// currency, into amounts for the given base currency.
+func convertRates(base models.CurrencyCode, rates map[models.CurrencyCode]float64) (map[models.CurrencyCode]iwallet.Amount, error) {
 	reserveCurrency, ok := models.CurrencyDefinitions[ReserveCurrency.String()]
 	if !ok {
 		return nil, fmt.Errorf("reserve currency %s is not in map", ReserveCurrency.String())
@@ -152,17 +243,17 @@ func (b *openBazaarAPI) fetchRates(base models.CurrencyCode) (map[models.Currenc
 	reserveMap := make(map[models.CurrencyCode]iwallet.Amount)
 
 	for cc, rate := range rates {
-		def, ok := models.CurrencyDefinitions[cc]
+		def, ok := models.CurrencyDefinitions[cc.String()]
 		if !ok {
 			continue
 		}
 
-		convertedRate, err := reserveOne.ConvertTo(def, rate.Last)
+		convertedRate, err := reserveOne.ConvertTo(def, rate)
 		if err != nil {
 			return nil, err
 		}
 
-		reserveMap[models.CurrencyCode(cc)] = convertedRate.Amount
+		reserveMap[cc] = convertedRate.Amount
 	}
 
 	if base.String() == reserveCurrency.Code.String() {
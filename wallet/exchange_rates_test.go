@@ -47,7 +47,10 @@ func TestExchangeRateProvider_GetRate(t *testing.T) {
 		},
 	)
 
-	provider := NewExchangeRateProvider([]string{"https://ticker.openbazaar.org/api"})
+	provider, err := NewExchangeRateProvider([]string{"https://ticker.openbazaar.org/api"}, DefaultMaxDeviation)
+	if err != nil {
+		t.Fatal(err)
+	}
 	obAPI, ok := provider.providers[0].(*openBazaarAPI)
 	if !ok {
 		t.Fatal("Type assertion failure provider 0 is not openBazaarAPI")
@@ -88,7 +91,10 @@ func TestExchangeRateProvider_GetAllRates(t *testing.T) {
 		},
 	)
 
-	provider := NewExchangeRateProvider([]string{"https://ticker.openbazaar.org/api"})
+	provider, err := NewExchangeRateProvider([]string{"https://ticker.openbazaar.org/api"}, DefaultMaxDeviation)
+	if err != nil {
+		t.Fatal(err)
+	}
 	obAPI, ok := provider.providers[0].(*openBazaarAPI)
 	if !ok {
 		t.Fatal("Type assertion failure provider 0 is not openBazaarAPI")
@@ -146,7 +152,12 @@ func TestExchangeRateProvider_openBazaarAPI(t *testing.T) {
 		},
 	)
 
-	btcRates, err := api.fetchRates("BTC")
+	rates, err := api.fetchRates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	btcRates, err := convertRates("BTC", rates)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -162,7 +173,7 @@ func TestExchangeRateProvider_openBazaarAPI(t *testing.T) {
 		}
 	}
 
-	bchRates, err := api.fetchRates("BCH")
+	bchRates, err := convertRates("BCH", rates)
 	if err != nil {
 		t.Fatal(err)
 	}
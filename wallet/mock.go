@@ -1154,7 +1154,10 @@ func NewMockExchangeRates() (*ExchangeRateProvider, error) {
 		},
 	)
 
-	provider := NewExchangeRateProvider([]string{"https://testrates.com/api"})
+	provider, err := NewExchangeRateProvider([]string{"https://testrates.com/api"}, DefaultMaxDeviation)
+	if err != nil {
+		return nil, err
+	}
 	obAPI, ok := provider.providers[0].(*openBazaarAPI)
 	if !ok {
 		return nil, errors.New("type assertion failure provider 0 is not openBazaarAPI")
@@ -0,0 +1,352 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	iwallet "github.com/cpacia/wallet-interface"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ = CoinControl(&MockWallet{})
+
+// mockTxSize approximates the size in bytes of a transaction with the
+// given number of inputs and outputs.
+func mockTxSize(inputs, outputs int) iwallet.Amount {
+	return iwallet.NewAmount(10 + 148*inputs + 34*outputs)
+}
+
+// mockFeeRate returns the fee per byte paid at the fee level.
+func mockFeeRate(feeLevel iwallet.FeeLevel) iwallet.Amount {
+	switch feeLevel {
+	case iwallet.FLSuperEconomic:
+		return iwallet.NewAmount(1)
+	case iwallet.FlEconomic:
+		return iwallet.NewAmount(2)
+	case iwallet.FlNormal:
+		return iwallet.NewAmount(4)
+	case iwallet.FlPriority:
+		return iwallet.NewAmount(6)
+	}
+	if feeLevel > 0 {
+		return iwallet.NewAmount(int(feeLevel))
+	}
+	return iwallet.NewAmount(4)
+}
+
+// ListUnspent returns the unspent outputs of the wallet sorted by outpoint.
+func (w *MockWallet) ListUnspent() ([]Utxo, error) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	utxos := make([]Utxo, 0, len(w.utxos))
+	for op, utxo := range w.utxos {
+		utxos = append(utxos, Utxo{
+			Outpoint: op,
+			Address:  utxo.address.String(),
+			Amount:   utxo.value,
+			Height:   utxo.height,
+		})
+	}
+	sort.Slice(utxos, func(i, j int) bool {
+		return utxos[i].Outpoint < utxos[j].Outpoint
+	})
+	return utxos, nil
+}
+
+// SpendOutputs builds a transaction paying all of the request's outputs.
+// If utxos are selected only those are spent, otherwise utxos are added in
+// outpoint order until the outputs and fee are covered. Change which would
+// be dust is added to the fee. The raw transaction is the JSON encoding of
+// the transaction.
+func (w *MockWallet) SpendOutputs(dbtx iwallet.Tx, req *SpendRequest) (*SpendResult, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if len(req.Outputs) == 0 {
+		return nil, errors.New("no outputs")
+	}
+	total := iwallet.NewAmount(0)
+	for _, out := range req.Outputs {
+		if out.Amount.Cmp(iwallet.NewAmount(0)) <= 0 {
+			return nil, errors.New("output amount must be positive")
+		}
+		total = total.Add(out.Amount)
+	}
+
+	var (
+		feeRate  = mockFeeRate(req.FeeLevel)
+		selected []mockUtxo
+		totalIn  = iwallet.NewAmount(0)
+		feeFor   = func(inputs int) iwallet.Amount { return feeRate.Mul(mockTxSize(inputs, len(req.Outputs)+1)) }
+	)
+	if len(req.Utxos) > 0 {
+		seen := make(map[string]bool)
+		for _, op := range req.Utxos {
+			op = strings.ToLower(op)
+			utxo, ok := w.utxos[op]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrUtxoNotFound, op)
+			}
+			if seen[op] {
+				continue
+			}
+			seen[op] = true
+			selected = append(selected, utxo)
+			totalIn = totalIn.Add(utxo.value)
+		}
+	} else {
+		ops := make([]string, 0, len(w.utxos))
+		for op := range w.utxos {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		for _, op := range ops {
+			if totalIn.Cmp(total.Add(feeFor(len(selected)))) >= 0 {
+				break
+			}
+			selected = append(selected, w.utxos[op])
+			totalIn = totalIn.Add(w.utxos[op].value)
+		}
+	}
+	fee := feeFor(len(selected))
+	if totalIn.Cmp(total.Add(fee)) < 0 {
+		return nil, errors.New("insufficient funds")
+	}
+
+	txidBytes := make([]byte, 32)
+	rand.Read(txidBytes)
+
+	txn := iwallet.Transaction{
+		ID:        iwallet.TransactionID(hex.EncodeToString(txidBytes)),
+		Timestamp: time.Now(),
+	}
+	for _, utxo := range selected {
+		txn.From = append(txn.From, iwallet.SpendInfo{
+			ID:      utxo.outpoint,
+			Address: utxo.address,
+			Amount:  utxo.value,
+		})
+	}
+	for i, out := range req.Outputs {
+		txn.To = append(txn.To, iwallet.SpendInfo{
+			Address: out.Address,
+			Amount:  out.Amount,
+			ID:      mockOutpoint(txidBytes, i),
+		})
+	}
+	change := totalIn.Sub(total).Sub(fee)
+	if w.IsDust(change) {
+		fee = totalIn.Sub(total)
+	} else {
+		changeAddr, err := w.newAddress()
+		if err != nil {
+			return nil, err
+		}
+		txn.To = append(txn.To, iwallet.SpendInfo{
+			Address: changeAddr,
+			Amount:  change,
+			ID:      mockOutpoint(txidBytes, len(txn.To)),
+		})
+	}
+	txn.Value = w.transactionValue(txn)
+
+	raw, err := json.Marshal(&txn)
+	if err != nil {
+		return nil, err
+	}
+	result := &SpendResult{Txid: txn.ID, Fee: fee, RawTx: raw}
+	if req.DryRun {
+		return result, nil
+	}
+	w.commitOnTx(dbtx, txn, "")
+	return result, nil
+}
+
+// BumpFee increases the fee of an unconfirmed transaction. RBF requires all
+// the inputs to be ours and reduces our change by the extra fee. CPFP spends
+// our largest unspent output of the transaction back to us with a fee that
+// brings the fee rate of both transactions up to the fee level.
+func (w *MockWallet) BumpFee(dbtx iwallet.Tx, txid iwallet.TransactionID, method BumpMethod, feeLevel iwallet.FeeLevel) (*SpendResult, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	parent, ok := w.transactions[txid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	if parent.Height > 0 {
+		return nil, fmt.Errorf("%w: transaction is confirmed", ErrCannotBump)
+	}
+
+	var (
+		feeRate = mockFeeRate(feeLevel)
+		oldFee  = iwallet.NewAmount(0)
+	)
+	for _, in := range parent.From {
+		oldFee = oldFee.Add(in.Amount)
+	}
+	for _, out := range parent.To {
+		oldFee = oldFee.Sub(out.Amount)
+	}
+
+	txidBytes := make([]byte, 32)
+	rand.Read(txidBytes)
+
+	var (
+		txn      iwallet.Transaction
+		fee      iwallet.Amount
+		replaces iwallet.TransactionID
+	)
+	switch method {
+	case BumpRBF:
+		for _, in := range parent.From {
+			if _, ok := w.addrs[in.Address]; !ok {
+				return nil, fmt.Errorf("%w: inputs are not ours", ErrCannotBump)
+			}
+		}
+		fee = feeRate.Mul(mockTxSize(len(parent.From), len(parent.To)))
+		if fee.Cmp(oldFee) <= 0 {
+			return nil, fmt.Errorf("%w: fee level does not increase the fee", ErrCannotBump)
+		}
+		changeIdx := -1
+		for i, out := range parent.To {
+			if _, ok := w.addrs[out.Address]; ok {
+				changeIdx = i
+			}
+		}
+		if changeIdx < 0 {
+			return nil, fmt.Errorf("%w: transaction has no change output", ErrCannotBump)
+		}
+		change := parent.To[changeIdx].Amount.Sub(fee.Sub(oldFee))
+		if change.Cmp(iwallet.NewAmount(0)) <= 0 || w.IsDust(change) {
+			return nil, fmt.Errorf("%w: change is too small", ErrCannotBump)
+		}
+
+		txn = iwallet.Transaction{
+			ID:        iwallet.TransactionID(hex.EncodeToString(txidBytes)),
+			Timestamp: time.Now(),
+			From:      parent.From,
+		}
+		for i, out := range parent.To {
+			if i == changeIdx {
+				out.Amount = change
+			}
+			out.ID = mockOutpoint(txidBytes, i)
+			txn.To = append(txn.To, out)
+		}
+		replaces = parent.ID
+	case BumpCPFP:
+		parentBytes, err := hex.DecodeString(parent.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		var utxo *mockUtxo
+		for i := range parent.To {
+			u, ok := w.utxos[hex.EncodeToString(mockOutpoint(parentBytes, i))]
+			if ok && (utxo == nil || u.value.Cmp(utxo.value) > 0) {
+				utxo = &u
+			}
+		}
+		if utxo == nil {
+			return nil, fmt.Errorf("%w: transaction has no unspent outputs of ours", ErrCannotBump)
+		}
+		fee = feeRate.Mul(mockTxSize(len(parent.From), len(parent.To)).Add(mockTxSize(1, 1))).Sub(oldFee)
+		if fee.Cmp(iwallet.NewAmount(0)) <= 0 {
+			return nil, fmt.Errorf("%w: transaction already pays the fee level", ErrCannotBump)
+		}
+		amount := utxo.value.Sub(fee)
+		if amount.Cmp(iwallet.NewAmount(0)) <= 0 || w.IsDust(amount) {
+			return nil, fmt.Errorf("%w: output is too small", ErrCannotBump)
+		}
+		addr, err := w.newAddress()
+		if err != nil {
+			return nil, err
+		}
+		txn = iwallet.Transaction{
+			ID:        iwallet.TransactionID(hex.EncodeToString(txidBytes)),
+			Timestamp: time.Now(),
+			From: []iwallet.SpendInfo{
+				{ID: utxo.outpoint, Address: utxo.address, Amount: utxo.value},
+			},
+			To: []iwallet.SpendInfo{
+				{ID: mockOutpoint(txidBytes, 0), Address: addr, Amount: amount},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unknown bump method %s", method)
+	}
+	txn.Value = w.transactionValue(txn)
+
+	raw, err := json.Marshal(&txn)
+	if err != nil {
+		return nil, err
+	}
+	w.commitOnTx(dbtx, txn, replaces)
+	return &SpendResult{Txid: txn.ID, Fee: fee, RawTx: raw}, nil
+}
+
+// transactionValue returns the net amount the transaction pays the wallet.
+// The caller must hold the lock.
+func (w *MockWallet) transactionValue(txn iwallet.Transaction) iwallet.Amount {
+	value := iwallet.NewAmount(0)
+	for _, out := range txn.To {
+		if _, ok := w.addrs[out.Address]; ok {
+			value = value.Add(out.Amount)
+		}
+	}
+	for _, in := range txn.From {
+		if _, ok := w.addrs[in.Address]; ok {
+			value = value.Sub(in.Amount)
+		}
+	}
+	return value
+}
+
+// commitOnTx applies the transaction to the wallet and broadcasts it when
+// the dbtx is committed. If replaces is set that transaction and its
+// outputs are removed from the wallet.
+func (w *MockWallet) commitOnTx(dbtx iwallet.Tx, txn iwallet.Transaction, replaces iwallet.TransactionID) {
+	dbtx.(*dbTx).onCommit = func() error {
+		w.mtx.Lock()
+		if replaces != "" {
+			delete(w.transactions, replaces)
+			for op := range w.utxos {
+				if strings.HasPrefix(op, replaces.String()) {
+					delete(w.utxos, op)
+				}
+			}
+		}
+		for _, in := range txn.From {
+			delete(w.utxos, hex.EncodeToString(in.ID))
+		}
+		for _, out := range txn.To {
+			if _, ok := w.addrs[out.Address]; ok {
+				w.utxos[hex.EncodeToString(out.ID)] = mockUtxo{
+					outpoint: out.ID,
+					address:  out.Address,
+					value:    out.Amount,
+				}
+				w.addrs[out.Address] = true
+			}
+		}
+		w.transactions[txn.ID] = txn
+		w.mtx.Unlock()
+		if w.outgoing != nil {
+			w.outgoing <- txn
+		}
+		return nil
+	}
+}
+
+// mockOutpoint returns the outpoint of the output at the index.
+func mockOutpoint(txid []byte, index int) []byte {
+	idx := make([]byte, 4)
+	binary.BigEndian.PutUint32(idx, uint32(index))
+	return append(append([]byte{}, txid...), idx...)
+}
@@ -0,0 +1,222 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	iwallet "github.com/cpacia/wallet-interface"
+	"testing"
+)
+
+func newCoinControlWallet(t *testing.T, amounts ...int) *MockWallet {
+	w := NewMockWallet()
+	for _, amount := range amounts {
+		addr, err := w.newAddress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		txid := make([]byte, 32)
+		rand.Read(txid)
+		op := mockOutpoint(txid, 0)
+		w.utxos[hex.EncodeToString(op)] = mockUtxo{
+			outpoint: op,
+			address:  addr,
+			value:    iwallet.NewAmount(amount),
+			height:   1,
+		}
+		w.addrs[addr] = true
+	}
+	return w
+}
+
+func randomAddress() iwallet.Address {
+	b := make([]byte, 20)
+	rand.Read(b)
+	return iwallet.NewAddress(hex.EncodeToString(b), iwallet.CtMock)
+}
+
+func TestMockWallet_ListUnspent(t *testing.T) {
+	w := newCoinControlWallet(t, 10000, 20000, 30000)
+
+	utxos, err := w.ListUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 3 {
+		t.Fatalf("Expected 3 utxos, got %d", len(utxos))
+	}
+	total := iwallet.NewAmount(0)
+	for i, utxo := range utxos {
+		if i > 0 && utxos[i-1].Outpoint >= utxo.Outpoint {
+			t.Error("Utxos not sorted")
+		}
+		total = total.Add(utxo.Amount)
+	}
+	if total.Cmp(iwallet.NewAmount(60000)) != 0 {
+		t.Errorf("Expected total 60000, got %s", total)
+	}
+}
+
+func TestMockWallet_SpendOutputs(t *testing.T) {
+	w := newCoinControlWallet(t, 100000, 200000, 300000)
+	utxos, err := w.ListUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := []SpendOutput{
+		{Address: randomAddress(), Amount: iwallet.NewAmount(50000)},
+		{Address: randomAddress(), Amount: iwallet.NewAmount(60000)},
+	}
+
+	// Dry run with selected utxos and a custom fee rate.
+	dbtx, err := w.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := w.SpendOutputs(dbtx, &SpendRequest{
+		Outputs:  outputs,
+		Utxos:    []string{utxos[0].Outpoint, utxos[1].Outpoint},
+		FeeLevel: 10,
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Fee.Cmp(iwallet.NewAmount(10*(10+148*2+34*3))) != 0 {
+		t.Errorf("Incorrect fee %s", result.Fee)
+	}
+	var txn iwallet.Transaction
+	if err := json.Unmarshal(result.RawTx, &txn); err != nil {
+		t.Fatal(err)
+	}
+	if len(txn.From) != 2 || len(txn.To) != 3 || txn.ID != result.Txid {
+		t.Errorf("Incorrect raw transaction %v", txn)
+	}
+	if err := dbtx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := w.ListUnspent(); len(after) != 3 {
+		t.Errorf("Dry run changed the utxos")
+	}
+
+	// Selected utxo that doesn't cover the outputs.
+	dbtx, err = w.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.SpendOutputs(dbtx, &SpendRequest{Outputs: outputs, Utxos: []string{utxos[0].Outpoint}, FeeLevel: 1000})
+	if err == nil {
+		t.Error("Expected insufficient funds error")
+	}
+	_, err = w.SpendOutputs(dbtx, &SpendRequest{Outputs: outputs, Utxos: []string{"abcd"}})
+	if !errors.Is(err, ErrUtxoNotFound) {
+		t.Errorf("Expected utxo not found error, got %v", err)
+	}
+
+	// Spend with automatic selection.
+	result, err = w.SpendOutputs(dbtx, &SpendRequest{Outputs: outputs, FeeLevel: iwallet.FlNormal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	spent, err := w.GetTransaction(result.Txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedValue := iwallet.NewAmount(-110000).Sub(result.Fee)
+	if spent.Value.Cmp(expectedValue) != 0 {
+		t.Errorf("Expected value %s, got %s", expectedValue, spent.Value)
+	}
+	unconfirmed, confirmed, err := w.Balance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Add(unconfirmed).Cmp(iwallet.NewAmount(600000).Add(expectedValue)) != 0 {
+		t.Errorf("Incorrect balance %s", confirmed.Add(unconfirmed))
+	}
+}
+
+func TestMockWallet_BumpFee(t *testing.T) {
+	w := newCoinControlWallet(t, 100000)
+
+	dbtx, err := w.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := w.SpendOutputs(dbtx, &SpendRequest{
+		Outputs:  []SpendOutput{{Address: randomAddress(), Amount: iwallet.NewAmount(50000)}},
+		FeeLevel: iwallet.FLSuperEconomic,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// RBF
+	dbtx, err = w.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.BumpFee(dbtx, result.Txid, BumpRBF, iwallet.FLSuperEconomic); !errors.Is(err, ErrCannotBump) {
+		t.Errorf("Expected cannot bump error, got %v", err)
+	}
+	replacement, err := w.BumpFee(dbtx, result.Txid, BumpRBF, iwallet.FlPriority)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if replacement.Fee.Cmp(iwallet.NewAmount(6*(10+148+34*2))) != 0 {
+		t.Errorf("Incorrect replacement fee %s", replacement.Fee)
+	}
+	if _, err := w.GetTransaction(result.Txid); err == nil {
+		t.Error("Replaced transaction still exists")
+	}
+	utxos, err := w.ListUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedChange := iwallet.NewAmount(50000).Sub(replacement.Fee)
+	if len(utxos) != 1 || utxos[0].Amount.Cmp(expectedChange) != 0 {
+		t.Errorf("Expected change of %s, got %v", expectedChange, utxos)
+	}
+
+	// CPFP
+	dbtx, err = w.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := w.BumpFee(dbtx, replacement.Txid, BumpCPFP, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	expectedFee := iwallet.NewAmount(20 * (10 + 148 + 34*2 + 10 + 148 + 34)).Sub(replacement.Fee)
+	if child.Fee.Cmp(expectedFee) != 0 {
+		t.Errorf("Expected child fee %s, got %s", expectedFee, child.Fee)
+	}
+	utxos, err = w.ListUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].Amount.Cmp(expectedChange.Sub(expectedFee)) != 0 {
+		t.Errorf("Incorrect utxos after CPFP %v", utxos)
+	}
+
+	dbtx, err = w.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.BumpFee(dbtx, "abc", BumpCPFP, 20); err == nil {
+		t.Error("Expected not found error")
+	}
+}